@@ -0,0 +1,182 @@
+// Package auth is the former import path of the server's auth package, kept so existing
+// imports keep building.
+//
+// Deprecated: Use the public API in
+// EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth; the rest is no longer part of it.
+package auth
+
+import internalauth "EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+
+type (
+	// Deprecated: AuthorizationHandler is no longer part of the public API.
+	AuthorizationHandler = internalauth.AuthorizationHandler
+
+	// Deprecated: StateStore is no longer part of the public API.
+	StateStore = internalauth.StateStore
+
+	// Deprecated: Use auth.AuthState from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	AuthState = internalauth.AuthState
+
+	// Deprecated: CallbackHandler is no longer part of the public API.
+	CallbackHandler = internalauth.CallbackHandler
+
+	// Deprecated: Use auth.TokenStorage from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	TokenStorage = internalauth.TokenStorage
+
+	// Deprecated: Use auth.AuthCodeInfo from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	AuthCodeInfo = internalauth.AuthCodeInfo
+
+	// Deprecated: Use auth.AccessTokenInfo from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	AccessTokenInfo = internalauth.AccessTokenInfo
+
+	// Deprecated: InMemoryTokenStorage is no longer part of the public API.
+	InMemoryTokenStorage = internalauth.InMemoryTokenStorage
+
+	// Deprecated: Use auth.Config from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	Config = internalauth.Config
+
+	// Deprecated: Use auth.GitHubTokenVerifier from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	GitHubTokenVerifier = internalauth.GitHubTokenVerifier
+
+	// Deprecated: ProtectedResourceMetadataHandler is no longer part of the public API.
+	ProtectedResourceMetadataHandler = internalauth.ProtectedResourceMetadataHandler
+
+	// Deprecated: AuthServerMetadataHandler is no longer part of the public API.
+	AuthServerMetadataHandler = internalauth.AuthServerMetadataHandler
+
+	// Deprecated: Use auth.Middleware from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	Middleware = internalauth.Middleware
+
+	// Deprecated: ProtectedResourceMetadata is no longer part of the public API.
+	ProtectedResourceMetadata = internalauth.ProtectedResourceMetadata
+
+	// Deprecated: AuthServerMetadata is no longer part of the public API.
+	AuthServerMetadata = internalauth.AuthServerMetadata
+
+	// Deprecated: Use auth.ClientRegistrationRequest from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	ClientRegistrationRequest = internalauth.ClientRegistrationRequest
+
+	// Deprecated: ClientRegistrationResponse is no longer part of the public API.
+	ClientRegistrationResponse = internalauth.ClientRegistrationResponse
+
+	// Deprecated: ClientRegistrationError is no longer part of the public API.
+	ClientRegistrationError = internalauth.ClientRegistrationError
+
+	// Deprecated: Use auth.OAuthClient from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	OAuthClient = internalauth.OAuthClient
+
+	// Deprecated: Use auth.TokenValidationResult from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	TokenValidationResult = internalauth.TokenValidationResult
+
+	// Deprecated: GitHubUserInfo is no longer part of the public API.
+	GitHubUserInfo = internalauth.GitHubUserInfo
+
+	// Deprecated: PKCEChallenge is no longer part of the public API.
+	PKCEChallenge = internalauth.PKCEChallenge
+
+	// Deprecated: OAuthError is no longer part of the public API.
+	OAuthError = internalauth.OAuthError
+
+	// Deprecated: RegistrationHandler is no longer part of the public API.
+	RegistrationHandler = internalauth.RegistrationHandler
+
+	// Deprecated: Use auth.ClientStorage from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	ClientStorage = internalauth.ClientStorage
+
+	// Deprecated: InMemoryClientStorage is no longer part of the public API.
+	InMemoryClientStorage = internalauth.InMemoryClientStorage
+
+	// Deprecated: Use auth.TokenCache from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	TokenCache = internalauth.TokenCache
+
+	// Deprecated: InMemoryTokenCache is no longer part of the public API.
+	InMemoryTokenCache = internalauth.InMemoryTokenCache
+
+	// Deprecated: TokenEndpointHandler is no longer part of the public API.
+	TokenEndpointHandler = internalauth.TokenEndpointHandler
+
+	// Deprecated: TokenProxyHandler is no longer part of the public API.
+	TokenProxyHandler = internalauth.TokenProxyHandler
+
+	// Deprecated: AuthorizeProxyHandler is no longer part of the public API.
+	AuthorizeProxyHandler = internalauth.AuthorizeProxyHandler
+)
+
+const (
+	// Deprecated: ErrorInvalidRedirectURI is no longer part of the public API.
+	ErrorInvalidRedirectURI = internalauth.ErrorInvalidRedirectURI
+
+	// Deprecated: ErrorInvalidClientMetadata is no longer part of the public API.
+	ErrorInvalidClientMetadata = internalauth.ErrorInvalidClientMetadata
+
+	// Deprecated: ErrorInvalidSoftwareStatement is no longer part of the public API.
+	ErrorInvalidSoftwareStatement = internalauth.ErrorInvalidSoftwareStatement
+
+	// Deprecated: ErrorUnapprovedSoftwareStatement is no longer part of the public API.
+	ErrorUnapprovedSoftwareStatement = internalauth.ErrorUnapprovedSoftwareStatement
+
+	// Deprecated: ErrorInvalidRequest is no longer part of the public API.
+	ErrorInvalidRequest = internalauth.ErrorInvalidRequest
+
+	// Deprecated: ErrorServerError is no longer part of the public API.
+	ErrorServerError = internalauth.ErrorServerError
+)
+
+var (
+	// Deprecated: NewStateStore is no longer part of the public API.
+	NewStateStore = internalauth.NewStateStore
+
+	// Deprecated: NewAuthorizationHandler is no longer part of the public API.
+	NewAuthorizationHandler = internalauth.NewAuthorizationHandler
+
+	// Deprecated: NewInMemoryTokenStorage is no longer part of the public API.
+	NewInMemoryTokenStorage = internalauth.NewInMemoryTokenStorage
+
+	// Deprecated: NewCallbackHandler is no longer part of the public API.
+	NewCallbackHandler = internalauth.NewCallbackHandler
+
+	// Deprecated: Use auth.DefaultConfig from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	DefaultConfig = internalauth.DefaultConfig
+
+	// Deprecated: Use auth.LoadConfigFromEnv from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	LoadConfigFromEnv = internalauth.LoadConfigFromEnv
+
+	// Deprecated: Use auth.NewGitHubTokenVerifier from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	NewGitHubTokenVerifier = internalauth.NewGitHubTokenVerifier
+
+	// Deprecated: NewProtectedResourceMetadataHandler is no longer part of the public API.
+	NewProtectedResourceMetadataHandler = internalauth.NewProtectedResourceMetadataHandler
+
+	// Deprecated: NewAuthServerMetadataHandler is no longer part of the public API.
+	NewAuthServerMetadataHandler = internalauth.NewAuthServerMetadataHandler
+
+	// Deprecated: Use auth.NewMiddleware from EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth.
+	NewMiddleware = internalauth.NewMiddleware
+
+	// Deprecated: NewRegistrationHandler is no longer part of the public API.
+	NewRegistrationHandler = internalauth.NewRegistrationHandler
+
+	// Deprecated: NewInMemoryClientStorage is no longer part of the public API.
+	NewInMemoryClientStorage = internalauth.NewInMemoryClientStorage
+
+	// Deprecated: NewInMemoryClientStorageWithDefaults is no longer part of the public API.
+	NewInMemoryClientStorageWithDefaults = internalauth.NewInMemoryClientStorageWithDefaults
+
+	// Deprecated: GenerateClientID is no longer part of the public API.
+	GenerateClientID = internalauth.GenerateClientID
+
+	// Deprecated: GenerateClientSecret is no longer part of the public API.
+	GenerateClientSecret = internalauth.GenerateClientSecret
+
+	// Deprecated: NewInMemoryTokenCache is no longer part of the public API.
+	NewInMemoryTokenCache = internalauth.NewInMemoryTokenCache
+
+	// Deprecated: NewTokenEndpointHandler is no longer part of the public API.
+	NewTokenEndpointHandler = internalauth.NewTokenEndpointHandler
+
+	// Deprecated: NewTokenProxyHandler is no longer part of the public API.
+	NewTokenProxyHandler = internalauth.NewTokenProxyHandler
+
+	// Deprecated: NewAuthorizeProxyHandler is no longer part of the public API.
+	NewAuthorizeProxyHandler = internalauth.NewAuthorizeProxyHandler
+)
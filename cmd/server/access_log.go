@@ -0,0 +1,130 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/accesslog"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/audit"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/logfile"
+)
+
+// accessLogMiddleware returns middleware writing access logs as configured by ACCESS_LOG_FORMAT
+// and the ACCESS_LOG_* file options, or a no-op if access logging is disabled
+// Log files rotate as configured and are also reopened on SIGHUP for external logrotate
+func accessLogMiddleware() func(http.Handler) http.Handler {
+	passthrough := func(next http.Handler) http.Handler { return next }
+
+	formatName := os.Getenv("ACCESS_LOG_FORMAT")
+	if formatName == "" {
+		return passthrough
+	}
+	format, err := accesslog.ParseFormat(formatName)
+	if err != nil {
+		log.Printf("Warning: %v. Access logging will be disabled.", err)
+		return passthrough
+	}
+
+	var out io.Writer = os.Stdout
+	file, err := logfile.OpenFromEnv("ACCESS")
+	if err != nil {
+		log.Printf("Warning: %v. Access logging will be disabled.", err)
+		return passthrough
+	}
+	if file != nil {
+		reopenOnSIGHUP(file)
+		out = file
+		log.Printf("Writing %s access logs to %s", format, os.Getenv("ACCESS_LOG_FILE"))
+	} else {
+		log.Printf("Writing %s access logs to stdout", format)
+	}
+
+	return accesslog.New(out, format).Handler
+}
+
+// setupApplicationLog copies the application log to the file configured by APP_LOG_FILE,
+// keeping stderr output for the container log driver
+func setupApplicationLog() {
+	file, err := logfile.OpenFromEnv("APP")
+	if err != nil {
+		log.Printf("Warning: %v. Application logs will only be written to stderr.", err)
+		return
+	}
+	if file == nil {
+		return
+	}
+
+	reopenOnSIGHUP(file)
+	log.SetOutput(io.MultiWriter(os.Stderr, file))
+	log.Printf("Writing application logs to %s", os.Getenv("APP_LOG_FILE"))
+}
+
+// Default anomaly detection thresholds
+const (
+	defaultAnomalyWindowSeconds   = 300
+	defaultInvalidTokenThreshold  = 100
+	defaultPKCEFailureThreshold   = 20
+	defaultUnknownClientThreshold = 20
+)
+
+// setupAuditLog stores token audit events in the file configured by AUDIT_LOG_FILE and the
+// AUDIT_LOG_* rotation options; without it only the most recent events are kept, in memory
+// Events are also posted to the webhooks in AUDIT_WEBHOOK_URLS
+func setupAuditLog() {
+	audit.Default.SetTrustForwardedFor(trustForwardedForFromEnv())
+
+	webhooks, err := audit.WebhooksFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid audit webhook configuration: %v", err)
+	}
+	for _, webhook := range webhooks {
+		audit.Default.AddHook(webhook)
+	}
+	if len(webhooks) > 0 {
+		log.Printf("Sending token events to %d audit webhook(s)", len(webhooks))
+	}
+
+	// Failure patterns that suggest an attack raise anomalies in the audit log
+	if window := countFromEnv("ANOMALY_WINDOW_SECONDS", defaultAnomalyWindowSeconds); window > 0 {
+		thresholds := audit.Thresholds{
+			Window:              time.Duration(window) * time.Second,
+			InvalidTokens:       countFromEnv("ANOMALY_INVALID_TOKEN_THRESHOLD", defaultInvalidTokenThreshold),
+			PKCEFailures:        countFromEnv("ANOMALY_PKCE_FAILURE_THRESHOLD", defaultPKCEFailureThreshold),
+			UnknownClientsPerIP: countFromEnv("ANOMALY_UNKNOWN_CLIENT_THRESHOLD", defaultUnknownClientThreshold),
+		}
+		audit.Default.AddHook(audit.NewDetector(audit.Default, thresholds))
+		log.Printf("Anomaly detection: %d invalid tokens, %d PKCE failures, or %d unknown clients per IP within %v",
+			thresholds.InvalidTokens, thresholds.PKCEFailures, thresholds.UnknownClientsPerIP, thresholds.Window)
+	}
+
+	file, err := logfile.OpenFromEnv("AUDIT")
+	if err != nil {
+		log.Fatalf("Failed to open audit log: %v", err)
+	}
+	if file == nil {
+		log.Printf("Keeping recent token audit events in memory (set AUDIT_LOG_FILE to retain them)")
+		return
+	}
+
+	reopenOnSIGHUP(file)
+	audit.Default.SetStore(audit.NewFileStore(file))
+	log.Printf("Writing token audit events to %s", os.Getenv("AUDIT_LOG_FILE"))
+}
+
+// reopenOnSIGHUP reopens file whenever the process receives SIGHUP
+func reopenOnSIGHUP(file *logfile.File) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if err := file.Reopen(); err != nil {
+				log.Printf("Failed to reopen log file: %v", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// defaultSecretReloadInterval is how often credentials are re-fetched from Secrets Manager when
+// GITHUB_OAUTH_SECRET_NAME is set and CONFIG_RELOAD_INTERVAL_SECONDS isn't
+const defaultSecretReloadInterval = 3600
+
+// watchConfigReloads reloads the GitHub credentials, redirect URIs, and scopes in config on
+// SIGHUP, and every CONFIG_RELOAD_INTERVAL_SECONDS when set, so a secret rotated in Secrets
+// Manager is picked up without a redeploy; CONFIG_FILE is read again first
+// A reload that fails keeps the current configuration
+func watchConfigReloads(config *auth.Config) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	var tick <-chan time.Time
+	fallback := 0
+	if os.Getenv("GITHUB_OAUTH_SECRET_NAME") != "" {
+		fallback = defaultSecretReloadInterval
+	}
+	if interval := countFromEnv("CONFIG_RELOAD_INTERVAL_SECONDS", fallback); interval > 0 {
+		tick = time.NewTicker(time.Duration(interval) * time.Second).C
+		log.Printf("Reloading OAuth configuration every %ds and on SIGHUP", interval)
+	}
+
+	go func() {
+		for {
+			select {
+			case <-hup:
+			case <-tick:
+			}
+			if path := os.Getenv("CONFIG_FILE"); path != "" {
+				if err := auth.ApplyConfigFile(path); err != nil {
+					log.Printf("[CONFIG] Failed to reload, keeping the current configuration: %v", err)
+					continue
+				}
+			}
+			changed, err := config.ReloadFromEnv()
+			if err != nil {
+				log.Printf("[CONFIG] Failed to reload, keeping the current configuration: %v", err)
+				continue
+			}
+			if len(changed) > 0 {
+				log.Printf("[CONFIG] Reloaded %s", strings.Join(changed, ", "))
+			}
+		}
+	}()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/cors"
+)
+
+// defaultCORSMaxAge is how long browsers cache preflight results unless CORS_MAX_AGE_SECONDS is set
+const defaultCORSMaxAge = time.Hour
+
+// corsPolicies holds the CORS policy for each group of routes
+// Routes without a policy (authorize, callback, admin, health) send no CORS headers
+type corsPolicies struct {
+	// mcp covers the MCP endpoint, which browser clients call with credentials and session IDs
+	mcp cors.Policy
+
+	// oauth covers the token and registration endpoints used by browser-based public clients,
+	// including reading, updating, and deleting a registration
+	oauth cors.Policy
+
+	// discovery covers the read-only .well-known metadata documents
+	discovery cors.Policy
+}
+
+// corsPoliciesFromEnv builds the route policies from CORS_ALLOWED_ORIGINS, CORS_OAUTH_ALLOWED_ORIGINS,
+// and CORS_MAX_AGE_SECONDS
+func corsPoliciesFromEnv() corsPolicies {
+	maxAge := defaultCORSMaxAge
+	if maxAgeStr := os.Getenv("CORS_MAX_AGE_SECONDS"); maxAgeStr != "" {
+		seconds, err := strconv.Atoi(maxAgeStr)
+		if err != nil || seconds < 0 {
+			log.Printf("Warning: invalid CORS_MAX_AGE_SECONDS %q, using %s", maxAgeStr, defaultCORSMaxAge)
+		} else {
+			maxAge = time.Duration(seconds) * time.Second
+		}
+	}
+
+	mcpOrigins := os.Getenv("CORS_ALLOWED_ORIGINS")
+	if mcpOrigins == "" {
+		// MCP Inspector
+		mcpOrigins = "http://localhost:6277,http://localhost:6274"
+	}
+	oauthOrigins := os.Getenv("CORS_OAUTH_ALLOWED_ORIGINS")
+	if oauthOrigins == "" {
+		oauthOrigins = cors.Wildcard
+	}
+
+	return corsPolicies{
+		mcp: cors.Policy{
+			AllowedOrigins:   cors.ParseOrigins(mcpOrigins),
+			AllowedMethods:   []string{"GET", "POST", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Content-Type", "Authorization", "Mcp-Protocol-Version", "Mcp-Session-Id", "Last-Event-ID"},
+			ExposedHeaders:   []string{"Mcp-Session-Id", "WWW-Authenticate"},
+			AllowCredentials: true,
+			MaxAge:           maxAge,
+		},
+		oauth: cors.Policy{
+			AllowedOrigins: cors.ParseOrigins(oauthOrigins),
+			AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowedHeaders: []string{"Content-Type", "Authorization"},
+			ExposedHeaders: []string{"WWW-Authenticate"},
+			MaxAge:         maxAge,
+		},
+		discovery: cors.Policy{
+			AllowedOrigins: []string{cors.Wildcard},
+			AllowedMethods: []string{"GET", "OPTIONS"},
+			AllowedHeaders: []string{"Mcp-Protocol-Version"},
+			MaxAge:         maxAge,
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/health"
+)
+
+// Health thresholds
+const (
+	// storageSlowThreshold is how long a storage ping may take before storage is degraded
+	storageSlowThreshold = 500 * time.Millisecond
+
+	// tokenCacheMinHitRate is the token cache hit rate below which GitHub is called for most
+	// verifications, and tokenCacheMinLookups how many lookups it takes to judge it
+	tokenCacheMinHitRate = 0.5
+	tokenCacheMinLookups = 100
+)
+
+// newHealthChecker creates the /health checks; storage and verifier are nil without OAuth
+func newHealthChecker(storage *auth.Storage, verifier *auth.GitHubTokenVerifier) *health.Checker {
+	checker := health.New()
+	checker.Register("circuit_breakers", health.BreakerCheck(breaker.Default))
+	if storage != nil {
+		checker.Register("storage", health.LatencyCheck(storage.Ping, storageSlowThreshold))
+	}
+	if verifier != nil {
+		checker.Register("token_cache", health.HitRateCheck(func() (int64, int64) {
+			stats := verifier.Stats()
+			return stats.CacheHits, stats.CacheMisses
+		}, tokenCacheMinHitRate, tokenCacheMinLookups))
+		budget := verifier.Budget()
+		checker.Register("github_budget", health.BudgetCheck(func() (int, int, bool) {
+			statuses := budget.Snapshot()
+			if len(statuses) == 0 {
+				return 0, 0, false
+			}
+			return statuses[0].Remaining, statuses[0].Limit, true
+		}, budget.Reserve()))
+	}
+	return checker
+}
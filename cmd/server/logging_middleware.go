@@ -0,0 +1,165 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+// defaultSlowRequestThreshold is used when SLOW_REQUEST_THRESHOLD_MS is not set
+const defaultSlowRequestThreshold = 2 * time.Second
+
+// maxCapturedBody bounds how much of a request body is kept to identify slow tool calls
+const maxCapturedBody = 64 * 1024
+
+// responseWriter wraps http.ResponseWriter to capture the status code.
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (rw *responseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.ResponseWriter.Header()
+}
+
+// slowRequestThresholdFromEnv reads SLOW_REQUEST_THRESHOLD_MS; 0 disables slow request detection
+func slowRequestThresholdFromEnv() time.Duration {
+	value := os.Getenv("SLOW_REQUEST_THRESHOLD_MS")
+	if value == "" {
+		return defaultSlowRequestThreshold
+	}
+	ms, err := strconv.Atoi(value)
+	if err != nil || ms < 0 {
+		log.Printf("Warning: Invalid SLOW_REQUEST_THRESHOLD_MS %q, using %v", value, defaultSlowRequestThreshold)
+		return defaultSlowRequestThreshold
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// limitedBuffer keeps the first max bytes written to it and discards the rest
+type limitedBuffer struct {
+	bytes.Buffer
+	max int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room > 0 {
+		if len(p) > room {
+			b.Buffer.Write(p[:room])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+// jsonRPCToolName returns the tool name from a JSON-RPC tools/call body, or the method for other requests
+func jsonRPCToolName(body []byte) string {
+	var msg struct {
+		Method string `json:"method"`
+		Params struct {
+			Name string `json:"name"`
+		} `json:"params"`
+	}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return ""
+	}
+	if msg.Method == "tools/call" {
+		return msg.Params.Name
+	}
+	return msg.Method
+}
+
+func loggingHandler(handler http.Handler, slowThreshold time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		// Keep a copy of the body as the handler reads it, to name the tool if the request is slow
+		captured := &limitedBuffer{max: maxCapturedBody}
+		if slowThreshold > 0 && r.Body != nil && r.Method == http.MethodPost {
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.TeeReader(r.Body, captured), r.Body}
+		}
+
+		// Create a response writer wrapper to capture status code.
+		wrapped := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+		// Log request details including session ID if present
+		sessionID := r.Header.Get("Mcp-Session-Id")
+		sessionInfo := ""
+		if sessionID != "" {
+			sessionInfo = " | Session: " + sessionID
+		}
+
+		log.Printf("[REQUEST] %s | %s | %s %s%s",
+			start.Format(time.RFC3339),
+			r.RemoteAddr,
+			r.Method,
+			r.URL.Path,
+			sessionInfo)
+
+		// Call the actual handler.
+		handler.ServeHTTP(wrapped, r)
+
+		// Log response details including session ID if set in response
+		responseSessionID := wrapped.Header().Get("Mcp-Session-Id")
+		responseSessionInfo := ""
+		if responseSessionID != "" {
+			responseSessionInfo = " | Response Session: " + responseSessionID
+		}
+
+		duration := time.Since(start)
+		log.Printf("[RESPONSE] %s | %s | %s %s | Status: %d | Duration: %v%s",
+			time.Now().Format(time.RFC3339),
+			r.RemoteAddr,
+			r.Method,
+			r.URL.Path,
+			wrapped.statusCode,
+			duration,
+			responseSessionInfo)
+
+		if slowThreshold > 0 && duration >= slowThreshold {
+			if sessionID == "" {
+				sessionID = responseSessionID
+			}
+			logSlowRequest(r, wrapped.statusCode, duration, slowThreshold, sessionID, jsonRPCToolName(captured.Bytes()))
+		}
+	})
+}
+
+// logSlowRequest flags a request that exceeded the latency threshold and emits an [ALERT] event
+// that log-based metrics can count
+func logSlowRequest(r *http.Request, status int, duration, threshold time.Duration, sessionID, tool string) {
+	log.Printf("[SLOW] %s %s | Status: %d | Duration: %v (threshold %v) | Session: %s | Tool: %s",
+		r.Method, r.URL.Path, status, duration, threshold, sessionID, tool)
+
+	event, _ := json.Marshal(instance.Current().Tag(map[string]any{
+		"event":        "slow_request",
+		"method":       r.Method,
+		"path":         r.URL.Path,
+		"status":       status,
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": threshold.Milliseconds(),
+		"session":      sessionID,
+		"tool":         tool,
+	}))
+	log.Printf("[ALERT] %s", event)
+}
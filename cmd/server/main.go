@@ -0,0 +1,669 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/adminapi"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/audit"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/docs"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/maintenance"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/metering"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/policy"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/release"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/sse"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/telemetry"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	mcpserver "EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/server"
+)
+
+func main() {
+	printConfigSchema := flag.Bool("print-config-schema", false, "print the environment variable schema as JSON and exit")
+	printClientConfig := flag.Bool("print-client-config", false, "print MCP client configuration snippets for this server as JSON and exit")
+	selfUpdate := flag.Bool("self-update", false, "replace this binary with the latest release from UPDATE_CHECK_REPO and exit")
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or JSON file of environment variable settings; the environment overrides it")
+	flag.Parse()
+
+	applyConfigFile(*configFile)
+
+	if *selfUpdate {
+		runSelfUpdate()
+		return
+	}
+
+	if *printClientConfig {
+		config, err := auth.LoadConfigFromEnv()
+		if err != nil {
+			log.Fatalf("Failed to load OAuth config: %v", err)
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(auth.NewClientConfig(config)); err != nil {
+			log.Fatalf("Failed to print client config: %v", err)
+		}
+		return
+	}
+
+	if *printConfigSchema {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(auth.ConfigSchema()); err != nil {
+			log.Fatalf("Failed to print config schema: %v", err)
+		}
+		return
+	}
+
+	setupApplicationLog()
+	setupAuditLog()
+	detectInstance()
+	shutdownTelemetry := setupTelemetry()
+	startUpdateChecks()
+
+	host := os.Getenv("HOST")
+	port := os.Getenv("PORT")
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	if port == "" {
+		port = "8080"
+		if tlsSettingsFromEnv().enabled() {
+			port = "443"
+		}
+	}
+	runServer(fmt.Sprintf("%s:%s", host, port))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTelemetry(ctx); err != nil {
+		log.Printf("Warning: Failed to flush traces: %v", err)
+	}
+}
+
+// setupTelemetry starts exporting OpenTelemetry traces if an OTLP endpoint is configured
+// Tracing is never fatal; the returned function flushes pending spans on shutdown
+func setupTelemetry() func(context.Context) error {
+	shutdown, err := telemetry.Setup(context.Background())
+	if err != nil {
+		log.Printf("Warning: %v. Traces will not be exported.", err)
+		return func(context.Context) error { return nil }
+	}
+	return shutdown
+}
+
+// applyConfigFile sets the variables in the --config or CONFIG_FILE file that the environment
+// doesn't set; an unreadable or invalid file is fatal rather than running half configured
+func applyConfigFile(path string) {
+	if path == "" {
+		return
+	}
+	if err := auth.ApplyConfigFile(path); err != nil {
+		log.Fatalf("Invalid config file:\n%v", err)
+	}
+	// Reloads read the same file
+	_ = os.Setenv("CONFIG_FILE", path)
+	log.Printf("Loaded settings from %s", path)
+}
+
+// startUpdateChecks logs the running version and, when UPDATE_CHECK_REPO is set, checks for a
+// newer release at startup and every UPDATE_CHECK_INTERVAL_HOURS (0 checks only at startup)
+func startUpdateChecks() {
+	log.Printf("Running version %s", release.Version)
+	checker := release.NewCheckerFromEnv()
+	if !checker.Enabled() {
+		return
+	}
+	release.Default = checker
+	interval := time.Duration(countFromEnv("UPDATE_CHECK_INTERVAL_HOURS", int(release.DefaultCheckInterval.Hours()))) * time.Hour
+	go checker.Run(context.Background(), interval)
+}
+
+// runSelfUpdate replaces the running binary with the latest release, for bare-metal installs
+func runSelfUpdate() {
+	checker := release.NewCheckerFromEnv()
+	if !checker.Enabled() {
+		log.Fatalf("Set UPDATE_CHECK_REPO to the GitHub repository releases are published to")
+	}
+	executable, err := os.Executable()
+	if err != nil {
+		log.Fatalf("Failed to find the running binary: %v", err)
+	}
+	if executable, err = filepath.EvalSymlinks(executable); err != nil {
+		log.Fatalf("Failed to find the running binary: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	installed, err := checker.Update(ctx, executable)
+	if err != nil {
+		log.Fatalf("Self-update failed: %v", err)
+	}
+	if installed == nil {
+		log.Printf("Version %s is up to date", release.Version)
+		return
+	}
+	log.Printf("Updated %s from %s to %s; restart the server to run it", executable, release.Version, installed.TagName)
+}
+
+// detectInstance looks up the region, availability zone, and instance serving requests and
+// prefixes every log line with them
+func detectInstance() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	metadata, err := instance.NewDetectorFromEnv().Detect(ctx)
+	if err != nil {
+		log.Printf("Warning: %v. Instance metadata will be incomplete.", err)
+	}
+	instance.SetCurrent(metadata)
+
+	if tag := metadata.String(); tag != "" {
+		log.SetPrefix("[" + tag + "] ")
+		log.SetFlags(log.Flags() | log.Lmsgprefix)
+		log.Printf("Serving from region %q, availability zone %q, instance %q", metadata.Region, metadata.AvailabilityZone, metadata.InstanceID)
+	}
+}
+
+// maintenanceModeFromEnv creates the maintenance switch, enabled at startup if MAINTENANCE_MODE is true
+func maintenanceModeFromEnv() *maintenance.Mode {
+	mode := maintenance.New()
+	if enabled := os.Getenv("MAINTENANCE_MODE"); enabled == "true" || enabled == "1" {
+		mode.Enable(os.Getenv("MAINTENANCE_MESSAGE"))
+	}
+	return mode
+}
+
+// policyEngineFromEnv returns the OPA policy evaluated for every tool call, or nil when POLICY_OPA_URL is unset
+func policyEngineFromEnv() policy.Engine {
+	opa := policy.NewOPAFromEnv()
+	if opa == nil {
+		return nil
+	}
+	log.Printf("Tool calls are authorized by the OPA policy at %s", opa.URL)
+	return opa
+}
+
+// meteringExporterFromEnv returns the usage exporter configured by METERING_EXPORT, or nil when
+// it is unset; a malformed configuration is fatal rather than silently losing usage
+func meteringExporterFromEnv() *metering.Exporter {
+	exporter, err := metering.FromEnv(context.Background(), metering.New())
+	if err != nil {
+		log.Fatalf("Failed to configure usage metering: %v", err)
+	}
+	if exporter != nil {
+		log.Printf("Exporting usage as %s every %s", exporter.Format, exporter.Interval)
+	}
+	return exporter
+}
+
+// toolRolloutsFromEnv reads TOOL_ROLLOUT, which limits new tools to a percentage of users or
+// named users; a malformed rollout is fatal rather than exposing the tool to everyone
+func toolRolloutsFromEnv() map[string]tools.Rollout {
+	rollouts, err := tools.ParseRollouts(os.Getenv("TOOL_ROLLOUT"))
+	if err != nil {
+		log.Fatalf("Invalid TOOL_ROLLOUT: %v", err)
+	}
+	for name, rollout := range rollouts {
+		log.Printf("Tool %s is rolled out to %d%% of users plus %d named user(s)", name, rollout.Percent, len(rollout.Users))
+	}
+	return rollouts
+}
+
+// toolCacheFromEnv creates the tool result cache from TOOL_CACHE_TTLS; it caches nothing when unset
+func toolCacheFromEnv() *tools.ResultCache {
+	ttls, err := tools.ParseCacheTTLs(os.Getenv("TOOL_CACHE_TTLS"))
+	if err != nil {
+		log.Fatalf("Invalid TOOL_CACHE_TTLS: %v", err)
+	}
+	for name, ttl := range ttls {
+		log.Printf("Results of tool %s are cached for %s", name, ttl)
+	}
+	return tools.NewResultCache(ttls)
+}
+
+// sessionTimeout is how long an idle MCP session (and its token binding) is kept
+// Only POST requests keep a session alive: an open GET stream, even one kept up by heartbeats,
+// doesn't stop the session from expiring once the client stops sending requests
+const sessionTimeout = 30 * time.Minute
+
+func runServer(addr string) {
+	slowThreshold := slowRequestThresholdFromEnv()
+
+	// Load OAuth configuration
+	config, err := auth.LoadConfigFromEnv()
+	if errors.Is(err, auth.ErrSecretUnavailable) {
+		// Serving without authentication because Secrets Manager was briefly unavailable
+		// would expose every tool; exit so the orchestrator restarts the task instead
+		log.Fatalf("Failed to load OAuth config, refusing to start without authentication: %v", err)
+	}
+	if err != nil {
+		if enabled := os.Getenv("OAUTH_ENABLED"); enabled == "true" || enabled == "1" {
+			log.Fatalf("Failed to load OAuth config, refusing to start without authentication: %v", err)
+		}
+		log.Printf("Warning: Failed to load OAuth config: %v. OAuth will be disabled.", err)
+		runServerWithoutAuth(addr)
+		return
+	}
+
+	// Report every configuration problem at once so they can all be fixed in one pass
+	report := config.ValidationReport()
+	report.Log()
+
+	// Check if OAuth is enabled
+	if !config.OAuthEnabled {
+		log.Printf("OAuth is disabled (set OAUTH_ENABLED=true to enable)")
+		runServerWithoutAuth(addr)
+		return
+	}
+
+	// OAuth was asked for, so a configuration mistake must not leave every tool, admin tools
+	// included, open to anyone; exit so it gets fixed instead
+	if report.HasFatal() {
+		log.Fatalf("Invalid OAuth config, refusing to start without authentication (%d fatal issue(s)):\n%v", len(report.Fatal()), report.Err())
+	}
+
+	watchConfigReloads(config)
+
+	if config.Sandbox {
+		startSandbox()
+	}
+
+	// Initialize OAuth storage (with default clients) from the configured backend
+	storage, err := auth.NewStorage(context.Background(), config)
+	if err != nil {
+		log.Fatalf("Failed to initialize %s storage, refusing to start without authentication: %v", config.StorageBackend, err)
+	}
+	clientStorage := storage.Clients
+	tokenStorage := storage.Tokens
+	tokenCache := storage.Cache
+	githubVerifier := auth.NewGitHubTokenVerifier(config, tokenCache, tokenStorage)
+	middleware := auth.NewMiddleware(config, githubVerifier)
+
+	// Sign JWT access tokens if configured, so they verify without storage or GitHub calls
+	var jwtIssuer *auth.JWTIssuer
+	if config.TokenFormat == auth.TokenFormatJWT {
+		jwtIssuer, err = auth.NewJWTIssuerFromConfig(context.Background(), config)
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing key, refusing to start without authentication: %v", err)
+		}
+		githubVerifier.SetJWTIssuer(jwtIssuer)
+	}
+
+	log.Printf("Pre-registered OAuth client: vscode (client_id can be used in MCP config)")
+
+	// Create authorization handler with state store
+	authHandler := auth.NewAuthorizationHandlerWithStateStorage(config, clientStorage, storage.States)
+
+	// Create callback handler that shares the state store
+	callbackHandler := auth.NewCallbackHandler(config, authHandler.GetStateStore(), tokenStorage)
+	callbackHandler.SetGitHubBudget(githubVerifier.Budget())
+	if config.RequireConsent {
+		authHandler.EnableConsent()
+	}
+
+	// Create token endpoint handler
+	tokenHandler := auth.NewTokenEndpointHandler(config, clientStorage, tokenStorage)
+	if jwtIssuer != nil {
+		tokenHandler.SetJWTIssuer(jwtIssuer)
+	}
+
+	// Usage is metered per user and client only when it is exported
+	var meter *metering.Meter
+	meteringExporter := meteringExporterFromEnv()
+	if meteringExporter != nil {
+		meter = meteringExporter.Meter
+		go meteringExporter.Run()
+	}
+
+	limits := rateLimitsFromEnv()
+	rollouts := toolRolloutsFromEnv()
+	policyEngine := policyEngineFromEnv()
+	toolCache := toolCacheFromEnv()
+
+	// newServer creates an MCP server serving persona's tools, or every tool when persona is nil;
+	// the main server and every persona share its middleware
+	newServer := func(name string, persona *mcpserver.Persona) *mcp.Server {
+		return mcpserver.New(mcpserver.Options{
+			Name:    name,
+			Persona: persona,
+			// Tools outside a caller's rollout are hidden before scopes or policy could reveal them
+			Middleware: []mcp.Middleware{
+				tools.TracingMiddleware,
+				tools.RolloutMiddleware(rollouts),
+				tools.ScopeMiddleware,
+				tools.PolicyMiddleware(policyEngine),
+				activity.Middleware(activity.Default),
+				metering.Middleware(meter),
+				limits.warner.Middleware(),
+				toolCache.Middleware(),
+				tools.SlowCallMiddleware(slowThreshold),
+			},
+		})
+	}
+
+	// Create an MCP server
+	tools.SetClientRegistrar(auth.NewRegistrationHandler(config, clientStorage))
+	tools.SetSecretStore(storage.States, config.AuthStateTTL)
+	tools.SetGitHubAPIURL(config.GitHubAPIURL)
+	tools.SetGitHubBudget(githubVerifier.Budget())
+	tools.SetJWTAccessTokens(config.TokenFormat == auth.TokenFormatJWT)
+	mcpserver.SetServerURL(config.ServerURL)
+	server := newServer("time-server", nil)
+
+	// Create the streamable HTTP handler with session timeout
+	// Sessions are needed for GET requests (SSE streaming)
+	handler := mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
+		return server
+	}, &mcp.StreamableHTTPOptions{
+		SessionTimeout: sessionTimeout, // Automatically close idle sessions
+		EventStore:     sseEventStoreFromEnv(),
+	})
+
+	// Ping event streams so idle SSE connections survive the ALB idle timeout
+	heartbeat := sseHeartbeatFromEnv()
+
+	// Wrap MCP handler with OAuth authentication on every method, including GET for SSE streaming,
+	// except for JSON-RPC methods explicitly allowed for unauthenticated discovery
+	// Session IDs are bound to the token that created them, so a leaked session ID can't be reused
+	sessionBindings := auth.NewSessionBindings(sessionTimeout)
+	authenticatedHandler := middleware.RequireAuthExceptMethods([]string{"mcp:tools"})(sessionBindings.Middleware(heartbeat.Middleware(handler)))
+	if len(config.UnauthenticatedMethods) > 0 {
+		log.Printf("MCP methods allowed without a token: %s", strings.Join(config.UnauthenticatedMethods, ", "))
+	}
+
+	maintenanceMode := maintenanceModeFromEnv()
+	corsPolicy := corsPoliciesFromEnv()
+
+	// Set up routes
+	mux := http.NewServeMux()
+
+	// Public endpoints (no authentication required)
+	healthChecker := newHealthChecker(storage, githubVerifier)
+	tools.SetHealthReport(healthChecker.Report)
+	mux.Handle("/health", healthChecker)
+	mux.Handle("/ready", maintenanceMode.ReadinessHandler(storage.Ping))
+	mux.Handle("/.well-known/oauth-protected-resource",
+		corsPolicy.discovery.Handler(auth.NewProtectedResourceMetadataHandler(config)))
+	mux.Handle("/.well-known/oauth-authorization-server",
+		corsPolicy.discovery.Handler(auth.NewAuthServerMetadataHandler(config)))
+	// Alias for OpenID Connect discovery (VS Code compatibility)
+	mux.Handle("/.well-known/openid-configuration",
+		corsPolicy.discovery.Handler(auth.NewAuthServerMetadataHandler(config)))
+	mux.Handle("/client-config", corsPolicy.discovery.Handler(auth.NewClientConfigHandler(config)))
+	// Documentation, linked from the protected resource metadata
+	mux.Handle("/docs", docs.NewHandler())
+	mux.Handle("/docs/", docs.NewHandler())
+
+	if jwtIssuer != nil {
+		mux.Handle("/.well-known/jwks.json", corsPolicy.discovery.Handler(auth.NewJWKSHandler(jwtIssuer)))
+	}
+
+	// The sandbox's stand-in for GitHub, which the OAuth flow calls back into
+	if config.Sandbox {
+		mux.Handle(auth.SandboxGitHubPath+"/", auth.NewSandboxGitHubHandler())
+	}
+
+	// DCR endpoint (if enabled)
+	if config.EnableDCR {
+		registrationHandler := corsPolicy.oauth.Handler(auth.NewRegistrationHandler(config, clientStorage))
+		mux.Handle("/register", registrationHandler)
+		mux.Handle("/register/", registrationHandler)
+		log.Printf("Dynamic Client Registration enabled at /register (clients manage theirs at /register/{client_id})")
+	}
+
+	// OAuth endpoints (proper OAuth 2.1 flow with DCR support)
+	// authorize and callback are browser navigations, so they need no CORS policy
+	mux.Handle("/oauth/authorize", authHandler)
+	mux.Handle("/oauth/token", corsPolicy.oauth.Handler(tokenHandler))
+	mux.Handle("/oauth/callback", callbackHandler)
+
+	// Device authorization (RFC 8628) for clients that can't open a browser; the user enters
+	// the code they display at /oauth/device
+	mux.Handle("/oauth/device_authorization",
+		corsPolicy.oauth.Handler(auth.NewDeviceAuthorizationHandler(config, clientStorage, tokenStorage, storage.States)))
+	deviceVerification := auth.NewDeviceVerificationHandler(config, storage.States)
+	if config.RequireConsent {
+		deviceVerification.EnableConsent(clientStorage, tokenStorage)
+	}
+	mux.Handle("/oauth/device", deviceVerification)
+
+	// Admin endpoints
+	mux.Handle("/admin/config-schema",
+		middleware.RequireAuth([]string{"mcp:admin"})(auth.NewConfigSchemaHandler()))
+	mux.Handle("/admin/maintenance",
+		middleware.RequireAuth([]string{"mcp:admin"})(maintenanceMode.AdminHandler()))
+	mux.Handle("/admin/circuit-breakers",
+		middleware.RequireAuth([]string{"mcp:admin"})(breaker.NewStatusHandler(breaker.Default)))
+	mux.Handle("/admin/token-verification",
+		middleware.RequireAuth([]string{"mcp:admin"})(auth.NewVerifierStatsHandler(githubVerifier)))
+	mux.Handle("/admin/github-budget",
+		middleware.RequireAuth([]string{"mcp:admin"})(auth.NewGitHubBudgetHandler(githubVerifier.Budget())))
+	mux.Handle("/admin/sse-streams",
+		middleware.RequireAuth([]string{"mcp:admin"})(sse.NewStatusHandler(heartbeat)))
+	mux.Handle("/admin/activity",
+		middleware.RequireAuth([]string{"mcp:admin"})(activity.NewStatusHandler(activity.Default)))
+	mux.Handle("/admin/tool-cache",
+		middleware.RequireAuth([]string{"mcp:admin"})(tools.NewCacheStatsHandler(toolCache)))
+
+	// Operator endpoints that change state also accept a static token, for scripts without OAuth
+	requireAdmin := middleware.RequireAdmin(os.Getenv("ADMIN_API_TOKEN"))
+	mux.Handle("/admin/clients", requireAdmin(adminapi.NewClientsHandler(clientStorage)))
+	tokensHandler := adminapi.NewTokensHandler(tokenStorage)
+	if jwtIssuer != nil {
+		tokensHandler.SetJWTIssuer(jwtIssuer)
+	}
+	mux.Handle("/admin/tokens", requireAdmin(tokensHandler))
+	mux.Handle("/admin/tokens/revoke", requireAdmin(tokensHandler))
+	mux.Handle("/admin/audit", requireAdmin(audit.NewHandler(audit.Default)))
+
+	// Optional GraphQL API for dashboards; any user can query their own activity, every other
+	// field requires mcp:admin
+	if enabled := os.Getenv("ADMIN_GRAPHQL_ENABLED"); enabled == "true" || enabled == "1" {
+		schema, err := adminapi.NewSchema(adminapi.Sources{
+			Clients:   clientStorage,
+			Verifier:  githubVerifier,
+			Heartbeat: heartbeat,
+			Activity:  activity.Default,
+			Breakers:  breaker.Default,
+		})
+		if err != nil {
+			log.Fatalf("Failed to build GraphQL schema: %v", err)
+		}
+		mux.Handle("/admin/graphql",
+			middleware.RequireAuth([]string{"mcp:tools"})(adminapi.NewHandler(schema)))
+		log.Printf("GraphQL API available at /admin/graphql (admin fields require mcp:admin scope)")
+	}
+
+	// Protected MCP endpoint
+	// CORS runs first so preflights are answered without credentials or during maintenance
+	// Rate limiting runs before authentication so floods never reach GitHub token validation
+	mux.Handle("/", corsPolicy.mcp.Handler(maintenanceMode.Middleware(limits.middleware()(authenticatedHandler))))
+
+	// Focused servers for different audiences, each with its own tools and required scopes
+	servers := []*mcp.Server{server}
+	for _, persona := range personasFromEnv() {
+		personaHandler, personaServer := newPersonaHandler(persona, newServer, &mcp.StreamableHTTPOptions{
+			SessionTimeout: sessionTimeout,
+			EventStore:     sseEventStoreFromEnv(),
+		})
+		servers = append(servers, personaServer)
+		authenticatedPersona := middleware.RequireAuthExceptMethods(persona.Scopes)(sessionBindings.Middleware(heartbeat.Middleware(personaHandler)))
+		mux.Handle(persona.Path(), corsPolicy.mcp.Handler(maintenanceMode.Middleware(limits.middleware()(authenticatedPersona))))
+	}
+
+	// Sessions on every server, personas included, share the session bindings
+	mux.Handle("/admin/sessions", requireAdmin(adminapi.NewSessionsHandler(sessionBindings, servers...)))
+
+	handlerWithLogging := loggingHandler(accessLogMiddleware()(telemetry.HTTPMiddleware(mux)), slowThreshold)
+
+	srv := serverTuningFromEnv().newServer(addr, handlerWithLogging)
+	tls := tlsSettingsFromEnv()
+
+	log.Printf("MCP server listening on %s", addr)
+	log.Printf("OAuth 2.1 authentication enabled with GitHub")
+	log.Printf("Protected Resource Metadata: /.well-known/oauth-protected-resource")
+	log.Printf("Authorization Server Metadata: /.well-known/oauth-authorization-server")
+	log.Printf("Client configuration snippets: /client-config")
+	log.Printf("Available tool: Get City Time (any city or IANA time zone)")
+	log.Printf("Available tool: Get Fortune")
+	log.Printf("Available tool: APR Calculator")
+	log.Printf("Available tool: AWS Costs (requires mcp:admin scope)")
+	log.Printf("Available tool: Deployment Status (requires mcp:admin scope)")
+	log.Printf("Available tool: Tail Logs (requires mcp:admin scope)")
+	log.Printf("Health check available at /health")
+	log.Printf("Config schema available at /admin/config-schema (requires mcp:admin scope)")
+	log.Printf("Circuit breakers available at /admin/circuit-breakers (requires mcp:admin scope)")
+	log.Printf("Token verification counters available at /admin/token-verification (requires mcp:admin scope)")
+	log.Printf("GitHub API rate limit budget available at /admin/github-budget (requires mcp:admin scope)")
+	log.Printf("SSE stream counters available at /admin/sse-streams (requires mcp:admin scope)")
+	log.Printf("User activity available at /admin/activity (requires mcp:admin scope)")
+	log.Printf("Tool result cache counters available at /admin/tool-cache (requires mcp:admin scope)")
+	log.Printf("Clients, tokens, and sessions available at /admin/clients, /admin/tokens, and /admin/sessions (requires mcp:admin scope or ADMIN_API_TOKEN)")
+	log.Printf("Token audit log available at /admin/audit (requires mcp:admin scope or ADMIN_API_TOKEN)")
+	log.Printf("Maintenance mode can be toggled at /admin/maintenance (requires mcp:admin scope)")
+
+	go func() {
+		if err := tls.listenAndServe(srv); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	// Graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+	storage.Close()
+
+	// Export the usage of the unfinished period, which would otherwise be lost
+	if meteringExporter != nil {
+		if err := meteringExporter.Export(ctx); err != nil {
+			log.Printf("[METERING] Failed to export usage: %v", err)
+		}
+	}
+
+	log.Println("Server exiting")
+}
+
+// serverURLWithoutAuth is the URL prompts refer to when OAuth is off: MCP_SERVER_URL, or
+// localhost on the listening port
+func serverURLWithoutAuth(addr string) string {
+	if serverURL := os.Getenv("MCP_SERVER_URL"); serverURL != "" {
+		return strings.TrimSuffix(serverURL, "/")
+	}
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return ""
+	}
+	return "http://localhost:" + port
+}
+
+func runServerWithoutAuth(addr string) {
+	slowThreshold := slowRequestThresholdFromEnv()
+	maintenanceMode := maintenanceModeFromEnv()
+
+	rollouts := toolRolloutsFromEnv()
+	policyEngine := policyEngineFromEnv()
+	toolCache := toolCacheFromEnv()
+
+	// newServer creates an MCP server without authentication, for the main server and personas
+	newServer := func(name string, persona *mcpserver.Persona) *mcp.Server {
+		return mcpserver.New(mcpserver.Options{
+			Name:    name,
+			Persona: persona,
+			// Tools outside a caller's rollout are hidden before scopes or policy could reveal them
+			Middleware: []mcp.Middleware{
+				tools.TracingMiddleware,
+				tools.RolloutMiddleware(rollouts),
+				tools.ScopeMiddleware,
+				tools.PolicyMiddleware(policyEngine),
+				activity.Middleware(activity.Default),
+				toolCache.Middleware(),
+				tools.SlowCallMiddleware(slowThreshold),
+			},
+		})
+	}
+
+	mcpserver.SetServerURL(serverURLWithoutAuth(addr))
+	server := newServer("time-server", nil)
+
+	// Create the streamable HTTP handler
+	handler := mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
+		return server
+	}, &mcp.StreamableHTTPOptions{
+		EventStore: sseEventStoreFromEnv(),
+	})
+
+	corsPolicy := corsPoliciesFromEnv()
+	heartbeat := sseHeartbeatFromEnv()
+
+	mux := http.NewServeMux()
+	mux.Handle("/", corsPolicy.mcp.Handler(maintenanceMode.Middleware(heartbeat.Middleware(handler))))
+	for _, persona := range personasFromEnv() {
+		personaHandler, _ := newPersonaHandler(persona, newServer, &mcp.StreamableHTTPOptions{
+			EventStore: sseEventStoreFromEnv(),
+		})
+		mux.Handle(persona.Path(), corsPolicy.mcp.Handler(maintenanceMode.Middleware(heartbeat.Middleware(personaHandler))))
+	}
+	healthChecker := newHealthChecker(nil, nil)
+	tools.SetHealthReport(healthChecker.Report)
+	mux.Handle("/health", healthChecker)
+	mux.Handle("/ready", maintenanceMode.ReadinessHandler())
+	mux.Handle("/docs", docs.NewHandler())
+	mux.Handle("/docs/", docs.NewHandler())
+
+	handlerWithLogging := loggingHandler(accessLogMiddleware()(telemetry.HTTPMiddleware(mux)), slowThreshold)
+
+	srv := serverTuningFromEnv().newServer(addr, handlerWithLogging)
+	tls := tlsSettingsFromEnv()
+
+	log.Printf("MCP server listening on %s", addr)
+	log.Printf("Health check available at /health")
+
+	go func() {
+		if err := tls.listenAndServe(srv); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("Server failed: %v", err)
+		}
+	}()
+
+	// Graceful shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	log.Println("Shutting down server...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		log.Fatalf("Server forced to shutdown: %v", err)
+	}
+
+	log.Println("Server exiting")
+}
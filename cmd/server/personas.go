@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	mcpserver "EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/server"
+)
+
+// personasFromEnv reads MCP_PERSONAS, exiting on an invalid value rather than serving
+// personas with the wrong tools
+func personasFromEnv() []mcpserver.Persona {
+	personas, err := mcpserver.ParsePersonas(os.Getenv("MCP_PERSONAS"))
+	if err != nil {
+		log.Fatalf("Invalid MCP_PERSONAS: %v", err)
+	}
+	return personas
+}
+
+// newPersonaHandler creates the persona's MCP server with newServer, with the persona's tools
+// and every prompt and resource, and returns its streamable HTTP handler along with the server
+func newPersonaHandler(persona mcpserver.Persona, newServer func(name string, persona *mcpserver.Persona) *mcp.Server, options *mcp.StreamableHTTPOptions) (http.Handler, *mcp.Server) {
+	server := newServer("time-server-"+persona.Name, &persona)
+
+	return mcp.NewStreamableHTTPHandler(func(req *http.Request) *mcp.Server {
+		return server
+	}, options), server
+}
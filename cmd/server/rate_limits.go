@@ -0,0 +1,82 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/ratelimit"
+)
+
+// Default rate limits for the MCP endpoint
+const (
+	defaultTokenRatePerMinute = 120
+	defaultTokenBurst         = 30
+	defaultIPRatePerMinute    = 300
+	defaultIPBurst            = 60
+)
+
+// defaultRateLimitWarnPercent is how much of their per-token burst users use before being warned
+const defaultRateLimitWarnPercent = 80
+
+// rateLimits are the MCP endpoint's limiters, and the warner telling users they are close to one
+type rateLimits struct {
+	byToken, byIP     *ratelimit.Limiter
+	trustForwardedFor bool
+	warner            *ratelimit.Warner
+}
+
+// rateLimitsFromEnv creates the MCP endpoint rate limiters from RATE_LIMIT_TOKEN_PER_MINUTE,
+// RATE_LIMIT_TOKEN_BURST, RATE_LIMIT_IP_PER_MINUTE, RATE_LIMIT_IP_BURST,
+// RATE_LIMIT_TRUST_FORWARDED_FOR, and RATE_LIMIT_WARN_PERCENT; a rate of 0 disables that limit
+func rateLimitsFromEnv() rateLimits {
+	tokenRate := countFromEnv("RATE_LIMIT_TOKEN_PER_MINUTE", defaultTokenRatePerMinute)
+	tokenBurst := countFromEnv("RATE_LIMIT_TOKEN_BURST", defaultTokenBurst)
+	ipRate := countFromEnv("RATE_LIMIT_IP_PER_MINUTE", defaultIPRatePerMinute)
+	ipBurst := countFromEnv("RATE_LIMIT_IP_BURST", defaultIPBurst)
+	warnPercent := countFromEnv("RATE_LIMIT_WARN_PERCENT", defaultRateLimitWarnPercent)
+	if warnPercent > 100 {
+		log.Printf("Warning: Invalid RATE_LIMIT_WARN_PERCENT %d, using %d", warnPercent, defaultRateLimitWarnPercent)
+		warnPercent = defaultRateLimitWarnPercent
+	}
+	trustForwardedFor := trustForwardedForFromEnv()
+
+	log.Printf("Rate limits: %d/min (burst %d) per token, %d/min (burst %d) per client IP (trust X-Forwarded-For %t), warning at %d%%",
+		tokenRate, tokenBurst, ipRate, ipBurst, trustForwardedFor, warnPercent)
+	byToken := ratelimit.New(tokenRate, tokenBurst)
+	return rateLimits{
+		byToken:           byToken,
+		byIP:              ratelimit.New(ipRate, ipBurst),
+		trustForwardedFor: trustForwardedFor,
+		warner:            ratelimit.NewWarner(byToken, float64(warnPercent)/100),
+	}
+}
+
+// middleware rejects requests over the limits
+func (l rateLimits) middleware() func(http.Handler) http.Handler {
+	return ratelimit.Middleware(l.byToken, l.byIP, l.trustForwardedFor)
+}
+
+// trustForwardedForFromEnv reads RATE_LIMIT_TRUST_FORWARDED_FOR, which also applies to the IPs
+// recorded in the audit log
+// Behind the ALB every request comes from the load balancer's address, so the client IP
+// must come from X-Forwarded-For; only trust it when a proxy is guaranteed to set it
+func trustForwardedForFromEnv() bool {
+	trust := os.Getenv("RATE_LIMIT_TRUST_FORWARDED_FOR")
+	return trust == "true" || trust == "1"
+}
+
+// countFromEnv reads a non-negative integer from name, using fallback if unset or invalid
+func countFromEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Printf("Warning: Invalid %s %q, using %d", name, value, fallback)
+		return fallback
+	}
+	return n
+}
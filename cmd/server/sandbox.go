@@ -0,0 +1,40 @@
+package main
+
+import (
+	"log"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+)
+
+// sandboxActivity is the sample activity recorded at startup in sandbox mode, so activity
+// resources, admin endpoints, and dashboards have users to show
+var sandboxActivity = []struct {
+	user     string
+	sessions int
+	calls    map[string]int
+}{
+	{auth.SandboxUser, 1, map[string]int{"get-city-time": 2}},
+	{"octocat", 3, map[string]int{"get-city-time": 5, "get-fortune": 2, "get-deployment-status": 1}},
+	{"hubot", 1, map[string]int{"tail-logs": 4, "get-aws-costs": 1}},
+	{"monalisa", 2, map[string]int{"convert-currency": 3, "calculate-apr": 1}},
+}
+
+// startSandbox switches tools to canned data and records the sample activity
+func startSandbox() {
+	log.Printf("Sandbox mode: GitHub is simulated at %s (everyone signs in as %s), storage is in memory, and tools return canned data",
+		auth.SandboxGitHubPath, auth.SandboxUser)
+	tools.SetSandbox(true)
+
+	for _, sample := range sandboxActivity {
+		for range sample.sessions {
+			activity.Default.RecordSession(sample.user)
+		}
+		for tool, calls := range sample.calls {
+			for range calls {
+				activity.Default.RecordToolCall(sample.user, tool)
+			}
+		}
+	}
+}
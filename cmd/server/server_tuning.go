@@ -0,0 +1,151 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/sse"
+)
+
+// Server defaults, chosen for long-lived SSE streams behind the ALB
+const (
+	// defaultReadHeaderTimeout bounds how long a client may take to send request headers
+	defaultReadHeaderTimeout = 10 * time.Second
+
+	// defaultIdleTimeout must exceed the ALB idle timeout (60s by default), otherwise the server
+	// closes keep-alive connections the ALB is about to reuse and clients see 502s
+	defaultIdleTimeout = 120 * time.Second
+
+	// defaultMaxHeaderBytes matches http.DefaultMaxHeaderBytes
+	defaultMaxHeaderBytes = http.DefaultMaxHeaderBytes
+
+	// defaultSSEHeartbeat keeps event streams well inside the ALB idle timeout (60s by default)
+	defaultSSEHeartbeat = 25 * time.Second
+
+	// defaultSSEReplayBytes bounds the events kept for clients resuming streams, across all sessions
+	defaultSSEReplayBytes = 10 << 20
+)
+
+// serverTuning holds the http.Server settings read from the environment
+// ReadTimeout and WriteTimeout default to 0 (none): they cover the whole request or response,
+// so any non-zero value cuts SSE streams that live longer than it
+type serverTuning struct {
+	readHeaderTimeout time.Duration
+	readTimeout       time.Duration
+	writeTimeout      time.Duration
+	idleTimeout       time.Duration
+	maxHeaderBytes    int
+	http2             bool
+}
+
+// serverTuningFromEnv reads HTTP_READ_HEADER_TIMEOUT_SECONDS, HTTP_READ_TIMEOUT_SECONDS,
+// HTTP_WRITE_TIMEOUT_SECONDS, HTTP_IDLE_TIMEOUT_SECONDS, HTTP_MAX_HEADER_BYTES, and HTTP2_ENABLED
+func serverTuningFromEnv() serverTuning {
+	enableHTTP2 := os.Getenv("HTTP2_ENABLED")
+
+	return serverTuning{
+		readHeaderTimeout: secondsFromEnv("HTTP_READ_HEADER_TIMEOUT_SECONDS", defaultReadHeaderTimeout),
+		readTimeout:       secondsFromEnv("HTTP_READ_TIMEOUT_SECONDS", 0),
+		writeTimeout:      secondsFromEnv("HTTP_WRITE_TIMEOUT_SECONDS", 0),
+		idleTimeout:       secondsFromEnv("HTTP_IDLE_TIMEOUT_SECONDS", defaultIdleTimeout),
+		maxHeaderBytes:    intFromEnv("HTTP_MAX_HEADER_BYTES", defaultMaxHeaderBytes),
+		http2:             enableHTTP2 == "true" || enableHTTP2 == "1",
+	}
+}
+
+// newServer creates an http.Server for addr with these settings
+// With HTTP/2 enabled the server also accepts unencrypted HTTP/2 (h2c), which is what the ALB
+// speaks to targets whose protocol version is HTTP2; when the server terminates TLS itself,
+// HTTP/2 over TLS is negotiated either way
+func (t serverTuning) newServer(addr string, handler http.Handler) *http.Server {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: t.readHeaderTimeout,
+		ReadTimeout:       t.readTimeout,
+		WriteTimeout:      t.writeTimeout,
+		IdleTimeout:       t.idleTimeout,
+		MaxHeaderBytes:    t.maxHeaderBytes,
+	}
+
+	if t.http2 {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		srv.Protocols = protocols
+	}
+
+	if t.writeTimeout > 0 {
+		log.Printf("Warning: HTTP_WRITE_TIMEOUT_SECONDS is %v; SSE streams will be closed after that long", t.writeTimeout)
+	}
+	log.Printf("HTTP server tuning: read header timeout %v, read timeout %v, write timeout %v, idle timeout %v, max header bytes %d, HTTP/2 %t",
+		t.readHeaderTimeout, t.readTimeout, t.writeTimeout, t.idleTimeout, t.maxHeaderBytes, t.http2)
+	return srv
+}
+
+// sseHeartbeatFromEnv creates the event stream heartbeat from SSE_HEARTBEAT_SECONDS; 0 disables pings
+func sseHeartbeatFromEnv() *sse.Heartbeat {
+	interval := secondsFromEnv("SSE_HEARTBEAT_SECONDS", defaultSSEHeartbeat)
+	if interval > 0 {
+		log.Printf("Pinging idle SSE streams every %v", interval)
+	}
+	return sse.New(interval)
+}
+
+// sseEventStoreFromEnv keeps recent stream events, up to SSE_REPLAY_BUFFER_BYTES across all
+// sessions, so a client reconnecting with Last-Event-ID after a network blip gets the
+// notifications it missed; 0 disables resumption
+// Events are kept in memory, like sessions, so a stream can only be resumed on its own instance
+func sseEventStoreFromEnv() mcp.EventStore {
+	limit := defaultSSEReplayBytes
+	if value := os.Getenv("SSE_REPLAY_BUFFER_BYTES"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			log.Printf("Warning: Invalid SSE_REPLAY_BUFFER_BYTES %q, using %d", value, limit)
+		} else {
+			limit = n
+		}
+	}
+	if limit == 0 {
+		return nil
+	}
+
+	store := mcp.NewMemoryEventStore(nil)
+	store.SetMaxBytes(limit)
+	log.Printf("SSE streams are resumable with Last-Event-ID (replay buffer %d bytes)", limit)
+	return store
+}
+
+// secondsFromEnv reads a non-negative number of seconds from name, using fallback if unset or invalid
+func secondsFromEnv(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		log.Printf("Warning: Invalid %s %q, using %v", name, value, fallback)
+		return fallback
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// intFromEnv reads a positive integer from name, using fallback if unset or invalid
+func intFromEnv(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Printf("Warning: Invalid %s %q, using %d", name, value, fallback)
+		return fallback
+	}
+	return n
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// defaultACMECacheDir is where Let's Encrypt certificates are kept unless ACME_CACHE_DIR is set
+const defaultACMECacheDir = "acme-cache"
+
+// defaultACMEHTTPAddr serves HTTP-01 challenges and redirects plain HTTP to HTTPS
+const defaultACMEHTTPAddr = ":80"
+
+// tlsSettings describes how the server terminates TLS; with neither a certificate nor ACME
+// domains it serves plain HTTP and leaves TLS to the load balancer
+type tlsSettings struct {
+	certFile string
+	keyFile  string
+
+	acme         *autocert.Manager
+	acmeHTTPAddr string
+}
+
+// tlsSettingsFromEnv reads TLS_CERT_FILE and TLS_KEY_FILE, or ACME_DOMAINS, ACME_EMAIL,
+// ACME_CACHE_DIR, and ACME_HTTP_ADDR for Let's Encrypt certificates
+// A half-configured certificate is fatal rather than silently falling back to plain HTTP
+func tlsSettingsFromEnv() tlsSettings {
+	settings := tlsSettings{
+		certFile: os.Getenv("TLS_CERT_FILE"),
+		keyFile:  os.Getenv("TLS_KEY_FILE"),
+	}
+	if (settings.certFile == "") != (settings.keyFile == "") {
+		log.Fatalf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	var domains []string
+	for _, domain := range strings.Split(os.Getenv("ACME_DOMAINS"), ",") {
+		if trimmed := strings.TrimSpace(domain); trimmed != "" {
+			domains = append(domains, trimmed)
+		}
+	}
+	if len(domains) == 0 {
+		return settings
+	}
+	if settings.certFile != "" {
+		log.Fatalf("Set either TLS_CERT_FILE/TLS_KEY_FILE or ACME_DOMAINS, not both")
+	}
+
+	cacheDir := os.Getenv("ACME_CACHE_DIR")
+	if cacheDir == "" {
+		cacheDir = defaultACMECacheDir
+	}
+	settings.acmeHTTPAddr = os.Getenv("ACME_HTTP_ADDR")
+	if settings.acmeHTTPAddr == "" {
+		settings.acmeHTTPAddr = defaultACMEHTTPAddr
+	}
+	settings.acme = &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      os.Getenv("ACME_EMAIL"),
+	}
+	log.Printf("Requesting Let's Encrypt certificates for %s (cache %s)", strings.Join(domains, ", "), cacheDir)
+	return settings
+}
+
+// enabled reports whether the server terminates TLS itself
+func (t tlsSettings) enabled() bool {
+	return t.certFile != "" || t.acme != nil
+}
+
+// listenAndServe serves srv over HTTPS when TLS is configured, and plain HTTP otherwise
+// With ACME, a second listener answers HTTP-01 challenges and redirects everything else to HTTPS
+func (t tlsSettings) listenAndServe(srv *http.Server) error {
+	switch {
+	case t.acme != nil:
+		srv.TLSConfig = t.acme.TLSConfig()
+		go func() {
+			challenges := &http.Server{
+				Addr:              t.acmeHTTPAddr,
+				Handler:           t.acme.HTTPHandler(nil),
+				ReadHeaderTimeout: defaultReadHeaderTimeout,
+			}
+			if err := challenges.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("Warning: ACME challenge listener on %s failed: %v", t.acmeHTTPAddr, err)
+			}
+		}()
+		log.Printf("Serving HTTPS with Let's Encrypt certificates; HTTP-01 challenges on %s", t.acmeHTTPAddr)
+		return srv.ListenAndServeTLS("", "")
+	case t.certFile != "":
+		log.Printf("Serving HTTPS with certificate %s", t.certFile)
+		return srv.ListenAndServeTLS(t.certFile, t.keyFile)
+	default:
+		return srv.ListenAndServe()
+	}
+}
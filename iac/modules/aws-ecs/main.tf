@@ -113,6 +113,57 @@ data "aws_iam_policy_document" "task_logging_policy_document" {
     ]
     resources = [aws_secretsmanager_secret.github_oauth.arn]
   }
+
+  # Used by the get-aws-costs tool. Cost Explorer does not support resource-level permissions.
+  statement {
+    effect = "Allow"
+    actions = [
+      "ce:GetCostAndUsage",
+      "ce:GetCostForecast",
+    ]
+    resources = ["*"]
+  }
+
+  # Used by the get-deployment-status tool
+  statement {
+    effect = "Allow"
+    actions = [
+      "ecs:DescribeServices",
+    ]
+    resources = [aws_ecs_service.main.id]
+  }
+
+  statement {
+    effect = "Allow"
+    actions = [
+      "cloudformation:DescribeStackEvents",
+    ]
+    resources = ["*"]
+  }
+
+  # Used by the tail-logs tool, limited to this service's own log group
+  statement {
+    effect = "Allow"
+    actions = [
+      "logs:FilterLogEvents",
+    ]
+    resources = [
+      aws_cloudwatch_log_group.ecs_task.arn,
+      "${aws_cloudwatch_log_group.ecs_task.arn}:*",
+    ]
+  }
+
+  # Persistent OAuth client and token storage (STORAGE_BACKEND=dynamodb)
+  statement {
+    effect = "Allow"
+    actions = [
+      "dynamodb:GetItem",
+      "dynamodb:PutItem",
+      "dynamodb:DeleteItem",
+      "dynamodb:Scan",
+    ]
+    resources = [aws_dynamodb_table.oauth.arn]
+  }
 }
 
 resource "aws_iam_policy" "task_logging_policy" {
@@ -130,6 +181,37 @@ resource "aws_cloudwatch_log_group" "ecs_task" {
   retention_in_days = 5
 }
 
+# Counts the [ALERT] events the server logs for HTTP requests over the slow threshold
+resource "aws_cloudwatch_log_metric_filter" "slow_requests" {
+  name           = format("tf-ecs-%s-slow-requests", data.github_repository.main.name)
+  log_group_name = aws_cloudwatch_log_group.ecs_task.name
+  pattern        = "\"[ALERT]\" \"slow_request\""
+
+  metric_transformation {
+    name          = "SlowRequests"
+    namespace     = "MCPServer"
+    value         = "1"
+    default_value = "0"
+  }
+}
+
+# OAuth clients, tokens, and cached GitHub validations, so restarts don't log every user out
+resource "aws_dynamodb_table" "oauth" {
+  name         = format("tf-ecs-%s-oauth", data.github_repository.main.name)
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "pk"
+
+  attribute {
+    name = "pk"
+    type = "S"
+  }
+
+  ttl {
+    attribute_name = "expires_at"
+    enabled        = true
+  }
+}
+
 # Secrets Manager secret for GitHub OAuth credentials
 resource "aws_secretsmanager_secret" "github_oauth" {
   name        = format("tf-ecs-%s-github-oauth", data.github_repository.main.name)
@@ -165,6 +247,32 @@ resource "aws_ecs_task_definition" "main" {
           {
             name  = "GITHUB_OAUTH_SECRET_NAME"
             value = aws_secretsmanager_secret.github_oauth.name
+          },
+          {
+            name  = "ECS_CLUSTER_NAME"
+            value = aws_ecs_cluster.main.name
+          },
+          {
+            # Can't reference aws_ecs_service.main here since the service depends on this task definition
+            name  = "ECS_SERVICE_NAME"
+            value = format("tf-ecs-task-%s", data.github_repository.main.name)
+          },
+          {
+            name  = "CLOUDWATCH_LOG_GROUPS"
+            value = aws_cloudwatch_log_group.ecs_task.name
+          },
+          {
+            name  = "STORAGE_BACKEND"
+            value = "dynamodb"
+          },
+          {
+            name  = "DYNAMODB_TABLE_NAME"
+            value = aws_dynamodb_table.oauth.name
+          },
+          {
+            # The ALB appends the client IP to X-Forwarded-For
+            name  = "RATE_LIMIT_TRUST_FORWARDED_FOR"
+            value = "true"
           }
         ]
       )
@@ -0,0 +1,122 @@
+// Package accesslog writes HTTP access logs in the Common or Combined Log Format used by
+// Apache and nginx, so they can be fed into existing log tooling
+package accesslog
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Format selects the access log line format
+type Format string
+
+const (
+	// Common is the NCSA Common Log Format
+	Common Format = "common"
+
+	// Combined is the Common Log Format with referer and user agent
+	Combined Format = "combined"
+)
+
+// ParseFormat validates a format name
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(name)) {
+	case Common:
+		return Common, nil
+	case Combined:
+		return Combined, nil
+	default:
+		return "", fmt.Errorf("unknown access log format %q (use common or combined)", name)
+	}
+}
+
+// Logger writes one line per request to its writer
+type Logger struct {
+	mu     sync.Mutex
+	out    io.Writer
+	format Format
+	now    func() time.Time
+}
+
+// New creates a logger writing lines in format to out
+func New(out io.Writer, format Format) *Logger {
+	return &Logger{out: out, format: format, now: time.Now}
+}
+
+// Handler wraps next so every request it serves is logged
+func (l *Logger) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := l.now()
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(recorder, r)
+
+		l.write(r, start, recorder.status, recorder.bytes)
+	})
+}
+
+func (l *Logger) write(r *http.Request, start time.Time, status, bytes int) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	size := "-"
+	if bytes > 0 {
+		size = fmt.Sprint(bytes)
+	}
+
+	line := fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %s`,
+		host,
+		start.Format("02/Jan/2006:15:04:05 -0700"),
+		r.Method,
+		r.URL.RequestURI(),
+		r.Proto,
+		status,
+		size)
+	if l.format == Combined {
+		line += fmt.Sprintf(` "%s" "%s"`, quoteOrDash(r.Referer()), quoteOrDash(r.UserAgent()))
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.out, line+"\n")
+}
+
+// quoteOrDash escapes quotes in a header value, or returns "-" if it is empty
+func quoteOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+// responseRecorder captures the status code and response size
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += n
+	return n, err
+}
+
+// Flush forwards to the underlying writer so streaming responses still work
+func (r *responseRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
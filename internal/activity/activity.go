@@ -0,0 +1,264 @@
+// Package activity aggregates what each user does on the server (sessions started and tools
+// invoked) into hourly buckets, shared by the activity resource, admin endpoint, and analytics
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// BucketSize is the granularity activity is recorded at
+const BucketSize = time.Hour
+
+// Retention is how long activity is kept
+const Retention = 7 * 24 * time.Hour
+
+// Bucket is a user's activity during the period starting at Start
+type Bucket struct {
+	Start     time.Time        `json:"start"`
+	Sessions  int64            `json:"sessions"`
+	ToolCalls map[string]int64 `json:"tool_calls"`
+}
+
+// UserSummary totals a user's activity over the retention window
+type UserSummary struct {
+	User       string    `json:"user"`
+	Sessions   int64     `json:"sessions"`
+	ToolCalls  int64     `json:"tool_calls"`
+	LastActive time.Time `json:"last_active"`
+}
+
+// Tracker records activity per user in memory
+type Tracker struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	users map[string]map[time.Time]*Bucket
+}
+
+// Default is the tracker fed by the MCP middleware and read by the resource and admin endpoint
+var Default = New()
+
+// New creates an empty tracker
+func New() *Tracker {
+	return &Tracker{
+		clock: clock.System{},
+		users: make(map[string]map[time.Time]*Bucket),
+	}
+}
+
+// SetClock replaces the clock used to bucket activity (used in tests)
+func (t *Tracker) SetClock(c clock.Clock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.clock = c
+}
+
+// RecordSession counts a new MCP session for user
+func (t *Tracker) RecordSession(user string) {
+	t.record(user, func(b *Bucket) { b.Sessions++ })
+}
+
+// RecordToolCall counts a call of tool by user
+func (t *Tracker) RecordToolCall(user, tool string) {
+	t.record(user, func(b *Bucket) { b.ToolCalls[tool]++ })
+}
+
+// record applies update to user's current bucket; anonymous activity isn't tracked
+func (t *Tracker) record(user string, update func(*Bucket)) {
+	if user == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.clock.Now()
+	buckets, ok := t.users[user]
+	if !ok {
+		buckets = make(map[time.Time]*Bucket)
+		t.users[user] = buckets
+	}
+	start := now.Truncate(BucketSize)
+	bucket, ok := buckets[start]
+	if !ok {
+		bucket = &Bucket{Start: start, ToolCalls: make(map[string]int64)}
+		buckets[start] = bucket
+		t.pruneLocked(now)
+	}
+	update(bucket)
+}
+
+// pruneLocked drops buckets older than Retention, and users left without any
+func (t *Tracker) pruneLocked(now time.Time) {
+	cutoff := now.Add(-Retention)
+	for user, buckets := range t.users {
+		for start := range buckets {
+			if start.Before(cutoff) {
+				delete(buckets, start)
+			}
+		}
+		if len(buckets) == 0 {
+			delete(t.users, user)
+		}
+	}
+}
+
+// Timeline returns user's activity, oldest first, aggregated into buckets of size (a multiple of
+// BucketSize, e.g. 24h for daily totals)
+func (t *Tracker) Timeline(user string, size time.Duration) []Bucket {
+	if size < BucketSize {
+		size = BucketSize
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := t.clock.Now().Add(-Retention)
+	aggregated := make(map[time.Time]*Bucket)
+	for start, bucket := range t.users[user] {
+		if start.Before(cutoff) {
+			continue
+		}
+		key := start.Truncate(size)
+		total, ok := aggregated[key]
+		if !ok {
+			total = &Bucket{Start: key, ToolCalls: make(map[string]int64)}
+			aggregated[key] = total
+		}
+		total.Sessions += bucket.Sessions
+		for tool, calls := range bucket.ToolCalls {
+			total.ToolCalls[tool] += calls
+		}
+	}
+
+	timeline := make([]Bucket, 0, len(aggregated))
+	for _, bucket := range aggregated {
+		timeline = append(timeline, *bucket)
+	}
+	sort.Slice(timeline, func(i, j int) bool { return timeline[i].Start.Before(timeline[j].Start) })
+	return timeline
+}
+
+// Summary totals every user's activity, most recently active first
+func (t *Tracker) Summary() []UserSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := t.clock.Now().Add(-Retention)
+	summaries := make([]UserSummary, 0, len(t.users))
+	for user, buckets := range t.users {
+		summary := UserSummary{User: user}
+		for start, bucket := range buckets {
+			if start.Before(cutoff) {
+				continue
+			}
+			summary.Sessions += bucket.Sessions
+			for _, calls := range bucket.ToolCalls {
+				summary.ToolCalls += calls
+			}
+			if start.After(summary.LastActive) {
+				summary.LastActive = start
+			}
+		}
+		if !summary.LastActive.IsZero() {
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if !summaries[i].LastActive.Equal(summaries[j].LastActive) {
+			return summaries[i].LastActive.After(summaries[j].LastActive)
+		}
+		return summaries[i].User < summaries[j].User
+	})
+	return summaries
+}
+
+// ActiveUsers counts the users active within the last window, to the granularity of BucketSize
+func (t *Tracker) ActiveUsers(window time.Duration) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := t.clock.Now().Add(-window).Truncate(BucketSize)
+	active := 0
+	for _, buckets := range t.users {
+		for start := range buckets {
+			if !start.Before(cutoff) {
+				active++
+				break
+			}
+		}
+	}
+	return active
+}
+
+// User returns the GitHub login of the caller of req, or "" if it is unauthenticated
+func User(req mcp.Request) string {
+	extra := req.GetExtra()
+	if extra == nil || extra.TokenInfo == nil {
+		return ""
+	}
+	user, _ := extra.TokenInfo.Extra["subject"].(string)
+	return user
+}
+
+// Middleware records sessions (initialize requests) and tool calls in tracker
+func Middleware(tracker *Tracker) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method == "initialize" {
+				tracker.RecordSession(User(req))
+			} else if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil {
+				tracker.RecordToolCall(User(req), call.Params.Name)
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// StatusHandler serves activity as JSON: a summary of every user, or with ?user=login that user's
+// timeline, in hourly buckets or daily ones with &bucket=day
+type StatusHandler struct {
+	tracker *Tracker
+}
+
+// NewStatusHandler creates a new handler for the given tracker
+func NewStatusHandler(tracker *Tracker) *StatusHandler {
+	return &StatusHandler{tracker: tracker}
+}
+
+// ServeHTTP implements http.Handler
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var response any
+	if user := r.URL.Query().Get("user"); user == "" {
+		response = h.tracker.Summary()
+	} else {
+		size := BucketSize
+		switch bucket := r.URL.Query().Get("bucket"); bucket {
+		case "", "hour":
+		case "day":
+			size = 24 * time.Hour
+		default:
+			http.Error(w, "Unknown bucket "+bucket+"; use hour or day", http.StatusBadRequest)
+			return
+		}
+		response = h.tracker.Timeline(user, size)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode activity", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,349 @@
+// Package adminapi serves operator APIs: a read-only GraphQL API over the server's operational
+// state, so internal dashboards can fetch clients, usage, and health in one query instead of
+// stitching together the /admin REST endpoints, and REST endpoints that manage clients, tokens,
+// and sessions
+package adminapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	oauth "EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/sse"
+)
+
+// adminScope is required by every field except me
+const adminScope = "mcp:admin"
+
+// errAdminRequired is returned for admin fields queried without the mcp:admin scope
+var errAdminRequired = errors.New("this field requires the " + adminScope + " scope")
+
+// Sources are the parts of the server the API reads from; nil sources resolve to null
+type Sources struct {
+	Clients   oauth.ClientStorage
+	Verifier  *oauth.GitHubTokenVerifier
+	Heartbeat *sse.Heartbeat
+	Activity  *activity.Tracker
+	Breakers  *breaker.Registry
+}
+
+// toolCount is one entry of a bucket's per-tool call counts
+type toolCount struct {
+	Tool  string `json:"tool"`
+	Calls int64  `json:"calls"`
+}
+
+// NewSchema builds the GraphQL schema over sources
+// Fields other than me require the mcp:admin scope and resolve to an error without it, so a
+// dashboard query can mix public and admin fields and still get the parts it may see
+func NewSchema(sources Sources) (graphql.Schema, error) {
+	toolCountType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ToolCount",
+		Fields: graphql.Fields{
+			"tool":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"calls": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	bucketType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "ActivityBucket",
+		Description: "A user's activity during the period starting at start",
+		Fields: graphql.Fields{
+			"start":    &graphql.Field{Type: graphql.NewNonNull(graphql.DateTime)},
+			"sessions": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"toolCalls": &graphql.Field{
+				Type: graphql.NewList(toolCountType),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					bucket := p.Source.(activity.Bucket)
+					counts := make([]toolCount, 0, len(bucket.ToolCalls))
+					for tool, calls := range bucket.ToolCalls {
+						counts = append(counts, toolCount{Tool: tool, Calls: calls})
+					}
+					sort.Slice(counts, func(i, j int) bool { return counts[i].Tool < counts[j].Tool })
+					return counts, nil
+				},
+			},
+		},
+	})
+
+	userType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "User",
+		Description: "A GitHub user's activity over the last 7 days",
+		Fields: graphql.Fields{
+			"login":      &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: summaryField(func(s activity.UserSummary) any { return s.User })},
+			"sessions":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: summaryField(func(s activity.UserSummary) any { return s.Sessions })},
+			"toolCalls":  &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: summaryField(func(s activity.UserSummary) any { return s.ToolCalls })},
+			"lastActive": &graphql.Field{Type: graphql.DateTime, Resolve: summaryField(func(s activity.UserSummary) any { return nullTime(s.LastActive) })},
+			"timeline": &graphql.Field{
+				Type: graphql.NewList(bucketType),
+				Args: graphql.FieldConfigArgument{
+					"bucket": &graphql.ArgumentConfig{
+						Type:         graphql.String,
+						DefaultValue: "hour",
+						Description:  "hour or day",
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					if sources.Activity == nil {
+						return nil, nil
+					}
+					size := activity.BucketSize
+					switch p.Args["bucket"] {
+					case "hour":
+					case "day":
+						size = 24 * time.Hour
+					default:
+						return nil, errors.New("bucket must be hour or day")
+					}
+					return sources.Activity.Timeline(p.Source.(activity.UserSummary).User, size), nil
+				},
+			},
+		},
+	})
+
+	clientType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "Client",
+		Description: "A registered OAuth client",
+		Fields: graphql.Fields{
+			"clientId":       &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: clientField(func(c *oauth.OAuthClient) any { return c.ClientID })},
+			"clientName":     &graphql.Field{Type: graphql.String, Resolve: clientField(func(c *oauth.OAuthClient) any { return c.Metadata.ClientName })},
+			"redirectUris":   &graphql.Field{Type: graphql.NewList(graphql.String), Resolve: clientField(func(c *oauth.OAuthClient) any { return c.Metadata.RedirectURIs })},
+			"scope":          &graphql.Field{Type: graphql.String, Resolve: clientField(func(c *oauth.OAuthClient) any { return c.Metadata.Scope })},
+			"public":         &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: clientField(func(c *oauth.OAuthClient) any { return c.ClientSecret == "" })},
+			"createdAt":      &graphql.Field{Type: graphql.DateTime, Resolve: clientField(func(c *oauth.OAuthClient) any { return nullTime(c.CreatedAt) })},
+			"lastUsedAt":     &graphql.Field{Type: graphql.DateTime, Resolve: clientField(func(c *oauth.OAuthClient) any { return nullTimePtr(c.LastUsedAt) })},
+			"expiresAt":      &graphql.Field{Type: graphql.DateTime, Resolve: clientField(func(c *oauth.OAuthClient) any { return nullTimePtr(c.ExpiresAt) })},
+			"autoRegistered": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean), Resolve: clientField(func(c *oauth.OAuthClient) any { return c.AutoRegistered })},
+		},
+	})
+
+	breakerType := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CircuitBreaker",
+		Description: "A circuit breaker protecting an upstream API",
+		Fields: graphql.Fields{
+			"name":                &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+			"state":               &graphql.Field{Type: graphql.NewNonNull(graphql.String), Resolve: breakerField(func(s breaker.Status) any { return string(s.State) })},
+			"consecutiveFailures": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: breakerField(func(s breaker.Status) any { return s.Failures })},
+			"retryAt":             &graphql.Field{Type: graphql.DateTime, Resolve: breakerField(func(s breaker.Status) any { return nullTime(s.RetryAt) })},
+			"lastError":           &graphql.Field{Type: graphql.String, Resolve: breakerField(func(s breaker.Status) any { return s.LastError })},
+		},
+	})
+
+	tokenVerificationType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "TokenVerification",
+		Fields: graphql.Fields{
+			"verifications":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: verifierField(func(s oauth.VerifierStats) any { return s.Verifications })},
+			"duplicatesAvoided": &graphql.Field{Type: graphql.NewNonNull(graphql.Int), Resolve: verifierField(func(s oauth.VerifierStats) any { return s.DuplicatesAvoided })},
+		},
+	})
+
+	sseStreamsType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SSEStreams",
+		Fields: graphql.Fields{
+			"active":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"opened":    &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"completed": &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"dropped":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"pings":     &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+			"resumed":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		},
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"me": &graphql.Field{
+				Type:        userType,
+				Description: "The caller's own activity",
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					info := auth.TokenInfoFromContext(p.Context)
+					if info == nil {
+						return nil, errors.New("authentication required")
+					}
+					login, _ := info.Extra["subject"].(string)
+					return userSummary(sources.Activity, login), nil
+				},
+			},
+			"user": &graphql.Field{
+				Type:        userType,
+				Description: "A user's activity",
+				Args: graphql.FieldConfigArgument{
+					"login": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+				},
+				Resolve: adminOnly(func(p graphql.ResolveParams) (any, error) {
+					return userSummary(sources.Activity, p.Args["login"].(string)), nil
+				}),
+			},
+			"usage": &graphql.Field{
+				Type:        graphql.NewList(userType),
+				Description: "Every active user, most recently active first",
+				Resolve: adminOnly(func(p graphql.ResolveParams) (any, error) {
+					if sources.Activity == nil {
+						return nil, nil
+					}
+					return sources.Activity.Summary(), nil
+				}),
+			},
+			"clients": &graphql.Field{
+				Type:        graphql.NewList(clientType),
+				Description: "Registered OAuth clients",
+				Resolve: adminOnly(func(p graphql.ResolveParams) (any, error) {
+					if sources.Clients == nil {
+						return nil, nil
+					}
+					return sources.Clients.ListClients()
+				}),
+			},
+			"circuitBreakers": &graphql.Field{
+				Type: graphql.NewList(breakerType),
+				Resolve: adminOnly(func(p graphql.ResolveParams) (any, error) {
+					if sources.Breakers == nil {
+						return nil, nil
+					}
+					return sources.Breakers.Statuses(), nil
+				}),
+			},
+			"tokenVerification": &graphql.Field{
+				Type: tokenVerificationType,
+				Resolve: adminOnly(func(p graphql.ResolveParams) (any, error) {
+					if sources.Verifier == nil {
+						return nil, nil
+					}
+					return sources.Verifier.Stats(), nil
+				}),
+			},
+			"sseStreams": &graphql.Field{
+				Type: sseStreamsType,
+				Resolve: adminOnly(func(p graphql.ResolveParams) (any, error) {
+					if sources.Heartbeat == nil {
+						return nil, nil
+					}
+					return sources.Heartbeat.Stats(), nil
+				}),
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// adminOnly wraps resolve so it only runs for callers with the mcp:admin scope
+func adminOnly(resolve graphql.FieldResolveFn) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		info := auth.TokenInfoFromContext(p.Context)
+		if info == nil || !slices.Contains(info.Scopes, adminScope) {
+			return nil, errAdminRequired
+		}
+		return resolve(p)
+	}
+}
+
+// userSummary returns login's activity summary, which is empty if the user hasn't been active
+func userSummary(tracker *activity.Tracker, login string) activity.UserSummary {
+	if tracker != nil {
+		for _, summary := range tracker.Summary() {
+			if summary.User == login {
+				return summary
+			}
+		}
+	}
+	return activity.UserSummary{User: login}
+}
+
+// nullTime resolves zero times to null
+func nullTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t
+}
+
+func nullTimePtr(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+func summaryField(get func(activity.UserSummary) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(activity.UserSummary)), nil }
+}
+
+func clientField(get func(*oauth.OAuthClient) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(*oauth.OAuthClient)), nil }
+}
+
+func breakerField(get func(breaker.Status) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(breaker.Status)), nil }
+}
+
+func verifierField(get func(oauth.VerifierStats) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) { return get(p.Source.(oauth.VerifierStats)), nil }
+}
+
+// Handler executes GraphQL queries sent as JSON POST bodies or in the query string of a GET
+type Handler struct {
+	schema graphql.Schema
+}
+
+// NewHandler creates a new handler for the given schema
+func NewHandler(schema graphql.Schema) *Handler {
+	return &Handler{schema: schema}
+}
+
+// graphQLRequest is the standard GraphQL-over-HTTP request body
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req graphQLRequest
+	switch r.Method {
+	case http.MethodGet:
+		req.Query = r.URL.Query().Get("query")
+		req.OperationName = r.URL.Query().Get("operationName")
+		if variables := r.URL.Query().Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				http.Error(w, "Invalid variables", http.StatusBadRequest)
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if req.Query == "" {
+		http.Error(w, "query is required", http.StatusBadRequest)
+		return
+	}
+
+	result := graphql.Do(graphql.Params{
+		Schema:         h.schema,
+		RequestString:  req.Query,
+		OperationName:  req.OperationName,
+		VariableValues: req.Variables,
+		Context:        r.Context(),
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
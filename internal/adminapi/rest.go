@@ -0,0 +1,293 @@
+package adminapi
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/audit"
+	oauth "EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// staleClientAge is how long a client goes without being issued a token before it's listed as stale
+const staleClientAge = 30 * 24 * time.Hour
+
+// ClientSummary is a registered client as listed to operators, without its secret
+type ClientSummary struct {
+	ClientID       string     `json:"client_id"`
+	ClientName     string     `json:"client_name,omitempty"`
+	RedirectURIs   []string   `json:"redirect_uris"`
+	Public         bool       `json:"public"`
+	AutoRegistered bool       `json:"auto_registered"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	Stale          bool       `json:"stale"`
+}
+
+// ClientsHandler lists registered clients on GET, only stale ones with ?stale=true, and deletes
+// one on DELETE ?client_id=
+type ClientsHandler struct {
+	clients oauth.ClientStorage
+}
+
+// NewClientsHandler creates a new handler for the given client storage
+func NewClientsHandler(clients oauth.ClientStorage) *ClientsHandler {
+	return &ClientsHandler{clients: clients}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ClientsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		clients, err := h.clients.ListClients()
+		if err != nil {
+			http.Error(w, "Failed to list clients", http.StatusInternalServerError)
+			return
+		}
+		onlyStale := r.URL.Query().Get("stale") == "true"
+		now := time.Now()
+		summaries := make([]ClientSummary, 0, len(clients))
+		for _, client := range clients {
+			lastActive := client.CreatedAt
+			if client.LastUsedAt != nil {
+				lastActive = *client.LastUsedAt
+			}
+			stale := now.Sub(lastActive) > staleClientAge
+			if onlyStale && !stale {
+				continue
+			}
+			summaries = append(summaries, ClientSummary{
+				ClientID:       client.ClientID,
+				ClientName:     client.Metadata.ClientName,
+				RedirectURIs:   client.Metadata.RedirectURIs,
+				Public:         client.ClientSecret == "",
+				AutoRegistered: client.AutoRegistered,
+				CreatedAt:      client.CreatedAt,
+				LastUsedAt:     client.LastUsedAt,
+				ExpiresAt:      client.ExpiresAt,
+				Stale:          stale,
+			})
+		}
+		sort.Slice(summaries, func(i, j int) bool { return summaries[i].ClientID < summaries[j].ClientID })
+		writeJSON(w, summaries)
+
+	case http.MethodDelete:
+		clientID := r.URL.Query().Get("client_id")
+		if clientID == "" {
+			http.Error(w, "client_id is required", http.StatusBadRequest)
+			return
+		}
+		if _, err := h.clients.GetClient(clientID); err != nil {
+			http.Error(w, "Client not found", http.StatusNotFound)
+			return
+		}
+		if err := h.clients.DeleteClient(clientID); err != nil {
+			http.Error(w, "Failed to delete client", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("[ADMIN] Deleted client %s", clientID)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// TokenSummary is a stored access token as listed to operators, without the token itself or
+// the GitHub token behind it
+type TokenSummary struct {
+	ID        string    `json:"id"`
+	ClientID  string    `json:"client_id"`
+	User      string    `json:"user,omitempty"`
+	Scope     string    `json:"scope"`
+	GrantType string    `json:"grant_type,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TokensHandler lists stored access tokens on GET and revokes one on POST with a JSON body of
+// {"id": string}, an ID from the list, or {"token": string}
+// JWT access tokens aren't stored, so they aren't listed and can only be revoked by token
+type TokensHandler struct {
+	tokens    oauth.TokenStorage
+	jwtIssuer *oauth.JWTIssuer
+}
+
+// NewTokensHandler creates a new handler for the given token storage
+func NewTokensHandler(tokens oauth.TokenStorage) *TokensHandler {
+	return &TokensHandler{tokens: tokens}
+}
+
+// SetJWTIssuer lets the handler revoke JWT access tokens signed by issuer
+func (h *TokensHandler) SetJWTIssuer(issuer *oauth.JWTIssuer) {
+	h.jwtIssuer = issuer
+}
+
+// ServeHTTP implements http.Handler
+func (h *TokensHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		tokens, err := h.tokens.ListAccessTokens()
+		if err != nil {
+			http.Error(w, "Failed to list tokens", http.StatusInternalServerError)
+			return
+		}
+		summaries := make([]TokenSummary, 0, len(tokens))
+		for _, token := range tokens {
+			summaries = append(summaries, TokenSummary{
+				ID:        token.ID,
+				ClientID:  token.ClientID,
+				User:      token.Subject,
+				Scope:     token.Scope,
+				GrantType: token.GrantType,
+				CreatedAt: token.CreatedAt,
+				ExpiresAt: token.ExpiresAt,
+			})
+		}
+		sort.Slice(summaries, func(i, j int) bool { return summaries[i].CreatedAt.Before(summaries[j].CreatedAt) })
+		writeJSON(w, summaries)
+
+	case http.MethodPost:
+		h.revoke(w, r)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// revoke revokes the token named in the request body
+func (h *TokensHandler) revoke(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID    string `json:"id"`
+		Token string `json:"token"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || (req.ID == "") == (req.Token == "") {
+		http.Error(w, "Invalid request body: give either id or token", http.StatusBadRequest)
+		return
+	}
+	if oauth.LooksLikeJWT(req.Token) {
+		h.revokeJWT(w, r, req.Token)
+		return
+	}
+
+	id := req.ID
+	if id == "" {
+		id = oauth.AccessTokenID(req.Token)
+	}
+	info, err := h.tokens.RevokeAccessTokenByID(id)
+	if errors.Is(err, oauth.ErrAccessTokenNotFound) {
+		http.Error(w, "Token not found or expired", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to revoke token", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[ADMIN] Revoked access token issued to client %s", info.ClientID)
+	audit.Default.Record(r, audit.Event{Type: audit.TokenRevoked, ClientID: info.ClientID, User: info.Subject, GrantType: info.GrantType})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// revokeJWT revokes a JWT access token, so verifiers refuse it until it expires
+func (h *TokensHandler) revokeJWT(w http.ResponseWriter, r *http.Request, token string) {
+	if h.jwtIssuer == nil {
+		http.Error(w, "This server doesn't issue JWT access tokens", http.StatusBadRequest)
+		return
+	}
+	claims, err := oauth.RevokeJWT(h.tokens, h.jwtIssuer, token)
+	if errors.Is(err, oauth.ErrAccessTokenNotFound) {
+		http.Error(w, "Token not found or expired", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Failed to revoke token", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[ADMIN] Revoked JWT access token %s issued to client %s", claims.ID, claims.ClientID)
+	audit.Default.Record(r, audit.Event{Type: audit.TokenRevoked, ClientID: claims.ClientID, User: claims.Subject})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SessionsHandler lists active MCP sessions on GET and disconnects one on DELETE ?id=
+type SessionsHandler struct {
+	servers  []*mcp.Server
+	bindings *oauth.SessionBindings
+}
+
+// NewSessionsHandler creates a new handler for the sessions of servers, such as the main server
+// and every persona, described by the bindings they share
+func NewSessionsHandler(bindings *oauth.SessionBindings, servers ...*mcp.Server) *SessionsHandler {
+	return &SessionsHandler{servers: servers, bindings: bindings}
+}
+
+// sessions yields the open sessions of every server
+func (h *SessionsHandler) sessions(yield func(*mcp.ServerSession) bool) {
+	for _, server := range h.servers {
+		for session := range server.Sessions() {
+			if !yield(session) {
+				return
+			}
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *SessionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		open := make(map[string]bool)
+		for session := range h.sessions {
+			open[session.ID()] = true
+		}
+		sessions := make([]oauth.BoundSession, 0, len(open))
+		for _, session := range h.bindings.Sessions() {
+			if open[session.ID] {
+				sessions = append(sessions, session)
+			}
+		}
+		writeJSON(w, sessions)
+
+	case http.MethodDelete:
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			http.Error(w, "id is required", http.StatusBadRequest)
+			return
+		}
+		var found *mcp.ServerSession
+		for session := range h.sessions {
+			if session.ID() == id {
+				found = session
+				break
+			}
+		}
+		if found == nil {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		// Unbinding first makes further requests with this session ID fail even if the
+		// transport hasn't noticed the session closing yet
+		h.bindings.Unbind(id)
+		if err := found.Close(); err != nil {
+			log.Printf("Warning: Failed to close session %s: %v", id, err)
+		}
+		log.Printf("[ADMIN] Disconnected session %s", id)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// writeJSON encodes response as the JSON body of w
+func writeJSON(w http.ResponseWriter, response any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,206 @@
+// Package audit records token events (issuance, failed validation, and revocation) with who,
+// through which client, and from where, in an append-only store operators can query
+package audit
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/ratelimit"
+)
+
+// Event types
+const (
+	TokenIssued      = "token_issued"
+	ValidationFailed = "validation_failed"
+	TokenRevoked     = "token_revoked"
+	PKCEFailed       = "pkce_failed"
+	UnknownClient    = "unknown_client"
+	AnomalyDetected  = "anomaly_detected"
+)
+
+// EventTypes lists every event type
+var EventTypes = []string{TokenIssued, ValidationFailed, TokenRevoked, PKCEFailed, UnknownClient, AnomalyDetected}
+
+// Query limits for the admin endpoint
+const (
+	defaultQueryLimit = 100
+	maxQueryLimit     = 1000
+)
+
+// Event is a recorded token event
+type Event struct {
+	Time      time.Time `json:"time"`
+	Type      string    `json:"type"`
+	ClientID  string    `json:"client_id,omitempty"`
+	User      string    `json:"user,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	GrantType string    `json:"grant_type,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// Filter selects events; empty fields match everything
+type Filter struct {
+	Type     string
+	User     string
+	ClientID string
+	Since    time.Time
+	// Limit caps the number of events returned, newest first (0 returns all)
+	Limit int
+}
+
+// matches reports whether e is selected by f
+func (f Filter) matches(e Event) bool {
+	return (f.Type == "" || e.Type == f.Type) &&
+		(f.User == "" || e.User == f.User) &&
+		(f.ClientID == "" || e.ClientID == f.ClientID) &&
+		!e.Time.Before(f.Since)
+}
+
+// Store keeps events; events are only ever appended
+type Store interface {
+	Append(e Event) error
+	// Query returns the events selected by f, newest first
+	Query(f Filter) ([]Event, error)
+}
+
+// Hook is notified of every recorded event; it must not block
+type Hook interface {
+	Notify(e Event)
+}
+
+// Log timestamps events, attributes them to the caller's IP, appends them to its store, and
+// notifies its hooks
+type Log struct {
+	mu                sync.RWMutex
+	store             Store
+	hooks             []Hook
+	clock             clock.Clock
+	trustForwardedFor bool
+}
+
+// Default is the log written by the token endpoint, token verifier, and admin API
+var Default = New(NewMemoryStore(defaultMemoryCapacity))
+
+// New creates a log appending to store
+func New(store Store) *Log {
+	return &Log{store: store, clock: clock.System{}}
+}
+
+// SetStore replaces the store events are appended to
+func (l *Log) SetStore(store Store) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.store = store
+}
+
+// AddHook notifies hook of events recorded from now on
+func (l *Log) AddHook(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hooks = append(l.hooks, hook)
+}
+
+// SetClock replaces the clock used to timestamp events (used in tests)
+func (l *Log) SetClock(c clock.Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = c
+}
+
+// SetTrustForwardedFor takes the caller's IP from X-Forwarded-For, as the rate limiter does
+func (l *Log) SetTrustForwardedFor(trust bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.trustForwardedFor = trust
+}
+
+// Record appends e, made by the caller of r (which may be nil)
+// Failing to audit doesn't fail the request, but is logged
+func (l *Log) Record(r *http.Request, e Event) {
+	l.mu.RLock()
+	store, hooks, now, trust := l.store, l.hooks, l.clock.Now(), l.trustForwardedFor
+	l.mu.RUnlock()
+
+	e.Time = now.UTC()
+	if r != nil {
+		e.IP = ratelimit.ClientIP(r, trust)
+	}
+	if err := store.Append(e); err != nil {
+		log.Printf("[AUDIT] Failed to record %s for client %s: %v", e.Type, e.ClientID, err)
+	}
+	for _, hook := range hooks {
+		hook.Notify(e)
+	}
+}
+
+// Query returns the events selected by f, newest first
+func (l *Log) Query(f Filter) ([]Event, error) {
+	l.mu.RLock()
+	store := l.store
+	l.mu.RUnlock()
+	return store.Query(f)
+}
+
+// Handler serves the log on GET, filtered by the type, user, client_id, since (RFC 3339), and
+// limit query parameters
+type Handler struct {
+	log *Log
+}
+
+// NewHandler creates a handler querying l
+func NewHandler(l *Log) *Handler {
+	return &Handler{log: l}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := Filter{
+		Type:     query.Get("type"),
+		User:     query.Get("user"),
+		ClientID: query.Get("client_id"),
+		Limit:    defaultQueryLimit,
+	}
+	if since := query.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			http.Error(w, "Invalid since; use an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		filter.Since = t
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 || n > maxQueryLimit {
+			http.Error(w, "Invalid limit; use 1 to "+strconv.Itoa(maxQueryLimit), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = n
+	}
+
+	events, err := h.log.Query(filter)
+	if err != nil {
+		log.Printf("[AUDIT] Failed to query audit log: %v", err)
+		http.Error(w, "Failed to query audit log", http.StatusInternalServerError)
+		return
+	}
+	if events == nil {
+		events = []Event{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"events": events}); err != nil {
+		http.Error(w, "Failed to encode audit log", http.StatusInternalServerError)
+	}
+}
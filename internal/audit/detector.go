@@ -0,0 +1,148 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+// Thresholds are how many failures within Window raise an anomaly; 0 disables a rule
+type Thresholds struct {
+	Window time.Duration
+	// InvalidTokens counts failed token validations across all callers
+	InvalidTokens int
+	// PKCEFailures counts failed PKCE verifications across all callers
+	PKCEFailures int
+	// UnknownClientsPerIP counts token requests naming unknown clients from one IP
+	UnknownClientsPerIP int
+}
+
+// detectorRule raises an anomaly when events of one type pass a threshold, per IP or overall
+type detectorRule struct {
+	anomaly   string
+	eventType string
+	threshold int
+	perIP     bool
+}
+
+// Detector watches failure events for patterns of abuse, such as token guessing or client
+// probing, and records an AnomalyDetected event (with an [ALERT] log line) when a rule's
+// threshold is crossed
+// An anomaly is raised once per window for each rule and IP, however long the attack lasts
+type Detector struct {
+	mu     sync.Mutex
+	log    *Log
+	window time.Duration
+	rules  []detectorRule
+	// failures holds the recent failure times for each rule and key
+	failures map[string][]time.Time
+	// raised holds when each rule and key last raised an anomaly
+	raised    map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewDetector creates a detector recording anomalies in l; add it to l with AddHook
+func NewDetector(l *Log, thresholds Thresholds) *Detector {
+	d := &Detector{
+		log:      l,
+		window:   thresholds.Window,
+		failures: make(map[string][]time.Time),
+		raised:   make(map[string]time.Time),
+	}
+	for _, rule := range []detectorRule{
+		{anomaly: "invalid_token_spike", eventType: ValidationFailed, threshold: thresholds.InvalidTokens},
+		{anomaly: "pkce_failure_spike", eventType: PKCEFailed, threshold: thresholds.PKCEFailures},
+		{anomaly: "unknown_client_probe", eventType: UnknownClient, threshold: thresholds.UnknownClientsPerIP, perIP: true},
+	} {
+		if rule.threshold > 0 {
+			d.rules = append(d.rules, rule)
+		}
+	}
+	return d
+}
+
+// Notify implements Hook
+func (d *Detector) Notify(e Event) {
+	var anomalies []Event
+	d.mu.Lock()
+	d.sweep(e.Time)
+	for _, rule := range d.rules {
+		if e.Type != rule.eventType {
+			continue
+		}
+		key := rule.anomaly
+		if rule.perIP {
+			key += " " + e.IP
+		}
+
+		recent := d.failures[key][:0]
+		for _, t := range d.failures[key] {
+			if e.Time.Sub(t) < d.window {
+				recent = append(recent, t)
+			}
+		}
+		recent = append(recent, e.Time)
+		d.failures[key] = recent
+
+		if len(recent) < rule.threshold {
+			continue
+		}
+		if last, ok := d.raised[key]; ok && e.Time.Sub(last) < d.window {
+			continue
+		}
+		d.raised[key] = e.Time
+
+		anomaly := Event{
+			Type:   AnomalyDetected,
+			Detail: fmt.Sprintf("%s: %d %s events in %v", rule.anomaly, len(recent), rule.eventType, d.window),
+		}
+		if rule.perIP {
+			anomaly.Detail = fmt.Sprintf("%s: %d %s events from %s in %v", rule.anomaly, len(recent), rule.eventType, e.IP, d.window)
+		}
+		anomalies = append(anomalies, anomaly)
+		alertAnomaly(rule, e, len(recent), d.window)
+	}
+	d.mu.Unlock()
+
+	// Recording notifies this detector again, so it must happen without the lock
+	for _, anomaly := range anomalies {
+		d.log.Record(nil, anomaly)
+	}
+}
+
+// sweep forgets failures and anomalies older than the window, at most once per window
+func (d *Detector) sweep(now time.Time) {
+	if now.Sub(d.lastSweep) < d.window {
+		return
+	}
+	d.lastSweep = now
+	for key, times := range d.failures {
+		if len(times) == 0 || now.Sub(times[len(times)-1]) >= d.window {
+			delete(d.failures, key)
+		}
+	}
+	for key, t := range d.raised {
+		if now.Sub(t) >= d.window {
+			delete(d.raised, key)
+		}
+	}
+}
+
+// alertAnomaly emits an [ALERT] event that log-based metrics and alarms can match
+func alertAnomaly(rule detectorRule, e Event, count int, window time.Duration) {
+	fields := map[string]any{
+		"event":          rule.anomaly,
+		"failure_type":   rule.eventType,
+		"count":          count,
+		"window_seconds": int(window.Seconds()),
+	}
+	if rule.perIP {
+		fields["ip"] = e.IP
+	}
+	event, _ := json.Marshal(instance.Current().Tag(fields))
+	log.Printf("[ALERT] %s", event)
+}
@@ -0,0 +1,158 @@
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/logfile"
+)
+
+// defaultMemoryCapacity is how many events the default store keeps when no file is configured
+const defaultMemoryCapacity = 10000
+
+// MemoryStore keeps the most recent events in memory, dropping the oldest once full
+type MemoryStore struct {
+	mu       sync.Mutex
+	events   []Event
+	next     int
+	capacity int
+}
+
+// NewMemoryStore creates a store keeping up to capacity events
+func NewMemoryStore(capacity int) *MemoryStore {
+	return &MemoryStore{capacity: capacity}
+}
+
+// Append implements Store
+func (s *MemoryStore) Append(e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.events) < s.capacity {
+		s.events = append(s.events, e)
+		return nil
+	}
+	s.events[s.next] = e
+	s.next = (s.next + 1) % s.capacity
+	return nil
+}
+
+// Query implements Store
+func (s *MemoryStore) Query(f Filter) ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []Event
+	for i := range s.events {
+		// Walk from the newest event back
+		e := s.events[(s.next-1-i+2*len(s.events))%len(s.events)]
+		if !f.matches(e) {
+			continue
+		}
+		events = append(events, e)
+		if f.Limit > 0 && len(events) == f.Limit {
+			break
+		}
+	}
+	return events, nil
+}
+
+// FileStore appends events to a log file as JSON lines, and queries it along with its
+// rotated backups
+type FileStore struct {
+	mu   sync.Mutex
+	file *logfile.File
+}
+
+// NewFileStore creates a store appending to file
+func NewFileStore(file *logfile.File) *FileStore {
+	return &FileStore{file: file}
+}
+
+// Append implements Store
+func (s *FileStore) Append(e Event) error {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.file.Write(append(line, '\n'))
+	return err
+}
+
+// Query implements Store
+// It reads every retained file, so it is meant for occasional investigation
+func (s *FileStore) Query(f Filter) ([]Event, error) {
+	backups, err := s.file.Backups()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log backups: %w", err)
+	}
+
+	var events []Event
+	for _, path := range append(backups, s.file.Path()) {
+		// A backup is briefly listed twice while it is being compressed
+		if slices.Contains(backups, path+".gz") {
+			continue
+		}
+		matched, err := readEvents(path, f)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, matched...)
+	}
+
+	// Files hold events oldest first
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	if f.Limit > 0 && len(events) > f.Limit {
+		events = events[:f.Limit]
+	}
+	return events, nil
+}
+
+// readEvents returns the events in the file at path selected by f, skipping lines it can't parse
+// A file that was pruned or compressed since it was listed has nothing to read
+func readEvents(path string, f Filter) ([]Event, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var reader io.Reader = file
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read compressed audit log %s: %w", path, err)
+		}
+		defer func() { _ = gz.Close() }()
+		reader = gz
+	}
+
+	var events []Event
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || !f.matches(e) {
+			continue
+		}
+		events = append(events, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log %s: %w", path, err)
+	}
+	return events, nil
+}
@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Webhook delivery headers
+const (
+	// WebhookSignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the timestamp, a
+	// period, and the body, keyed with the webhook secret
+	WebhookSignatureHeader = "X-Webhook-Signature"
+
+	// WebhookTimestampHeader carries the Unix time the delivery was signed, so receivers can
+	// reject replays
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+
+	// WebhookIDHeader identifies an event; retries of the same event share it
+	WebhookIDHeader = "X-Webhook-ID"
+)
+
+// Webhook delivery limits
+const (
+	webhookQueueSize = 1000
+	webhookAttempts  = 3
+	webhookTimeout   = 10 * time.Second
+)
+
+// defaultWebhookTypes are the events sent when AUDIT_WEBHOOK_EVENTS is unset; individual failures
+// are left out since anyone can cause them, but anomalies they add up to are sent
+var defaultWebhookTypes = []string{TokenIssued, TokenRevoked, AnomalyDetected}
+
+// Webhook posts events of the selected types to a URL as signed JSON, from a queue so
+// recording an event never waits on the receiver
+// Events are dropped, and logged, when the queue is full or every attempt fails
+type Webhook struct {
+	url    string
+	secret []byte
+	types  []string
+	client *http.Client
+	queue  chan webhookDelivery
+	// retryDelay is the wait before the first retry, doubling after each attempt
+	retryDelay time.Duration
+}
+
+// webhookDelivery is a queued event with the ID its attempts share
+type webhookDelivery struct {
+	id    string
+	event Event
+}
+
+// NewWebhook creates a webhook posting events of types to url, signed with secret, and starts
+// delivering
+func NewWebhook(url string, secret []byte, types []string) *Webhook {
+	w := &Webhook{
+		url:        url,
+		secret:     secret,
+		types:      types,
+		client:     &http.Client{Timeout: webhookTimeout},
+		queue:      make(chan webhookDelivery, webhookQueueSize),
+		retryDelay: time.Second,
+	}
+	go w.run()
+	return w
+}
+
+// Notify implements Hook
+func (w *Webhook) Notify(e Event) {
+	if !slices.Contains(w.types, e.Type) {
+		return
+	}
+
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	select {
+	case w.queue <- webhookDelivery{id: hex.EncodeToString(id), event: e}:
+	default:
+		log.Printf("[AUDIT] Webhook queue for %s is full, dropping %s for client %s", w.url, e.Type, e.ClientID)
+	}
+}
+
+// run delivers queued events for the life of the process
+func (w *Webhook) run() {
+	for delivery := range w.queue {
+		delay := w.retryDelay
+		for attempt := 1; ; attempt++ {
+			err := w.deliver(delivery)
+			if err == nil {
+				break
+			}
+			if attempt == webhookAttempts {
+				log.Printf("[AUDIT] Failed to deliver %s to webhook %s: %v", delivery.event.Type, w.url, err)
+				break
+			}
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+}
+
+// deliver posts one event, signed at the time of the attempt
+func (w *Webhook) deliver(delivery webhookDelivery) error {
+	body, err := json.Marshal(delivery.event)
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, delivery.id)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhook(w.secret, timestamp, body))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, message)
+	}
+	return nil
+}
+
+// SignWebhook returns the hex HMAC-SHA256 of timestamp, a period, and body keyed with secret,
+// as sent in WebhookSignatureHeader
+func SignWebhook(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhooksFromEnv creates webhooks for each URL in AUDIT_WEBHOOK_URLS (comma-separated), signed
+// with AUDIT_WEBHOOK_SECRET and sending the event types in AUDIT_WEBHOOK_EVENTS
+// It returns nil when AUDIT_WEBHOOK_URLS is unset
+func WebhooksFromEnv() ([]*Webhook, error) {
+	urls := os.Getenv("AUDIT_WEBHOOK_URLS")
+	if urls == "" {
+		return nil, nil
+	}
+
+	secret := os.Getenv("AUDIT_WEBHOOK_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("AUDIT_WEBHOOK_SECRET is required to sign webhooks")
+	}
+
+	types := defaultWebhookTypes
+	if events := os.Getenv("AUDIT_WEBHOOK_EVENTS"); events != "" {
+		types = nil
+		for _, t := range strings.Split(events, ",") {
+			t = strings.TrimSpace(t)
+			if !slices.Contains(EventTypes, t) {
+				return nil, fmt.Errorf("invalid AUDIT_WEBHOOK_EVENTS entry %q: use one of %s", t, strings.Join(EventTypes, ", "))
+			}
+			types = append(types, t)
+		}
+	}
+
+	var webhooks []*Webhook
+	for _, raw := range strings.Split(urls, ",") {
+		raw = strings.TrimSpace(raw)
+		parsed, err := url.Parse(raw)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return nil, fmt.Errorf("invalid AUDIT_WEBHOOK_URLS entry %q", raw)
+		}
+		webhooks = append(webhooks, NewWebhook(raw, []byte(secret), types))
+	}
+	return webhooks, nil
+}
@@ -12,7 +12,10 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
 )
 
 // AuthorizationHandler handles OAuth 2.1 authorization requests
@@ -20,12 +23,36 @@ import (
 type AuthorizationHandler struct {
 	config        *Config
 	clientStorage ClientStorage
-	stateStore    *StateStore // Store for OAuth state and PKCE parameters
+	stateStore    StateStorage // Store for OAuth state and PKCE parameters
+	// requireConsent shows users the client, redirect URI, and scopes before sending them to GitHub
+	requireConsent bool
+}
+
+// StateStorage stores the state of authorization flows between the authorize and callback requests
+// Implementations must stop returning a state once it is older than the configured lifetime
+type StateStorage interface {
+	// Store saves an auth state
+	Store(state string, authState *AuthState) error
+
+	// Get retrieves an auth state
+	Get(state string) (*AuthState, bool)
+
+	// Delete removes an auth state
+	Delete(state string) error
+
+	// Consume retrieves and removes an auth state in one step, so only one caller can redeem it
+	Consume(state string) (*AuthState, bool)
 }
 
 // StateStore stores OAuth state, PKCE parameters, and client info during the flow
+// It keeps states in memory, so the callback must reach the instance that started the flow
+// It is safe for concurrent use; expired states are swept in the background
 type StateStore struct {
-	states map[string]*AuthState
+	mu      sync.Mutex
+	states  map[string]*AuthState
+	ttl     time.Duration
+	clock   clock.Clock
+	sweeper *sweeper
 }
 
 // AuthState holds the state for an ongoing authorization flow
@@ -37,55 +64,147 @@ type AuthState struct {
 	CodeChallenge       string
 	CodeChallengeMethod string
 	Resource            string
-	CreatedAt           time.Time
+	DeviceCode          string `json:",omitempty"` // Set when the flow approves a device instead of redirecting
+	// ConsentApproved is set when the user approved the flow on the consent page, and
+	// ConsentLogin when a consent remembered for that GitHub user skipped the page
+	ConsentApproved bool   `json:",omitempty"`
+	ConsentLogin    string `json:",omitempty"`
+	// Set instead of the flow fields for a one-time secret waiting to be read by Subject
+	Secret    string `json:",omitempty"`
+	Subject   string `json:",omitempty"`
+	CreatedAt time.Time
 }
 
-// NewStateStore creates a new state store
-func NewStateStore() *StateStore {
-	return &StateStore{
+// defaultAuthStateTTL is how long states live when no lifetime is configured
+const defaultAuthStateTTL = 10 * time.Minute
+
+// NewStateStore creates a new state store whose states expire after ttl (10 minutes if zero)
+func NewStateStore(ttl time.Duration) *StateStore {
+	if ttl <= 0 {
+		ttl = defaultAuthStateTTL
+	}
+	s := &StateStore{
 		states: make(map[string]*AuthState),
+		ttl:    ttl,
+		clock:  clock.System{},
 	}
+
+	// Start background cleanup goroutine
+	s.sweeper = startSweeper(s.Sweep)
+
+	return s
+}
+
+// Close stops the background cleanup; the store stays usable
+func (s *StateStore) Close() {
+	s.sweeper.close()
+}
+
+// SetClock replaces the clock used to expire old states
+func (s *StateStore) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
 }
 
 // Store saves an auth state
-func (s *StateStore) Store(state string, authState *AuthState) {
+func (s *StateStore) Store(state string, authState *AuthState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.states[state] = authState
-	// Clean up old states (older than 10 minutes)
-	cutoff := time.Now().Add(-10 * time.Minute)
-	for k, v := range s.states {
-		if v.CreatedAt.Before(cutoff) {
-			delete(s.states, k)
-		}
-	}
+	return nil
 }
 
 // Get retrieves an auth state
 func (s *StateStore) Get(state string) (*AuthState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	authState, ok := s.states[state]
-	return authState, ok
+	if !ok || authState.CreatedAt.Before(s.clock.Now().Add(-s.ttl)) {
+		return nil, false
+	}
+	return authState, true
 }
 
 // Delete removes an auth state
-func (s *StateStore) Delete(state string) {
+func (s *StateStore) Delete(state string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, state)
+	return nil
+}
+
+// Consume retrieves and removes an auth state under the lock
+func (s *StateStore) Consume(state string) (*AuthState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authState, ok := s.states[state]
 	delete(s.states, state)
+	if !ok || authState.CreatedAt.Before(s.clock.Now().Add(-s.ttl)) {
+		return nil, false
+	}
+	return authState, true
+}
+
+// Len returns how many states are held, including expired ones not yet swept
+func (s *StateStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.states)
+}
+
+// Sweep removes expired states
+func (s *StateStore) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := s.clock.Now().Add(-s.ttl)
+	for state, authState := range s.states {
+		if authState.CreatedAt.Before(cutoff) {
+			delete(s.states, state)
+		}
+	}
 }
 
-// NewAuthorizationHandler creates a new authorization handler
+// NewAuthorizationHandler creates a new authorization handler that keeps states in memory
+// The state store is swept for the life of the process; pass a StateStore to
+// NewAuthorizationHandlerWithStateStorage to be able to close it
 func NewAuthorizationHandler(config *Config, clientStorage ClientStorage) *AuthorizationHandler {
+	return NewAuthorizationHandlerWithStateStorage(config, clientStorage, NewStateStore(config.AuthStateTTL))
+}
+
+// NewAuthorizationHandlerWithStateStorage creates a new authorization handler that keeps states in stateStore
+func NewAuthorizationHandlerWithStateStorage(config *Config, clientStorage ClientStorage, stateStore StateStorage) *AuthorizationHandler {
 	return &AuthorizationHandler{
 		config:        config,
 		clientStorage: clientStorage,
-		stateStore:    NewStateStore(),
+		stateStore:    stateStore,
 	}
 }
 
 // GetStateStore returns the state store (needed by callback handler)
-func (h *AuthorizationHandler) GetStateStore() *StateStore {
+func (h *AuthorizationHandler) GetStateStore() StateStorage {
 	return h.stateStore
 }
 
+// EnableConsent asks users to approve the client, its redirect URI, and its scopes before they
+// are sent to GitHub
+func (h *AuthorizationHandler) EnableConsent() {
+	h.requireConsent = true
+}
+
 // ServeHTTP implements http.Handler
+// Clients send users here with a GET; the consent page posts the user's decision back here
 func (h *AuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.decideConsent(w, r)
+		return
+	}
+
 	// Parse query parameters
 	query := r.URL.Query()
 
@@ -119,7 +238,7 @@ func (h *AuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			// Auto-register the client if redirect_uri is in allowed list
 			if redirectURI != "" && h.config.IsRedirectURIAllowed(redirectURI) {
 				log.Printf("Auto-registering unknown client_id: %s with redirect_uri: %s", clientID, redirectURI)
-				
+
 				// Create a new client registration
 				newClient := &OAuthClient{
 					ClientID:     clientID,
@@ -132,15 +251,17 @@ func (h *AuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 						ClientName:              "Auto-registered MCP Client",
 						Scope:                   "mcp:tools mcp:resources read:user",
 					},
-					CreatedAt: time.Now(),
+					CreatedAt:      h.config.now(),
+					AutoRegistered: true,
 				}
-				
+				newClient.ExpiresAt = h.config.clientExpiry(newClient, newClient.CreatedAt)
+
 				if err := h.clientStorage.StoreClient(newClient); err != nil {
 					log.Printf("Failed to auto-register client: %v", err)
 					h.sendError(w, r, redirectURI, clientState, "server_error", "Failed to register client")
 					return
 				}
-				
+
 				client = newClient
 				log.Printf("Successfully auto-registered client: %s", clientID)
 			} else {
@@ -197,15 +318,6 @@ func (h *AuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	// Generate internal state for GitHub OAuth flow
-	internalState, err := generateRandomString(32)
-	if err != nil {
-		log.Printf("Failed to generate state: %v", err)
-		h.sendError(w, r, redirectURI, clientState, "server_error", "Failed to generate state")
-		return
-	}
-
-	// Store the authorization state
 	authState := &AuthState{
 		ClientID:            clientID,
 		RedirectURI:         redirectURI,
@@ -214,28 +326,60 @@ func (h *AuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		CodeChallenge:       codeChallenge,
 		CodeChallengeMethod: codeChallengeMethod,
 		Resource:            resource,
-		CreatedAt:           time.Now(),
+		CreatedAt:           h.config.now(),
 	}
-	h.stateStore.Store(internalState, authState)
 
-	// Build GitHub authorization URL
-	githubAuthURL, err := url.Parse(h.config.GitHubAuthURL)
-	if err != nil {
-		log.Printf("Invalid GitHub auth URL: %v", err)
-		h.sendError(w, r, redirectURI, clientState, "server_error", "Invalid authorization server configuration")
+	// Ask first, so a client can't use the user's existing GitHub session without them knowing,
+	// unless they already approved this client for these scopes
+	if h.requireConsent && !skipRememberedConsent(r, h.config, authState) {
+		if err := askConsent(w, h.clientStorage, h.stateStore, authState, "/oauth/authorize"); err != nil {
+			log.Printf("Failed to ask for consent: %v", err)
+			h.sendError(w, r, redirectURI, clientState, "server_error", "Failed to store consent request")
+		}
 		return
 	}
 
+	// Redirect user to GitHub for authentication
+	if err := redirectToGitHub(w, r, h.config, h.stateStore, authState); err != nil {
+		log.Printf("Failed to start GitHub authorization: %v", err)
+		h.sendError(w, r, redirectURI, clientState, "server_error", "Failed to start GitHub authorization")
+	}
+}
+
+// decideConsent handles the user's answer on the consent page, sending them on to GitHub if
+// they approved
+func (h *AuthorizationHandler) decideConsent(w http.ResponseWriter, r *http.Request) {
+	authState, approved, ok := redeemConsent(r, h.stateStore)
+	if !ok {
+		http.Error(w, "Invalid or expired consent request", http.StatusBadRequest)
+		return
+	}
+	if !approved {
+		h.sendError(w, r, authState.RedirectURI, authState.State, "access_denied", "The user denied the request")
+		return
+	}
+	if err := redirectToGitHub(w, r, h.config, h.stateStore, authState); err != nil {
+		log.Printf("Failed to start GitHub authorization: %v", err)
+		h.sendError(w, r, authState.RedirectURI, authState.State, "server_error", "Failed to start GitHub authorization")
+	}
+}
+
+// githubAuthorizeURL returns the GitHub authorization URL for a flow whose state is internalState
+func githubAuthorizeURL(config *Config, internalState string) (string, error) {
+	githubAuthURL, err := url.Parse(config.GitHubAuthURL)
+	if err != nil {
+		return "", err
+	}
+
 	// Set up GitHub OAuth parameters
 	githubQuery := githubAuthURL.Query()
-	githubQuery.Set("client_id", h.config.GitHubClientID)
-	githubQuery.Set("redirect_uri", h.config.ServerURL+"/oauth/callback")
-	githubQuery.Set("scope", "read:user")
+	githubClientID, _ := config.GitHubCredentials()
+	githubQuery.Set("client_id", githubClientID)
+	githubQuery.Set("redirect_uri", config.ServerURL+"/oauth/callback")
+	githubQuery.Set("scope", config.githubOAuthScopes())
 	githubQuery.Set("state", internalState)
 	githubAuthURL.RawQuery = githubQuery.Encode()
-
-	// Redirect user to GitHub for authentication
-	http.Redirect(w, r, githubAuthURL.String(), http.StatusFound)
+	return githubAuthURL.String(), nil
 }
 
 // sendError sends an OAuth error response
@@ -0,0 +1,546 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// CallbackHandler handles OAuth callbacks from GitHub
+type CallbackHandler struct {
+	config       *Config
+	stateStore   StateStorage
+	tokenStorage TokenStorage
+	budget       *GitHubBudget
+	httpClient   *http.Client
+}
+
+// TokenStorage stores authorization codes and access tokens
+type TokenStorage interface {
+	StoreAuthCode(code string, authInfo *AuthCodeInfo) error
+	GetAuthCode(code string) (*AuthCodeInfo, error)
+	DeleteAuthCode(code string) error
+	// ConsumeAuthCode retrieves and deletes an authorization code in one step, so concurrent
+	// requests can't both redeem it
+	ConsumeAuthCode(code string) (*AuthCodeInfo, error)
+	StoreAccessToken(token string, tokenInfo *AccessTokenInfo) error
+	GetAccessToken(token string) (*AccessTokenInfo, error)
+	// RevokeAccessToken deletes an access token before it expires; unknown tokens are not an error
+	RevokeAccessToken(token string) error
+	// ListAccessTokens returns the unexpired access tokens, named by ID rather than the token
+	ListAccessTokens() ([]*StoredAccessToken, error)
+	// RevokeAccessTokenByID deletes the access token with the given ID and returns what it held
+	RevokeAccessTokenByID(id string) (*AccessTokenInfo, error)
+}
+
+// ErrAccessTokenNotFound is returned by RevokeAccessTokenByID for unknown and expired tokens
+var ErrAccessTokenNotFound = errors.New("access token not found or expired")
+
+// StoredAccessToken is an access token as listed to operators
+type StoredAccessToken struct {
+	// ID is the token's hash (see AccessTokenID), which names it without being usable as a token
+	ID string
+	AccessTokenInfo
+}
+
+// AuthCodeInfo holds information about an authorization code
+type AuthCodeInfo struct {
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Resource            string
+	GitHubAccessToken   string // The token we got from GitHub
+	Subject             string // GitHub login, only looked up when issuing JWT access tokens
+	ExpiresAt           time.Time
+	CreatedAt           time.Time
+
+	// For device codes, which are pending until GitHubAccessToken is set: Denied is set when the
+	// user may not connect
+	Denied bool `json:",omitempty"`
+
+	// For device poll records, which are kept apart from their device codes so recording a poll
+	// never overwrites an approval: when the client last polled and how long it must now wait
+	LastPolledAt time.Time     `json:",omitzero"`
+	PollInterval time.Duration `json:",omitzero"`
+}
+
+// AccessTokenInfo holds information about an access token
+type AccessTokenInfo struct {
+	ClientID          string
+	Scope             string
+	Resource          string
+	GitHubAccessToken string
+	// GrantType is client_credentials for tokens issued to a client rather than a GitHub user,
+	// which have no GitHub token to validate; empty for authorization code tokens
+	GrantType string `json:",omitempty"`
+	// Subject is the GitHub login or ServiceClientSubject the token was issued to, when that was
+	// known at issuance
+	Subject   string `json:",omitempty"`
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// ServiceClientSubject is the subject of tokens issued to clientID through client_credentials
+// GitHub logins can't contain a colon, so it never collides with a user
+func ServiceClientSubject(clientID string) string {
+	return "client:" + clientID
+}
+
+// InMemoryTokenStorage is an in-memory implementation of TokenStorage
+// It is safe for concurrent use; expired codes and tokens are swept in the background
+// Access tokens are keyed by their ID, like in the other backends, so they can be listed and revoked
+// without holding the token
+type InMemoryTokenStorage struct {
+	mu           sync.Mutex
+	authCodes    map[string]*AuthCodeInfo
+	accessTokens map[string]*AccessTokenInfo
+	clock        clock.Clock
+	sweeper      *sweeper
+}
+
+// NewInMemoryTokenStorage creates a new in-memory token storage
+func NewInMemoryTokenStorage() *InMemoryTokenStorage {
+	s := &InMemoryTokenStorage{
+		authCodes:    make(map[string]*AuthCodeInfo),
+		accessTokens: make(map[string]*AccessTokenInfo),
+		clock:        clock.System{},
+	}
+
+	// Start background cleanup goroutine
+	s.sweeper = startSweeper(s.Sweep)
+
+	return s
+}
+
+// Close stops the background cleanup; the storage stays usable
+func (s *InMemoryTokenStorage) Close() {
+	s.sweeper.close()
+}
+
+// SetClock replaces the clock used to expire codes and tokens
+func (s *InMemoryTokenStorage) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+func (s *InMemoryTokenStorage) StoreAuthCode(code string, authInfo *AuthCodeInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.authCodes[code] = authInfo
+	return nil
+}
+
+func (s *InMemoryTokenStorage) GetAuthCode(code string) (*AuthCodeInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authInfo, ok := s.authCodes[code]
+	if !ok {
+		return nil, fmt.Errorf("authorization code not found")
+	}
+	if s.clock.Now().After(authInfo.ExpiresAt) {
+		delete(s.authCodes, code)
+		return nil, fmt.Errorf("authorization code expired")
+	}
+	return authInfo, nil
+}
+
+func (s *InMemoryTokenStorage) DeleteAuthCode(code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.authCodes, code)
+	return nil
+}
+
+func (s *InMemoryTokenStorage) ConsumeAuthCode(code string) (*AuthCodeInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authInfo, ok := s.authCodes[code]
+	if !ok {
+		return nil, fmt.Errorf("authorization code not found")
+	}
+	delete(s.authCodes, code)
+	if s.clock.Now().After(authInfo.ExpiresAt) {
+		return nil, fmt.Errorf("authorization code expired")
+	}
+	return authInfo, nil
+}
+
+func (s *InMemoryTokenStorage) StoreAccessToken(token string, tokenInfo *AccessTokenInfo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accessTokens[AccessTokenID(token)] = tokenInfo
+	return nil
+}
+
+func (s *InMemoryTokenStorage) GetAccessToken(token string) (*AccessTokenInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := AccessTokenID(token)
+	tokenInfo, ok := s.accessTokens[id]
+	if !ok {
+		return nil, fmt.Errorf("access token not found")
+	}
+	if s.clock.Now().After(tokenInfo.ExpiresAt) {
+		delete(s.accessTokens, id)
+		return nil, fmt.Errorf("access token expired")
+	}
+	return tokenInfo, nil
+}
+
+func (s *InMemoryTokenStorage) RevokeAccessToken(token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.accessTokens, AccessTokenID(token))
+	return nil
+}
+
+func (s *InMemoryTokenStorage) ListAccessTokens() ([]*StoredAccessToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	tokens := make([]*StoredAccessToken, 0, len(s.accessTokens))
+	for id, info := range s.accessTokens {
+		if now.After(info.ExpiresAt) {
+			continue
+		}
+		tokens = append(tokens, &StoredAccessToken{ID: id, AccessTokenInfo: *info})
+	}
+	return tokens, nil
+}
+
+func (s *InMemoryTokenStorage) RevokeAccessTokenByID(id string) (*AccessTokenInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokenInfo, ok := s.accessTokens[id]
+	delete(s.accessTokens, id)
+	if !ok || s.clock.Now().After(tokenInfo.ExpiresAt) {
+		return nil, ErrAccessTokenNotFound
+	}
+	return tokenInfo, nil
+}
+
+// Len returns how many codes and tokens are held, including expired ones not yet swept
+func (s *InMemoryTokenStorage) Len() (codes, tokens int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.authCodes), len(s.accessTokens)
+}
+
+// Sweep removes expired codes and tokens
+func (s *InMemoryTokenStorage) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for code, info := range s.authCodes {
+		if now.After(info.ExpiresAt) {
+			delete(s.authCodes, code)
+		}
+	}
+	for token, info := range s.accessTokens {
+		if now.After(info.ExpiresAt) {
+			delete(s.accessTokens, token)
+		}
+	}
+}
+
+// NewCallbackHandler creates a new callback handler
+func NewCallbackHandler(config *Config, stateStore StateStorage, tokenStorage TokenStorage) *CallbackHandler {
+	budget := NewGitHubBudget(config.GitHubAPIBudgetReserve)
+	if config.Clock != nil {
+		budget.SetClock(config.Clock)
+	}
+	return &CallbackHandler{
+		config:       config,
+		stateStore:   stateStore,
+		tokenStorage: tokenStorage,
+		budget:       budget,
+		httpClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// SetGitHubBudget makes the handler's GitHub calls spend budget, normally the verifier's, so
+// one budget sees every call made with a token
+func (h *CallbackHandler) SetGitHubBudget(budget *GitHubBudget) {
+	h.budget = budget
+}
+
+// ServeHTTP implements http.Handler
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Get the authorization code and state from the query parameters
+	githubCode := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+	errorParam := r.URL.Query().Get("error")
+	errorDescription := r.URL.Query().Get("error_description")
+
+	// Check for errors from GitHub
+	if errorParam != "" {
+		http.Error(w, fmt.Sprintf("Authorization error: %s - %s", errorParam, errorDescription), http.StatusBadRequest)
+		return
+	}
+
+	// Check if we have a code
+	if githubCode == "" {
+		http.Error(w, "No authorization code received", http.StatusBadRequest)
+		return
+	}
+
+	// Redeem the auth state; user codes are only redeemed through the device verification page,
+	// and pending consents through the consent page
+	// The GitHub code can't be exchanged twice, so the state is spent whatever happens next
+	if strings.HasPrefix(state, devicePrefix) || strings.HasPrefix(state, consentPrefix) {
+		http.Error(w, "Invalid or expired state parameter", http.StatusBadRequest)
+		return
+	}
+	authState, ok := h.stateStore.Consume(state)
+	if !ok {
+		http.Error(w, "Invalid or expired state parameter", http.StatusBadRequest)
+		return
+	}
+
+	// Exchange GitHub code for access token
+	githubToken, err := h.exchangeGitHubCode(githubCode)
+	if err != nil {
+		log.Printf("Failed to exchange GitHub code: %v", err)
+		h.sendErrorRedirect(w, r, authState, "server_error", "Failed to obtain access token")
+		return
+	}
+
+	// JWT access tokens are never re-checked with GitHub, so identify the user now
+	var subject string
+	if h.config.TokenFormat == TokenFormatJWT {
+		subject, err = lookupGitHubLogin(r.Context(), h.config, h.budget, h.httpClient, githubToken)
+		if err != nil {
+			log.Printf("Failed to look up GitHub user: %v", err)
+			h.sendErrorRedirect(w, r, authState, "server_error", "Failed to identify GitHub user")
+			return
+		}
+
+		// The membership policy can only be enforced here, since JWTs are never re-checked with GitHub
+		if h.config.HasGitHubMembershipPolicy() {
+			member, err := checkGitHubMembership(r.Context(), h.config, h.budget, h.httpClient, githubToken, subject)
+			if err != nil {
+				log.Printf("Failed to check GitHub membership: %v", err)
+				h.sendErrorRedirect(w, r, authState, "server_error", "Failed to check GitHub membership")
+				return
+			}
+			if !member {
+				log.Printf("[GITHUB] Denied %s: not a member of an allowed organization or team", subject)
+				h.sendErrorRedirect(w, r, authState, "access_denied", accessDeniedMessage(h.config, subject))
+				return
+			}
+		}
+	}
+
+	// Consents are remembered per GitHub user, so they can only be checked and recorded now
+	login := subject
+	if authState.ConsentLogin != "" || authState.ConsentApproved {
+		if login == "" {
+			login, err = lookupGitHubLogin(r.Context(), h.config, h.budget, h.httpClient, githubToken)
+		}
+		switch {
+		case authState.ConsentLogin != "" && err != nil:
+			log.Printf("Failed to look up GitHub user: %v", err)
+			h.sendErrorRedirect(w, r, authState, "server_error", "Failed to identify GitHub user")
+			return
+		case authState.ConsentLogin != "" && authState.ConsentLogin != login:
+			// Someone else approved this client in this browser, so this user hasn't
+			log.Printf("[OAUTH] Remembered consent for client %s belongs to %s, not %s", authState.ClientID, authState.ConsentLogin, login)
+			forgetConsent(w, authState.ClientID)
+			h.sendErrorRedirect(w, r, authState, "access_denied", "Another GitHub user approved this client in this browser; authorize again to approve it yourself")
+			return
+		case authState.ConsentApproved && err != nil:
+			// Remembering is a convenience, so the user is just asked again next time
+			log.Printf("Not remembering consent: failed to look up GitHub user: %v", err)
+			login = ""
+		case authState.ConsentApproved:
+			rememberConsent(w, h.config, login, authState)
+		}
+	}
+
+	// mcp:admin is only granted to the configured admins, whichever scopes the client asked for
+	if scopes := strings.Fields(authState.Scope); slices.Contains(scopes, adminScope) {
+		if login == "" {
+			login, err = lookupGitHubLogin(r.Context(), h.config, h.budget, h.httpClient, githubToken)
+			if err != nil {
+				log.Printf("Failed to look up GitHub user: %v", err)
+				h.sendErrorRedirect(w, r, authState, "server_error", "Failed to identify GitHub user")
+				return
+			}
+		}
+		admin, err := isGitHubAdmin(r.Context(), h.config, h.budget, h.httpClient, githubToken, login)
+		if err != nil {
+			log.Printf("Failed to check GitHub admin membership: %v", err)
+			h.sendErrorRedirect(w, r, authState, "server_error", "Failed to check GitHub membership")
+			return
+		}
+		if !admin {
+			log.Printf("[OAUTH] Not granting %s to %s: not listed in ADMIN_GITHUB_USERS or ADMIN_GITHUB_TEAMS", adminScope, login)
+			authState.Scope = strings.Join(slices.DeleteFunc(scopes, func(scope string) bool { return scope == adminScope }), " ")
+		}
+	}
+
+	// Device flows have no redirect; the client picks up its token by polling
+	if authState.DeviceCode != "" {
+		h.approveDevice(w, authState, githubToken, subject)
+		return
+	}
+
+	h.completeAuthorization(w, r, authState, githubToken, subject)
+}
+
+// completeAuthorization issues our own authorization code and redirects back to the client with it
+func (h *CallbackHandler) completeAuthorization(w http.ResponseWriter, r *http.Request, authState *AuthState, githubToken, subject string) {
+	// Generate our own authorization code for the client
+	ourAuthCode, err := generateRandomString(32)
+	if err != nil {
+		log.Printf("Failed to generate auth code: %v", err)
+		h.sendErrorRedirect(w, r, authState, "server_error", "Failed to generate authorization code")
+		return
+	}
+
+	// Store the authorization code with the GitHub token
+	authCodeInfo := &AuthCodeInfo{
+		ClientID:            authState.ClientID,
+		RedirectURI:         authState.RedirectURI,
+		Scope:               authState.Scope,
+		CodeChallenge:       authState.CodeChallenge,
+		CodeChallengeMethod: authState.CodeChallengeMethod,
+		Resource:            authState.Resource,
+		GitHubAccessToken:   githubToken,
+		Subject:             subject,
+		ExpiresAt:           h.config.now().Add(h.config.AuthCodeTTL),
+		CreatedAt:           h.config.now(),
+	}
+
+	if err := h.tokenStorage.StoreAuthCode(ourAuthCode, authCodeInfo); err != nil {
+		log.Printf("Failed to store auth code: %v", err)
+		h.sendErrorRedirect(w, r, authState, "server_error", "Failed to store authorization code")
+		return
+	}
+
+	// Redirect back to the client with our authorization code
+	redirectURL, err := url.Parse(authState.RedirectURI)
+	if err != nil {
+		http.Error(w, "Invalid redirect URI", http.StatusBadRequest)
+		return
+	}
+
+	query := redirectURL.Query()
+	query.Set("code", ourAuthCode)
+	if authState.State != "" {
+		query.Set("state", authState.State)
+	}
+	redirectURL.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
+
+// exchangeGitHubCode exchanges a GitHub authorization code for an access token
+func (h *CallbackHandler) exchangeGitHubCode(code string) (string, error) {
+	// Build token request
+	data := url.Values{}
+	clientID, clientSecret := h.config.GitHubCredentials()
+	data.Set("client_id", clientID)
+	data.Set("client_secret", clientSecret)
+	data.Set("code", code)
+	data.Set("redirect_uri", h.config.ServerURL+"/oauth/callback")
+
+	// Make request to GitHub
+	req, err := http.NewRequest("POST", h.config.GitHubTokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := doGitHub(h.budget, h.httpClient, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to exchange code: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("GitHub token exchange failed: %s - %s", resp.Status, string(body))
+	}
+
+	// Parse response
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		Scope       string `json:"scope"`
+		Error       string `json:"error"`
+		ErrorDesc   string `json:"error_description"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	if tokenResp.Error != "" {
+		return "", fmt.Errorf("GitHub error: %s - %s", tokenResp.Error, tokenResp.ErrorDesc)
+	}
+
+	if tokenResp.AccessToken == "" {
+		return "", fmt.Errorf("no access token in response")
+	}
+
+	return tokenResp.AccessToken, nil
+}
+
+// sendErrorRedirect redirects back to the client with an error
+// Device flows have nowhere to redirect, so the error is shown to the user, and a denied device
+// is told so on its next poll
+func (h *CallbackHandler) sendErrorRedirect(w http.ResponseWriter, r *http.Request, authState *AuthState, errorCode, errorDescription string) {
+	if authState.DeviceCode != "" {
+		if errorCode == "access_denied" {
+			h.denyDevice(authState.DeviceCode)
+		}
+		http.Error(w, fmt.Sprintf("%s: %s", errorCode, errorDescription), http.StatusBadRequest)
+		return
+	}
+
+	redirectURL, err := url.Parse(authState.RedirectURI)
+	if err != nil {
+		http.Error(w, "Invalid redirect URI", http.StatusBadRequest)
+		return
+	}
+
+	query := redirectURL.Query()
+	query.Set("error", errorCode)
+	query.Set("error_description", errorDescription)
+	if authState.State != "" {
+		query.Set("state", authState.State)
+	}
+	redirectURL.RawQuery = query.Encode()
+
+	http.Redirect(w, r, redirectURL.String(), http.StatusFound)
+}
@@ -0,0 +1,125 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// clientConfigServerName is the key the server is configured under in client snippets
+const clientConfigServerName = "deployment-project"
+
+// ClientConfig holds ready-to-paste MCP client configuration for this server
+type ClientConfig struct {
+	ServerURL string `json:"server_url"`
+	// Auth is nil when OAuth is disabled
+	Auth *ClientAuthHints `json:"auth,omitempty"`
+	// VSCode is the content of .vscode/mcp.json
+	VSCode map[string]any `json:"vscode"`
+	// ClaudeDesktop is the content of claude_desktop_config.json, which reaches remote servers
+	// through the mcp-remote bridge
+	ClaudeDesktop map[string]any `json:"claude_desktop"`
+}
+
+// ClientAuthHints tells clients how to authenticate with this server
+type ClientAuthHints struct {
+	ResourceMetadataURL            string                `json:"resource_metadata_url"`
+	AuthorizationServerMetadataURL string                `json:"authorization_server_metadata_url"`
+	RegistrationEndpoint           string                `json:"registration_endpoint,omitempty"`
+	Scopes                         []string              `json:"scopes"`
+	PreregisteredClients           []PreregisteredClient `json:"preregistered_clients"`
+}
+
+// PreregisteredClient is a client that can authenticate without dynamic registration
+type PreregisteredClient struct {
+	ClientID     string   `json:"client_id"`
+	ClientName   string   `json:"client_name"`
+	RedirectURIs []string `json:"redirect_uris"`
+}
+
+// NewClientConfig generates client configuration from the server's config
+func NewClientConfig(config *Config) ClientConfig {
+	clientConfig := ClientConfig{
+		ServerURL: config.ServerURL,
+		VSCode: map[string]any{
+			"servers": map[string]any{
+				clientConfigServerName: map[string]any{
+					"type": "http",
+					"url":  config.ServerURL,
+				},
+			},
+		},
+		ClaudeDesktop: map[string]any{
+			"mcpServers": map[string]any{
+				clientConfigServerName: map[string]any{
+					"command": "npx",
+					"args":    []string{"-y", "mcp-remote", config.ServerURL},
+				},
+			},
+		},
+	}
+	if !config.OAuthEnabled {
+		return clientConfig
+	}
+
+	hints := &ClientAuthHints{
+		ResourceMetadataURL:            config.GetResourceMetadataURL(),
+		AuthorizationServerMetadataURL: config.ServerURL + "/.well-known/oauth-authorization-server",
+		Scopes:                         config.SupportedScopes(),
+		PreregisteredClients:           []PreregisteredClient{},
+	}
+	if config.EnableDCR {
+		hints.RegistrationEndpoint = config.GetRegistrationEndpointURL()
+	}
+	for _, client := range DefaultClients() {
+		hints.PreregisteredClients = append(hints.PreregisteredClients, PreregisteredClient{
+			ClientID:     client.ClientID,
+			ClientName:   client.Metadata.ClientName,
+			RedirectURIs: client.Metadata.RedirectURIs,
+		})
+	}
+	clientConfig.Auth = hints
+	return clientConfig
+}
+
+// ClientConfigHandler serves client configuration snippets as JSON
+// ?client=vscode or ?client=claude-desktop returns only that client's file content
+type ClientConfigHandler struct {
+	config *Config
+}
+
+// NewClientConfigHandler creates a new handler for the given config
+func NewClientConfigHandler(config *Config) *ClientConfigHandler {
+	return &ClientConfigHandler{config: config}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ClientConfigHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	clientConfig := NewClientConfig(h.config)
+	var response any = clientConfig
+	switch client := r.URL.Query().Get("client"); client {
+	case "":
+	case "vscode":
+		response = clientConfig.VSCode
+	case "claude-desktop":
+		response = clientConfig.ClaudeDesktop
+	default:
+		http.Error(w, "Unknown client "+client+"; use vscode or claude-desktop", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(response); err != nil {
+		http.Error(w, "Failed to encode client config", http.StatusInternalServerError)
+	}
+}
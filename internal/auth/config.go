@@ -0,0 +1,556 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// Config holds the OAuth configuration for the MCP server
+// Fields tagged with env are loaded by LoadConfigFromEnv and described by ConfigSchema
+type Config struct {
+	// ServerURL is the canonical URL of the MCP server (e.g., https://your-server.com or http://localhost:8080)
+	ServerURL string `env:"MCP_SERVER_URL" desc:"Canonical URL of the MCP server"`
+
+	// GitHub OAuth App credentials
+	GitHubClientID     string `env:"GITHUB_CLIENT_ID" desc:"GitHub OAuth App client ID"`
+	GitHubClientSecret string `env:"GITHUB_CLIENT_SECRET" desc:"GitHub OAuth App client secret" secret:"true"`
+
+	// AllowedRedirectURIs is the list of valid redirect URIs for OAuth clients
+	// Must include VS Code redirect URIs: http://127.0.0.1:33418 and https://vscode.dev/redirect
+	AllowedRedirectURIs []string `env:"OAUTH_REDIRECT_URIS" desc:"Comma-separated redirect URIs added to the defaults"`
+
+	// ScopesSupported lists the scopes supported by this MCP server
+	ScopesSupported []string `env:"OAUTH_SCOPES_SUPPORTED" desc:"Comma-separated scopes supported by the server"`
+
+	// TokenExpiryDuration is how long access tokens remain valid
+	TokenExpiryDuration time.Duration `env:"TOKEN_EXPIRY_SECONDS" desc:"Access token lifetime in seconds"`
+
+	// EnforceHTTPS requires HTTPS for all OAuth operations (except localhost)
+	EnforceHTTPS bool `env:"ENFORCE_HTTPS" desc:"Require HTTPS (except localhost)"`
+
+	// OAuthEnabled controls whether OAuth authentication is enabled
+	// If false, the server runs without authentication (for local development)
+	OAuthEnabled bool `env:"OAUTH_ENABLED" desc:"Enable OAuth authentication"`
+
+	// EnableDCR enables Dynamic Client Registration endpoint
+	EnableDCR bool `env:"ENABLE_DCR" desc:"Enable Dynamic Client Registration"`
+
+	// AllowPublicClients allows registration of public clients (without client_secret)
+	AllowPublicClients bool `env:"ALLOW_PUBLIC_CLIENTS" desc:"Allow clients without secrets"`
+
+	// RequireConsent shows users which client, redirect URI, and scopes they are approving before
+	// they are sent to GitHub, for both authorization code and device flows; approvals are
+	// remembered per GitHub user, client, and scopes
+	RequireConsent bool `env:"OAUTH_REQUIRE_CONSENT" desc:"Ask users to approve each client and its scopes"`
+
+	// TokenFormat selects the access tokens issued by the token endpoint: opaque random strings
+	// looked up in storage, or signed JWTs verified locally
+	TokenFormat string `env:"TOKEN_FORMAT" desc:"Access token format: opaque or jwt"`
+
+	// JWTSigningKey is the PEM-encoded RSA private key used when TokenFormat is jwt
+	JWTSigningKey string `env:"JWT_SIGNING_KEY" desc:"PEM-encoded RSA private key for signing JWT access tokens" secret:"true"`
+
+	// JWTSigningKeySecretName is the Secrets Manager secret holding the signing key, if JWTSigningKey is unset
+	JWTSigningKeySecretName string `env:"JWT_SIGNING_KEY_SECRET_NAME" desc:"Secrets Manager secret holding the JWT signing key"`
+
+	// GitHub API configuration
+	GitHubAPIURL string `env:"GITHUB_API_URL" desc:"GitHub API base URL (for GitHub Enterprise)"`
+
+	// GitHubAllowedOrgs and GitHubAllowedTeams restrict access to active members of any of these
+	// organizations or teams (as org/team-slug); both empty allows every GitHub user
+	GitHubAllowedOrgs  []string `env:"GITHUB_ALLOWED_ORGS" desc:"Comma-separated GitHub organizations whose members may connect"`
+	GitHubAllowedTeams []string `env:"GITHUB_ALLOWED_TEAMS" desc:"Comma-separated GitHub teams (org/team-slug) whose members may connect"`
+
+	// AdminGitHubUsers and AdminGitHubTeams are the users who may be granted mcp:admin: the listed
+	// GitHub users and active members of the teams (as org/team-slug); both empty grants it to no
+	// user. Services are allowed it separately, with ClientCredentialsAdminClients
+	AdminGitHubUsers []string `env:"ADMIN_GITHUB_USERS" desc:"Comma-separated GitHub users who may be granted the mcp:admin scope"`
+	AdminGitHubTeams []string `env:"ADMIN_GITHUB_TEAMS" desc:"Comma-separated GitHub teams (org/team-slug) whose members may be granted the mcp:admin scope"`
+
+	// GitHubRepoAccess asks GitHub for the repo scope at login, so the GitHub tools can read and
+	// change repositories on the user's behalf
+	GitHubRepoAccess bool `env:"GITHUB_REPO_ACCESS" desc:"Request the repo scope from GitHub at login, for the GitHub repository tools"`
+
+	// Authorization server endpoints (GitHub)
+	GitHubAuthURL  string `env:"GITHUB_AUTH_URL" desc:"GitHub OAuth authorize URL"`
+	GitHubTokenURL string `env:"GITHUB_TOKEN_URL" desc:"GitHub OAuth token URL"`
+
+	// GitHubAPIBudgetReserve is the number of GitHub API calls per token held back for critical
+	// requests; non-critical re-verifications are skipped once the remaining rate limit reaches it
+	GitHubAPIBudgetReserve int `env:"GITHUB_API_BUDGET_RESERVE" desc:"GitHub API calls per token reserved for new logins"`
+
+	// UnauthenticatedMethods lists the JSON-RPC methods the MCP endpoint serves without a token,
+	// e.g. initialize and tools/list for discovery; empty requires a token for everything
+	UnauthenticatedMethods []string `env:"MCP_UNAUTHENTICATED_METHODS" desc:"Comma-separated JSON-RPC methods allowed without a token"`
+
+	// ClientCredentialsClients lists the confidential clients allowed to use the client_credentials
+	// grant; empty disables it, since anyone can register a client through DCR
+	ClientCredentialsClients []string `env:"CLIENT_CREDENTIALS_CLIENTS" desc:"Comma-separated client IDs allowed to use the client_credentials grant"`
+
+	// ClientCredentialsAdminClients lists the client_credentials clients whose tokens may carry
+	// mcp:admin; it is dropped from every other client's token, whatever the client registered
+	ClientCredentialsAdminClients []string `env:"CLIENT_CREDENTIALS_ADMIN_CLIENTS" desc:"Comma-separated client_credentials client IDs that may be granted mcp:admin"`
+
+	// MaxRequestBodyBytes limits the size of MCP request bodies
+	MaxRequestBodyBytes int `env:"MCP_MAX_BODY_BYTES" desc:"Maximum MCP request body size in bytes"`
+
+	// AuthStateTTL is how long an authorization flow may wait for the GitHub callback
+	AuthStateTTL time.Duration `env:"AUTH_STATE_TTL_SECONDS" desc:"Authorization state lifetime in seconds"`
+
+	// AuthCodeTTL is how long an issued authorization code can be exchanged for a token
+	AuthCodeTTL time.Duration `env:"AUTH_CODE_TTL_SECONDS" desc:"Authorization code lifetime in seconds"`
+
+	// ClockSkew is how far instance clocks may drift apart; tokens, authorization codes, and states
+	// are accepted for this long past their expiry so a drifting instance doesn't reject them early
+	ClockSkew time.Duration `env:"CLOCK_SKEW_SECONDS" desc:"Seconds tokens, codes, and states are still accepted after they expire, to tolerate clock drift between instances"`
+
+	// ClientTTL is how long a client registered through DCR is kept without being issued a token;
+	// zero keeps registrations forever
+	ClientTTL time.Duration `env:"CLIENT_TTL_SECONDS" desc:"Seconds an unused DCR client registration is kept, 0 to keep it forever"`
+
+	// AutoRegisteredClientTTL is ClientTTL for clients auto-registered on their first authorization
+	AutoRegisteredClientTTL time.Duration `env:"AUTO_REGISTERED_CLIENT_TTL_SECONDS" desc:"Seconds an unused auto-registered client is kept, 0 to keep it forever"`
+
+	// StorageBackend selects where clients, tokens, and cached validations are kept: memory, dynamodb, redis, or postgres
+	StorageBackend string `env:"STORAGE_BACKEND" desc:"Storage for clients and tokens: memory, dynamodb, redis, or postgres"`
+
+	// DynamoDBTableName is the table used when StorageBackend is dynamodb
+	DynamoDBTableName string `env:"DYNAMODB_TABLE_NAME" desc:"DynamoDB table used when STORAGE_BACKEND is dynamodb"`
+
+	// RedisURL is the redis:// or rediss:// URL used when StorageBackend is redis
+	RedisURL string `env:"REDIS_URL" desc:"Redis URL used when STORAGE_BACKEND is redis" secret:"true"`
+
+	// RedisKeyPrefix namespaces every key so several deployments can share one Redis
+	RedisKeyPrefix string `env:"REDIS_KEY_PREFIX" desc:"Prefix for every Redis key"`
+
+	// PostgresURL is the postgres:// connection URL used when StorageBackend is postgres
+	PostgresURL string `env:"POSTGRES_URL" desc:"Postgres connection URL used when STORAGE_BACKEND is postgres" secret:"true"`
+
+	// PostgresTableName is the table records are kept in, created at startup if it doesn't exist
+	PostgresTableName string `env:"POSTGRES_TABLE_NAME" desc:"Postgres table used when STORAGE_BACKEND is postgres"`
+
+	// Sandbox runs the server for demos and development: OAuth goes through a built-in stand-in
+	// for GitHub, storage is in memory, and tools return canned data instead of calling out
+	Sandbox bool `env:"SANDBOX" desc:"Run with a stand-in GitHub, in-memory storage, and canned tool data"`
+
+	// Clock is the source of the current time for issuing codes, states, and tokens
+	Clock clock.Clock
+
+	// reloadMu guards the settings Reload replaces while requests are served
+	reloadMu sync.RWMutex
+}
+
+// DefaultConfig returns a Config with default values
+func DefaultConfig() *Config {
+	return &Config{
+		ServerURL: "http://localhost:8080",
+		AllowedRedirectURIs: []string{
+			"http://127.0.0.1:33418",
+			"http://127.0.0.1:33418/",
+			"http://127.0.0.1:33418/done",
+			"https://vscode.dev/redirect",
+		},
+		ScopesSupported: []string{
+			"mcp:tools",
+			"mcp:resources",
+			"read:user",
+		},
+		TokenExpiryDuration:     1 * time.Hour,
+		TokenFormat:             TokenFormatOpaque,
+		EnforceHTTPS:            false, // Default to false for development
+		OAuthEnabled:            false, // Default to false for local development
+		EnableDCR:               true,
+		AllowPublicClients:      true,
+		RequireConsent:          true,
+		GitHubAPIURL:            "https://api.github.com",
+		GitHubAuthURL:           "https://github.com/login/oauth/authorize",
+		GitHubTokenURL:          "https://github.com/login/oauth/access_token",
+		GitHubAPIBudgetReserve:  100,
+		MaxRequestBodyBytes:     1 << 20,
+		AuthStateTTL:            10 * time.Minute,
+		AuthCodeTTL:             10 * time.Minute,
+		ClientTTL:               90 * 24 * time.Hour,
+		AutoRegisteredClientTTL: 30 * 24 * time.Hour,
+		StorageBackend:          StorageBackendMemory,
+		RedisKeyPrefix:          "mcp:",
+		PostgresTableName:       "mcp_oauth",
+		Clock:                   clock.System{},
+	}
+}
+
+// LoadConfigFromEnv loads configuration from environment variables
+func LoadConfigFromEnv() (*Config, error) {
+	cfg := DefaultConfig()
+
+	// Required: Server URL
+	if serverURL := os.Getenv("MCP_SERVER_URL"); serverURL != "" {
+		// Validate URL format
+		parsedURL, err := url.Parse(serverURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_SERVER_URL: %w", err)
+		}
+		// Remove trailing slash for consistency
+		cfg.ServerURL = strings.TrimSuffix(parsedURL.String(), "/")
+	} else if host := os.Getenv("HOST"); host != "" && os.Getenv("PORT") != "" {
+		port := os.Getenv("PORT")
+		scheme := "http"
+		if os.Getenv("USE_HTTPS") == "true" {
+			scheme = "https"
+		}
+		cfg.ServerURL = fmt.Sprintf("%s://%s:%s", scheme, host, port)
+	}
+
+	// Required for OAuth: GitHub OAuth App credentials
+	// First check for direct environment variables (local development)
+	cfg.GitHubClientID = os.Getenv("GITHUB_CLIENT_ID")
+	cfg.GitHubClientSecret = os.Getenv("GITHUB_CLIENT_SECRET")
+
+	// Optional: Sandbox mode, which never needs real credentials
+	if sandbox := os.Getenv("SANDBOX"); sandbox != "" {
+		cfg.Sandbox = sandbox == "true" || sandbox == "1"
+	}
+
+	// If not found, check for AWS Secrets Manager secret name (production)
+	if !cfg.Sandbox && (cfg.GitHubClientID == "" || cfg.GitHubClientSecret == "") {
+		if secretName := os.Getenv("GITHUB_OAUTH_SECRET_NAME"); secretName != "" {
+			// Load from AWS Secrets Manager
+			if err := loadGitHubCredsFromSecretsManager(cfg, secretName); err != nil {
+				return nil, fmt.Errorf("failed to load GitHub credentials from Secrets Manager: %w", err)
+			}
+		}
+	}
+
+	// Optional: Additional redirect URIs
+	if redirectURIs := os.Getenv("OAUTH_REDIRECT_URIS"); redirectURIs != "" {
+		uris := strings.Split(redirectURIs, ",")
+		for _, uri := range uris {
+			trimmed := strings.TrimSpace(uri)
+			if trimmed != "" {
+				// Validate redirect URI
+				if _, err := url.Parse(trimmed); err != nil {
+					return nil, fmt.Errorf("invalid redirect URI %s: %w", trimmed, err)
+				}
+				cfg.AllowedRedirectURIs = append(cfg.AllowedRedirectURIs, trimmed)
+			}
+		}
+	}
+
+	// Optional: Custom scopes
+	if scopes := os.Getenv("OAUTH_SCOPES_SUPPORTED"); scopes != "" {
+		cfg.ScopesSupported = strings.Split(scopes, ",")
+		for i, scope := range cfg.ScopesSupported {
+			cfg.ScopesSupported[i] = strings.TrimSpace(scope)
+		}
+	}
+
+	// Optional: Token expiry
+	if expiryStr := os.Getenv("TOKEN_EXPIRY_SECONDS"); expiryStr != "" {
+		expiry, err := strconv.Atoi(expiryStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TOKEN_EXPIRY_SECONDS: %w", err)
+		}
+		cfg.TokenExpiryDuration = time.Duration(expiry) * time.Second
+	}
+
+	// Optional: Token format
+	if format := os.Getenv("TOKEN_FORMAT"); format != "" {
+		cfg.TokenFormat = strings.ToLower(format)
+	}
+	cfg.JWTSigningKey = os.Getenv("JWT_SIGNING_KEY")
+	cfg.JWTSigningKeySecretName = os.Getenv("JWT_SIGNING_KEY_SECRET_NAME")
+
+	// Optional: HTTPS enforcement
+	if enforceHTTPS := os.Getenv("ENFORCE_HTTPS"); enforceHTTPS != "" {
+		cfg.EnforceHTTPS = enforceHTTPS == "true" || enforceHTTPS == "1"
+	}
+
+	// Optional: OAuth enablement (defaults to false for local development)
+	if oauthEnabled := os.Getenv("OAUTH_ENABLED"); oauthEnabled != "" {
+		cfg.OAuthEnabled = oauthEnabled == "true" || oauthEnabled == "1"
+	}
+
+	// Optional: DCR enablement
+	if enableDCR := os.Getenv("ENABLE_DCR"); enableDCR != "" {
+		cfg.EnableDCR = enableDCR == "true" || enableDCR == "1"
+	}
+
+	// Optional: Public clients
+	if allowPublic := os.Getenv("ALLOW_PUBLIC_CLIENTS"); allowPublic != "" {
+		cfg.AllowPublicClients = allowPublic == "true" || allowPublic == "1"
+	}
+
+	// Optional: Consent page
+	if requireConsent := os.Getenv("OAUTH_REQUIRE_CONSENT"); requireConsent != "" {
+		cfg.RequireConsent = requireConsent == "true" || requireConsent == "1"
+	}
+
+	// Optional: Custom GitHub URLs (for testing or GitHub Enterprise)
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" {
+		cfg.GitHubAPIURL = strings.TrimSuffix(apiURL, "/")
+	}
+	if authURL := os.Getenv("GITHUB_AUTH_URL"); authURL != "" {
+		cfg.GitHubAuthURL = authURL
+	}
+	if tokenURL := os.Getenv("GITHUB_TOKEN_URL"); tokenURL != "" {
+		cfg.GitHubTokenURL = tokenURL
+	}
+
+	// Optional: GitHub membership policy
+	cfg.GitHubAllowedOrgs = splitList(os.Getenv("GITHUB_ALLOWED_ORGS"))
+	cfg.GitHubAllowedTeams = splitList(os.Getenv("GITHUB_ALLOWED_TEAMS"))
+
+	// Optional: Who may be granted mcp:admin
+	cfg.AdminGitHubUsers = splitList(os.Getenv("ADMIN_GITHUB_USERS"))
+	cfg.AdminGitHubTeams = splitList(os.Getenv("ADMIN_GITHUB_TEAMS"))
+
+	// Optional: Repository access for the GitHub tools
+	if repoAccess := os.Getenv("GITHUB_REPO_ACCESS"); repoAccess != "" {
+		cfg.GitHubRepoAccess = repoAccess == "true" || repoAccess == "1"
+	}
+
+	// Optional: GitHub API rate limit reserve
+	if reserveStr := os.Getenv("GITHUB_API_BUDGET_RESERVE"); reserveStr != "" {
+		reserve, err := strconv.Atoi(reserveStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid GITHUB_API_BUDGET_RESERVE: %w", err)
+		}
+		cfg.GitHubAPIBudgetReserve = reserve
+	}
+
+	// Optional: Unauthenticated discovery
+	cfg.UnauthenticatedMethods = splitList(os.Getenv("MCP_UNAUTHENTICATED_METHODS"))
+	cfg.ClientCredentialsClients = splitList(os.Getenv("CLIENT_CREDENTIALS_CLIENTS"))
+	cfg.ClientCredentialsAdminClients = splitList(os.Getenv("CLIENT_CREDENTIALS_ADMIN_CLIENTS"))
+	if maxBodyStr := os.Getenv("MCP_MAX_BODY_BYTES"); maxBodyStr != "" {
+		maxBody, err := strconv.Atoi(maxBodyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MCP_MAX_BODY_BYTES: %w", err)
+		}
+		cfg.MaxRequestBodyBytes = maxBody
+	}
+
+	// Optional: Authorization flow lifetimes
+	if ttlStr := os.Getenv("AUTH_STATE_TTL_SECONDS"); ttlStr != "" {
+		ttl, err := strconv.Atoi(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_STATE_TTL_SECONDS: %w", err)
+		}
+		cfg.AuthStateTTL = time.Duration(ttl) * time.Second
+	}
+	if ttlStr := os.Getenv("AUTH_CODE_TTL_SECONDS"); ttlStr != "" {
+		ttl, err := strconv.Atoi(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTH_CODE_TTL_SECONDS: %w", err)
+		}
+		cfg.AuthCodeTTL = time.Duration(ttl) * time.Second
+	}
+
+	// Optional: Clock skew tolerance
+	if skewStr := os.Getenv("CLOCK_SKEW_SECONDS"); skewStr != "" {
+		skew, err := strconv.Atoi(skewStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLOCK_SKEW_SECONDS: %w", err)
+		}
+		cfg.ClockSkew = time.Duration(skew) * time.Second
+	}
+
+	// Optional: Client registration lifetimes
+	if ttlStr := os.Getenv("CLIENT_TTL_SECONDS"); ttlStr != "" {
+		ttl, err := strconv.Atoi(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CLIENT_TTL_SECONDS: %w", err)
+		}
+		cfg.ClientTTL = time.Duration(ttl) * time.Second
+	}
+	if ttlStr := os.Getenv("AUTO_REGISTERED_CLIENT_TTL_SECONDS"); ttlStr != "" {
+		ttl, err := strconv.Atoi(ttlStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid AUTO_REGISTERED_CLIENT_TTL_SECONDS: %w", err)
+		}
+		cfg.AutoRegisteredClientTTL = time.Duration(ttl) * time.Second
+	}
+
+	// Optional: Persistent storage
+	if backend := os.Getenv("STORAGE_BACKEND"); backend != "" {
+		cfg.StorageBackend = strings.ToLower(backend)
+	}
+	cfg.DynamoDBTableName = os.Getenv("DYNAMODB_TABLE_NAME")
+	cfg.RedisURL = os.Getenv("REDIS_URL")
+	if prefix := os.Getenv("REDIS_KEY_PREFIX"); prefix != "" {
+		cfg.RedisKeyPrefix = prefix
+	}
+	cfg.PostgresURL = os.Getenv("POSTGRES_URL")
+	if table := os.Getenv("POSTGRES_TABLE_NAME"); table != "" {
+		cfg.PostgresTableName = table
+	}
+
+	if cfg.Sandbox {
+		cfg.applySandbox()
+	}
+
+	return cfg, nil
+}
+
+// splitList splits a comma-separated list, trimming spaces and dropping empty entries
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// Validate checks if the configuration is valid
+// It returns all fatal problems joined together; use ValidationReport to also see warnings
+func (c *Config) Validate() error {
+	return c.ValidationReport().Err()
+}
+
+// clientExpiry returns when client expires if it goes unused from now on, or nil if it never does
+// Pre-registered clients are stored without an expiry and keep it that way
+func (c *Config) clientExpiry(client *OAuthClient, now time.Time) *time.Time {
+	ttl := c.ClientTTL
+	if client.AutoRegistered {
+		ttl = c.AutoRegisteredClientTTL
+	}
+	if ttl <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(ttl)
+	return &expiresAt
+}
+
+// now returns the current time from the configured clock
+func (c *Config) now() time.Time {
+	if c.Clock == nil {
+		return time.Now()
+	}
+	return c.Clock.Now()
+}
+
+// expiryClock returns the clock expiry checks use, ClockSkew behind the configured clock
+func (c *Config) expiryClock() clock.Clock {
+	var base clock.Clock = clock.System{}
+	if c.Clock != nil {
+		base = c.Clock
+	}
+	return clock.Behind{Clock: base, Offset: c.ClockSkew}
+}
+
+// GetResourceMetadataURL returns the URL for the protected resource metadata endpoint
+func (c *Config) GetResourceMetadataURL() string {
+	return c.ServerURL + "/.well-known/oauth-protected-resource"
+}
+
+// GetRegistrationEndpointURL returns the URL for the dynamic client registration endpoint
+func (c *Config) GetRegistrationEndpointURL() string {
+	// Return registration endpoint if DCR is enabled
+	if !c.EnableDCR {
+		return ""
+	}
+	return c.ServerURL + "/register"
+}
+
+// IsRedirectURIAllowed checks if a redirect URI is in the allowed list
+// It normalizes URIs by removing trailing slashes for comparison
+func (c *Config) IsRedirectURIAllowed(uri string) bool {
+	// Normalize the incoming URI
+	normalizedURI := strings.TrimSuffix(uri, "/")
+
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	for _, allowed := range c.AllowedRedirectURIs {
+		// Normalize the allowed URI
+		normalizedAllowed := strings.TrimSuffix(allowed, "/")
+
+		// Check exact match or normalized match
+		if uri == allowed || normalizedURI == normalizedAllowed {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsClientCredentials reports whether clientID may use the client_credentials grant
+func (c *Config) AllowsClientCredentials(clientID string) bool {
+	return slices.Contains(c.ClientCredentialsClients, clientID)
+}
+
+// AllowsClientCredentialsAdmin reports whether clientID's client_credentials tokens may carry mcp:admin
+func (c *Config) AllowsClientCredentialsAdmin(clientID string) bool {
+	return slices.Contains(c.ClientCredentialsAdminClients, clientID)
+}
+
+// IsScopeSupported checks if a scope is supported
+func (c *Config) IsScopeSupported(scope string) bool {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	for _, supported := range c.ScopesSupported {
+		if scope == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// isLocalhost checks if a host is localhost or 127.0.0.1
+func isLocalhost(host string) bool {
+	// Remove port if present
+	if idx := strings.Index(host, ":"); idx != -1 {
+		host = host[:idx]
+	}
+	return host == "localhost" || host == "127.0.0.1" || host == "::1"
+}
+
+// loadGitHubCredsFromSecretsManager loads GitHub OAuth credentials from AWS Secrets Manager
+func loadGitHubCredsFromSecretsManager(cfg *Config, secretName string) error {
+	// Retrieve the secret, retrying throttling and transient errors
+	value, err := DefaultSecretFetcher.Fetch(context.Background(), secretName)
+	if err != nil {
+		return err
+	}
+
+	// Parse the secret JSON; redirect URIs and scopes are optional, so they can be changed
+	// alongside the credentials and picked up by a reload
+	var secrets struct {
+		GitHubClientID     string `json:"GITHUB_CLIENT_ID"`
+		GitHubClientSecret string `json:"GITHUB_CLIENT_SECRET"`
+		RedirectURIs       string `json:"OAUTH_REDIRECT_URIS"`
+		ScopesSupported    string `json:"OAUTH_SCOPES_SUPPORTED"`
+	}
+
+	if err := json.Unmarshal([]byte(value), &secrets); err != nil {
+		return fmt.Errorf("failed to parse secret JSON: %w", err)
+	}
+
+	// Set the credentials
+	cfg.GitHubClientID = secrets.GitHubClientID
+	cfg.GitHubClientSecret = secrets.GitHubClientSecret
+	for _, uri := range splitList(secrets.RedirectURIs) {
+		if _, err := url.Parse(uri); err != nil {
+			return fmt.Errorf("invalid redirect URI %s in secret: %w", uri, err)
+		}
+		cfg.AllowedRedirectURIs = append(cfg.AllowedRedirectURIs, uri)
+	}
+	if scopes := splitList(secrets.ScopesSupported); len(scopes) > 0 {
+		cfg.ScopesSupported = scopes
+	}
+
+	return nil
+}
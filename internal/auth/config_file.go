@@ -0,0 +1,166 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configKeyPattern is the form of an environment variable name
+var configKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+var (
+	configFileMu sync.Mutex
+	// configFileKeys are the variables the last ApplyConfigFile set, which a later call may
+	// replace or unset
+	configFileKeys = map[string]bool{}
+)
+
+// ApplyConfigFile reads a YAML or JSON file mapping environment variable names (any case) to
+// values and sets each variable the environment doesn't already set, so environment variables
+// override the file and every setting read from the environment can come from the file
+// Lists may be written as sequences; nested objects are not supported
+// Variables described by ConfigSchema are checked against their type, and every problem is
+// returned at once naming the offending key; nothing is set when there are any
+// Calling it again, to reload the file, replaces the values it set before
+func ApplyConfigFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var values map[string]any
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &values)
+	} else {
+		err = yaml.Unmarshal(data, &values)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	types := make(map[string]string)
+	for _, variable := range ConfigSchema() {
+		types[variable.Name] = variable.Type
+	}
+
+	env := make(map[string]string, len(values))
+	var errs []error
+	for key, value := range values {
+		name := strings.ToUpper(key)
+		if !configKeyPattern.MatchString(name) {
+			errs = append(errs, fmt.Errorf("%s: invalid key %q: keys are environment variable names such as OAUTH_ENABLED", path, key))
+			continue
+		}
+		formatted, err := formatConfigValue(value, types[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", path, key, err))
+			continue
+		}
+		env[name] = formatted
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+		return errors.Join(errs...)
+	}
+
+	configFileMu.Lock()
+	defer configFileMu.Unlock()
+	for name := range configFileKeys {
+		if _, ok := env[name]; !ok {
+			_ = os.Unsetenv(name)
+		}
+	}
+	next := make(map[string]bool, len(env))
+	for name, value := range env {
+		if _, set := os.LookupEnv(name); set && !configFileKeys[name] {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+		next[name] = true
+	}
+	configFileKeys = next
+	return nil
+}
+
+// formatConfigValue writes a file value the way it would be written in the environment, checking
+// it against the schema type when there is one
+func formatConfigValue(value any, schemaType string) (string, error) {
+	if items, ok := value.([]any); ok {
+		if schemaType != "" && schemaType != "list" {
+			return "", fmt.Errorf("expected a %s, got a list", schemaType)
+		}
+		formatted := make([]string, 0, len(items))
+		for _, item := range items {
+			s, err := formatConfigScalar(item)
+			if err != nil {
+				return "", fmt.Errorf("list entry: %w", err)
+			}
+			if strings.Contains(s, ",") {
+				return "", fmt.Errorf("list entry %q contains a comma", s)
+			}
+			formatted = append(formatted, s)
+		}
+		return strings.Join(formatted, ","), nil
+	}
+
+	s, err := formatConfigScalar(value)
+	if err != nil {
+		return "", err
+	}
+	switch schemaType {
+	case "bool":
+		if _, err := strconv.ParseBool(s); err != nil {
+			return "", fmt.Errorf("expected true or false, got %q", s)
+		}
+	case "int", "seconds":
+		if _, err := strconv.Atoi(s); err != nil {
+			return "", fmt.Errorf("expected a whole number, got %q", s)
+		}
+	case "duration":
+		if _, err := time.ParseDuration(s); err != nil {
+			return "", fmt.Errorf("expected a duration such as 24h, got %q", s)
+		}
+	}
+	return s, nil
+}
+
+// formatConfigScalar formats a single string, number, or bool
+func formatConfigScalar(value any) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	case map[string]any:
+		return "", fmt.Errorf("nested objects are not supported")
+	default:
+		return "", fmt.Errorf("unsupported value of type %T", value)
+	}
+}
@@ -0,0 +1,67 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"fmt"
+	"slices"
+)
+
+// GitHubCredentials returns the GitHub OAuth App credentials, which Reload may rotate
+func (c *Config) GitHubCredentials() (clientID, clientSecret string) {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	return c.GitHubClientID, c.GitHubClientSecret
+}
+
+// SupportedScopes returns a copy of the supported scopes, which Reload may replace
+func (c *Config) SupportedScopes() []string {
+	c.reloadMu.RLock()
+	defer c.reloadMu.RUnlock()
+	return slices.Clone(c.ScopesSupported)
+}
+
+// Reload replaces the settings that can change while the server runs (the GitHub credentials,
+// allowed redirect URIs, and supported scopes) with those in next, and returns the environment
+// variables whose values changed
+// Everything else, including storage and sessions, is left alone, so clients stay signed in
+func (c *Config) Reload(next *Config) []string {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	var changed []string
+	if c.GitHubClientID != next.GitHubClientID {
+		changed = append(changed, "GITHUB_CLIENT_ID")
+	}
+	if c.GitHubClientSecret != next.GitHubClientSecret {
+		changed = append(changed, "GITHUB_CLIENT_SECRET")
+	}
+	if !slices.Equal(c.AllowedRedirectURIs, next.AllowedRedirectURIs) {
+		changed = append(changed, "OAUTH_REDIRECT_URIS")
+	}
+	if !slices.Equal(c.ScopesSupported, next.ScopesSupported) {
+		changed = append(changed, "OAUTH_SCOPES_SUPPORTED")
+	}
+
+	c.GitHubClientID = next.GitHubClientID
+	c.GitHubClientSecret = next.GitHubClientSecret
+	c.AllowedRedirectURIs = slices.Clone(next.AllowedRedirectURIs)
+	c.ScopesSupported = slices.Clone(next.ScopesSupported)
+	return changed
+}
+
+// ReloadFromEnv loads the configuration again, re-fetching GITHUB_OAUTH_SECRET_NAME from
+// Secrets Manager, and applies it with Reload
+// A configuration with fatal issues is rejected and the current one kept
+func (c *Config) ReloadFromEnv() ([]string, error) {
+	next, err := LoadConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if report := next.ValidationReport(); report.HasFatal() {
+		return nil, fmt.Errorf("invalid configuration: %w", report.Err())
+	}
+	return c.Reload(next), nil
+}
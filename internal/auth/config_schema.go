@@ -0,0 +1,110 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigVariable describes a single environment variable read by the server
+// Field names the Config field it is loaded into, and is empty for variables read elsewhere
+type ConfigVariable struct {
+	Name        string `json:"name"`
+	Field       string `json:"field,omitempty"`
+	Type        string `json:"type"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description"`
+	Secret      bool   `json:"secret,omitempty"`
+}
+
+// ConfigSchema describes every environment variable the server reads
+// Config's variables are derived from its struct tags, with defaults from DefaultConfig so the
+// documentation always matches runtime behavior; the variables read elsewhere follow
+func ConfigSchema() []ConfigVariable {
+	defaults := reflect.ValueOf(DefaultConfig()).Elem()
+	configType := defaults.Type()
+
+	var schema []ConfigVariable
+	for i := 0; i < configType.NumField(); i++ {
+		field := configType.Field(i)
+		name := field.Tag.Get("env")
+		if name == "" {
+			continue
+		}
+
+		variable := ConfigVariable{
+			Name:        name,
+			Field:       field.Name,
+			Type:        schemaType(field.Type),
+			Description: field.Tag.Get("desc"),
+			Secret:      field.Tag.Get("secret") == "true",
+		}
+		if !variable.Secret {
+			variable.Default = schemaDefault(defaults.Field(i))
+		}
+		schema = append(schema, variable)
+	}
+
+	schema = append(schema, otherVariables...)
+	schema = append(schema, logFileVariables("ACCESS", "File to write access logs to instead of stdout")...)
+	schema = append(schema, logFileVariables("APP", "File to copy application logs to, in addition to stderr")...)
+	schema = append(schema, logFileVariables("AUDIT", "File to append token audit events to as JSON lines")...)
+	return schema
+}
+
+// schemaType names the format the environment variable is parsed as
+func schemaType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return "seconds"
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return "list"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int:
+		return "int"
+	default:
+		return "string"
+	}
+}
+
+// schemaDefault formats a default value the way it would be written in the environment
+func schemaDefault(v reflect.Value) string {
+	if d, ok := v.Interface().(time.Duration); ok {
+		return strconv.Itoa(int(d.Seconds()))
+	}
+	if list, ok := v.Interface().([]string); ok {
+		return strings.Join(list, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// ConfigSchemaHandler serves the configuration schema as JSON
+type ConfigSchemaHandler struct{}
+
+// NewConfigSchemaHandler creates a new handler for the configuration schema
+func NewConfigSchemaHandler() *ConfigSchemaHandler {
+	return &ConfigSchemaHandler{}
+}
+
+// ServeHTTP implements http.Handler
+func (h *ConfigSchemaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ConfigSchema()); err != nil {
+		http.Error(w, "Failed to encode schema", http.StatusInternalServerError)
+	}
+}
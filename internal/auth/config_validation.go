@@ -0,0 +1,300 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+)
+
+// Severity classifies how serious a configuration problem is
+type Severity string
+
+const (
+	// SeverityFatal problems prevent OAuth from being enabled
+	SeverityFatal Severity = "fatal"
+
+	// SeverityWarning problems are allowed but probably not what the operator intended
+	SeverityWarning Severity = "warning"
+)
+
+// maxRecommendedTokenExpiry is the longest token lifetime that doesn't trigger a warning
+const maxRecommendedTokenExpiry = 24 * time.Hour
+
+// maxRecommendedAuthCodeTTL is the longest authorization code lifetime that doesn't trigger a warning
+// RFC 6749 recommends a maximum of 10 minutes
+const maxRecommendedAuthCodeTTL = 10 * time.Minute
+
+// maxRecommendedClockSkew is the largest clock skew tolerance that doesn't trigger a warning
+const maxRecommendedClockSkew = 5 * time.Minute
+
+// ValidationIssue is a single configuration problem
+type ValidationIssue struct {
+	// Field is the environment variable (or config field) the problem relates to
+	Field    string   `json:"field"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// Error implements error
+func (i ValidationIssue) Error() string {
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+// ValidationReport collects every configuration problem so operators can fix them in one pass
+type ValidationReport struct {
+	Issues []ValidationIssue `json:"issues"`
+}
+
+func (r *ValidationReport) add(severity Severity, field, format string, args ...any) {
+	r.Issues = append(r.Issues, ValidationIssue{
+		Field:    field,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Fatal returns the issues that prevent OAuth from being enabled
+func (r *ValidationReport) Fatal() []ValidationIssue {
+	return r.filter(SeverityFatal)
+}
+
+// Warnings returns the issues that are allowed but should be reviewed
+func (r *ValidationReport) Warnings() []ValidationIssue {
+	return r.filter(SeverityWarning)
+}
+
+func (r *ValidationReport) filter(severity Severity) []ValidationIssue {
+	var issues []ValidationIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// HasFatal reports whether any issue is fatal
+func (r *ValidationReport) HasFatal() bool {
+	return len(r.Fatal()) > 0
+}
+
+// Err joins all fatal issues into a single error, or returns nil if there are none
+func (r *ValidationReport) Err() error {
+	var errs []error
+	for _, issue := range r.Fatal() {
+		errs = append(errs, issue)
+	}
+	return errors.Join(errs...)
+}
+
+// Log writes every issue to the standard logger, fatal issues first
+func (r *ValidationReport) Log() {
+	for _, issue := range r.Fatal() {
+		log.Printf("[CONFIG] Fatal: %s", issue.Error())
+	}
+	for _, issue := range r.Warnings() {
+		log.Printf("[CONFIG] Warning: %s", issue.Error())
+	}
+}
+
+// String summarizes the report, one issue per line
+func (r *ValidationReport) String() string {
+	if len(r.Issues) == 0 {
+		return "configuration OK"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d fatal, %d warning(s)", len(r.Fatal()), len(r.Warnings()))
+	for _, issue := range r.Issues {
+		fmt.Fprintf(&b, "\n  [%s] %s", issue.Severity, issue.Error())
+	}
+	return b.String()
+}
+
+// ValidationReport checks the whole configuration and returns every problem found
+func (c *Config) ValidationReport() *ValidationReport {
+	report := &ValidationReport{}
+
+	// Validate server URL
+	var parsedURL *url.URL
+	if c.ServerURL == "" {
+		report.add(SeverityFatal, "MCP_SERVER_URL", "server URL is required")
+	} else if u, err := url.Parse(c.ServerURL); err != nil {
+		report.add(SeverityFatal, "MCP_SERVER_URL", "invalid server URL: %v", err)
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		report.add(SeverityFatal, "MCP_SERVER_URL", "server URL must use http or https scheme")
+	} else {
+		parsedURL = u
+	}
+
+	// Check HTTPS enforcement
+	if parsedURL != nil && parsedURL.Scheme == "http" && !isLocalhost(parsedURL.Host) {
+		if c.EnforceHTTPS {
+			report.add(SeverityFatal, "ENFORCE_HTTPS", "HTTPS enforcement enabled but server URL uses HTTP for non-localhost")
+		} else {
+			report.add(SeverityWarning, "MCP_SERVER_URL", "server URL uses HTTP for non-localhost host %s", parsedURL.Host)
+		}
+	}
+
+	// Validate GitHub credentials if OAuth is enabled
+	if c.OAuthEnabled {
+		if c.GitHubClientID == "" {
+			report.add(SeverityFatal, "GITHUB_CLIENT_ID", "GitHub client ID is required when OAuth is enabled")
+		}
+		if c.GitHubClientSecret == "" && !c.AllowPublicClients {
+			report.add(SeverityFatal, "GITHUB_CLIENT_SECRET", "GitHub client secret is required when public clients are not allowed")
+		}
+	} else if parsedURL != nil && !isLocalhost(parsedURL.Host) {
+		report.add(SeverityWarning, "OAUTH_ENABLED", "OAuth is disabled but the server is not on localhost")
+	}
+
+	// Validate redirect URIs
+	if len(c.AllowedRedirectURIs) == 0 {
+		report.add(SeverityFatal, "OAUTH_REDIRECT_URIS", "at least one redirect URI must be configured")
+	}
+	for _, uri := range c.AllowedRedirectURIs {
+		parsed, err := url.Parse(uri)
+		if err != nil {
+			report.add(SeverityFatal, "OAUTH_REDIRECT_URIS", "invalid redirect URI %s: %v", uri, err)
+			continue
+		}
+		if parsed.Scheme == "http" && !isLocalhost(parsed.Host) {
+			report.add(SeverityWarning, "OAUTH_REDIRECT_URIS", "redirect URI %s uses HTTP for non-localhost", uri)
+		}
+	}
+
+	// Validate scopes
+	if len(c.ScopesSupported) == 0 {
+		report.add(SeverityFatal, "OAUTH_SCOPES_SUPPORTED", "at least one scope must be supported")
+	} else if !c.IsScopeSupported("mcp:tools") {
+		report.add(SeverityWarning, "OAUTH_SCOPES_SUPPORTED", "mcp:tools is not supported, so clients cannot call tools")
+	}
+
+	// Validate token expiry
+	if c.TokenExpiryDuration <= 0 {
+		report.add(SeverityFatal, "TOKEN_EXPIRY_SECONDS", "token expiry duration must be positive")
+	} else if c.TokenExpiryDuration > maxRecommendedTokenExpiry {
+		report.add(SeverityWarning, "TOKEN_EXPIRY_SECONDS", "token expiry of %s is longer than the recommended %s",
+			c.TokenExpiryDuration, maxRecommendedTokenExpiry)
+	}
+
+	// Validate token format
+	switch c.TokenFormat {
+	case TokenFormatOpaque:
+	case TokenFormatJWT:
+		if c.JWTSigningKey == "" && c.JWTSigningKeySecretName == "" {
+			report.add(SeverityFatal, "JWT_SIGNING_KEY", "a signing key (or JWT_SIGNING_KEY_SECRET_NAME) is required when TOKEN_FORMAT is jwt")
+		} else if c.JWTSigningKey != "" {
+			if _, err := ParseRSAPrivateKeyPEM([]byte(c.JWTSigningKey)); err != nil {
+				report.add(SeverityFatal, "JWT_SIGNING_KEY", "%v", err)
+			}
+		}
+	default:
+		report.add(SeverityFatal, "TOKEN_FORMAT", "unknown token format %q (use opaque or jwt)", c.TokenFormat)
+	}
+
+	// Validate GitHub membership policy
+	for _, team := range c.GitHubAllowedTeams {
+		if org, slug, ok := strings.Cut(team, "/"); !ok || org == "" || slug == "" || strings.Contains(slug, "/") {
+			report.add(SeverityFatal, "GITHUB_ALLOWED_TEAMS", "team %q must be written as org/team-slug", team)
+		}
+	}
+
+	// Validate who may be granted mcp:admin
+	for _, team := range c.AdminGitHubTeams {
+		if org, slug, ok := strings.Cut(team, "/"); !ok || org == "" || slug == "" || strings.Contains(slug, "/") {
+			report.add(SeverityFatal, "ADMIN_GITHUB_TEAMS", "team %q must be written as org/team-slug", team)
+		}
+	}
+	for _, clientID := range c.ClientCredentialsAdminClients {
+		if !c.AllowsClientCredentials(clientID) {
+			report.add(SeverityWarning, "CLIENT_CREDENTIALS_ADMIN_CLIENTS", "client %q is not in CLIENT_CREDENTIALS_CLIENTS, so it gets no client_credentials tokens", clientID)
+		}
+	}
+	if c.IsScopeSupported(adminScope) && len(c.AdminGitHubUsers) == 0 && len(c.AdminGitHubTeams) == 0 {
+		report.add(SeverityWarning, "ADMIN_GITHUB_USERS", "%s is supported but no GitHub user may be granted it; set ADMIN_GITHUB_USERS or ADMIN_GITHUB_TEAMS", adminScope)
+	}
+
+	// Validate GitHub API budget reserve
+	if c.GitHubAPIBudgetReserve < 0 {
+		report.add(SeverityFatal, "GITHUB_API_BUDGET_RESERVE", "budget reserve cannot be negative")
+	}
+
+	// Validate unauthenticated discovery
+	for _, method := range c.UnauthenticatedMethods {
+		if method == "tools/call" {
+			report.add(SeverityWarning, "MCP_UNAUTHENTICATED_METHODS", "tools/call is allowed without a token, so anyone can call tools")
+		}
+	}
+	if c.MaxRequestBodyBytes <= 0 {
+		report.add(SeverityFatal, "MCP_MAX_BODY_BYTES", "maximum request body size must be positive")
+	}
+
+	// Validate authorization flow lifetimes
+	if c.AuthStateTTL <= 0 {
+		report.add(SeverityFatal, "AUTH_STATE_TTL_SECONDS", "authorization state lifetime must be positive")
+	}
+	if c.AuthCodeTTL <= 0 {
+		report.add(SeverityFatal, "AUTH_CODE_TTL_SECONDS", "authorization code lifetime must be positive")
+	} else if c.AuthCodeTTL > maxRecommendedAuthCodeTTL {
+		report.add(SeverityWarning, "AUTH_CODE_TTL_SECONDS", "authorization code lifetime of %s is longer than the recommended %s",
+			c.AuthCodeTTL, maxRecommendedAuthCodeTTL)
+	}
+
+	// Validate clock skew tolerance
+	if c.ClockSkew < 0 {
+		report.add(SeverityFatal, "CLOCK_SKEW_SECONDS", "clock skew tolerance can't be negative")
+	} else if c.ClockSkew > maxRecommendedClockSkew {
+		report.add(SeverityWarning, "CLOCK_SKEW_SECONDS", "clock skew tolerance of %s keeps expired tokens usable for longer than the recommended %s",
+			c.ClockSkew, maxRecommendedClockSkew)
+	}
+
+	// Validate client registration lifetimes
+	if c.ClientTTL < 0 {
+		report.add(SeverityFatal, "CLIENT_TTL_SECONDS", "client lifetime can't be negative")
+	}
+	if c.AutoRegisteredClientTTL < 0 {
+		report.add(SeverityFatal, "AUTO_REGISTERED_CLIENT_TTL_SECONDS", "auto-registered client lifetime can't be negative")
+	}
+
+	// Validate storage backend
+	switch c.StorageBackend {
+	case StorageBackendMemory:
+		if parsedURL != nil && !isLocalhost(parsedURL.Host) {
+			report.add(SeverityWarning, "STORAGE_BACKEND", "in-memory storage loses all clients and tokens on restart")
+		}
+	case StorageBackendDynamoDB:
+		if c.DynamoDBTableName == "" {
+			report.add(SeverityFatal, "DYNAMODB_TABLE_NAME", "table name is required when STORAGE_BACKEND is dynamodb")
+		}
+	case StorageBackendRedis:
+		if c.RedisURL == "" {
+			report.add(SeverityFatal, "REDIS_URL", "Redis URL is required when STORAGE_BACKEND is redis")
+		} else if _, err := redis.ParseURL(c.RedisURL); err != nil {
+			report.add(SeverityFatal, "REDIS_URL", "invalid Redis URL: %v", err)
+		}
+	case StorageBackendPostgres:
+		if c.PostgresURL == "" {
+			report.add(SeverityFatal, "POSTGRES_URL", "Postgres URL is required when STORAGE_BACKEND is postgres")
+		} else if _, err := pgxpool.ParseConfig(c.PostgresURL); err != nil {
+			report.add(SeverityFatal, "POSTGRES_URL", "invalid Postgres URL: %v", err)
+		}
+		if c.PostgresTableName == "" {
+			report.add(SeverityFatal, "POSTGRES_TABLE_NAME", "table name is required when STORAGE_BACKEND is postgres")
+		}
+	default:
+		report.add(SeverityFatal, "STORAGE_BACKEND", "unknown storage backend %q (use memory, dynamodb, redis, or postgres)", c.StorageBackend)
+	}
+
+	return report
+}
@@ -0,0 +1,93 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// otherVariables describes the environment variables read outside LoadConfigFromEnv, by the
+// server, its tools, and the packages they use; their defaults live next to the code reading
+// them, so keep these in step when changing one
+var otherVariables = []ConfigVariable{
+	{Name: "ACCESS_LOG_FORMAT", Type: "string", Description: "Write access logs in common or combined log format (disabled when unset)"},
+	{Name: "ACME_CACHE_DIR", Type: "string", Default: "acme-cache", Description: "Directory where Let's Encrypt certificates are cached"},
+	{Name: "ACME_DOMAINS", Type: "list", Description: "Comma-separated domains to serve HTTPS for with Let's Encrypt certificates"},
+	{Name: "ACME_EMAIL", Type: "string", Description: "Contact email registered with Let's Encrypt"},
+	{Name: "ACME_HTTP_ADDR", Type: "string", Default: ":80", Description: "Listener for HTTP-01 challenges, which also redirects plain HTTP to HTTPS"},
+	{Name: "ADMIN_API_TOKEN", Type: "string", Description: "Static bearer token accepted by the admin API in addition to mcp:admin tokens", Secret: true},
+	{Name: "ADMIN_GRAPHQL_ENABLED", Type: "bool", Default: "false", Description: "Serve the GraphQL API at /admin/graphql"},
+	{Name: "ANOMALY_INVALID_TOKEN_THRESHOLD", Type: "int", Default: "100", Description: "Failed token validations within the window that raise an anomaly (0 disables)"},
+	{Name: "ANOMALY_PKCE_FAILURE_THRESHOLD", Type: "int", Default: "20", Description: "Failed PKCE verifications within the window that raise an anomaly (0 disables)"},
+	{Name: "ANOMALY_UNKNOWN_CLIENT_THRESHOLD", Type: "int", Default: "20", Description: "Token requests for unknown clients from one IP within the window that raise an anomaly (0 disables)"},
+	{Name: "ANOMALY_WINDOW_SECONDS", Type: "seconds", Default: "300", Description: "Window the anomaly detector counts failures over (0 disables detection)"},
+	{Name: "AUDIT_WEBHOOK_EVENTS", Type: "list", Default: "token_issued,token_revoked,anomaly_detected", Description: "Comma-separated audit event types sent to the webhooks"},
+	{Name: "AUDIT_WEBHOOK_SECRET", Type: "string", Description: "HMAC-SHA256 key signing webhook deliveries", Secret: true},
+	{Name: "AUDIT_WEBHOOK_URLS", Type: "list", Description: "Comma-separated URLs token events are posted to"},
+	{Name: "AWS_DEFAULT_REGION", Type: "string", Description: "AWS region used when AWS_REGION is unset"},
+	{Name: "AWS_MONTHLY_BUDGET", Type: "string", Description: "Monthly AWS budget in dollars reported against by get-aws-costs"},
+	{Name: "AWS_REGION", Type: "string", Description: "AWS region used when the instance metadata doesn't give one"},
+	{Name: "CLOUDFORMATION_STACK_NAMES", Type: "list", Description: "Comma-separated CloudFormation stacks inspected by get-deployment-status"},
+	{Name: "CLOUDWATCH_LOG_GROUPS", Type: "list", Description: "Comma-separated log groups readable by tail-logs"},
+	{Name: "CONFIG_FILE", Type: "string", Description: "YAML or JSON file of environment variable settings"},
+	{Name: "CONFIG_RELOAD_INTERVAL_SECONDS", Type: "seconds", Description: "How often to reload the configuration (3600 with GITHUB_OAUTH_SECRET_NAME, otherwise 0 for SIGHUP only)"},
+	{Name: "CORS_ALLOWED_ORIGINS", Type: "list", Default: "http://localhost:6277,http://localhost:6274", Description: "Comma-separated origins allowed to call the MCP endpoint with credentials"},
+	{Name: "CORS_MAX_AGE_SECONDS", Type: "seconds", Default: "3600", Description: "How long browsers may cache preflight results"},
+	{Name: "CORS_OAUTH_ALLOWED_ORIGINS", Type: "list", Default: "*", Description: "Comma-separated origins allowed to call the token and registration endpoints"},
+	{Name: "ECS_CLUSTER_NAME", Type: "string", Description: "ECS cluster inspected by get-deployment-status"},
+	{Name: "ECS_CONTAINER_METADATA_URI_V4", Type: "string", Description: "ECS task metadata endpoint, set by ECS"},
+	{Name: "ECS_SERVICE_NAME", Type: "list", Description: "Comma-separated ECS services inspected by get-deployment-status"},
+	{Name: "EXCHANGE_RATE_API_KEY", Type: "string", Description: "Bearer token sent to the exchange rate API", Secret: true},
+	{Name: "EXCHANGE_RATE_API_URL", Type: "string", Default: "https://api.frankfurter.app/latest", Description: "Exchange rate API used by convert-currency"},
+	{Name: "EXCHANGE_RATE_CACHE_SECONDS", Type: "seconds", Default: "3600", Description: "How long fetched exchange rates are used"},
+	{Name: "GITHUB_OAUTH_SECRET_NAME", Type: "string", Description: "Secrets Manager secret holding the GitHub OAuth App credentials"},
+	{Name: "HOST", Type: "string", Default: "0.0.0.0", Description: "Address to listen on"},
+	{Name: "HTTP2_ENABLED", Type: "bool", Default: "false", Description: "Also accept unencrypted HTTP/2 (h2c)"},
+	{Name: "HTTP_IDLE_TIMEOUT_SECONDS", Type: "seconds", Default: "120", Description: "Keep-alive idle timeout"},
+	{Name: "HTTP_MAX_HEADER_BYTES", Type: "int", Default: "1048576", Description: "Maximum size of request headers"},
+	{Name: "HTTP_READ_HEADER_TIMEOUT_SECONDS", Type: "seconds", Default: "10", Description: "Time allowed to read request headers"},
+	{Name: "HTTP_READ_TIMEOUT_SECONDS", Type: "seconds", Default: "0", Description: "Time allowed to read a whole request (0 for none)"},
+	{Name: "HTTP_WRITE_TIMEOUT_SECONDS", Type: "seconds", Default: "0", Description: "Time allowed to write a whole response (0 for none)"},
+	{Name: "IMDS_ENABLED", Type: "bool", Default: "true", Description: "Look up instance metadata from the EC2 instance metadata service when not on ECS"},
+	{Name: "MAINTENANCE_MESSAGE", Type: "string", Description: "Message shown to clients during maintenance"},
+	{Name: "MAINTENANCE_MODE", Type: "bool", Default: "false", Description: "Start with maintenance mode enabled"},
+	{Name: "MCP_PERSONAS", Type: "string", Description: "Semicolon-separated name(scopes)=tool,tool entries for extra MCP servers at /mcp/<name>"},
+	{Name: "METERING_EXPORT", Type: "string", Description: "Directory or s3://bucket/prefix usage records are exported to (disabled when unset)"},
+	{Name: "METERING_FORMAT", Type: "string", Default: "csv", Description: "Usage file format, csv or stripe"},
+	{Name: "METERING_INTERVAL_SECONDS", Type: "seconds", Default: "3600", Description: "How often usage is exported"},
+	{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Type: "string", Description: "OTLP/HTTP collector traces are exported to (disabled when unset)"},
+	{Name: "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", Type: "string", Description: "OTLP/HTTP traces endpoint, overriding OTEL_EXPORTER_OTLP_ENDPOINT"},
+	{Name: "OTEL_SERVICE_NAME", Type: "string", Default: "mcp-server", Description: "Service name reported on exported spans"},
+	{Name: "POLICY_OPA_URL", Type: "string", Description: "Open Policy Agent rule evaluated before every tool call (disabled when unset)"},
+	{Name: "PORT", Type: "int", Default: "8080", Description: "Port to listen on (443 when serving HTTPS)"},
+	{Name: "RATE_LIMIT_IP_BURST", Type: "int", Default: "60", Description: "Requests a client IP may send at once"},
+	{Name: "RATE_LIMIT_IP_PER_MINUTE", Type: "int", Default: "300", Description: "MCP requests allowed per client IP per minute (0 disables)"},
+	{Name: "RATE_LIMIT_TOKEN_BURST", Type: "int", Default: "30", Description: "Requests an access token may send at once"},
+	{Name: "RATE_LIMIT_TOKEN_PER_MINUTE", Type: "int", Default: "120", Description: "MCP requests allowed per access token per minute (0 disables)"},
+	{Name: "RATE_LIMIT_TRUST_FORWARDED_FOR", Type: "bool", Default: "false", Description: "Take the client IP from X-Forwarded-For"},
+	{Name: "RATE_LIMIT_WARN_PERCENT", Type: "int", Default: "80", Description: "Share of the per-token burst after which users are warned (0 disables)"},
+	{Name: "RUNBOOKS_DIR", Type: "string", Description: "Directory of Markdown runbooks replacing the built-in ones"},
+	{Name: "SECRETS_FETCH_ATTEMPTS", Type: "int", Default: "5", Description: "Tries at reading a Secrets Manager secret"},
+	{Name: "SECRETS_FETCH_TIMEOUT_SECONDS", Type: "seconds", Default: "30", Description: "Time allowed for reading a secret, across all attempts"},
+	{Name: "SLOW_REQUEST_THRESHOLD_MS", Type: "int", Default: "2000", Description: "Requests and tool calls slower than this many milliseconds are logged (0 disables)"},
+	{Name: "SSE_HEARTBEAT_SECONDS", Type: "seconds", Default: "25", Description: "Ping event streams silent for this long (0 disables)"},
+	{Name: "SSE_REPLAY_BUFFER_BYTES", Type: "int", Default: "10485760", Description: "Memory kept for replaying stream events to reconnecting clients (0 disables)"},
+	{Name: "TLS_CERT_FILE", Type: "string", Description: "PEM certificate to serve HTTPS with"},
+	{Name: "TLS_KEY_FILE", Type: "string", Description: "PEM key to serve HTTPS with"},
+	{Name: "TOOLS_DISABLED", Type: "list", Description: "Comma-separated tools not to serve"},
+	{Name: "TOOLS_ENABLED", Type: "list", Description: "Comma-separated tools to serve, leaving out every other tool"},
+	{Name: "TOOL_CACHE_TTLS", Type: "list", Description: "Comma-separated tool=duration entries caching a tool's results"},
+	{Name: "TOOL_ROLLOUT", Type: "list", Description: "Comma-separated tool=N% or tool=@login entries limiting tools to some users"},
+	{Name: "UPDATE_CHECK_INTERVAL_HOURS", Type: "int", Default: "24", Description: "Hours between checks for a newer release (0 checks only at startup)"},
+	{Name: "UPDATE_CHECK_REPO", Type: "string", Description: "GitHub repository whose releases are checked for a newer version"},
+	{Name: "USE_HTTPS", Type: "bool", Default: "false", Description: "Derive an https server URL from HOST and PORT when MCP_SERVER_URL is unset"},
+}
+
+// logFileVariables describes the variables configuring the log file of stream, e.g. ACCESS
+func logFileVariables(stream, description string) []ConfigVariable {
+	return []ConfigVariable{
+		{Name: stream + "_LOG_FILE", Type: "string", Description: description},
+		{Name: stream + "_LOG_MAX_SIZE_MB", Type: "int", Description: "Rotate the " + stream + " log file once it reaches this size"},
+		{Name: stream + "_LOG_ROTATE_INTERVAL", Type: "duration", Description: "Rotate the " + stream + " log file once it is this old"},
+		{Name: stream + "_LOG_COMPRESS", Type: "bool", Default: "false", Description: "Gzip rotated " + stream + " log files"},
+		{Name: stream + "_LOG_MAX_BACKUPS", Type: "int", Default: "0", Description: "Rotated " + stream + " log files to keep (0 keeps all)"},
+	}
+}
@@ -0,0 +1,269 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// consentPrefix keys flows waiting for the user's consent among authorization states
+	consentPrefix = "consent:"
+
+	// consentCookiePrefix names the cookies remembering which clients the user approved
+	consentCookiePrefix = "mcp_consent_"
+
+	// rememberedConsentTTL is how long an approval spares the user the consent page
+	rememberedConsentTTL = 30 * 24 * time.Hour
+)
+
+// scopeDescriptions explain scopes on the consent page; other scopes are shown as they are
+var scopeDescriptions = map[string]string{
+	"mcp:tools":     "Call tools on your behalf",
+	"mcp:resources": "Read resources such as runbooks",
+	"read:user":     "Read your GitHub profile",
+	"mcp:admin":     "Administer this server",
+}
+
+// consentPage asks the user to approve a client and the scopes it requested, before they are
+// sent to GitHub
+var consentPage = template.Must(template.New("consent").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Authorize {{.ClientName}}</title></head>
+<body>
+<h1>Authorize {{.ClientName}}</h1>
+<p>{{.ClientName}} is asking to:</p>
+<ul>
+{{range .Scopes}}<li>{{.}}</li>
+{{end}}</ul>
+{{if .Resource}}<p>on {{.Resource}}</p>{{end}}
+{{if .RedirectURI}}<p>Approving signs you in with GitHub, then returns you to <code>{{.RedirectURI}}</code>.</p>
+{{else}}<p>Approving signs you in with GitHub, then connects the device showing your code.</p>
+{{end}}<form method="post" action="{{.Action}}">
+<input type="hidden" name="consent" value="{{.ConsentID}}">
+<button type="submit" name="action" value="approve">Approve</button>
+<button type="submit" name="action" value="deny">Deny</button>
+</form>
+</body>
+</html>
+`))
+
+// consentPageData fills consentPage
+type consentPageData struct {
+	Action      string
+	ConsentID   string
+	ClientName  string
+	Scopes      []string
+	Resource    string
+	RedirectURI string
+}
+
+// askConsent keeps authState until the user decides, and shows them the consent page, which
+// posts their decision to action
+func askConsent(w http.ResponseWriter, clients ClientStorage, stateStore StateStorage, authState *AuthState, action string) error {
+	client, err := clients.GetClient(authState.ClientID)
+	if err != nil || client == nil {
+		return fmt.Errorf("client %s is no longer registered", authState.ClientID)
+	}
+
+	consentID, err := generateRandomString(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate consent ID: %w", err)
+	}
+	if err := stateStore.Store(consentPrefix+consentID, authState); err != nil {
+		return fmt.Errorf("failed to store consent request: %w", err)
+	}
+
+	data := consentPageData{
+		Action:      action,
+		ConsentID:   consentID,
+		ClientName:  client.Metadata.ClientName,
+		Resource:    authState.Resource,
+		RedirectURI: authState.RedirectURI,
+	}
+	if data.ClientName == "" {
+		data.ClientName = client.ClientID
+	}
+	for _, s := range strings.Fields(authState.Scope) {
+		if description, ok := scopeDescriptions[s]; ok {
+			s = description
+		}
+		data.Scopes = append(data.Scopes, s)
+	}
+
+	// The page must not be framed, or another site could trick the user into approving
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+	w.Header().Set("Content-Security-Policy", "frame-ancestors 'none'")
+	if err := consentPage.Execute(w, data); err != nil {
+		log.Printf("Failed to render consent page: %v", err)
+	}
+	return nil
+}
+
+// redeemConsent returns the flow the consent page posted a decision for, and whether the user
+// approved it; ok is false for unknown or expired consents
+// The consent ID is unguessable and redeemed once, so it also protects the form against CSRF
+func redeemConsent(r *http.Request, stateStore StateStorage) (authState *AuthState, approved, ok bool) {
+	consentID := r.PostFormValue("consent")
+	if consentID == "" {
+		return nil, false, false
+	}
+	authState, ok = stateStore.Consume(consentPrefix + consentID)
+	if !ok {
+		return nil, false, false
+	}
+
+	approved = r.PostFormValue("action") == "approve"
+	authState.ConsentApproved = approved
+	if approved {
+		log.Printf("[OAUTH] User approved client %s (scope %q)", authState.ClientID, authState.Scope)
+	} else {
+		log.Printf("[OAUTH] User denied client %s", authState.ClientID)
+	}
+	return authState, approved, true
+}
+
+// redirectToGitHub keeps authState under a new state and sends the user to GitHub with it
+func redirectToGitHub(w http.ResponseWriter, r *http.Request, config *Config, stateStore StateStorage, authState *AuthState) error {
+	internalState, err := generateRandomString(32)
+	if err != nil {
+		return fmt.Errorf("failed to generate state: %w", err)
+	}
+	if err := stateStore.Store(internalState, authState); err != nil {
+		return fmt.Errorf("failed to store authorization state: %w", err)
+	}
+
+	githubAuthURL, err := githubAuthorizeURL(config, internalState)
+	if err != nil {
+		return fmt.Errorf("invalid GitHub auth URL: %w", err)
+	}
+	http.Redirect(w, r, githubAuthURL, http.StatusFound)
+	return nil
+}
+
+// rememberedConsent is what a consent cookie records: that Login approved ClientID for Scopes
+type rememberedConsent struct {
+	Login     string    `json:"login"`
+	ClientID  string    `json:"client_id"`
+	Scopes    []string  `json:"scopes"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// skipRememberedConsent reports whether the browser remembers an approval of authState's client
+// for all of its scopes, and if so marks authState with the approving login for the callback to check
+// The user isn't known until GitHub signs them in, so the approval is only a claim until then
+func skipRememberedConsent(r *http.Request, config *Config, authState *AuthState) bool {
+	cookie, err := r.Cookie(consentCookieName(authState.ClientID))
+	if err != nil {
+		return false
+	}
+	consent, ok := verifyConsentCookie(config, cookie.Value)
+	if !ok || consent.ClientID != authState.ClientID || config.now().After(consent.ExpiresAt) {
+		return false
+	}
+	for _, scope := range strings.Fields(authState.Scope) {
+		if !slices.Contains(consent.Scopes, scope) {
+			return false
+		}
+	}
+	authState.ConsentLogin = consent.Login
+	return true
+}
+
+// rememberConsent sets a cookie sparing login the consent page for authState's client and scopes
+func rememberConsent(w http.ResponseWriter, config *Config, login string, authState *AuthState) {
+	scopes := strings.Fields(authState.Scope)
+	slices.Sort(scopes)
+	data, err := json.Marshal(rememberedConsent{
+		Login:     login,
+		ClientID:  authState.ClientID,
+		Scopes:    slices.Compact(scopes),
+		ExpiresAt: config.now().Add(rememberedConsentTTL),
+	})
+	if err != nil {
+		log.Printf("Failed to encode remembered consent: %v", err)
+		return
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	http.SetCookie(w, &http.Cookie{
+		Name:     consentCookieName(authState.ClientID),
+		Value:    payload + "." + signConsent(config, payload),
+		Path:     "/oauth",
+		MaxAge:   int(rememberedConsentTTL.Seconds()),
+		Secure:   strings.HasPrefix(config.ServerURL, "https://"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// forgetConsent clears the cookie remembering an approval of clientID
+func forgetConsent(w http.ResponseWriter, clientID string) {
+	http.SetCookie(w, &http.Cookie{Name: consentCookieName(clientID), Path: "/oauth", MaxAge: -1})
+}
+
+// consentCookieName names the cookie remembering approvals of clientID; client IDs may contain
+// characters cookie names can't, so they are hashed
+func consentCookieName(clientID string) string {
+	hash := sha256.Sum256([]byte(clientID))
+	return consentCookiePrefix + hex.EncodeToString(hash[:8])
+}
+
+// verifyConsentCookie returns the consent recorded in a cookie value, if it was signed by this server
+func verifyConsentCookie(config *Config, value string) (*rememberedConsent, bool) {
+	payload, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(signConsent(config, payload))) {
+		return nil, false
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, false
+	}
+	var consent rememberedConsent
+	if err := json.Unmarshal(data, &consent); err != nil {
+		return nil, false
+	}
+	return &consent, true
+}
+
+// signConsent signs a consent cookie payload
+func signConsent(config *Config, payload string) string {
+	mac := hmac.New(sha256.New, consentKey(config))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+var (
+	processConsentKey     []byte
+	processConsentKeyOnce sync.Once
+)
+
+// consentKey returns the key consent cookies are signed with, derived from the GitHub client
+// secret so every replica shares it; without one, a key only this process knows is used
+func consentKey(config *Config) []byte {
+	if config.GitHubClientSecret != "" {
+		key := sha256.Sum256([]byte("mcp consent cookie\x00" + config.GitHubClientSecret))
+		return key[:]
+	}
+	processConsentKeyOnce.Do(func() {
+		processConsentKey = make([]byte, 32)
+		_, _ = rand.Read(processConsentKey)
+	})
+	return processConsentKey
+}
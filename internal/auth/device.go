@@ -0,0 +1,358 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DeviceCodeGrantType is the grant type of token requests for device codes (RFC 8628)
+const DeviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+const (
+	// devicePollInterval is how often clients may poll the token endpoint for a device code
+	devicePollInterval = 5 * time.Second
+
+	// devicePrefix keys device codes among authorization codes, and user codes waiting to be
+	// entered among authorization states; generated codes and states never contain a colon
+	devicePrefix = "device:"
+
+	// devicePollPrefix keys the record of when a device code was last polled among authorization codes
+	devicePollPrefix = "device-poll:"
+
+	// userCodeAlphabet has no vowels, so user codes don't spell words, and no look-alike digits
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+// DeviceAuthorizationHandler starts device authorization flows (RFC 8628) for clients without a
+// browser, such as terminal MCP clients on remote hosts
+// The device code is kept with authorization codes and the user code with authorization states,
+// so with a shared storage backend (dynamodb, redis, or postgres) any instance can serve the
+// verification page, the GitHub callback, and the client's polls; memory storage keeps the whole
+// flow on one instance
+type DeviceAuthorizationHandler struct {
+	config        *Config
+	clientStorage ClientStorage
+	tokenStorage  TokenStorage
+	stateStore    StateStorage
+}
+
+// NewDeviceAuthorizationHandler creates a new device authorization handler
+func NewDeviceAuthorizationHandler(config *Config, clientStorage ClientStorage, tokenStorage TokenStorage, stateStore StateStorage) *DeviceAuthorizationHandler {
+	return &DeviceAuthorizationHandler{
+		config:        config,
+		clientStorage: clientStorage,
+		tokenStorage:  tokenStorage,
+		stateStore:    stateStore,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *DeviceAuthorizationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendError(w, "invalid_request", "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		h.sendError(w, "invalid_request", "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	clientID := r.FormValue("client_id")
+	if clientID == "" {
+		h.sendError(w, "invalid_request", "client_id is required", http.StatusBadRequest)
+		return
+	}
+	client, err := h.clientStorage.GetClient(clientID)
+	if err != nil || client == nil {
+		h.sendError(w, "invalid_client", "Unknown client_id", http.StatusUnauthorized)
+		return
+	}
+	if !slices.Contains(client.Metadata.GrantTypes, DeviceCodeGrantType) {
+		h.sendError(w, "unauthorized_client", "Client is not registered for the device_code grant", http.StatusBadRequest)
+		return
+	}
+
+	scope := r.FormValue("scope")
+	if scope == "" {
+		scope = "mcp:tools mcp:resources read:user"
+	}
+	for _, s := range strings.Split(scope, " ") {
+		if !h.config.IsScopeSupported(s) {
+			h.sendError(w, "invalid_scope", fmt.Sprintf("Scope '%s' is not supported", s), http.StatusBadRequest)
+			return
+		}
+	}
+
+	deviceCode, err := generateRandomString(32)
+	if err != nil {
+		h.sendError(w, "server_error", "Failed to generate device code", http.StatusInternalServerError)
+		return
+	}
+	userCode, err := generateUserCode()
+	if err != nil {
+		h.sendError(w, "server_error", "Failed to generate user code", http.StatusInternalServerError)
+		return
+	}
+
+	// The device code lives as long as the flow may wait for the GitHub callback
+	now := h.config.now()
+	err = h.tokenStorage.StoreAuthCode(devicePrefix+deviceCode, &AuthCodeInfo{
+		ClientID:  clientID,
+		Scope:     scope,
+		Resource:  r.FormValue("resource"),
+		ExpiresAt: now.Add(h.config.AuthStateTTL),
+		CreatedAt: now,
+	})
+	if err != nil {
+		log.Printf("Failed to store device code: %v", err)
+		h.sendError(w, "server_error", "Failed to store device code", http.StatusInternalServerError)
+		return
+	}
+	err = h.stateStore.Store(devicePrefix+userCode, &AuthState{
+		ClientID:   clientID,
+		Scope:      scope,
+		Resource:   r.FormValue("resource"),
+		DeviceCode: deviceCode,
+		CreatedAt:  now,
+	})
+	if err != nil {
+		log.Printf("Failed to store user code: %v", err)
+		h.sendError(w, "server_error", "Failed to store user code", http.StatusInternalServerError)
+		return
+	}
+
+	verificationURI := h.config.ServerURL + "/oauth/device"
+	response := map[string]interface{}{
+		"device_code":               deviceCode,
+		"user_code":                 formatUserCode(userCode),
+		"verification_uri":          verificationURI,
+		"verification_uri_complete": verificationURI + "?user_code=" + url.QueryEscape(formatUserCode(userCode)),
+		"expires_in":                int(h.config.AuthStateTTL.Seconds()),
+		"interval":                  int(devicePollInterval.Seconds()),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode device authorization response: %v", err)
+	}
+}
+
+// sendError sends an OAuth error response
+func (h *DeviceAuthorizationHandler) sendError(w http.ResponseWriter, errorCode, errorDescription string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := map[string]string{
+		"error":             errorCode,
+		"error_description": errorDescription,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode error response: %v", err)
+	}
+}
+
+// DeviceVerificationHandler serves the page where users enter the code shown by their device,
+// then sends them through the GitHub authorization flow
+type DeviceVerificationHandler struct {
+	config     *Config
+	stateStore StateStorage
+	// Set when users are asked to approve the client before they are sent to GitHub
+	clients ClientStorage
+	tokens  TokenStorage
+}
+
+// NewDeviceVerificationHandler creates a new device verification handler
+func NewDeviceVerificationHandler(config *Config, stateStore StateStorage) *DeviceVerificationHandler {
+	return &DeviceVerificationHandler{
+		config:     config,
+		stateStore: stateStore,
+	}
+}
+
+// EnableConsent asks users to approve the client and its scopes once they have entered their
+// code, before they are sent to GitHub; tokens holds the device codes, so a denied device is told
+// on its next poll
+func (h *DeviceVerificationHandler) EnableConsent(clients ClientStorage, tokens TokenStorage) {
+	h.clients = clients
+	h.tokens = tokens
+}
+
+// devicePage is the device verification page, also used to report the outcome of the flow
+var devicePage = template.Must(template.New("device").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Connect a device</title></head>
+<body>
+{{if .Message}}<p>{{.Message}}</p>{{else}}
+<h1>Connect a device</h1>
+{{if .Error}}<p><strong>{{.Error}}</strong></p>{{end}}
+<form method="post" action="/oauth/device">
+<label>Code shown on your device <input name="user_code" value="{{.UserCode}}" autocomplete="off" autofocus></label>
+<button type="submit">Continue with GitHub</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+// devicePageData fills devicePage
+type devicePageData struct {
+	UserCode string
+	Error    string
+	Message  string
+}
+
+// renderDevicePage writes the device page with status
+func renderDevicePage(w http.ResponseWriter, status int, data devicePageData) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := devicePage.Execute(w, data); err != nil {
+		log.Printf("Failed to render device page: %v", err)
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *DeviceVerificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		renderDevicePage(w, http.StatusOK, devicePageData{UserCode: r.URL.Query().Get("user_code")})
+	case http.MethodPost:
+		if r.PostFormValue("consent") != "" {
+			h.decideConsent(w, r)
+			return
+		}
+		h.verify(w, r)
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// verify redeems the entered user code and asks for consent or redirects to GitHub
+// A user code is redeemed once, so nobody else can complete the flow with it afterwards
+func (h *DeviceVerificationHandler) verify(w http.ResponseWriter, r *http.Request) {
+	entered := r.FormValue("user_code")
+	userCode := normalizeUserCode(entered)
+	authState, ok := h.stateStore.Consume(devicePrefix + userCode)
+	if userCode == "" || !ok {
+		renderDevicePage(w, http.StatusBadRequest, devicePageData{UserCode: entered, Error: "That code is invalid or has expired."})
+		return
+	}
+
+	if h.clients != nil && !skipRememberedConsent(r, h.config, authState) {
+		if err := askConsent(w, h.clients, h.stateStore, authState, "/oauth/device"); err != nil {
+			log.Printf("Failed to ask for consent: %v", err)
+			http.Error(w, "Failed to store consent request", http.StatusInternalServerError)
+		}
+		return
+	}
+	if err := redirectToGitHub(w, r, h.config, h.stateStore, authState); err != nil {
+		log.Printf("Failed to start GitHub authorization: %v", err)
+		http.Error(w, "Failed to start GitHub authorization", http.StatusInternalServerError)
+	}
+}
+
+// decideConsent handles the user's answer on the consent page, sending them on to GitHub if
+// they approved, and otherwise telling the device it was denied
+func (h *DeviceVerificationHandler) decideConsent(w http.ResponseWriter, r *http.Request) {
+	authState, approved, ok := redeemConsent(r, h.stateStore)
+	if !ok || authState.DeviceCode == "" || h.tokens == nil {
+		renderDevicePage(w, http.StatusBadRequest, devicePageData{Error: "That request is invalid or has expired. Enter the code again."})
+		return
+	}
+	if !approved {
+		denyDeviceCode(h.tokens, authState.DeviceCode)
+		renderDevicePage(w, http.StatusOK, devicePageData{Message: "The device was not connected. You can close this window."})
+		return
+	}
+	if err := redirectToGitHub(w, r, h.config, h.stateStore, authState); err != nil {
+		log.Printf("Failed to start GitHub authorization: %v", err)
+		http.Error(w, "Failed to start GitHub authorization", http.StatusInternalServerError)
+	}
+}
+
+// approveDevice attaches the user's GitHub token to the device code, so the device's next poll
+// gets an access token
+func (h *CallbackHandler) approveDevice(w http.ResponseWriter, authState *AuthState, githubToken, subject string) {
+	info, err := h.tokenStorage.GetAuthCode(devicePrefix + authState.DeviceCode)
+	if err != nil {
+		renderDevicePage(w, http.StatusBadRequest, devicePageData{Message: "The device code has expired. Start again on your device."})
+		return
+	}
+
+	approved := *info
+	approved.GitHubAccessToken = githubToken
+	approved.Subject = subject
+	approved.Scope = authState.Scope
+	if err := h.tokenStorage.StoreAuthCode(devicePrefix+authState.DeviceCode, &approved); err != nil {
+		log.Printf("Failed to store device code: %v", err)
+		http.Error(w, "Failed to store device authorization", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[OAUTH] Approved device authorization for client %s", authState.ClientID)
+	renderDevicePage(w, http.StatusOK, devicePageData{Message: "Your device is connected. You can close this window and return to it."})
+}
+
+// denyDevice marks a device code as denied, so the device stops polling
+func (h *CallbackHandler) denyDevice(deviceCode string) {
+	denyDeviceCode(h.tokenStorage, deviceCode)
+}
+
+// denyDeviceCode marks deviceCode as denied in tokens
+func denyDeviceCode(tokens TokenStorage, deviceCode string) {
+	info, err := tokens.GetAuthCode(devicePrefix + deviceCode)
+	if err != nil {
+		return
+	}
+	denied := *info
+	denied.Denied = true
+	if err := tokens.StoreAuthCode(devicePrefix+deviceCode, &denied); err != nil {
+		log.Printf("Failed to store device code: %v", err)
+	}
+}
+
+// generateUserCode generates a user code of userCodeLength characters from userCodeAlphabet
+func generateUserCode() (string, error) {
+	code := make([]byte, 0, userCodeLength)
+	b := make([]byte, 1)
+	for len(code) < userCodeLength {
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		// Reject bytes past the last whole multiple of the alphabet, so every letter is equally likely
+		if int(b[0]) >= 256-256%len(userCodeAlphabet) {
+			continue
+		}
+		code = append(code, userCodeAlphabet[int(b[0])%len(userCodeAlphabet)])
+	}
+	return string(code), nil
+}
+
+// formatUserCode splits a user code in two halves, e.g. BCDF-GHJK, to make it easier to type
+func formatUserCode(code string) string {
+	return code[:len(code)/2] + "-" + code[len(code)/2:]
+}
+
+// normalizeUserCode undoes formatUserCode and the usual typing variations
+func normalizeUserCode(entered string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(entered)))
+}
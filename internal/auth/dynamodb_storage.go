@@ -0,0 +1,525 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// DynamoDB item layout: every record lives in a single table keyed by "pk", with the record
+// JSON-encoded in "data" and its expiry in "expires_at" (the table's TTL attribute)
+const (
+	dynamoKeyAttribute     = "pk"
+	dynamoDataAttribute    = "data"
+	dynamoExpiresAttribute = "expires_at"
+
+	dynamoAuthCodePrefix    = "code#"
+	dynamoAccessTokenPrefix = "token#"
+	dynamoClientPrefix      = "client#"
+	dynamoCachePrefix       = "cache#"
+	dynamoStatePrefix       = "state#"
+
+	// dynamoTimeout bounds each DynamoDB call, since the storage interfaces take no context
+	dynamoTimeout = 5 * time.Second
+)
+
+// DynamoDBAPI is the subset of the DynamoDB client used by DynamoDBStorage
+type DynamoDBAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+}
+
+// DynamoDBStorage implements TokenStorage, ClientStorage, and TokenCache on a single DynamoDB
+// table, so tokens and registered clients survive restarts and redeploys
+// Tokens and codes are stored under a hash of their value, never the raw bearer value
+type DynamoDBStorage struct {
+	client DynamoDBAPI
+	table  string
+	clock  clock.Clock
+}
+
+// NewDynamoDBStorage creates a storage backed by table using client
+func NewDynamoDBStorage(client DynamoDBAPI, table string) *DynamoDBStorage {
+	return &DynamoDBStorage{
+		client: client,
+		table:  table,
+		clock:  clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to check expiry
+// DynamoDB TTL deletes expired items lazily, so expiry is always checked on read
+func (s *DynamoDBStorage) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Ping checks the table is reachable by reading a key that is never written
+func (s *DynamoDBStorage) Ping(ctx context.Context) error {
+	_, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.table),
+		Key: map[string]types.AttributeValue{
+			dynamoKeyAttribute: &types.AttributeValueMemberS{Value: "ping#"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("unable to reach DynamoDB table %s: %w", s.table, err)
+	}
+	return nil
+}
+
+// put stores value under key, expiring at expiresAt (if not zero)
+func (s *DynamoDBStorage) put(key string, value any, expiresAt time.Time) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	item := map[string]types.AttributeValue{
+		dynamoKeyAttribute:  &types.AttributeValueMemberS{Value: key},
+		dynamoDataAttribute: &types.AttributeValueMemberS{Value: string(data)},
+	}
+	if !expiresAt.IsZero() {
+		item[dynamoExpiresAttribute] = &types.AttributeValueMemberN{Value: strconv.FormatInt(expiresAt.Unix(), 10)}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoTimeout)
+	defer cancel()
+
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.table),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store item in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// get loads the item under key into value, returning false if it doesn't exist
+func (s *DynamoDBStorage) get(key string, value any) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoTimeout)
+	defer cancel()
+
+	output, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.table),
+		Key: map[string]types.AttributeValue{
+			dynamoKeyAttribute: &types.AttributeValueMemberS{Value: key},
+		},
+		// Codes are single use, so a deleted code must never be read back
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read item from DynamoDB: %w", err)
+	}
+	if output.Item == nil {
+		return false, nil
+	}
+
+	return true, decodeDynamoItem(output.Item, value)
+}
+
+// delete removes the item under key; with mustExist it fails if there was no such item
+func (s *DynamoDBStorage) delete(key string, mustExist bool) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoTimeout)
+	defer cancel()
+
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.table),
+		Key: map[string]types.AttributeValue{
+			dynamoKeyAttribute: &types.AttributeValueMemberS{Value: key},
+		},
+	}
+	if mustExist {
+		input.ConditionExpression = aws.String("attribute_exists(" + dynamoKeyAttribute + ")")
+	}
+
+	if _, err := s.client.DeleteItem(ctx, input); err != nil {
+		var conditionFailed *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionFailed) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to delete item from DynamoDB: %w", err)
+	}
+	return true, nil
+}
+
+// consume deletes the item under key and loads what it held into value, returning false if it
+// didn't exist; DeleteItem returns the old item atomically, so only one caller gets it
+func (s *DynamoDBStorage) consume(key string, value any) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoTimeout)
+	defer cancel()
+
+	output, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.table),
+		Key: map[string]types.AttributeValue{
+			dynamoKeyAttribute: &types.AttributeValueMemberS{Value: key},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to consume item from DynamoDB: %w", err)
+	}
+	if len(output.Attributes) == 0 {
+		return false, nil
+	}
+
+	return true, decodeDynamoItem(output.Attributes, value)
+}
+
+func decodeDynamoItem(item map[string]types.AttributeValue, value any) error {
+	data, ok := item[dynamoDataAttribute].(*types.AttributeValueMemberS)
+	if !ok {
+		return fmt.Errorf("DynamoDB item has no data attribute")
+	}
+	if err := json.Unmarshal([]byte(data.Value), value); err != nil {
+		return fmt.Errorf("failed to decode DynamoDB item: %w", err)
+	}
+	return nil
+}
+
+// StoreAuthCode stores an authorization code
+func (s *DynamoDBStorage) StoreAuthCode(code string, authInfo *AuthCodeInfo) error {
+	return s.put(dynamoAuthCodePrefix+hashSecret(code), authInfo, authInfo.ExpiresAt)
+}
+
+// GetAuthCode retrieves an authorization code
+func (s *DynamoDBStorage) GetAuthCode(code string) (*AuthCodeInfo, error) {
+	var info AuthCodeInfo
+	found, err := s.get(dynamoAuthCodePrefix+hashSecret(code), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired authorization code")
+	}
+	return &info, nil
+}
+
+// DeleteAuthCode deletes an authorization code
+func (s *DynamoDBStorage) DeleteAuthCode(code string) error {
+	_, err := s.delete(dynamoAuthCodePrefix+hashSecret(code), false)
+	return err
+}
+
+// ConsumeAuthCode retrieves and deletes an authorization code
+func (s *DynamoDBStorage) ConsumeAuthCode(code string) (*AuthCodeInfo, error) {
+	var info AuthCodeInfo
+	found, err := s.consume(dynamoAuthCodePrefix+hashSecret(code), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired authorization code")
+	}
+	return &info, nil
+}
+
+// StoreAccessToken stores an access token
+func (s *DynamoDBStorage) StoreAccessToken(token string, tokenInfo *AccessTokenInfo) error {
+	return s.put(dynamoAccessTokenPrefix+hashSecret(token), tokenInfo, tokenInfo.ExpiresAt)
+}
+
+// GetAccessToken retrieves an access token
+func (s *DynamoDBStorage) GetAccessToken(token string) (*AccessTokenInfo, error) {
+	var info AccessTokenInfo
+	found, err := s.get(dynamoAccessTokenPrefix+hashSecret(token), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired access token")
+	}
+	return &info, nil
+}
+
+// RevokeAccessToken deletes an access token
+func (s *DynamoDBStorage) RevokeAccessToken(token string) error {
+	_, err := s.delete(dynamoAccessTokenPrefix+AccessTokenID(token), false)
+	return err
+}
+
+// ListAccessTokens returns the unexpired access tokens
+func (s *DynamoDBStorage) ListAccessTokens() ([]*StoredAccessToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoTimeout)
+	defer cancel()
+
+	var tokens []*StoredAccessToken
+	var startKey map[string]types.AttributeValue
+	for {
+		output, err := s.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:        aws.String(s.table),
+			FilterExpression: aws.String("begins_with(" + dynamoKeyAttribute + ", :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":prefix": &types.AttributeValueMemberS{Value: dynamoAccessTokenPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list access tokens from DynamoDB: %w", err)
+		}
+
+		for _, item := range output.Items {
+			key, ok := item[dynamoKeyAttribute].(*types.AttributeValueMemberS)
+			if !ok || !strings.HasPrefix(key.Value, dynamoAccessTokenPrefix) {
+				continue
+			}
+			token := &StoredAccessToken{ID: strings.TrimPrefix(key.Value, dynamoAccessTokenPrefix)}
+			if err := decodeDynamoItem(item, &token.AccessTokenInfo); err != nil {
+				return nil, err
+			}
+			// DynamoDB deletes expired items up to days late
+			if s.clock.Now().After(token.ExpiresAt) {
+				continue
+			}
+			tokens = append(tokens, token)
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return tokens, nil
+		}
+		startKey = output.LastEvaluatedKey
+	}
+}
+
+// RevokeAccessTokenByID deletes the access token with the given ID
+func (s *DynamoDBStorage) RevokeAccessTokenByID(id string) (*AccessTokenInfo, error) {
+	var info AccessTokenInfo
+	found, err := s.consume(dynamoAccessTokenPrefix+id, &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, ErrAccessTokenNotFound
+	}
+	return &info, nil
+}
+
+// StoreClient stores a registered OAuth client
+func (s *DynamoDBStorage) StoreClient(client *OAuthClient) error {
+	if client == nil {
+		return fmt.Errorf("client cannot be nil")
+	}
+	if client.ClientID == "" {
+		return fmt.Errorf("client ID cannot be empty")
+	}
+
+	var expiresAt time.Time
+	if client.ExpiresAt != nil {
+		expiresAt = *client.ExpiresAt
+	}
+	return s.put(dynamoClientPrefix+client.ClientID, client, expiresAt)
+}
+
+// GetClient retrieves a client by client ID
+func (s *DynamoDBStorage) GetClient(clientID string) (*OAuthClient, error) {
+	var client OAuthClient
+	found, err := s.get(dynamoClientPrefix+clientID, &client)
+	if err != nil {
+		return nil, err
+	}
+	if !found || (client.ExpiresAt != nil && s.clock.Now().After(*client.ExpiresAt)) {
+		return nil, fmt.Errorf("client not found: %s", clientID)
+	}
+	return &client, nil
+}
+
+// DeleteClient removes a client from storage
+func (s *DynamoDBStorage) DeleteClient(clientID string) error {
+	deleted, err := s.delete(dynamoClientPrefix+clientID, true)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("client not found: %s", clientID)
+	}
+	return nil
+}
+
+// ListClients returns all registered clients
+func (s *DynamoDBStorage) ListClients() ([]*OAuthClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dynamoTimeout)
+	defer cancel()
+
+	var clients []*OAuthClient
+	var startKey map[string]types.AttributeValue
+	for {
+		output, err := s.client.Scan(ctx, &dynamodb.ScanInput{
+			TableName:        aws.String(s.table),
+			FilterExpression: aws.String("begins_with(" + dynamoKeyAttribute + ", :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":prefix": &types.AttributeValueMemberS{Value: dynamoClientPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list clients from DynamoDB: %w", err)
+		}
+
+		for _, item := range output.Items {
+			key, ok := item[dynamoKeyAttribute].(*types.AttributeValueMemberS)
+			if !ok || !strings.HasPrefix(key.Value, dynamoClientPrefix) {
+				continue
+			}
+			var client OAuthClient
+			if err := decodeDynamoItem(item, &client); err != nil {
+				return nil, err
+			}
+			// DynamoDB deletes expired items up to days late
+			if client.ExpiresAt != nil && s.clock.Now().After(*client.ExpiresAt) {
+				continue
+			}
+			clients = append(clients, &client)
+		}
+
+		if len(output.LastEvaluatedKey) == 0 {
+			return clients, nil
+		}
+		startKey = output.LastEvaluatedKey
+	}
+}
+
+// ValidateClientSecret checks if the provided secret matches the stored client
+func (s *DynamoDBStorage) ValidateClientSecret(clientID, secret string) (bool, error) {
+	client, err := s.GetClient(clientID)
+	if err != nil {
+		return false, err
+	}
+	return client.ClientSecret == hashSecret(secret), nil
+}
+
+// cachedValidation is the stored form of a TokenValidationResult, whose error can't be JSON encoded
+type cachedValidation struct {
+	Valid      bool            `json:"valid"`
+	ClientID   string          `json:"client_id,omitempty"`
+	Scopes     []string        `json:"scopes,omitempty"`
+	Subject    string          `json:"subject,omitempty"`
+	ExpiresAt  time.Time       `json:"expires_at"`
+	GitHubUser *GitHubUserInfo `json:"github_user,omitempty"`
+	// GitHubScopes are kept so cache hits still carry what the GitHub tools need
+	GitHubScopes []string  `json:"github_scopes,omitempty"`
+	Denied       bool      `json:"denied,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	CachedTill   time.Time `json:"cached_till"`
+}
+
+// Set stores a token validation result with an expiry
+func (s *DynamoDBStorage) Set(token string, result *TokenValidationResult, expiry time.Duration) error {
+	cached := cachedValidation{
+		Valid:        result.Valid,
+		ClientID:     result.ClientID,
+		Scopes:       result.Scopes,
+		Subject:      result.Subject,
+		ExpiresAt:    result.ExpiresAt,
+		GitHubUser:   result.GitHubUser,
+		GitHubScopes: result.GitHubScopes,
+		Denied:       result.AccessDenied,
+		CachedTill:   s.clock.Now().Add(expiry),
+	}
+	if result.Error != nil {
+		cached.Error = result.Error.Error()
+	}
+	return s.put(dynamoCachePrefix+hashSecret(token), cached, cached.CachedTill)
+}
+
+// Get retrieves a cached token validation result
+func (s *DynamoDBStorage) Get(token string) (*TokenValidationResult, bool) {
+	var cached cachedValidation
+	found, err := s.get(dynamoCachePrefix+hashSecret(token), &cached)
+	if err != nil || !found || s.clock.Now().After(cached.CachedTill) {
+		return nil, false
+	}
+
+	result := &TokenValidationResult{
+		Valid:        cached.Valid,
+		ClientID:     cached.ClientID,
+		Scopes:       cached.Scopes,
+		Subject:      cached.Subject,
+		ExpiresAt:    cached.ExpiresAt,
+		GitHubUser:   cached.GitHubUser,
+		GitHubScopes: cached.GitHubScopes,
+		AccessDenied: cached.Denied,
+	}
+	if cached.Error != "" {
+		result.Error = errors.New(cached.Error)
+	}
+	return result, true
+}
+
+// Delete removes a token from the cache
+func (s *DynamoDBStorage) Delete(token string) error {
+	_, err := s.delete(dynamoCachePrefix+hashSecret(token), false)
+	return err
+}
+
+// DynamoDBStateStore implements StateStorage on DynamoDB, so the GitHub callback, device
+// verification, and consent decisions can land on any instance
+type DynamoDBStateStore struct {
+	storage *DynamoDBStorage
+	ttl     time.Duration
+	clock   clock.Clock
+}
+
+// NewDynamoDBStateStore creates a state store in table whose states expire after ttl (10 minutes if zero)
+func NewDynamoDBStateStore(client DynamoDBAPI, table string, ttl time.Duration) *DynamoDBStateStore {
+	if ttl <= 0 {
+		ttl = defaultAuthStateTTL
+	}
+	return &DynamoDBStateStore{
+		storage: NewDynamoDBStorage(client, table),
+		ttl:     ttl,
+		clock:   clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to check expiry
+// DynamoDB TTL deletes expired items lazily, so expiry is always checked on read
+func (s *DynamoDBStateStore) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Store saves an auth state
+func (s *DynamoDBStateStore) Store(state string, authState *AuthState) error {
+	return s.storage.put(dynamoStatePrefix+hashSecret(state), authState, authState.CreatedAt.Add(s.ttl))
+}
+
+// Get retrieves an auth state
+func (s *DynamoDBStateStore) Get(state string) (*AuthState, bool) {
+	var authState AuthState
+	found, err := s.storage.get(dynamoStatePrefix+hashSecret(state), &authState)
+	if err != nil || !found || s.clock.Now().After(authState.CreatedAt.Add(s.ttl)) {
+		return nil, false
+	}
+	return &authState, true
+}
+
+// Delete removes an auth state
+func (s *DynamoDBStateStore) Delete(state string) error {
+	_, err := s.storage.delete(dynamoStatePrefix+hashSecret(state), false)
+	return err
+}
+
+// Consume retrieves and removes an auth state
+func (s *DynamoDBStateStore) Consume(state string) (*AuthState, bool) {
+	var authState AuthState
+	found, err := s.storage.consume(dynamoStatePrefix+hashSecret(state), &authState)
+	if err != nil || !found || s.clock.Now().After(authState.CreatedAt.Add(s.ttl)) {
+		return nil, false
+	}
+	return &authState, true
+}
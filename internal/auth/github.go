@@ -0,0 +1,443 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"go.opentelemetry.io/otel/attribute"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/audit"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/telemetry"
+)
+
+// lastKnownGoodTTL is how long a successful GitHub validation can stand in for a
+// re-verification that was skipped to preserve rate limit budget; it bounds how long a revoked
+// GitHub token or a user removed from an allowed org keeps access while the budget is low
+const lastKnownGoodTTL = 5 * time.Minute
+
+// githubBreaker stops calls to GitHub while it is failing, shared by token verification and code exchange
+var githubBreaker = breaker.New("github", 5, 30*time.Second)
+
+// GitHubTokenVerifier implements the MCP SDK's auth.TokenVerifier interface
+// It validates access tokens issued by our OAuth server
+type GitHubTokenVerifier struct {
+	config       *Config
+	httpClient   *http.Client
+	cache        TokenCache
+	tokenStorage TokenStorage
+	budget       *GitHubBudget
+	jwtIssuer    *JWTIssuer
+
+	verifications     atomic.Int64
+	duplicatesAvoided atomic.Int64
+	cacheHits         atomic.Int64
+	cacheMisses       atomic.Int64
+}
+
+// NewGitHubTokenVerifier creates a new GitHub token verifier
+func NewGitHubTokenVerifier(config *Config, cache TokenCache, tokenStorage TokenStorage) *GitHubTokenVerifier {
+	budget := NewGitHubBudget(config.GitHubAPIBudgetReserve)
+	if config.Clock != nil {
+		budget.SetClock(config.Clock)
+	}
+	return &GitHubTokenVerifier{
+		config: config,
+		httpClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		cache:        cache,
+		tokenStorage: tokenStorage,
+		budget:       budget,
+	}
+}
+
+// SetHTTPClient replaces the client used to call the GitHub API
+func (v *GitHubTokenVerifier) SetHTTPClient(client *http.Client) {
+	v.httpClient = client
+}
+
+// SetJWTIssuer makes the verifier check JWT access tokens locally against the issuer's key
+// Opaque tokens issued before switching formats are still looked up in storage
+func (v *GitHubTokenVerifier) SetJWTIssuer(issuer *JWTIssuer) {
+	v.jwtIssuer = issuer
+}
+
+// Budget returns the GitHub API rate limit budget used by this verifier
+func (v *GitHubTokenVerifier) Budget() *GitHubBudget {
+	return v.budget
+}
+
+// Stats returns the verifier's counters
+func (v *GitHubTokenVerifier) Stats() VerifierStats {
+	return VerifierStats{
+		Verifications:     v.verifications.Load(),
+		DuplicatesAvoided: v.duplicatesAvoided.Load(),
+		CacheHits:         v.cacheHits.Load(),
+		CacheMisses:       v.cacheMisses.Load(),
+	}
+}
+
+// Verify implements auth.TokenVerifier
+// This is called by the MCP SDK's RequireBearerToken middleware
+// Within a context from WithVerificationMemo, each token is only validated once
+func (v *GitHubTokenVerifier) Verify(ctx context.Context, token string, req *http.Request) (info *auth.TokenInfo, err error) {
+	ctx, span := telemetry.Start(ctx, "auth.verify_token")
+	defer func() { telemetry.End(span, err) }()
+
+	memo := verificationMemoFrom(ctx)
+	if memo == nil {
+		info, err = v.verify(ctx, token)
+		auditVerification(req, err)
+		return info, err
+	}
+
+	tokenHash := hashSecret(token)
+	if result, ok := memo.get(tokenHash); ok {
+		v.duplicatesAvoided.Add(1)
+		span.SetAttributes(attribute.Bool("auth.memoized", true))
+		return result.info, result.err
+	}
+
+	info, err = v.verify(ctx, token)
+	memo.put(tokenHash, memoizedVerification{info: info, err: err})
+	auditVerification(req, err)
+	return info, err
+}
+
+// auditVerification records a failed verification of a token presented with req
+// Memoized results were already recorded, so each failure is audited once per request
+func auditVerification(req *http.Request, err error) {
+	if err == nil {
+		return
+	}
+	audit.Default.Record(req, audit.Event{Type: audit.ValidationFailed, Detail: err.Error()})
+}
+
+// expiration is when the SDK stops accepting a token that expires at expiresAt, allowing for
+// the same clock skew as storage
+func (v *GitHubTokenVerifier) expiration(expiresAt time.Time) time.Time {
+	return expiresAt.Add(v.config.ClockSkew)
+}
+
+// verify validates a token against storage and GitHub, or locally if it is one of our JWTs
+func (v *GitHubTokenVerifier) verify(ctx context.Context, token string) (*auth.TokenInfo, error) {
+	v.verifications.Add(1)
+
+	if v.jwtIssuer != nil && LooksLikeJWT(token) {
+		return v.verifyJWT(token)
+	}
+
+	// Look up token in our storage
+	tokenInfo, err := v.tokenStorage.GetAccessToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: token not found or expired", auth.ErrInvalidToken)
+	}
+
+	// Tokens issued to a client act for that client; there is no GitHub user to check
+	if tokenInfo.GrantType == "client_credentials" {
+		return &auth.TokenInfo{
+			Scopes:     strings.Split(tokenInfo.Scope, " "),
+			Expiration: v.expiration(tokenInfo.ExpiresAt),
+			Extra: map[string]any{
+				"subject":   ServiceClientSubject(tokenInfo.ClientID),
+				"client_id": tokenInfo.ClientID,
+				"resource":  tokenInfo.Resource,
+			},
+		}, nil
+	}
+
+	// Check cache for GitHub token validation
+	cacheKey := "github:" + tokenInfo.GitHubAccessToken
+	if v.cache != nil {
+		cached, found := v.cache.Get(cacheKey)
+		if !found {
+			v.cacheMisses.Add(1)
+		} else {
+			v.cacheHits.Add(1)
+			if cached.Valid {
+				// Convert our TokenValidationResult to SDK's TokenInfo
+				return &auth.TokenInfo{
+					Scopes:     strings.Split(tokenInfo.Scope, " "),
+					Expiration: v.expiration(tokenInfo.ExpiresAt),
+					Extra: map[string]any{
+						"github_user":   cached.GitHubUser,
+						"github_token":  tokenInfo.GitHubAccessToken,
+						"github_scopes": cached.GitHubScopes,
+						"subject":       cached.Subject,
+						"client_id":     tokenInfo.ClientID,
+						"resource":      tokenInfo.Resource,
+					},
+				}, nil
+			}
+			// Cached but invalid
+			return nil, validationError(cached)
+		}
+	}
+
+	// Validate GitHub token with GitHub API
+	result, reused := v.validateWithBudget(ctx, tokenInfo.GitHubAccessToken)
+
+	// Cache the GitHub validation result, unless GitHub wasn't called: a reused result must not
+	// outlive lastKnownGoodTTL, and failures to call GitHub say nothing about the token
+	if v.cache != nil && !reused && !errors.Is(result.Error, ErrGitHubBudgetExhausted) && !errors.Is(result.Error, breaker.ErrOpen) {
+		_ = v.cache.Set(cacheKey, result, v.config.TokenExpiryDuration)
+	}
+
+	if !result.Valid {
+		return nil, validationError(result)
+	}
+
+	// Convert to SDK's TokenInfo
+	return &auth.TokenInfo{
+		Scopes:     strings.Split(tokenInfo.Scope, " "),
+		Expiration: v.expiration(tokenInfo.ExpiresAt),
+		Extra: map[string]any{
+			"github_user":   result.GitHubUser,
+			"github_token":  tokenInfo.GitHubAccessToken,
+			"github_scopes": result.GitHubScopes,
+			"subject":       result.Subject,
+			"client_id":     tokenInfo.ClientID,
+			"resource":      tokenInfo.Resource,
+		},
+	}, nil
+}
+
+// validationError converts a failed validation into the error returned by Verify
+func validationError(result *TokenValidationResult) error {
+	if result.AccessDenied {
+		return fmt.Errorf("%w: %v", ErrAccessDenied, result.Error)
+	}
+	return fmt.Errorf("%w: %v", auth.ErrInvalidToken, result.Error)
+}
+
+// verifyJWT verifies a JWT access token without a GitHub API call; storage is only checked for
+// revocations
+// The GitHub user was verified when the token was issued
+func (v *GitHubTokenVerifier) verifyJWT(token string) (*auth.TokenInfo, error) {
+	claims, err := v.jwtIssuer.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", auth.ErrInvalidToken, err)
+	}
+	if jwtRevoked(v.tokenStorage, claims.ID) {
+		return nil, fmt.Errorf("%w: token revoked", auth.ErrInvalidToken)
+	}
+
+	var resource string
+	if len(claims.Audience) > 0 {
+		resource = claims.Audience[0]
+	}
+	return &auth.TokenInfo{
+		Scopes:     strings.Split(claims.Scope, " "),
+		Expiration: v.expiration(claims.ExpiresAt.Time),
+		Extra: map[string]any{
+			"subject":   claims.Subject,
+			"client_id": claims.ClientID,
+			"resource":  resource,
+		},
+	}, nil
+}
+
+// validateWithBudget validates the token with GitHub if the rate limit budget allows it
+// Re-verifying a user with a recent successful validation is non-critical, so when the
+// budget is nearly exhausted the last known good result is reused instead, reported by reused
+func (v *GitHubTokenVerifier) validateWithBudget(ctx context.Context, token string) (result *TokenValidationResult, reused bool) {
+	staleKey := "github-stale:" + token
+
+	var lastKnownGood *TokenValidationResult
+	if v.cache != nil {
+		lastKnownGood, _ = v.cache.Get(staleKey)
+	}
+	if lastKnownGood != nil {
+		ctx = WithNonCriticalGitHubCalls(ctx)
+	}
+
+	result = v.validateWithGitHub(ctx, token)
+	if errors.Is(result.Error, ErrGitHubBudgetExhausted) {
+		if lastKnownGood != nil {
+			log.Printf("[GITHUB] Skipping re-verification of %s: %v", lastKnownGood.Subject, result.Error)
+			return lastKnownGood, true
+		}
+		return &TokenValidationResult{
+			Valid: false,
+			Error: result.Error,
+		}, false
+	}
+	if result.Valid && v.cache != nil {
+		_ = v.cache.Set(staleKey, result, lastKnownGoodTTL)
+	}
+
+	return result, false
+}
+
+// validateWithGitHub validates the token by calling GitHub's API
+func (v *GitHubTokenVerifier) validateWithGitHub(ctx context.Context, token string) (result *TokenValidationResult) {
+	ctx, span := telemetry.Start(ctx, "github.validate_token")
+	defer func() {
+		if result.Subject != "" {
+			span.SetAttributes(attribute.String("enduser.id", result.Subject))
+		}
+		telemetry.End(span, result.Error)
+	}()
+
+	// Call GitHub API to verify token and get user info
+	req, err := http.NewRequestWithContext(ctx, "GET", v.config.GitHubAPIURL+"/user", nil)
+	if err != nil {
+		return &TokenValidationResult{
+			Valid: false,
+			Error: fmt.Errorf("failed to create request: %w", err),
+		}
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := doGitHub(v.budget, v.httpClient, req)
+	if err != nil {
+		return &TokenValidationResult{
+			Valid: false,
+			Error: fmt.Errorf("failed to call GitHub API: %w", err),
+		}
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &TokenValidationResult{
+			Valid: false,
+			Error: fmt.Errorf("GitHub API returned status %d: %s", resp.StatusCode, string(body)),
+		}
+	}
+
+	var user GitHubUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return &TokenValidationResult{
+			Valid: false,
+			Error: fmt.Errorf("failed to decode GitHub response: %w", err),
+		}
+	}
+
+	// Enforce the organization and team membership policy
+	if v.config.HasGitHubMembershipPolicy() {
+		member, err := checkGitHubMembership(ctx, v.config, v.budget, v.httpClient, token, user.Login)
+		if err != nil {
+			return &TokenValidationResult{
+				Valid: false,
+				Error: fmt.Errorf("failed to check GitHub membership: %w", err),
+			}
+		}
+		if !member {
+			log.Printf("[GITHUB] Denied %s: not a member of an allowed organization or team", user.Login)
+			return &TokenValidationResult{
+				Valid:        false,
+				Subject:      user.Login,
+				AccessDenied: true,
+				Error:        errors.New(accessDeniedMessage(v.config, user.Login)),
+			}
+		}
+	}
+
+	// Get the scopes from the X-OAuth-Scopes header
+	scopes := parseGitHubScopes(resp.Header.Get("X-OAuth-Scopes"))
+
+	// Validate that required MCP scopes are present
+	// For GitHub, we map GitHub scopes to MCP scopes
+	mcpScopes := mapGitHubScopesToMCP(scopes)
+
+	// Set expiration based on configuration
+	expiresAt := v.config.now().Add(v.config.TokenExpiryDuration)
+
+	return &TokenValidationResult{
+		Valid:        true,
+		Scopes:       mcpScopes,
+		Subject:      user.Login,
+		ExpiresAt:    expiresAt,
+		GitHubUser:   &user,
+		GitHubScopes: scopes,
+		Error:        nil,
+	}
+}
+
+// parseGitHubScopes parses the X-OAuth-Scopes header from GitHub
+func parseGitHubScopes(scopeHeader string) []string {
+	if scopeHeader == "" {
+		return []string{}
+	}
+
+	scopes := strings.Split(scopeHeader, ",")
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		trimmed := strings.TrimSpace(scope)
+		if trimmed != "" {
+			result = append(result, trimmed)
+		}
+	}
+	return result
+}
+
+// mapGitHubScopesToMCP maps GitHub OAuth scopes to MCP scopes
+// This provides a flexible mapping between GitHub permissions and MCP tool access
+func mapGitHubScopesToMCP(githubScopes []string) []string {
+	mcpScopes := make([]string, 0)
+
+	// Always add read:user if the user authenticated
+	mcpScopes = append(mcpScopes, "read:user")
+
+	// Map GitHub scopes to MCP scopes
+	for _, scope := range githubScopes {
+		switch scope {
+		case "repo", "public_repo", "read:repo_hook":
+			// Repository access grants mcp:resources
+			if !contains(mcpScopes, "mcp:resources") {
+				mcpScopes = append(mcpScopes, "mcp:resources")
+			}
+		case "workflow", "write:repo_hook", "admin:repo_hook":
+			// Write access grants mcp:tools
+			if !contains(mcpScopes, "mcp:tools") {
+				mcpScopes = append(mcpScopes, "mcp:tools")
+			}
+		case "read:user", "user", "user:email":
+			// User scopes are already included
+			continue
+		default:
+			// Include other GitHub scopes as-is for extensibility
+			if !contains(mcpScopes, scope) {
+				mcpScopes = append(mcpScopes, scope)
+			}
+		}
+	}
+
+	// If no specific mappings were found, provide basic access
+	if len(mcpScopes) == 1 { // Only has read:user
+		mcpScopes = append(mcpScopes, "mcp:tools", "mcp:resources")
+	}
+
+	return mcpScopes
+}
+
+// contains checks if a slice contains a string
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
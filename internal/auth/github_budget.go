@@ -0,0 +1,274 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+// ErrGitHubBudgetExhausted is returned when a GitHub API call is refused to preserve rate limit
+var ErrGitHubBudgetExhausted = errors.New("GitHub API rate limit budget exhausted")
+
+// GitHubBudget tracks the remaining GitHub API rate limit for each token
+// Non-critical calls (such as re-verifying a user we already know, or a GitHub tool call) are
+// refused once the remaining budget drops to the reserve, so the remaining calls go to new logins
+// Refused calls are deliberately not queued until the window resets: that can be up to an hour,
+// far longer than any client waits for a response, and a refused re-verification already falls
+// back to the last known good validation
+type GitHubBudget struct {
+	mu      sync.Mutex
+	reserve int
+	limits  map[string]*rateLimitState
+	clock   clock.Clock
+}
+
+type rateLimitState struct {
+	limit     int
+	remaining int
+	reset     time.Time
+}
+
+// GitHubBudgetStatus is a snapshot of the rate limit state for a single token
+type GitHubBudgetStatus struct {
+	// TokenID is a short hash identifying the token without revealing it
+	TokenID   string    `json:"token_id"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+// NewGitHubBudget creates a new budget manager that holds back reserve calls for critical requests
+func NewGitHubBudget(reserve int) *GitHubBudget {
+	return &GitHubBudget{
+		reserve: reserve,
+		limits:  make(map[string]*rateLimitState),
+		clock:   clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to tell when rate limit windows reset
+func (b *GitHubBudget) SetClock(c clock.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = c
+}
+
+// Allow reports whether a GitHub API call may be made with token
+// Critical calls are only refused when the rate limit is fully exhausted
+func (b *GitHubBudget) Allow(token string, critical bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state, ok := b.limits[hashSecret(token)]
+	if !ok || b.clock.Now().After(state.reset) {
+		// Unknown or reset windows have their full budget available
+		return nil
+	}
+
+	if state.remaining <= 0 {
+		logGitHubBudgetEvent("github_budget_rejected", token, state, critical)
+		return fmt.Errorf("%w: resets at %s", ErrGitHubBudgetExhausted, state.reset.Format(time.RFC3339))
+	}
+	if !critical && state.remaining <= b.reserve {
+		logGitHubBudgetEvent("github_budget_rejected", token, state, critical)
+		return fmt.Errorf("%w: %d calls remaining are reserved for critical requests until %s",
+			ErrGitHubBudgetExhausted, state.remaining, state.reset.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// Observe records the rate limit headers from a GitHub API response made with token
+func (b *GitHubBudget) Observe(token string, header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	resetUnix, _ := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	state := &rateLimitState{
+		limit:     limit,
+		remaining: remaining,
+		reset:     time.Unix(resetUnix, 0),
+	}
+	b.limits[hashSecret(token)] = state
+
+	if remaining <= b.reserve {
+		log.Printf("[GITHUB] Rate limit budget low: %d/%d calls remaining until %s",
+			remaining, limit, time.Unix(resetUnix, 0).Format(time.RFC3339))
+		logGitHubBudgetEvent("github_budget_low", token, state, false)
+	}
+
+	// Drop windows that have already reset
+	now := b.clock.Now()
+	for key, state := range b.limits {
+		if now.After(state.reset) {
+			delete(b.limits, key)
+		}
+	}
+}
+
+// Snapshot returns the current budget state for every tracked token, lowest remaining first
+func (b *GitHubBudget) Snapshot() []GitHubBudgetStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]GitHubBudgetStatus, 0, len(b.limits))
+	for key, state := range b.limits {
+		statuses = append(statuses, GitHubBudgetStatus{
+			TokenID:   key[:8],
+			Limit:     state.limit,
+			Remaining: state.remaining,
+			Reset:     state.reset,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Remaining < statuses[j].Remaining
+	})
+
+	return statuses
+}
+
+// Reserve returns the number of calls per token held back for critical requests
+func (b *GitHubBudget) Reserve() int {
+	return b.reserve
+}
+
+// logGitHubBudgetEvent emits a [METRIC] event that log-based metrics and alarms can match
+func logGitHubBudgetEvent(name, token string, state *rateLimitState, critical bool) {
+	event, _ := json.Marshal(instance.Current().Tag(map[string]any{
+		"event":     name,
+		"token_id":  hashSecret(token)[:8],
+		"limit":     state.limit,
+		"remaining": state.remaining,
+		"reset":     state.reset.UTC().Format(time.RFC3339),
+		"critical":  critical,
+	}))
+	log.Printf("[METRIC] %s", event)
+}
+
+// nonCriticalGitHubCallsKey marks contexts whose GitHub calls are non-critical
+type nonCriticalGitHubCallsKey struct{}
+
+// WithNonCriticalGitHubCalls marks the GitHub calls made with ctx through a budget's client as
+// non-critical, so they are refused once the token is down to the reserve
+func WithNonCriticalGitHubCalls(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonCriticalGitHubCallsKey{}, true)
+}
+
+// Client returns a copy of base that checks every request against the budget before sending it
+// and records the rate limit GitHub reports in the response
+// Requests are critical unless their context comes from WithNonCriticalGitHubCalls; a refused
+// request fails with ErrGitHubBudgetExhausted without reaching GitHub
+func (b *GitHubBudget) Client(base *http.Client) *http.Client {
+	client := *base
+	client.Transport = &budgetTransport{budget: b, base: base.Transport}
+	return &client
+}
+
+// budgetTransport spends a GitHubBudget for each request it sends
+type budgetTransport struct {
+	budget *GitHubBudget
+	base   http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := githubBudgetKey(req)
+	nonCritical, _ := req.Context().Value(nonCriticalGitHubCallsKey{}).(bool)
+	if err := t.budget.Allow(key, !nonCritical); err != nil {
+		return nil, err
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.budget.Observe(key, resp.Header)
+	return resp, nil
+}
+
+// githubBudgetKey is what a request spends rate limit for: the bearer token it carries or, for
+// the OAuth app's own calls such as exchanging a code, the app on that GitHub host
+func githubBudgetKey(req *http.Request) string {
+	if token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+	return "app:" + req.URL.Host
+}
+
+// doGitHub sends req with client through the GitHub breaker and budget, returning the response
+// for the caller to close
+// Calls the budget refuses never reach GitHub, so they don't count against the breaker, and
+// neither do invalid tokens or missing resources; only failing to answer and server errors do
+func doGitHub(budget *GitHubBudget, client *http.Client, req *http.Request) (*http.Response, error) {
+	if err := githubBreaker.Allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := budget.Client(client).Do(req)
+	if err != nil {
+		if errors.Is(err, ErrGitHubBudgetExhausted) {
+			githubBreaker.Cancel()
+		} else {
+			githubBreaker.Failure(err)
+		}
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		githubBreaker.Failure(fmt.Errorf("GitHub API returned status %d", resp.StatusCode))
+	} else {
+		githubBreaker.Success()
+	}
+	return resp, nil
+}
+
+// GitHubBudgetHandler reports a budget's reserve and per-token rate limit state as JSON
+type GitHubBudgetHandler struct {
+	budget *GitHubBudget
+}
+
+// NewGitHubBudgetHandler creates a new handler for the given budget
+func NewGitHubBudgetHandler(budget *GitHubBudget) *GitHubBudgetHandler {
+	return &GitHubBudgetHandler{budget: budget}
+}
+
+// ServeHTTP implements http.Handler
+func (h *GitHubBudgetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(struct {
+		Reserve int                  `json:"reserve"`
+		Tokens  []GitHubBudgetStatus `json:"tokens"`
+	}{h.budget.reserve, h.budget.Snapshot()})
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
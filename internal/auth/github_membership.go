@@ -0,0 +1,161 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// adminScope administers the server; it is only granted to AdminGitHubUsers and AdminGitHubTeams
+const adminScope = "mcp:admin"
+
+// ErrAccessDenied is returned for valid tokens whose GitHub user is not allowed by the
+// organization and team policy; RequireAuth answers it with 403 instead of 401
+var ErrAccessDenied = errors.New("access denied")
+
+// HasGitHubMembershipPolicy reports whether access is restricted to GitHub organizations or teams
+func (c *Config) HasGitHubMembershipPolicy() bool {
+	return len(c.GitHubAllowedOrgs) > 0 || len(c.GitHubAllowedTeams) > 0
+}
+
+// githubOAuthScopes returns the scopes requested from GitHub
+// Checking private organization and team membership needs read:org, and the GitHub tools need repo
+func (c *Config) githubOAuthScopes() string {
+	scopes := "read:user"
+	if c.HasGitHubMembershipPolicy() || len(c.AdminGitHubTeams) > 0 {
+		scopes += " read:org"
+	}
+	if c.GitHubRepoAccess {
+		scopes += " repo"
+	}
+	return scopes
+}
+
+// githubMembership is the part of GitHub's org and team membership responses we use
+type githubMembership struct {
+	State string `json:"state"`
+}
+
+// checkGitHubMembership reports whether login is an active member of any allowed organization
+// or team, trying each in turn; it returns an error only if GitHub couldn't answer
+func checkGitHubMembership(ctx context.Context, cfg *Config, budget *GitHubBudget, client *http.Client, token, login string) (bool, error) {
+	for _, org := range cfg.GitHubAllowedOrgs {
+		path := "/user/memberships/orgs/" + url.PathEscape(org)
+		if active, err := githubMembershipActive(ctx, cfg, budget, client, token, path); err != nil || active {
+			return active, err
+		}
+	}
+
+	for _, team := range cfg.GitHubAllowedTeams {
+		org, slug, _ := strings.Cut(team, "/")
+		path := "/orgs/" + url.PathEscape(org) + "/teams/" + url.PathEscape(slug) + "/memberships/" + url.PathEscape(login)
+		if active, err := githubMembershipActive(ctx, cfg, budget, client, token, path); err != nil || active {
+			return active, err
+		}
+	}
+
+	return false, nil
+}
+
+// isGitHubAdmin reports whether login may be granted mcp:admin, being listed in AdminGitHubUsers
+// or an active member of one of AdminGitHubTeams; it returns an error only if GitHub couldn't answer
+func isGitHubAdmin(ctx context.Context, cfg *Config, budget *GitHubBudget, client *http.Client, token, login string) (bool, error) {
+	for _, user := range cfg.AdminGitHubUsers {
+		if strings.EqualFold(user, login) {
+			return true, nil
+		}
+	}
+
+	for _, team := range cfg.AdminGitHubTeams {
+		org, slug, _ := strings.Cut(team, "/")
+		path := "/orgs/" + url.PathEscape(org) + "/teams/" + url.PathEscape(slug) + "/memberships/" + url.PathEscape(login)
+		if active, err := githubMembershipActive(ctx, cfg, budget, client, token, path); err != nil || active {
+			return active, err
+		}
+	}
+
+	return false, nil
+}
+
+// githubMembershipActive fetches a membership resource and reports whether it is active
+// GitHub answers 404 (or 403 without read:org) when the user is not a member
+func githubMembershipActive(ctx context.Context, cfg *Config, budget *GitHubBudget, client *http.Client, token, path string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.GitHubAPIURL+path, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := doGitHub(budget, client, req)
+	if err != nil {
+		return false, fmt.Errorf("failed to call GitHub API: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Failed to close response body: %v", err)
+		}
+	}()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var membership githubMembership
+		if err := json.NewDecoder(resp.Body).Decode(&membership); err != nil {
+			return false, fmt.Errorf("failed to decode GitHub membership: %w", err)
+		}
+		return membership.State == "active", nil
+	case http.StatusNotFound, http.StatusForbidden:
+		return false, nil
+	default:
+		return false, fmt.Errorf("GitHub membership check returned status %d", resp.StatusCode)
+	}
+}
+
+// accessDeniedMessage explains the membership policy to a denied user
+func accessDeniedMessage(cfg *Config, login string) string {
+	allowed := append(append([]string{}, cfg.GitHubAllowedOrgs...), cfg.GitHubAllowedTeams...)
+	return fmt.Sprintf("GitHub user %s is not a member of an allowed organization or team (%s)", login, strings.Join(allowed, ", "))
+}
+
+// accessDeniedWriter replaces the MCP SDK's response to an ErrAccessDenied verification failure
+// (a plain-text 500) with a 403 OAuth error body
+type accessDeniedWriter struct {
+	http.ResponseWriter
+	denied  *error
+	written bool
+}
+
+func (w *accessDeniedWriter) WriteHeader(code int) {
+	if *w.denied == nil {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+
+	w.written = true
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", "application/json")
+	w.ResponseWriter.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w.ResponseWriter).Encode(map[string]string{
+		"error":             "access_denied",
+		"error_description": strings.TrimPrefix((*w.denied).Error(), ErrAccessDenied.Error()+": "),
+	})
+}
+
+func (w *accessDeniedWriter) Write(p []byte) (int, error) {
+	if *w.denied == nil {
+		return w.ResponseWriter.Write(p)
+	}
+	if !w.written {
+		w.WriteHeader(http.StatusForbidden)
+	}
+	return len(p), nil
+}
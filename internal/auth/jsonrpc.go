@@ -0,0 +1,107 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// jsonRPCMessage holds the fields needed to route a JSON-RPC request before authentication
+type jsonRPCMessage struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+}
+
+// JSONRPCMethods parses a JSON-RPC request body (a single message or a batch) and returns the
+// method of every message; it fails on anything that isn't well-formed JSON-RPC 2.0
+func JSONRPCMethods(body []byte) ([]string, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, fmt.Errorf("empty JSON-RPC body")
+	}
+
+	var messages []jsonRPCMessage
+	if body[0] == '[' {
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC batch: %w", err)
+		}
+		if len(messages) == 0 {
+			return nil, fmt.Errorf("empty JSON-RPC batch")
+		}
+	} else {
+		var message jsonRPCMessage
+		if err := json.Unmarshal(body, &message); err != nil {
+			return nil, fmt.Errorf("invalid JSON-RPC message: %w", err)
+		}
+		messages = []jsonRPCMessage{message}
+	}
+
+	methods := make([]string, 0, len(messages))
+	for _, message := range messages {
+		if message.JSONRPC != "2.0" || message.Method == "" {
+			return nil, fmt.Errorf("not a JSON-RPC 2.0 request")
+		}
+		methods = append(methods, message.Method)
+	}
+	return methods, nil
+}
+
+// RequireAuthExceptMethods is RequireAuth for the MCP endpoint: POST bodies are limited to
+// MaxRequestBodyBytes, and requests without a token are let through unauthenticated only if
+// every JSON-RPC method they contain is in UnauthenticatedMethods
+func (m *Middleware) RequireAuthExceptMethods(scopes []string) func(http.Handler) http.Handler {
+	requireAuth := m.RequireAuth(scopes)
+
+	allowed := make(map[string]bool, len(m.config.UnauthenticatedMethods))
+	for _, method := range m.config.UnauthenticatedMethods {
+		allowed[method] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		protected := requireAuth(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost && m.config.MaxRequestBodyBytes > 0 {
+				r.Body = http.MaxBytesReader(w, r.Body, int64(m.config.MaxRequestBodyBytes))
+			}
+
+			if len(allowed) == 0 || r.Method != http.MethodPost || r.Header.Get("Authorization") != "" {
+				protected.ServeHTTP(w, r)
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			methods, err := JSONRPCMethods(body)
+			if err != nil {
+				protected.ServeHTTP(w, r)
+				return
+			}
+			for _, method := range methods {
+				if !allowed[method] {
+					protected.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,329 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// Access token formats selectable with TOKEN_FORMAT
+const (
+	TokenFormatOpaque = "opaque"
+	TokenFormatJWT    = "jwt"
+)
+
+// revokedJWTPrefix keys the IDs of revoked JWT access tokens among authorization codes, where
+// they stay until the tokens would have expired
+const revokedJWTPrefix = "revoked-jwt:"
+
+// JWTClaims are the claims of the access tokens we sign
+type JWTClaims struct {
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWTIssuer signs and verifies RS256 access tokens, so tokens can be verified without storing
+// them or calling the GitHub API
+type JWTIssuer struct {
+	key    *rsa.PrivateKey
+	keyID  string
+	issuer string
+	clock  clock.Clock
+	leeway time.Duration
+}
+
+// NewJWTIssuer creates an issuer signing with key; issuer is the iss claim (the server URL)
+// The key ID is derived from the public key so it changes whenever the key is rotated
+func NewJWTIssuer(key *rsa.PrivateKey, issuer string) (*JWTIssuer, error) {
+	publicKey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode public key: %w", err)
+	}
+	thumbprint := sha256.Sum256(publicKey)
+
+	return &JWTIssuer{
+		key:    key,
+		keyID:  base64.RawURLEncoding.EncodeToString(thumbprint[:12]),
+		issuer: issuer,
+		clock:  clock.System{},
+	}, nil
+}
+
+// SetClock replaces the clock used for iat, exp, and expiry checks
+func (i *JWTIssuer) SetClock(c clock.Clock) {
+	i.clock = c
+}
+
+// SetLeeway accepts tokens for up to leeway past their expiry, to tolerate clock skew
+func (i *JWTIssuer) SetLeeway(leeway time.Duration) {
+	i.leeway = leeway
+}
+
+// KeyID returns the kid header of issued tokens
+func (i *JWTIssuer) KeyID() string {
+	return i.keyID
+}
+
+// Issue signs an access token for tokenInfo on behalf of subject (the GitHub login)
+func (i *JWTIssuer) Issue(tokenInfo *AccessTokenInfo, subject string) (string, error) {
+	jti, err := generateRandomString(16)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+
+	claims := JWTClaims{
+		ClientID: tokenInfo.ClientID,
+		Scope:    tokenInfo.Scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.issuer,
+			Subject:   subject,
+			ExpiresAt: jwt.NewNumericDate(tokenInfo.ExpiresAt),
+			IssuedAt:  jwt.NewNumericDate(tokenInfo.CreatedAt),
+			ID:        jti,
+		},
+	}
+	// Tokens are only for this server, so without a resource they are for the server itself
+	claims.Audience = jwt.ClaimStrings{i.issuer}
+	if tokenInfo.Resource != "" {
+		claims.Audience = jwt.ClaimStrings{tokenInfo.Resource}
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.keyID
+
+	signed, err := token.SignedString(i.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign access token: %w", err)
+	}
+	return signed, nil
+}
+
+// Verify checks the signature, issuer, audience, and expiry of an access token and returns its claims
+// Tokens need an ID so they can be revoked; checking it against revocations is up to the caller
+func (i *JWTIssuer) Verify(tokenString string) (*JWTClaims, error) {
+	claims := &JWTClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims,
+		func(token *jwt.Token) (any, error) {
+			if kid, _ := token.Header["kid"].(string); kid != i.keyID {
+				return nil, fmt.Errorf("unknown key ID %q", kid)
+			}
+			return &i.key.PublicKey, nil
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(i.issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(i.clock.Now),
+		jwt.WithLeeway(i.leeway),
+	)
+	if err != nil {
+		return nil, err
+	}
+	if !i.acceptsAudience(claims.Audience) {
+		return nil, fmt.Errorf("token is not meant for %s", i.issuer)
+	}
+	if claims.ID == "" {
+		return nil, fmt.Errorf("token has no ID")
+	}
+	return claims, nil
+}
+
+// acceptsAudience reports whether a token for audience is meant for this server: the server
+// itself or a resource under it, such as a persona's endpoint
+func (i *JWTIssuer) acceptsAudience(audience jwt.ClaimStrings) bool {
+	for _, aud := range audience {
+		if aud == i.issuer || strings.HasPrefix(aud, i.issuer+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokeJWT stops a JWT access token signed by issuer from verifying before it expires, by
+// recording its ID in tokens, which every verifier sharing the storage checks
+// Tokens that don't verify, or are already revoked, return ErrAccessTokenNotFound
+func RevokeJWT(tokens TokenStorage, issuer *JWTIssuer, token string) (*JWTClaims, error) {
+	claims, err := issuer.Verify(token)
+	if err != nil || jwtRevoked(tokens, claims.ID) {
+		return nil, ErrAccessTokenNotFound
+	}
+
+	// Leeway lets a token verify past its expiry, so the revocation has to outlive it as long
+	err = tokens.StoreAuthCode(revokedJWTPrefix+claims.ID, &AuthCodeInfo{
+		Subject:   claims.Subject,
+		ExpiresAt: claims.ExpiresAt.Add(issuer.leeway),
+		CreatedAt: issuer.clock.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record JWT revocation: %w", err)
+	}
+	return claims, nil
+}
+
+// jwtRevoked reports whether the JWT access token with the given ID was revoked
+func jwtRevoked(tokens TokenStorage, id string) bool {
+	_, err := tokens.GetAuthCode(revokedJWTPrefix + id)
+	return err == nil
+}
+
+// LooksLikeJWT reports whether a bearer token has the three-part JWS compact form
+func LooksLikeJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
+
+// JSONWebKey is an RSA public key in JWK form (RFC 7517)
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Modulus   string `json:"n"`
+	Exponent  string `json:"e"`
+}
+
+// JWKS returns the JSON Web Key Set clients and resource servers use to verify our tokens
+func (i *JWTIssuer) JWKS() map[string][]JSONWebKey {
+	publicKey := i.key.PublicKey
+	return map[string][]JSONWebKey{
+		"keys": {{
+			KeyType:   "RSA",
+			Use:       "sig",
+			Algorithm: jwt.SigningMethodRS256.Alg(),
+			KeyID:     i.keyID,
+			Modulus:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			Exponent:  base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		}},
+	}
+}
+
+// JWKSHandler serves the issuer's public keys at /.well-known/jwks.json
+type JWKSHandler struct {
+	issuer *JWTIssuer
+}
+
+// NewJWKSHandler creates a new handler for the JSON Web Key Set
+func NewJWKSHandler(issuer *JWTIssuer) *JWKSHandler {
+	return &JWKSHandler{
+		issuer: issuer,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *JWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=3600") // Cache for 1 hour
+
+	if err := json.NewEncoder(w).Encode(h.issuer.JWKS()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+		return
+	}
+}
+
+// ParseRSAPrivateKeyPEM parses a PKCS#1 or PKCS#8 PEM-encoded RSA private key
+func ParseRSAPrivateKeyPEM(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in signing key")
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key is not an RSA key")
+	}
+	return key, nil
+}
+
+// NewJWTIssuerFromConfig creates the issuer for TOKEN_FORMAT=jwt, loading the signing key from
+// JWT_SIGNING_KEY or, if unset, from the Secrets Manager secret named by JWT_SIGNING_KEY_SECRET_NAME
+func NewJWTIssuerFromConfig(ctx context.Context, cfg *Config) (*JWTIssuer, error) {
+	keyPEM := cfg.JWTSigningKey
+	if keyPEM == "" && cfg.JWTSigningKeySecretName != "" {
+		value, err := DefaultSecretFetcher.Fetch(ctx, cfg.JWTSigningKeySecretName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve signing key secret: %w", err)
+		}
+		keyPEM = value
+	}
+	if keyPEM == "" {
+		return nil, fmt.Errorf("no JWT signing key configured")
+	}
+
+	key, err := ParseRSAPrivateKeyPEM([]byte(keyPEM))
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := NewJWTIssuer(key, cfg.ServerURL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Clock != nil {
+		issuer.SetClock(cfg.Clock)
+	}
+	issuer.SetLeeway(cfg.ClockSkew)
+	log.Printf("Issuing JWT access tokens (kid %s)", issuer.KeyID())
+	return issuer, nil
+}
+
+// lookupGitHubLogin returns the login of the GitHub user owning token
+// JWTs can't be re-checked against GitHub, so the user is looked up once when the code is issued
+func lookupGitHubLogin(ctx context.Context, cfg *Config, budget *GitHubBudget, client *http.Client, token string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, cfg.GitHubAPIURL+"/user", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := doGitHub(budget, client, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call GitHub API: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GitHub API returned status %d", resp.StatusCode)
+	}
+
+	var user GitHubUserInfo
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return "", fmt.Errorf("failed to decode GitHub response: %w", err)
+	}
+	if user.Login == "" {
+		return "", fmt.Errorf("GitHub user has no login")
+	}
+	return user.Login, nil
+}
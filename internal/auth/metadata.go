@@ -36,7 +36,7 @@ func (h *ProtectedResourceMetadataHandler) ServeHTTP(w http.ResponseWriter, r *h
 		AuthorizationServers: []string{
 			h.config.ServerURL, // Point to our server's auth metadata endpoint
 		},
-		ScopesSupported: h.config.ScopesSupported,
+		ScopesSupported: h.config.SupportedScopes(),
 		BearerMethodsSupported: []string{
 			"header", // We only support Authorization header
 		},
@@ -81,14 +81,16 @@ func (h *AuthServerMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 		AuthorizationEndpoint: h.config.ServerURL + "/oauth/authorize",
 		TokenEndpoint:         h.config.ServerURL + "/oauth/token",
 		// Include registration endpoint if DCR is enabled
-		RegistrationEndpoint:  h.config.GetRegistrationEndpointURL(),
-		ScopesSupported:       h.config.ScopesSupported,
+		RegistrationEndpoint: h.config.GetRegistrationEndpointURL(),
+		ScopesSupported:      h.config.SupportedScopes(),
 		ResponseTypesSupported: []string{
 			"code", // Authorization code flow
 		},
+		DeviceAuthorizationEndpoint: h.config.ServerURL + "/oauth/device_authorization",
 		GrantTypesSupported: []string{
 			"authorization_code",
 			"refresh_token",
+			DeviceCodeGrantType,
 		},
 		TokenEndpointAuthMethodsSupported: []string{
 			"client_secret_post",
@@ -99,6 +101,12 @@ func (h *AuthServerMetadataHandler) ServeHTTP(w http.ResponseWriter, r *http.Req
 			"S256", // PKCE with SHA-256
 		},
 	}
+	if len(h.config.ClientCredentialsClients) > 0 {
+		metadata.GrantTypesSupported = append(metadata.GrantTypesSupported, "client_credentials")
+	}
+	if h.config.TokenFormat == TokenFormatJWT {
+		metadata.JWKSURI = h.config.ServerURL + "/.well-known/jwks.json"
+	}
 
 	// Set headers
 	w.Header().Set("Content-Type", "application/json")
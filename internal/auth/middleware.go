@@ -6,6 +6,8 @@ package auth
 
 import (
 	"context"
+	"crypto/subtle"
+	"errors"
 	"net/http"
 
 	"github.com/modelcontextprotocol/go-sdk/auth"
@@ -27,8 +29,8 @@ func NewMiddleware(config *Config, verifier *GitHubTokenVerifier) *Middleware {
 
 // RequireAuth returns HTTP middleware that requires OAuth authentication
 // This wraps the MCP SDK's auth.RequireBearerToken with our GitHub token verifier
-// Special handling: GET requests are allowed through without token validation to support SSE streaming
-// The MCP handler will validate the session ID
+// Every method is authenticated, including GET for SSE streams; use SessionBindings
+// to also tie session IDs to the token that created them
 func (m *Middleware) RequireAuth(scopes []string) func(http.Handler) http.Handler {
 	// Create the MCP SDK middleware with our verifier
 	opts := &auth.RequireBearerTokenOptions{
@@ -36,25 +38,44 @@ func (m *Middleware) RequireAuth(scopes []string) func(http.Handler) http.Handle
 		Scopes:              scopes,
 	}
 
-	sdkMiddleware := auth.RequireBearerToken(
-		func(ctx context.Context, token string, req *http.Request) (*auth.TokenInfo, error) {
-			return m.verifier.Verify(ctx, token, req)
-		},
-		opts,
-	)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Handlers behind this middleware verifying the same token reuse its result
+			r = r.WithContext(WithVerificationMemo(r.Context()))
+
+			// Users denied by the membership policy get 403, which the SDK middleware can't express,
+			// so remember the denial and rewrite its error response
+			var denied error
+			verifier := func(ctx context.Context, token string, req *http.Request) (*auth.TokenInfo, error) {
+				info, err := m.verifier.Verify(ctx, token, req)
+				if errors.Is(err, ErrAccessDenied) {
+					denied = err
+				}
+				return info, err
+			}
+
+			// Only the SDK's error response goes through the rewriting writer
+			handler := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				next.ServeHTTP(w, r)
+			})
+			auth.RequireBearerToken(verifier, opts)(handler).ServeHTTP(&accessDeniedWriter{ResponseWriter: w, denied: &denied}, r)
+		})
+	}
+}
 
-	// Wrap the SDK middleware to allow GET requests
+// RequireAdmin requires a token with the mcp:admin scope, or staticToken as the bearer token for
+// operator scripts that can't go through OAuth; an empty staticToken only allows OAuth tokens
+func (m *Middleware) RequireAdmin(staticToken string) func(http.Handler) http.Handler {
+	requireScope := m.RequireAuth([]string{"mcp:admin"})
 	return func(next http.Handler) http.Handler {
+		scoped := requireScope(next)
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Allow GET requests to pass through for SSE streaming
-			// The MCP handler will validate the session ID
-			if r.Method == http.MethodGet {
+			token := extractBearerToken(r.Header.Get("Authorization"))
+			if staticToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(staticToken)) == 1 {
 				next.ServeHTTP(w, r)
 				return
 			}
-
-			// For all other requests (POST, etc.), apply OAuth authentication
-			sdkMiddleware(next).ServeHTTP(w, r)
+			scoped.ServeHTTP(w, r)
 		})
 	}
 }
@@ -73,6 +94,7 @@ func (m *Middleware) OptionalAuth() func(http.Handler) http.Handler {
 			}
 
 			// Token present, validate it
+			r = r.WithContext(WithVerificationMemo(r.Context()))
 			tokenInfo, err := m.verifier.Verify(r.Context(), extractBearerToken(authHeader), r)
 			if err != nil {
 				// Invalid token, but we allow the request to proceed
@@ -41,6 +41,9 @@ type AuthServerMetadata struct {
 	// RegistrationEndpoint is the URL of the dynamic client registration endpoint (RFC 7591)
 	RegistrationEndpoint string `json:"registration_endpoint,omitempty"`
 
+	// DeviceAuthorizationEndpoint is the URL of the device authorization endpoint (RFC 8628)
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint,omitempty"`
+
 	// ScopesSupported lists the supported OAuth scopes
 	ScopesSupported []string `json:"scopes_supported,omitempty"`
 
@@ -55,6 +58,9 @@ type AuthServerMetadata struct {
 
 	// CodeChallengeMethodsSupported lists supported PKCE challenge methods
 	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported,omitempty"`
+
+	// JWKSURI is the URL of the keys that sign JWT access tokens, if they are issued
+	JWKSURI string `json:"jwks_uri,omitempty"`
 }
 
 // ClientRegistrationRequest represents a Dynamic Client Registration request per RFC 7591
@@ -110,6 +116,13 @@ type ClientRegistrationResponse struct {
 	// ClientSecretExpiresAt is the time at which the client secret will expire (0 if it will not expire)
 	ClientSecretExpiresAt int64 `json:"client_secret_expires_at,omitempty"`
 
+	// RegistrationAccessToken authorizes reading, updating, and deleting the registration
+	// (RFC 7592); it is only returned when the client is registered
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+
+	// RegistrationClientURI is where the registration can be managed
+	RegistrationClientURI string `json:"registration_client_uri,omitempty"`
+
 	// All registered metadata is returned
 	RedirectURIs            []string `json:"redirect_uris,omitempty"`
 	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method,omitempty"`
@@ -150,6 +163,10 @@ type OAuthClient struct {
 	// ClientSecret is the client secret (hashed for storage)
 	ClientSecret string `json:"client_secret,omitempty"`
 
+	// RegistrationAccessToken authorizes managing the registration (hashed for storage); clients
+	// that weren't registered through DCR have none and can't be managed
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+
 	// Metadata contains the client's registered metadata
 	Metadata ClientRegistrationRequest `json:"metadata"`
 
@@ -157,7 +174,15 @@ type OAuthClient struct {
 	CreatedAt time.Time `json:"created_at"`
 
 	// ExpiresAt is the timestamp when the client registration expires (optional)
+	// It is pushed back whenever the client is issued a token, so only unused clients expire
 	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// LastUsedAt is when the client was last issued a token, recorded at most daily
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// AutoRegistered is set for clients registered on their first authorization request
+	// rather than through DCR
+	AutoRegistered bool `json:"auto_registered,omitempty"`
 }
 
 // TokenValidationResult represents the result of validating an OAuth access token
@@ -180,6 +205,12 @@ type TokenValidationResult struct {
 	// GitHubUser contains the GitHub user information
 	GitHubUser *GitHubUserInfo
 
+	// GitHubScopes are the scopes GitHub reports the token was granted, as sent in X-OAuth-Scopes
+	GitHubScopes []string
+
+	// AccessDenied is set when the token is valid but its user is not allowed by the membership policy
+	AccessDenied bool
+
 	// Error contains validation error details if Valid is false
 	Error error
 }
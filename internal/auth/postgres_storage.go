@@ -0,0 +1,463 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// Postgres row layout: every record lives in a single table keyed by "key", with the record
+// JSON-encoded in "data" and its expiry in "expires_at" (null if it never expires)
+// Tokens, codes, and states are stored under a hash of their value, never the raw value
+const (
+	postgresAuthCodePrefix    = "code:"
+	postgresAccessTokenPrefix = "token:"
+	postgresClientPrefix      = "client:"
+	postgresCachePrefix       = "cache:"
+	postgresStatePrefix       = "state:"
+
+	// postgresTimeout bounds each query, since the storage interfaces take no context
+	postgresTimeout = 5 * time.Second
+)
+
+// PostgresAPI is the subset of a pgx connection pool used by PostgresStorage
+type PostgresAPI interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// PostgresStorage implements TokenStorage, ClientStorage, and TokenCache on a single Postgres
+// table, for deployments that already run a database and don't want Redis or DynamoDB
+// Expired rows are skipped on read and purged whenever an authorization code is issued
+type PostgresStorage struct {
+	db    PostgresAPI
+	table string
+	clock clock.Clock
+}
+
+// NewPostgresStorage creates a storage backed by table using db
+func NewPostgresStorage(db PostgresAPI, table string) *PostgresStorage {
+	return &PostgresStorage{
+		db:    db,
+		table: pgx.Identifier{table}.Sanitize(),
+		clock: clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to check expiry
+func (s *PostgresStorage) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// CreatePostgresTable creates table and its expiry index if they don't exist yet
+func CreatePostgresTable(ctx context.Context, db PostgresAPI, table string) error {
+	statements := []string{
+		"CREATE TABLE IF NOT EXISTS " + pgx.Identifier{table}.Sanitize() + " (key TEXT PRIMARY KEY, data JSONB NOT NULL, expires_at TIMESTAMPTZ)",
+		"CREATE INDEX IF NOT EXISTS " + pgx.Identifier{table + "_expires_at"}.Sanitize() + " ON " + pgx.Identifier{table}.Sanitize() + " (expires_at)",
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create Postgres table %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// postgresPut stores value under key, expiring at expiresAt (never if zero)
+// Records that have already expired are not written at all
+func postgresPut(db PostgresAPI, table string, clk clock.Clock, key string, value any, expiresAt time.Time) error {
+	var expires *time.Time
+	if !expiresAt.IsZero() {
+		if !expiresAt.After(clk.Now()) {
+			return nil
+		}
+		expires = &expiresAt
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	_, err = db.Exec(ctx, "INSERT INTO "+table+" (key, data, expires_at) VALUES ($1, $2, $3) "+
+		"ON CONFLICT (key) DO UPDATE SET data = EXCLUDED.data, expires_at = EXCLUDED.expires_at",
+		key, data, expires)
+	if err != nil {
+		return fmt.Errorf("failed to store row in Postgres: %w", err)
+	}
+	return nil
+}
+
+// postgresGet loads the record under key into value, returning false if it doesn't exist
+func postgresGet(db PostgresAPI, table, key string, value any) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	var data []byte
+	err := db.QueryRow(ctx, "SELECT data FROM "+table+" WHERE key = $1", key).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read row from Postgres: %w", err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to decode Postgres row: %w", err)
+	}
+	return true, nil
+}
+
+// postgresDelete removes the record under key and reports whether it existed
+func postgresDelete(db PostgresAPI, table, key string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	tag, err := db.Exec(ctx, "DELETE FROM "+table+" WHERE key = $1", key)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete row from Postgres: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// postgresConsume deletes the record under key and loads it into value, returning false if it
+// didn't exist; the single DELETE ... RETURNING lets only one caller have it
+func postgresConsume(db PostgresAPI, table, key string, value any) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	var data []byte
+	err := db.QueryRow(ctx, "DELETE FROM "+table+" WHERE key = $1 RETURNING data", key).Scan(&data)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to consume row from Postgres: %w", err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to decode Postgres row: %w", err)
+	}
+	return true, nil
+}
+
+// purgeExpired deletes rows that have expired
+func (s *PostgresStorage) purgeExpired() error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	if _, err := s.db.Exec(ctx, "DELETE FROM "+s.table+" WHERE expires_at < $1", s.clock.Now()); err != nil {
+		return fmt.Errorf("failed to purge expired rows from Postgres: %w", err)
+	}
+	return nil
+}
+
+// StoreAuthCode stores an authorization code
+func (s *PostgresStorage) StoreAuthCode(code string, authInfo *AuthCodeInfo) error {
+	if err := s.purgeExpired(); err != nil {
+		return err
+	}
+	return postgresPut(s.db, s.table, s.clock, postgresAuthCodePrefix+hashSecret(code), authInfo, authInfo.ExpiresAt)
+}
+
+// GetAuthCode retrieves an authorization code
+func (s *PostgresStorage) GetAuthCode(code string) (*AuthCodeInfo, error) {
+	var info AuthCodeInfo
+	found, err := postgresGet(s.db, s.table, postgresAuthCodePrefix+hashSecret(code), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired authorization code")
+	}
+	return &info, nil
+}
+
+// DeleteAuthCode deletes an authorization code
+func (s *PostgresStorage) DeleteAuthCode(code string) error {
+	_, err := postgresDelete(s.db, s.table, postgresAuthCodePrefix+hashSecret(code))
+	return err
+}
+
+// ConsumeAuthCode retrieves and deletes an authorization code
+func (s *PostgresStorage) ConsumeAuthCode(code string) (*AuthCodeInfo, error) {
+	var info AuthCodeInfo
+	found, err := postgresConsume(s.db, s.table, postgresAuthCodePrefix+hashSecret(code), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired authorization code")
+	}
+	return &info, nil
+}
+
+// StoreAccessToken stores an access token
+func (s *PostgresStorage) StoreAccessToken(token string, tokenInfo *AccessTokenInfo) error {
+	return postgresPut(s.db, s.table, s.clock, postgresAccessTokenPrefix+hashSecret(token), tokenInfo, tokenInfo.ExpiresAt)
+}
+
+// GetAccessToken retrieves an access token
+func (s *PostgresStorage) GetAccessToken(token string) (*AccessTokenInfo, error) {
+	var info AccessTokenInfo
+	found, err := postgresGet(s.db, s.table, postgresAccessTokenPrefix+hashSecret(token), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired access token")
+	}
+	return &info, nil
+}
+
+// RevokeAccessToken deletes an access token
+func (s *PostgresStorage) RevokeAccessToken(token string) error {
+	_, err := postgresDelete(s.db, s.table, postgresAccessTokenPrefix+AccessTokenID(token))
+	return err
+}
+
+// ListAccessTokens returns the unexpired access tokens
+func (s *PostgresStorage) ListAccessTokens() ([]*StoredAccessToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, "SELECT key, data FROM "+s.table+" WHERE key LIKE $1 AND (expires_at IS NULL OR expires_at > $2)",
+		postgresAccessTokenPrefix+"%", s.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens from Postgres: %w", err)
+	}
+	defer rows.Close()
+
+	var tokens []*StoredAccessToken
+	for rows.Next() {
+		var key string
+		var data []byte
+		if err := rows.Scan(&key, &data); err != nil {
+			return nil, fmt.Errorf("failed to read access token from Postgres: %w", err)
+		}
+		token := &StoredAccessToken{ID: strings.TrimPrefix(key, postgresAccessTokenPrefix)}
+		if err := json.Unmarshal(data, &token.AccessTokenInfo); err != nil {
+			return nil, fmt.Errorf("failed to decode Postgres row: %w", err)
+		}
+		tokens = append(tokens, token)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list access tokens from Postgres: %w", err)
+	}
+	return tokens, nil
+}
+
+// RevokeAccessTokenByID deletes the access token with the given ID
+func (s *PostgresStorage) RevokeAccessTokenByID(id string) (*AccessTokenInfo, error) {
+	var info AccessTokenInfo
+	found, err := postgresConsume(s.db, s.table, postgresAccessTokenPrefix+id, &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, ErrAccessTokenNotFound
+	}
+	return &info, nil
+}
+
+// StoreClient stores a registered OAuth client
+func (s *PostgresStorage) StoreClient(client *OAuthClient) error {
+	if client == nil {
+		return fmt.Errorf("client cannot be nil")
+	}
+	if client.ClientID == "" {
+		return fmt.Errorf("client ID cannot be empty")
+	}
+
+	var expiresAt time.Time
+	if client.ExpiresAt != nil {
+		expiresAt = *client.ExpiresAt
+	}
+	return postgresPut(s.db, s.table, s.clock, postgresClientPrefix+client.ClientID, client, expiresAt)
+}
+
+// GetClient retrieves a client by client ID
+func (s *PostgresStorage) GetClient(clientID string) (*OAuthClient, error) {
+	var client OAuthClient
+	found, err := postgresGet(s.db, s.table, postgresClientPrefix+clientID, &client)
+	if err != nil {
+		return nil, err
+	}
+	if !found || (client.ExpiresAt != nil && s.clock.Now().After(*client.ExpiresAt)) {
+		return nil, fmt.Errorf("client not found: %s", clientID)
+	}
+	return &client, nil
+}
+
+// DeleteClient removes a client from storage
+func (s *PostgresStorage) DeleteClient(clientID string) error {
+	deleted, err := postgresDelete(s.db, s.table, postgresClientPrefix+clientID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return fmt.Errorf("client not found: %s", clientID)
+	}
+	return nil
+}
+
+// ListClients returns all registered clients
+func (s *PostgresStorage) ListClients() ([]*OAuthClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+
+	rows, err := s.db.Query(ctx, "SELECT data FROM "+s.table+" WHERE key LIKE $1 AND (expires_at IS NULL OR expires_at > $2)",
+		postgresClientPrefix+"%", s.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients from Postgres: %w", err)
+	}
+	defer rows.Close()
+
+	var clients []*OAuthClient
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read client from Postgres: %w", err)
+		}
+		var client OAuthClient
+		if err := json.Unmarshal(data, &client); err != nil {
+			return nil, fmt.Errorf("failed to decode Postgres row: %w", err)
+		}
+		clients = append(clients, &client)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list clients from Postgres: %w", err)
+	}
+	return clients, nil
+}
+
+// ValidateClientSecret checks if the provided secret matches the stored client
+func (s *PostgresStorage) ValidateClientSecret(clientID, secret string) (bool, error) {
+	client, err := s.GetClient(clientID)
+	if err != nil {
+		return false, err
+	}
+	return client.ClientSecret == hashSecret(secret), nil
+}
+
+// Set stores a token validation result with an expiry
+func (s *PostgresStorage) Set(token string, result *TokenValidationResult, expiry time.Duration) error {
+	cached := cachedValidation{
+		Valid:        result.Valid,
+		ClientID:     result.ClientID,
+		Scopes:       result.Scopes,
+		Subject:      result.Subject,
+		ExpiresAt:    result.ExpiresAt,
+		GitHubUser:   result.GitHubUser,
+		GitHubScopes: result.GitHubScopes,
+		Denied:       result.AccessDenied,
+		CachedTill:   s.clock.Now().Add(expiry),
+	}
+	if result.Error != nil {
+		cached.Error = result.Error.Error()
+	}
+	return postgresPut(s.db, s.table, s.clock, postgresCachePrefix+hashSecret(token), cached, cached.CachedTill)
+}
+
+// Get retrieves a cached token validation result
+func (s *PostgresStorage) Get(token string) (*TokenValidationResult, bool) {
+	var cached cachedValidation
+	found, err := postgresGet(s.db, s.table, postgresCachePrefix+hashSecret(token), &cached)
+	if err != nil || !found || s.clock.Now().After(cached.CachedTill) {
+		return nil, false
+	}
+
+	result := &TokenValidationResult{
+		Valid:        cached.Valid,
+		ClientID:     cached.ClientID,
+		Scopes:       cached.Scopes,
+		Subject:      cached.Subject,
+		ExpiresAt:    cached.ExpiresAt,
+		GitHubUser:   cached.GitHubUser,
+		GitHubScopes: cached.GitHubScopes,
+		AccessDenied: cached.Denied,
+	}
+	if cached.Error != "" {
+		result.Error = errors.New(cached.Error)
+	}
+	return result, true
+}
+
+// Delete removes a token from the cache
+func (s *PostgresStorage) Delete(token string) error {
+	_, err := postgresDelete(s.db, s.table, postgresCachePrefix+hashSecret(token))
+	return err
+}
+
+// PostgresStateStore implements StateStorage on Postgres, so the GitHub callback can land on any instance
+type PostgresStateStore struct {
+	db    PostgresAPI
+	table string
+	ttl   time.Duration
+	clock clock.Clock
+}
+
+// NewPostgresStateStore creates a state store in table whose states expire after ttl (10 minutes if zero)
+func NewPostgresStateStore(db PostgresAPI, table string, ttl time.Duration) *PostgresStateStore {
+	if ttl <= 0 {
+		ttl = defaultAuthStateTTL
+	}
+	return &PostgresStateStore{
+		db:    db,
+		table: pgx.Identifier{table}.Sanitize(),
+		ttl:   ttl,
+		clock: clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to check expiry
+func (s *PostgresStateStore) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Store saves an auth state
+func (s *PostgresStateStore) Store(state string, authState *AuthState) error {
+	return postgresPut(s.db, s.table, s.clock, postgresStatePrefix+hashSecret(state), authState, authState.CreatedAt.Add(s.ttl))
+}
+
+// Get retrieves an auth state
+func (s *PostgresStateStore) Get(state string) (*AuthState, bool) {
+	var authState AuthState
+	found, err := postgresGet(s.db, s.table, postgresStatePrefix+hashSecret(state), &authState)
+	if err != nil || !found || s.clock.Now().After(authState.CreatedAt.Add(s.ttl)) {
+		return nil, false
+	}
+	return &authState, true
+}
+
+// Delete removes an auth state
+func (s *PostgresStateStore) Delete(state string) error {
+	_, err := postgresDelete(s.db, s.table, postgresStatePrefix+hashSecret(state))
+	return err
+}
+
+// Consume retrieves and removes an auth state
+func (s *PostgresStateStore) Consume(state string) (*AuthState, bool) {
+	var authState AuthState
+	found, err := postgresConsume(s.db, s.table, postgresStatePrefix+hashSecret(state), &authState)
+	if err != nil || !found || s.clock.Now().After(authState.CreatedAt.Add(s.ttl)) {
+		return nil, false
+	}
+	return &authState, true
+}
@@ -0,0 +1,472 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// Redis key layout: every key starts with the configured prefix, followed by the record type
+// Tokens, codes, and states are stored under a hash of their value, never the raw value
+const (
+	redisAuthCodePrefix    = "code:"
+	redisAccessTokenPrefix = "token:"
+	redisClientPrefix      = "client:"
+	redisClientIndex       = "clients"
+	redisAccessTokenIndex  = "tokens"
+	redisCachePrefix       = "cache:"
+	redisStatePrefix       = "state:"
+
+	// redisTimeout bounds each Redis call, since the storage interfaces take no context
+	redisTimeout = 5 * time.Second
+)
+
+// RedisStorage implements TokenStorage, ClientStorage, and TokenCache on Redis, so every
+// instance behind a load balancer sees the same codes, tokens, and registered clients
+// Records are JSON encoded and expire through Redis TTLs
+type RedisStorage struct {
+	client redis.Cmdable
+	prefix string
+	clock  clock.Clock
+}
+
+// NewRedisStorage creates a storage that keeps its records in client under keys starting with prefix
+func NewRedisStorage(client redis.Cmdable, prefix string) *RedisStorage {
+	return &RedisStorage{
+		client: client,
+		prefix: prefix,
+		clock:  clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to compute TTLs and check expiry
+func (s *RedisStorage) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// redisPut stores value under key, expiring at expiresAt (never if zero)
+// Records that have already expired are not written at all
+func redisPut(client redis.Cmdable, clk clock.Clock, key string, value any, expiresAt time.Time) error {
+	var ttl time.Duration
+	if !expiresAt.IsZero() {
+		ttl = expiresAt.Sub(clk.Now())
+		if ttl <= 0 {
+			return nil
+		}
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to store key in Redis: %w", err)
+	}
+	return nil
+}
+
+// redisGet loads the record under key into value, returning false if it doesn't exist
+func redisGet(client redis.Cmdable, key string, value any) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	data, err := client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to read key from Redis: %w", err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to decode Redis value: %w", err)
+	}
+	return true, nil
+}
+
+// redisConsume loads the record under key into value and deletes it with GETDEL, returning
+// false if it doesn't exist
+func redisConsume(client redis.Cmdable, key string, value any) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	data, err := client.GetDel(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to consume key from Redis: %w", err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return false, fmt.Errorf("failed to decode Redis value: %w", err)
+	}
+	return true, nil
+}
+
+// redisDelete removes the given keys and returns how many existed
+func redisDelete(client redis.Cmdable, keys ...string) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	deleted, err := client.Del(ctx, keys...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete key from Redis: %w", err)
+	}
+	return deleted, nil
+}
+
+// StoreAuthCode stores an authorization code
+func (s *RedisStorage) StoreAuthCode(code string, authInfo *AuthCodeInfo) error {
+	return redisPut(s.client, s.clock, s.prefix+redisAuthCodePrefix+hashSecret(code), authInfo, authInfo.ExpiresAt)
+}
+
+// GetAuthCode retrieves an authorization code
+func (s *RedisStorage) GetAuthCode(code string) (*AuthCodeInfo, error) {
+	var info AuthCodeInfo
+	found, err := redisGet(s.client, s.prefix+redisAuthCodePrefix+hashSecret(code), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired authorization code")
+	}
+	return &info, nil
+}
+
+// DeleteAuthCode deletes an authorization code
+func (s *RedisStorage) DeleteAuthCode(code string) error {
+	_, err := redisDelete(s.client, s.prefix+redisAuthCodePrefix+hashSecret(code))
+	return err
+}
+
+// ConsumeAuthCode retrieves and deletes an authorization code
+func (s *RedisStorage) ConsumeAuthCode(code string) (*AuthCodeInfo, error) {
+	var info AuthCodeInfo
+	found, err := redisConsume(s.client, s.prefix+redisAuthCodePrefix+hashSecret(code), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired authorization code")
+	}
+	return &info, nil
+}
+
+// StoreAccessToken stores an access token
+// Token IDs are also kept in an index set so ListAccessTokens doesn't need to scan the keyspace
+func (s *RedisStorage) StoreAccessToken(token string, tokenInfo *AccessTokenInfo) error {
+	id := AccessTokenID(token)
+	if err := redisPut(s.client, s.clock, s.prefix+redisAccessTokenPrefix+id, tokenInfo, tokenInfo.ExpiresAt); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := s.client.SAdd(ctx, s.prefix+redisAccessTokenIndex, id).Err(); err != nil {
+		return fmt.Errorf("failed to index access token in Redis: %w", err)
+	}
+	return nil
+}
+
+// GetAccessToken retrieves an access token
+func (s *RedisStorage) GetAccessToken(token string) (*AccessTokenInfo, error) {
+	var info AccessTokenInfo
+	found, err := redisGet(s.client, s.prefix+redisAccessTokenPrefix+hashSecret(token), &info)
+	if err != nil {
+		return nil, err
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, fmt.Errorf("invalid or expired access token")
+	}
+	return &info, nil
+}
+
+// RevokeAccessToken deletes an access token
+func (s *RedisStorage) RevokeAccessToken(token string) error {
+	_, err := s.RevokeAccessTokenByID(AccessTokenID(token))
+	if err != nil && !errors.Is(err, ErrAccessTokenNotFound) {
+		return err
+	}
+	return nil
+}
+
+// ListAccessTokens returns the unexpired access tokens
+// Index entries whose token has expired are skipped and dropped from the index
+func (s *RedisStorage) ListAccessTokens() ([]*StoredAccessToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	ids, err := s.client.SMembers(ctx, s.prefix+redisAccessTokenIndex).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access tokens from Redis: %w", err)
+	}
+
+	tokens := make([]*StoredAccessToken, 0, len(ids))
+	var expired []any
+	for _, id := range ids {
+		token := &StoredAccessToken{ID: id}
+		found, err := redisGet(s.client, s.prefix+redisAccessTokenPrefix+id, &token.AccessTokenInfo)
+		if err != nil {
+			return nil, err
+		}
+		if !found || s.clock.Now().After(token.ExpiresAt) {
+			expired = append(expired, id)
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+
+	// Redis expires token keys but not their index entries, so drop those here
+	if len(expired) > 0 {
+		if err := s.client.SRem(ctx, s.prefix+redisAccessTokenIndex, expired...).Err(); err != nil {
+			log.Printf("Failed to remove expired access tokens from the Redis index: %v", err)
+		}
+	}
+	return tokens, nil
+}
+
+// RevokeAccessTokenByID deletes the access token with the given ID
+func (s *RedisStorage) RevokeAccessTokenByID(id string) (*AccessTokenInfo, error) {
+	var info AccessTokenInfo
+	found, err := redisConsume(s.client, s.prefix+redisAccessTokenPrefix+id, &info)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := s.client.SRem(ctx, s.prefix+redisAccessTokenIndex, id).Err(); err != nil {
+		return nil, fmt.Errorf("failed to unindex access token in Redis: %w", err)
+	}
+	if !found || s.clock.Now().After(info.ExpiresAt) {
+		return nil, ErrAccessTokenNotFound
+	}
+	return &info, nil
+}
+
+// StoreClient stores a registered OAuth client
+// Client IDs are also kept in an index set so ListClients doesn't need to scan the keyspace
+func (s *RedisStorage) StoreClient(client *OAuthClient) error {
+	if client == nil {
+		return fmt.Errorf("client cannot be nil")
+	}
+	if client.ClientID == "" {
+		return fmt.Errorf("client ID cannot be empty")
+	}
+
+	var expiresAt time.Time
+	if client.ExpiresAt != nil {
+		expiresAt = *client.ExpiresAt
+	}
+	if err := redisPut(s.client, s.clock, s.prefix+redisClientPrefix+client.ClientID, client, expiresAt); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := s.client.SAdd(ctx, s.prefix+redisClientIndex, client.ClientID).Err(); err != nil {
+		return fmt.Errorf("failed to index client in Redis: %w", err)
+	}
+	return nil
+}
+
+// GetClient retrieves a client by client ID
+func (s *RedisStorage) GetClient(clientID string) (*OAuthClient, error) {
+	var client OAuthClient
+	found, err := redisGet(s.client, s.prefix+redisClientPrefix+clientID, &client)
+	if err != nil {
+		return nil, err
+	}
+	if !found || (client.ExpiresAt != nil && s.clock.Now().After(*client.ExpiresAt)) {
+		return nil, fmt.Errorf("client not found: %s", clientID)
+	}
+	return &client, nil
+}
+
+// DeleteClient removes a client from storage
+func (s *RedisStorage) DeleteClient(clientID string) error {
+	deleted, err := redisDelete(s.client, s.prefix+redisClientPrefix+clientID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	if err := s.client.SRem(ctx, s.prefix+redisClientIndex, clientID).Err(); err != nil {
+		return fmt.Errorf("failed to unindex client in Redis: %w", err)
+	}
+	if deleted == 0 {
+		return fmt.Errorf("client not found: %s", clientID)
+	}
+	return nil
+}
+
+// ListClients returns all registered clients
+// Index entries whose client has expired are skipped and left for the next DeleteClient
+func (s *RedisStorage) ListClients() ([]*OAuthClient, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	ids, err := s.client.SMembers(ctx, s.prefix+redisClientIndex).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients from Redis: %w", err)
+	}
+
+	clients := make([]*OAuthClient, 0, len(ids))
+	var expired []any
+	for _, id := range ids {
+		var client OAuthClient
+		found, err := redisGet(s.client, s.prefix+redisClientPrefix+id, &client)
+		if err != nil {
+			return nil, err
+		}
+		if !found || (client.ExpiresAt != nil && s.clock.Now().After(*client.ExpiresAt)) {
+			expired = append(expired, id)
+			continue
+		}
+		clients = append(clients, &client)
+	}
+
+	// Redis expires client keys but not their index entries, so drop those here
+	if len(expired) > 0 {
+		if err := s.client.SRem(ctx, s.prefix+redisClientIndex, expired...).Err(); err != nil {
+			log.Printf("Failed to remove expired clients from the Redis index: %v", err)
+		}
+	}
+	return clients, nil
+}
+
+// ValidateClientSecret checks if the provided secret matches the stored client
+func (s *RedisStorage) ValidateClientSecret(clientID, secret string) (bool, error) {
+	client, err := s.GetClient(clientID)
+	if err != nil {
+		return false, err
+	}
+	return client.ClientSecret == hashSecret(secret), nil
+}
+
+// Set stores a token validation result with an expiry
+func (s *RedisStorage) Set(token string, result *TokenValidationResult, expiry time.Duration) error {
+	cached := cachedValidation{
+		Valid:        result.Valid,
+		ClientID:     result.ClientID,
+		Scopes:       result.Scopes,
+		Subject:      result.Subject,
+		ExpiresAt:    result.ExpiresAt,
+		GitHubUser:   result.GitHubUser,
+		GitHubScopes: result.GitHubScopes,
+		Denied:       result.AccessDenied,
+		CachedTill:   s.clock.Now().Add(expiry),
+	}
+	if result.Error != nil {
+		cached.Error = result.Error.Error()
+	}
+	return redisPut(s.client, s.clock, s.prefix+redisCachePrefix+hashSecret(token), cached, cached.CachedTill)
+}
+
+// Get retrieves a cached token validation result
+func (s *RedisStorage) Get(token string) (*TokenValidationResult, bool) {
+	var cached cachedValidation
+	found, err := redisGet(s.client, s.prefix+redisCachePrefix+hashSecret(token), &cached)
+	if err != nil || !found || s.clock.Now().After(cached.CachedTill) {
+		return nil, false
+	}
+
+	result := &TokenValidationResult{
+		Valid:        cached.Valid,
+		ClientID:     cached.ClientID,
+		Scopes:       cached.Scopes,
+		Subject:      cached.Subject,
+		ExpiresAt:    cached.ExpiresAt,
+		GitHubUser:   cached.GitHubUser,
+		GitHubScopes: cached.GitHubScopes,
+		AccessDenied: cached.Denied,
+	}
+	if cached.Error != "" {
+		result.Error = errors.New(cached.Error)
+	}
+	return result, true
+}
+
+// Delete removes a token from the cache
+func (s *RedisStorage) Delete(token string) error {
+	_, err := redisDelete(s.client, s.prefix+redisCachePrefix+hashSecret(token))
+	return err
+}
+
+// RedisStateStore implements StateStorage on Redis, so the GitHub callback can land on any instance
+type RedisStateStore struct {
+	client redis.Cmdable
+	prefix string
+	ttl    time.Duration
+	clock  clock.Clock
+}
+
+// NewRedisStateStore creates a state store in client whose states expire after ttl (10 minutes if zero)
+func NewRedisStateStore(client redis.Cmdable, prefix string, ttl time.Duration) *RedisStateStore {
+	if ttl <= 0 {
+		ttl = defaultAuthStateTTL
+	}
+	return &RedisStateStore{
+		client: client,
+		prefix: prefix,
+		ttl:    ttl,
+		clock:  clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to compute TTLs and check expiry
+func (s *RedisStateStore) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Store saves an auth state
+func (s *RedisStateStore) Store(state string, authState *AuthState) error {
+	return redisPut(s.client, s.clock, s.prefix+redisStatePrefix+hashSecret(state), authState, authState.CreatedAt.Add(s.ttl))
+}
+
+// Get retrieves an auth state
+func (s *RedisStateStore) Get(state string) (*AuthState, bool) {
+	var authState AuthState
+	found, err := redisGet(s.client, s.prefix+redisStatePrefix+hashSecret(state), &authState)
+	if err != nil || !found || s.clock.Now().After(authState.CreatedAt.Add(s.ttl)) {
+		return nil, false
+	}
+	return &authState, true
+}
+
+// Delete removes an auth state
+func (s *RedisStateStore) Delete(state string) error {
+	_, err := redisDelete(s.client, s.prefix+redisStatePrefix+hashSecret(state))
+	return err
+}
+
+// Consume retrieves and removes an auth state
+func (s *RedisStateStore) Consume(state string) (*AuthState, bool) {
+	var authState AuthState
+	found, err := redisConsume(s.client, s.prefix+redisStatePrefix+hashSecret(state), &authState)
+	if err != nil || !found || s.clock.Now().After(authState.CreatedAt.Add(s.ttl)) {
+		return nil, false
+	}
+	return &authState, true
+}
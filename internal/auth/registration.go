@@ -0,0 +1,414 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// RegistrationHandler handles Dynamic Client Registration requests per RFC 7591
+type RegistrationHandler struct {
+	config  *Config
+	storage ClientStorage
+}
+
+// NewRegistrationHandler creates a new DCR handler
+func NewRegistrationHandler(config *Config, storage ClientStorage) *RegistrationHandler {
+	return &RegistrationHandler{
+		config:  config,
+		storage: storage,
+	}
+}
+
+// ServeHTTP implements http.Handler for the /register endpoint
+// Registrations are read, updated, and deleted at /register/{client_id} (RFC 7592)
+func (h *RegistrationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if clientID, ok := strings.CutPrefix(r.URL.Path, "/register/"); ok {
+		h.manage(w, r, clientID)
+		return
+	}
+
+	log.Printf("[DCR] Registration request received from %s", r.RemoteAddr)
+
+	// Only allow POST requests
+	if r.Method != http.MethodPost {
+		log.Printf("[DCR] Invalid method: %s", r.Method)
+		h.sendError(w, ErrorInvalidRequest, "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Check if DCR is enabled
+	if !h.config.EnableDCR {
+		log.Printf("[DCR] DCR is not enabled")
+		h.sendError(w, ErrorInvalidRequest, "Dynamic client registration is not enabled", http.StatusForbidden)
+		return
+	}
+
+	// Parse request body
+	var req ClientRegistrationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Printf("[DCR] Failed to decode request body: %v", err)
+		h.sendError(w, ErrorInvalidRequest, "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[DCR] Registration request: client_name=%s, redirect_uris=%v, grant_types=%v",
+		req.ClientName, req.RedirectURIs, req.GrantTypes)
+
+	response, err := h.Register(req)
+	var metadataErr *ClientMetadataError
+	if errors.As(err, &metadataErr) {
+		h.sendError(w, ErrorInvalidClientMetadata, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("[DCR] Failed to register client: %v", err)
+		h.sendError(w, ErrorServerError, "Failed to register client", http.StatusInternalServerError)
+		return
+	}
+	h.sendRegistration(w, response, http.StatusCreated)
+}
+
+// ClientMetadataError is returned by Register for registration requests that fail validation
+type ClientMetadataError struct {
+	Err error
+}
+
+func (e *ClientMetadataError) Error() string { return e.Err.Error() }
+
+func (e *ClientMetadataError) Unwrap() error { return e.Err }
+
+// Register validates and stores a new client, and returns its registration with the plaintext
+// secret and registration access token, which are never available again
+// It doesn't check EnableDCR, so admins can register clients when open registration is off
+func (h *RegistrationHandler) Register(req ClientRegistrationRequest) (ClientRegistrationResponse, error) {
+	// Validate the registration request
+	if err := h.validateRequest(&req); err != nil {
+		return ClientRegistrationResponse{}, &ClientMetadataError{Err: err}
+	}
+
+	// Generate client credentials
+	clientID, err := GenerateClientID()
+	if err != nil {
+		return ClientRegistrationResponse{}, fmt.Errorf("failed to generate client ID: %w", err)
+	}
+
+	var clientSecret string
+	var hashedSecret string
+
+	// Generate client secret for confidential clients
+	if req.TokenEndpointAuthMethod != "none" {
+		clientSecret, err = GenerateClientSecret()
+		if err != nil {
+			return ClientRegistrationResponse{}, fmt.Errorf("failed to generate client secret: %w", err)
+		}
+		hashedSecret = hashSecret(clientSecret)
+	}
+
+	// Every registered client can manage its own registration
+	registrationToken, err := generateRandomString(32)
+	if err != nil {
+		return ClientRegistrationResponse{}, fmt.Errorf("failed to generate registration access token: %w", err)
+	}
+
+	// Apply defaults
+	h.applyDefaults(&req)
+
+	// Create the OAuth client
+	now := h.config.now()
+	client := &OAuthClient{
+		ClientID:                clientID,
+		ClientSecret:            hashedSecret,
+		RegistrationAccessToken: hashSecret(registrationToken),
+		Metadata:                req,
+		CreatedAt:               now,
+	}
+	client.ExpiresAt = h.config.clientExpiry(client, now)
+
+	// Store the client
+	if err := h.storage.StoreClient(client); err != nil {
+		return ClientRegistrationResponse{}, fmt.Errorf("failed to store client registration: %w", err)
+	}
+
+	log.Printf("[DCR] Successfully registered client: %s (name: %s)", clientID, req.ClientName)
+
+	// Build response, returning the plaintext secret and registration access token only once
+	response := h.registrationResponse(client)
+	response.ClientSecret = clientSecret
+	response.RegistrationAccessToken = registrationToken
+	return response, nil
+}
+
+// registrationResponse describes client's registration, without any credentials
+func (h *RegistrationHandler) registrationResponse(client *OAuthClient) ClientRegistrationResponse {
+	req := client.Metadata
+	return ClientRegistrationResponse{
+		ClientID:                client.ClientID,
+		ClientIDIssuedAt:        client.CreatedAt.Unix(),
+		ClientSecretExpiresAt:   0, // Secrets don't expire by default
+		RegistrationClientURI:   h.config.GetRegistrationEndpointURL() + "/" + client.ClientID,
+		RedirectURIs:            req.RedirectURIs,
+		TokenEndpointAuthMethod: req.TokenEndpointAuthMethod,
+		GrantTypes:              req.GrantTypes,
+		ResponseTypes:           req.ResponseTypes,
+		ClientName:              req.ClientName,
+		ClientURI:               req.ClientURI,
+		LogoURI:                 req.LogoURI,
+		Scope:                   req.Scope,
+		Contacts:                req.Contacts,
+		JWKSURI:                 req.JWKSURI,
+		SoftwareID:              req.SoftwareID,
+		SoftwareVersion:         req.SoftwareVersion,
+	}
+}
+
+// sendRegistration sends a registration response
+func (h *RegistrationHandler) sendRegistration(w http.ResponseWriter, response ClientRegistrationResponse, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(statusCode)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		// Too late to change status code, but log the error
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
+
+// manage serves GET, PUT, and DELETE for a registration, authorized by the registration access
+// token issued with it
+// Unknown clients and wrong tokens get the same 401, so the endpoint doesn't reveal which
+// client IDs exist
+func (h *RegistrationHandler) manage(w http.ResponseWriter, r *http.Request, clientID string) {
+	if !h.config.EnableDCR {
+		h.sendError(w, ErrorInvalidRequest, "Dynamic client registration is not enabled", http.StatusForbidden)
+		return
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	client, err := h.storage.GetClient(clientID)
+	if !ok || err != nil || client == nil || client.RegistrationAccessToken == "" ||
+		subtle.ConstantTimeCompare([]byte(hashSecret(token)), []byte(client.RegistrationAccessToken)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		h.sendError(w, "invalid_token", "Invalid registration access token", http.StatusUnauthorized)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.sendRegistration(w, h.registrationResponse(client), http.StatusOK)
+
+	case http.MethodPut:
+		h.update(w, r, client)
+
+	case http.MethodDelete:
+		if err := h.storage.DeleteClient(clientID); err != nil {
+			log.Printf("[DCR] Failed to delete client %s: %v", clientID, err)
+			h.sendError(w, ErrorServerError, "Failed to delete client registration", http.StatusInternalServerError)
+			return
+		}
+		log.Printf("[DCR] Client deleted its registration: %s", clientID)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		h.sendError(w, ErrorInvalidRequest, "Only GET, PUT, and DELETE methods are allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// clientUpdateRequest is the body of a registration update, which names the client it replaces
+type clientUpdateRequest struct {
+	ClientID string `json:"client_id"`
+	ClientRegistrationRequest
+}
+
+// update replaces client's metadata with the request body
+// The authentication method can't change, since that would need a secret issued or dropped
+func (h *RegistrationHandler) update(w http.ResponseWriter, r *http.Request, client *OAuthClient) {
+	var req clientUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendError(w, ErrorInvalidRequest, "Invalid JSON in request body", http.StatusBadRequest)
+		return
+	}
+	if req.ClientID != client.ClientID {
+		h.sendError(w, ErrorInvalidRequest, "client_id must match the registration", http.StatusBadRequest)
+		return
+	}
+
+	if req.TokenEndpointAuthMethod == "" {
+		req.TokenEndpointAuthMethod = client.Metadata.TokenEndpointAuthMethod
+	}
+	if req.TokenEndpointAuthMethod != client.Metadata.TokenEndpointAuthMethod {
+		h.sendError(w, ErrorInvalidClientMetadata, "token_endpoint_auth_method can't be changed", http.StatusBadRequest)
+		return
+	}
+	// Scopes can be narrowed but not widened, so a client allowed the client_credentials grant
+	// can't add scopes, such as mcp:admin, after the operator approved it
+	if req.Scope == "" {
+		req.Scope = client.Metadata.Scope
+	}
+	current := strings.Fields(client.Metadata.Scope)
+	for _, scope := range strings.Fields(req.Scope) {
+		if !slices.Contains(current, scope) {
+			h.sendError(w, ErrorInvalidClientMetadata, fmt.Sprintf("scope %s can't be added to a registration; register a new client instead", scope), http.StatusBadRequest)
+			return
+		}
+	}
+	if err := h.validateRequest(&req.ClientRegistrationRequest); err != nil {
+		h.sendError(w, ErrorInvalidClientMetadata, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.applyDefaults(&req.ClientRegistrationRequest)
+
+	updated := *client
+	updated.Metadata = req.ClientRegistrationRequest
+	if err := h.storage.StoreClient(&updated); err != nil {
+		log.Printf("[DCR] Failed to update client %s: %v", client.ClientID, err)
+		h.sendError(w, ErrorServerError, "Failed to store client registration", http.StatusInternalServerError)
+		return
+	}
+
+	log.Printf("[DCR] Client updated its registration: %s", client.ClientID)
+	h.sendRegistration(w, h.registrationResponse(&updated), http.StatusOK)
+}
+
+// validateRequest validates the client registration request
+func (h *RegistrationHandler) validateRequest(req *ClientRegistrationRequest) error {
+	// Validate redirect URIs; only the authorization code and implicit grants redirect, and
+	// clients that don't list grant types get the authorization code grant
+	redirects := len(req.GrantTypes) == 0
+	for _, gt := range req.GrantTypes {
+		if gt == "authorization_code" || gt == "implicit" {
+			redirects = true
+		}
+	}
+	if len(req.RedirectURIs) == 0 && redirects {
+		return fmt.Errorf("at least one redirect_uri is required")
+	}
+
+	for _, uri := range req.RedirectURIs {
+		if uri == "" {
+			return fmt.Errorf("redirect_uri cannot be empty")
+		}
+
+		// For VS Code compatibility, ensure the required redirect URIs are present
+		// But allow additional ones
+		// Validate URI format (basic check)
+		if len(uri) > 2048 {
+			return fmt.Errorf("redirect_uri too long: %s", uri)
+		}
+	}
+
+	// Validate scopes
+	for _, scope := range strings.Fields(req.Scope) {
+		if !h.config.IsScopeSupported(scope) {
+			return fmt.Errorf("unsupported scope: %s", scope)
+		}
+	}
+
+	// Validate grant types
+	if len(req.GrantTypes) > 0 {
+		validGrantTypes := map[string]bool{
+			"authorization_code": true,
+			"implicit":           true,
+			"password":           true,
+			"client_credentials": true,
+			"refresh_token":      true,
+			DeviceCodeGrantType:  true,
+		}
+		for _, gt := range req.GrantTypes {
+			if !validGrantTypes[gt] {
+				return fmt.Errorf("invalid grant_type: %s", gt)
+			}
+		}
+	}
+
+	// Validate response types
+	if len(req.ResponseTypes) > 0 {
+		validResponseTypes := map[string]bool{
+			"code":  true,
+			"token": true,
+		}
+		for _, rt := range req.ResponseTypes {
+			if !validResponseTypes[rt] {
+				return fmt.Errorf("invalid response_type: %s", rt)
+			}
+		}
+	}
+
+	// Validate token endpoint auth method
+	if req.TokenEndpointAuthMethod != "" {
+		validMethods := map[string]bool{
+			"none":                true,
+			"client_secret_post":  true,
+			"client_secret_basic": true,
+		}
+		if !validMethods[req.TokenEndpointAuthMethod] {
+			return fmt.Errorf("invalid token_endpoint_auth_method: %s", req.TokenEndpointAuthMethod)
+		}
+
+		// Check if public clients are allowed
+		if req.TokenEndpointAuthMethod == "none" && !h.config.AllowPublicClients {
+			return fmt.Errorf("public clients are not allowed")
+		}
+	}
+
+	// Validate client name length
+	if len(req.ClientName) > 256 {
+		return fmt.Errorf("client_name too long (max 256 characters)")
+	}
+
+	return nil
+}
+
+// applyDefaults applies default values to the registration request
+func (h *RegistrationHandler) applyDefaults(req *ClientRegistrationRequest) {
+	// Default token endpoint auth method
+	if req.TokenEndpointAuthMethod == "" {
+		if h.config.AllowPublicClients {
+			req.TokenEndpointAuthMethod = "none"
+		} else {
+			req.TokenEndpointAuthMethod = "client_secret_basic"
+		}
+	}
+
+	// Default grant types
+	if len(req.GrantTypes) == 0 {
+		req.GrantTypes = []string{"authorization_code"}
+	}
+
+	// Default response types
+	if len(req.ResponseTypes) == 0 {
+		req.ResponseTypes = []string{"code"}
+	}
+
+	// Default scope
+	if req.Scope == "" {
+		req.Scope = "mcp:tools mcp:resources read:user"
+	}
+}
+
+// sendError sends an error response
+func (h *RegistrationHandler) sendError(w http.ResponseWriter, errorCode, description string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(statusCode)
+
+	errorResp := ClientRegistrationError{
+		Error:            errorCode,
+		ErrorDescription: description,
+	}
+
+	if err := json.NewEncoder(w).Encode(errorResp); err != nil {
+		log.Printf("Failed to encode error response: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SandboxGitHubPath is where the sandbox serves its stand-in for GitHub's OAuth and API endpoints
+const SandboxGitHubPath = "/sandbox/github"
+
+// SandboxUser is the GitHub login everyone signs in as in the sandbox
+const SandboxUser = "sandbox-user"
+
+// Prefixes of the codes and tokens the sandbox GitHub hands out
+const (
+	sandboxCodePrefix  = "sandbox_code_"
+	sandboxTokenPrefix = "sandbox_gho_"
+)
+
+// applySandbox points GitHub at the server's own sandbox endpoints and keeps everything in
+// memory, so the full OAuth flow works without GitHub, AWS, or a database
+func (c *Config) applySandbox() {
+	c.OAuthEnabled = true
+	if c.GitHubClientID == "" || c.GitHubClientSecret == "" {
+		c.GitHubClientID = "sandbox"
+		c.GitHubClientSecret = "sandbox"
+	}
+	c.GitHubAuthURL = c.ServerURL + SandboxGitHubPath + "/login/oauth/authorize"
+	c.GitHubTokenURL = c.ServerURL + SandboxGitHubPath + "/login/oauth/access_token"
+	c.GitHubAPIURL = c.ServerURL + SandboxGitHubPath + "/api"
+	c.StorageBackend = StorageBackendMemory
+}
+
+// sandboxScopes are the scopes every sandbox token is granted
+const sandboxScopes = "read:user, read:org, repo"
+
+// SandboxGitHubHandler stands in for GitHub in the sandbox: authorization is granted at once
+// as SandboxUser, every organization and team membership is active, and SandboxUser owns one
+// repository with a couple of issues
+type SandboxGitHubHandler struct {
+	mux *http.ServeMux
+}
+
+// NewSandboxGitHubHandler creates the sandbox GitHub, to be mounted at SandboxGitHubPath
+func NewSandboxGitHubHandler() *SandboxGitHubHandler {
+	h := &SandboxGitHubHandler{mux: http.NewServeMux()}
+	h.mux.HandleFunc("GET "+SandboxGitHubPath+"/login/oauth/authorize", h.authorize)
+	h.mux.HandleFunc("POST "+SandboxGitHubPath+"/login/oauth/access_token", h.accessToken)
+	h.mux.HandleFunc("GET "+SandboxGitHubPath+"/api/user", h.user)
+	h.mux.HandleFunc("GET "+SandboxGitHubPath+"/api/user/memberships/orgs/{org}", h.membership)
+	h.mux.HandleFunc("GET "+SandboxGitHubPath+"/api/orgs/{org}/teams/{team}/memberships/{login}", h.membership)
+	h.mux.HandleFunc("GET "+SandboxGitHubPath+"/api/user/repos", h.repos)
+	h.mux.HandleFunc("GET "+SandboxGitHubPath+"/api/repos/{owner}/{repo}/issues", h.issues)
+	h.mux.HandleFunc("POST "+SandboxGitHubPath+"/api/repos/{owner}/{repo}/issues", h.createIssue)
+	return h
+}
+
+func (h *SandboxGitHubHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// authorize skips the login page and sends the user straight back with a code
+func (h *SandboxGitHubHandler) authorize(w http.ResponseWriter, r *http.Request) {
+	redirectURI, err := url.Parse(r.URL.Query().Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" {
+		http.Error(w, "redirect_uri is required", http.StatusBadRequest)
+		return
+	}
+	query := redirectURI.Query()
+	code, err := generateRandomString(16)
+	if err != nil {
+		http.Error(w, "failed to generate code", http.StatusInternalServerError)
+		return
+	}
+	query.Set("code", sandboxCodePrefix+code)
+	query.Set("state", r.URL.Query().Get("state"))
+	redirectURI.RawQuery = query.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+// accessToken exchanges any sandbox code for a token
+func (h *SandboxGitHubHandler) accessToken(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.FormValue("code"), sandboxCodePrefix) {
+		writeSandboxJSON(w, http.StatusOK, map[string]string{
+			"error":             "bad_verification_code",
+			"error_description": "The code passed is incorrect or expired.",
+		})
+		return
+	}
+	token, err := generateRandomString(16)
+	if err != nil {
+		http.Error(w, "failed to generate token", http.StatusInternalServerError)
+		return
+	}
+	writeSandboxJSON(w, http.StatusOK, map[string]string{
+		"access_token": sandboxTokenPrefix + token,
+		"token_type":   "bearer",
+		"scope":        "read:user",
+	})
+}
+
+// user describes SandboxUser to holders of a sandbox token
+func (h *SandboxGitHubHandler) user(w http.ResponseWriter, r *http.Request) {
+	if !sandboxAuthorized(r) {
+		writeSandboxJSON(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+		return
+	}
+	w.Header().Set("X-OAuth-Scopes", sandboxScopes)
+	writeSandboxJSON(w, http.StatusOK, GitHubUserInfo{
+		Login: SandboxUser,
+		ID:    1,
+		Email: SandboxUser + "@example.com",
+		Name:  "Sandbox User",
+	})
+}
+
+// membership reports every membership as active
+func (h *SandboxGitHubHandler) membership(w http.ResponseWriter, r *http.Request) {
+	if !sandboxAuthorized(r) {
+		writeSandboxJSON(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+		return
+	}
+	writeSandboxJSON(w, http.StatusOK, githubMembership{State: "active"})
+}
+
+// repos lists SandboxUser's repository
+func (h *SandboxGitHubHandler) repos(w http.ResponseWriter, r *http.Request) {
+	if !sandboxAuthorized(r) {
+		writeSandboxJSON(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+		return
+	}
+	writeSandboxJSON(w, http.StatusOK, []map[string]any{{
+		"full_name":         SandboxUser + "/hello-world",
+		"description":       "A repository in the sandbox",
+		"private":           false,
+		"stargazers_count":  3,
+		"open_issues_count": 2,
+		"updated_at":        "2025-01-01T12:00:00Z",
+	}})
+}
+
+// issues lists the same two open issues for any repository
+func (h *SandboxGitHubHandler) issues(w http.ResponseWriter, r *http.Request) {
+	if !sandboxAuthorized(r) {
+		writeSandboxJSON(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+		return
+	}
+	repoURL := "https://github.com/" + r.PathValue("owner") + "/" + r.PathValue("repo")
+	writeSandboxJSON(w, http.StatusOK, []map[string]any{
+		sandboxIssue(repoURL, 2, "Add a dark theme", "bug"),
+		sandboxIssue(repoURL, 1, "Document the sandbox", "documentation"),
+	})
+}
+
+// createIssue pretends to open the issue as number 3
+func (h *SandboxGitHubHandler) createIssue(w http.ResponseWriter, r *http.Request) {
+	if !sandboxAuthorized(r) {
+		writeSandboxJSON(w, http.StatusUnauthorized, map[string]string{"message": "Bad credentials"})
+		return
+	}
+	var request struct {
+		Title string `json:"title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Title == "" {
+		writeSandboxJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "Validation Failed"})
+		return
+	}
+	repoURL := "https://github.com/" + r.PathValue("owner") + "/" + r.PathValue("repo")
+	writeSandboxJSON(w, http.StatusCreated, sandboxIssue(repoURL, 3, request.Title))
+}
+
+func sandboxIssue(repoURL string, number int, title string, labels ...string) map[string]any {
+	labelObjects := make([]map[string]string, 0, len(labels))
+	for _, label := range labels {
+		labelObjects = append(labelObjects, map[string]string{"name": label})
+	}
+	return map[string]any{
+		"number":     number,
+		"title":      title,
+		"state":      "open",
+		"html_url":   repoURL + "/issues/" + strconv.Itoa(number),
+		"user":       map[string]string{"login": SandboxUser},
+		"labels":     labelObjects,
+		"comments":   0,
+		"updated_at": "2025-01-01T12:00:00Z",
+	}
+}
+
+// sandboxAuthorized reports whether r carries a token issued by the sandbox GitHub
+func sandboxAuthorized(r *http.Request) bool {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return strings.HasPrefix(token, sandboxTokenPrefix)
+}
+
+func writeSandboxJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,146 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// ErrSecretUnavailable is returned when a secret still can't be read after every retry, as
+// opposed to a secret that was read but is invalid
+var ErrSecretUnavailable = errors.New("secret unavailable")
+
+// Secret fetch defaults, overridden by SECRETS_FETCH_ATTEMPTS and SECRETS_FETCH_TIMEOUT_SECONDS
+const (
+	defaultSecretAttempts  = 5
+	defaultSecretTimeout   = 30 * time.Second
+	defaultSecretBaseDelay = 500 * time.Millisecond
+	defaultSecretMaxDelay  = 8 * time.Second
+)
+
+// SecretsManagerAPI is the part of the Secrets Manager client the server uses
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SecretFetcher reads secrets from Secrets Manager, retrying throttling and transient errors
+// with exponential backoff and jitter, for at most Attempts tries within Timeout
+type SecretFetcher struct {
+	Attempts  int
+	Timeout   time.Duration
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+
+	mu     sync.Mutex
+	client SecretsManagerAPI
+}
+
+// DefaultSecretFetcher reads the GitHub OAuth and JWT signing key secrets
+var DefaultSecretFetcher = &SecretFetcher{
+	Attempts:  defaultSecretAttempts,
+	Timeout:   defaultSecretTimeout,
+	BaseDelay: defaultSecretBaseDelay,
+	MaxDelay:  defaultSecretMaxDelay,
+}
+
+// SetClient replaces the Secrets Manager client; nil goes back to one created from the default
+// AWS configuration on the next fetch
+func (f *SecretFetcher) SetClient(client SecretsManagerAPI) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.client = client
+}
+
+// getClient returns the Secrets Manager client, creating it on first use
+func (f *SecretFetcher) getClient(ctx context.Context) (SecretsManagerAPI, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.client == nil {
+		awsCfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load AWS SDK config: %w", err)
+		}
+		f.client = secretsmanager.NewFromConfig(awsCfg)
+	}
+	return f.client, nil
+}
+
+// Fetch returns the string value of the secret name
+// Errors that retrying can't fix, such as a missing secret or denied access, are returned at
+// once; either way the error wraps ErrSecretUnavailable
+func (f *SecretFetcher) Fetch(ctx context.Context, name string) (string, error) {
+	attempts, timeout := secretFetchLimitsFromEnv(f.Attempts, f.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client, err := f.getClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrSecretUnavailable, err)
+	}
+
+	delay := f.BaseDelay
+	for attempt := 1; ; attempt++ {
+		result, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &name})
+		if err == nil {
+			if result.SecretString == nil {
+				return "", fmt.Errorf("%w: secret %s has no string value", ErrSecretUnavailable, name)
+			}
+			if attempt > 1 {
+				log.Printf("[SECRETS] Read %s on attempt %d", name, attempt)
+			}
+			return *result.SecretString, nil
+		}
+
+		if !retryableSecretError(err) || attempt >= attempts {
+			return "", fmt.Errorf("%w: failed to retrieve %s after %d attempt(s): %w", ErrSecretUnavailable, name, attempt, err)
+		}
+
+		// Full jitter keeps instances starting together from retrying in lockstep
+		wait := time.Duration(rand.Int64N(int64(delay) + 1))
+		log.Printf("[SECRETS] Failed to read %s (attempt %d of %d), retrying in %v: %v", name, attempt, attempts, wait.Round(time.Millisecond), err)
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("%w: gave up on %s after %v: %w", ErrSecretUnavailable, name, timeout, err)
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, f.MaxDelay)
+	}
+}
+
+// retryableSecretError reports whether err is throttling or a transient failure worth retrying
+func retryableSecretError(err error) bool {
+	if retry.IsErrorRetryables(retry.DefaultRetryables).IsErrorRetryable(err) == aws.TrueTernary {
+		return true
+	}
+	// Secrets Manager reports its own server errors with this code
+	var coded interface{ ErrorCode() string }
+	return errors.As(err, &coded) && coded.ErrorCode() == "InternalServiceError"
+}
+
+// secretFetchLimitsFromEnv applies SECRETS_FETCH_ATTEMPTS and SECRETS_FETCH_TIMEOUT_SECONDS to
+// the fetcher's limits, ignoring invalid values
+func secretFetchLimitsFromEnv(attempts int, timeout time.Duration) (int, time.Duration) {
+	if n, err := strconv.Atoi(os.Getenv("SECRETS_FETCH_ATTEMPTS")); err == nil && n > 0 {
+		attempts = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("SECRETS_FETCH_TIMEOUT_SECONDS")); err == nil && n > 0 {
+		timeout = time.Duration(n) * time.Second
+	}
+	return max(attempts, 1), timeout
+}
@@ -0,0 +1,208 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"log"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// sessionIDHeader is the header carrying the MCP session ID on streamable HTTP requests
+const sessionIDHeader = "Mcp-Session-Id"
+
+// SessionBindings binds MCP session IDs to the access token that created them, so a session ID
+// that is guessed or sniffed is useless without the same token
+// It must run after RequireAuth, which guarantees every request carrying a token has a verified
+// one; a session created without a token (initialize allowed for unauthenticated discovery) is
+// bound to the first token used with it
+type SessionBindings struct {
+	mu          sync.Mutex
+	bindings    map[string]*sessionBinding
+	idleTimeout time.Duration
+	clock       clock.Clock
+}
+
+// sessionBinding records which token owns a session
+// Only a hash of the token is kept, like every other token store; it is empty until a session
+// created without a token is first used with one
+type sessionBinding struct {
+	tokenHash string
+	user      string
+	lastSeen  time.Time
+}
+
+// BoundSession describes an active session for operators
+type BoundSession struct {
+	ID string `json:"id"`
+	// User is the GitHub login of the token that created the session
+	User     string    `json:"user,omitempty"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// NewSessionBindings creates an empty binding table
+// Bindings unused for idleTimeout are dropped, matching the MCP handler's session timeout
+func NewSessionBindings(idleTimeout time.Duration) *SessionBindings {
+	return &SessionBindings{
+		bindings:    make(map[string]*sessionBinding),
+		idleTimeout: idleTimeout,
+		clock:       clock.System{},
+	}
+}
+
+// SetClock replaces the clock used to expire idle bindings
+func (b *SessionBindings) SetClock(c clock.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = c
+}
+
+// Middleware checks the session binding of every request that carries a session ID (including
+// GET for SSE streams and DELETE) and records the binding when a new session is created
+// Mismatched or unknown sessions get 404, the same as an expired session, so clients
+// re-initialize and session IDs can't be probed
+func (b *SessionBindings) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var tokenHash, user string
+		if token := extractBearerToken(r.Header.Get("Authorization")); token != "" {
+			tokenHash = hashSecret(token)
+		}
+		if info := auth.TokenInfoFromContext(r.Context()); info != nil {
+			user, _ = info.Extra["subject"].(string)
+		}
+
+		sessionID := r.Header.Get(sessionIDHeader)
+		if sessionID == "" {
+			// A request without a session may create one; bind it to this token, if any
+			recorder := &sessionBindingRecorder{ResponseWriter: w, bindings: b, tokenHash: tokenHash, user: user}
+			next.ServeHTTP(recorder, r)
+			return
+		}
+
+		if !b.check(sessionID, tokenHash, user) {
+			log.Printf("[SECURITY] Rejected %s with session ID not bound to the presented token (remote %s)", r.Method, r.RemoteAddr)
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+
+		if r.Method == http.MethodDelete {
+			b.Unbind(sessionID)
+		}
+	})
+}
+
+// bind records that tokenHash, issued to user, owns sessionID and drops idle bindings
+func (b *SessionBindings) bind(sessionID, tokenHash, user string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	for id, binding := range b.bindings {
+		if now.Sub(binding.lastSeen) > b.idleTimeout {
+			delete(b.bindings, id)
+		}
+	}
+	b.bindings[sessionID] = &sessionBinding{tokenHash: tokenHash, user: user, lastSeen: now}
+}
+
+// check reports whether sessionID is bound to tokenHash and still active
+// A session created without a token is bound to the first token used with it, issued to user;
+// until then it can be used without a token, but once bound only that token can use it
+func (b *SessionBindings) check(sessionID, tokenHash, user string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	binding, ok := b.bindings[sessionID]
+	if !ok {
+		return false
+	}
+	now := b.clock.Now()
+	if now.Sub(binding.lastSeen) > b.idleTimeout {
+		delete(b.bindings, sessionID)
+		return false
+	}
+	if binding.tokenHash == "" && tokenHash != "" {
+		binding.tokenHash = tokenHash
+		binding.user = user
+	}
+	if binding.tokenHash != tokenHash {
+		return false
+	}
+	binding.lastSeen = now
+	return true
+}
+
+// Unbind forgets a session, so later requests carrying its ID get 404
+func (b *SessionBindings) Unbind(sessionID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.bindings, sessionID)
+}
+
+// Sessions returns the sessions that haven't been idle past the timeout, most recently used first
+func (b *SessionBindings) Sessions() []BoundSession {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.clock.Now()
+	sessions := make([]BoundSession, 0, len(b.bindings))
+	for id, binding := range b.bindings {
+		if now.Sub(binding.lastSeen) > b.idleTimeout {
+			continue
+		}
+		sessions = append(sessions, BoundSession{ID: id, User: binding.user, LastSeen: binding.lastSeen})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeen.Equal(sessions[j].LastSeen) {
+			return sessions[i].LastSeen.After(sessions[j].LastSeen)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions
+}
+
+// sessionBindingRecorder binds the session ID the MCP handler assigns in its response headers
+type sessionBindingRecorder struct {
+	http.ResponseWriter
+	bindings    *SessionBindings
+	tokenHash   string
+	user        string
+	wroteHeader bool
+}
+
+func (r *sessionBindingRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		if sessionID := r.Header().Get(sessionIDHeader); sessionID != "" && code < http.StatusBadRequest {
+			r.bindings.bind(sessionID, r.tokenHash, r.user)
+		}
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *sessionBindingRecorder) Write(p []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush forwards to the underlying writer so streaming responses still work
+func (r *sessionBindingRecorder) Flush() {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
 )
 
 // ClientStorage defines the interface for storing and retrieving OAuth clients
@@ -34,15 +36,53 @@ type ClientStorage interface {
 // InMemoryClientStorage provides an in-memory implementation of ClientStorage
 // This is suitable for development and testing, but should be replaced with
 // persistent storage (database, Redis, etc.) for production use
+// Expired clients are swept in the background
 type InMemoryClientStorage struct {
 	mu      sync.RWMutex
 	clients map[string]*OAuthClient
+	clock   clock.Clock
+	sweeper *sweeper
 }
 
 // NewInMemoryClientStorage creates a new in-memory client storage
 func NewInMemoryClientStorage() *InMemoryClientStorage {
-	return &InMemoryClientStorage{
+	s := &InMemoryClientStorage{
 		clients: make(map[string]*OAuthClient),
+		clock:   clock.System{},
+	}
+
+	// Start background cleanup goroutine
+	s.sweeper = startSweeper(s.Sweep)
+
+	return s
+}
+
+// Close stops the background cleanup; the storage stays usable
+func (s *InMemoryClientStorage) Close() {
+	s.sweeper.close()
+}
+
+// SetClock replaces the clock used to expire clients
+func (s *InMemoryClientStorage) SetClock(c clock.Clock) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = c
+}
+
+// expired reports whether client has expired; callers must hold s.mu
+func (s *InMemoryClientStorage) expired(client *OAuthClient) bool {
+	return client.ExpiresAt != nil && s.clock.Now().After(*client.ExpiresAt)
+}
+
+// Sweep removes expired clients
+func (s *InMemoryClientStorage) Sweep() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for clientID, client := range s.clients {
+		if s.expired(client) {
+			delete(s.clients, clientID)
+		}
 	}
 }
 
@@ -50,7 +90,16 @@ func NewInMemoryClientStorage() *InMemoryClientStorage {
 // with optional default clients for common MCP clients
 func NewInMemoryClientStorageWithDefaults() *InMemoryClientStorage {
 	storage := NewInMemoryClientStorage()
-	
+
+	for _, client := range DefaultClients() {
+		_ = storage.StoreClient(client)
+	}
+
+	return storage
+}
+
+// DefaultClients returns the clients pre-registered in every client storage
+func DefaultClients() []*OAuthClient {
 	// Pre-register a generic VS Code client with standard redirect URIs
 	// This allows any VS Code instance to authenticate without explicit registration
 	vsCodeClient := &OAuthClient{
@@ -75,10 +124,8 @@ func NewInMemoryClientStorageWithDefaults() *InMemoryClientStorage {
 		},
 		CreatedAt: time.Now(),
 	}
-	
-	_ = storage.StoreClient(vsCodeClient)
-	
-	return storage
+
+	return []*OAuthClient{vsCodeClient}
 }
 
 // StoreClient stores a registered OAuth client
@@ -106,7 +153,7 @@ func (s *InMemoryClientStorage) GetClient(clientID string) (*OAuthClient, error)
 	defer s.mu.RUnlock()
 
 	client, exists := s.clients[clientID]
-	if !exists {
+	if !exists || s.expired(client) {
 		return nil, fmt.Errorf("client not found: %s", clientID)
 	}
 
@@ -135,6 +182,9 @@ func (s *InMemoryClientStorage) ListClients() ([]*OAuthClient, error) {
 
 	clients := make([]*OAuthClient, 0, len(s.clients))
 	for _, client := range s.clients {
+		if s.expired(client) {
+			continue
+		}
 		clientCopy := *client
 		clients = append(clients, &clientCopy)
 	}
@@ -178,6 +228,11 @@ func hashSecret(secret string) string {
 	return base64.StdEncoding.EncodeToString(hash[:])
 }
 
+// AccessTokenID returns the ID an access token is stored, listed, and revoked under
+func AccessTokenID(token string) string {
+	return hashSecret(token)
+}
+
 // TokenCache defines the interface for caching token validation results
 // This helps reduce calls to GitHub's API for frequently validated tokens
 type TokenCache interface {
@@ -193,8 +248,10 @@ type TokenCache interface {
 
 // InMemoryTokenCache provides an in-memory implementation of TokenCache
 type InMemoryTokenCache struct {
-	mu    sync.RWMutex
-	cache map[string]*cacheEntry
+	mu      sync.RWMutex
+	cache   map[string]*cacheEntry
+	clock   clock.Clock
+	sweeper *sweeper
 }
 
 type cacheEntry struct {
@@ -206,14 +263,27 @@ type cacheEntry struct {
 func NewInMemoryTokenCache() *InMemoryTokenCache {
 	cache := &InMemoryTokenCache{
 		cache: make(map[string]*cacheEntry),
+		clock: clock.System{},
 	}
 
 	// Start background cleanup goroutine
-	go cache.cleanupExpired()
+	cache.sweeper = startSweeper(cache.cleanupExpired)
 
 	return cache
 }
 
+// Close stops the background cleanup; the cache stays usable
+func (c *InMemoryTokenCache) Close() {
+	c.sweeper.close()
+}
+
+// SetClock replaces the clock used to expire cache entries
+func (c *InMemoryTokenCache) SetClock(clk clock.Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock = clk
+}
+
 // Set stores a token validation result with an expiry
 func (c *InMemoryTokenCache) Set(token string, result *TokenValidationResult, expiry time.Duration) error {
 	c.mu.Lock()
@@ -221,7 +291,7 @@ func (c *InMemoryTokenCache) Set(token string, result *TokenValidationResult, ex
 
 	c.cache[token] = &cacheEntry{
 		result:    result,
-		expiresAt: time.Now().Add(expiry),
+		expiresAt: c.clock.Now().Add(expiry),
 	}
 
 	return nil
@@ -238,7 +308,7 @@ func (c *InMemoryTokenCache) Get(token string) (*TokenValidationResult, bool) {
 	}
 
 	// Check if expired
-	if time.Now().After(entry.expiresAt) {
+	if c.clock.Now().After(entry.expiresAt) {
 		return nil, false
 	}
 
@@ -255,19 +325,51 @@ func (c *InMemoryTokenCache) Delete(token string) error {
 	return nil
 }
 
-// cleanupExpired removes expired entries from the cache periodically
+// cleanupExpired removes expired entries from the cache
 func (c *InMemoryTokenCache) cleanupExpired() {
-	ticker := time.NewTicker(5 * time.Minute)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for token, entry := range c.cache {
+		if now.After(entry.expiresAt) {
+			delete(c.cache, token)
+		}
+	}
+}
+
+// sweepInterval is how often in-memory stores remove expired entries
+const sweepInterval = 5 * time.Minute
+
+// sweeper calls a sweep function every sweepInterval until it is closed
+type sweeper struct {
+	stop chan struct{}
+	once sync.Once
+}
+
+// startSweeper starts calling sweep in the background
+func startSweeper(sweep func()) *sweeper {
+	s := &sweeper{stop: make(chan struct{})}
+	go s.run(sweep)
+	return s
+}
+
+// run sweeps on every tick until the sweeper is closed
+func (s *sweeper) run(sweep func()) {
+	ticker := time.NewTicker(sweepInterval)
 	defer ticker.Stop()
 
-	for range ticker.C {
-		c.mu.Lock()
-		now := time.Now()
-		for token, entry := range c.cache {
-			if now.After(entry.expiresAt) {
-				delete(c.cache, token)
-			}
+	for {
+		select {
+		case <-ticker.C:
+			sweep()
+		case <-s.stop:
+			return
 		}
-		c.mu.Unlock()
 	}
 }
+
+// close stops the sweeper; later calls do nothing
+func (s *sweeper) close() {
+	s.once.Do(func() { close(s.stop) })
+}
@@ -0,0 +1,226 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// Storage backends selectable with STORAGE_BACKEND
+const (
+	StorageBackendMemory   = "memory"
+	StorageBackendDynamoDB = "dynamodb"
+	StorageBackendRedis    = "redis"
+	StorageBackendPostgres = "postgres"
+)
+
+// redisConnectTimeout bounds the initial Redis ping at startup
+const redisConnectTimeout = 5 * time.Second
+
+// postgresConnectTimeout bounds connecting to Postgres and creating the table at startup
+const postgresConnectTimeout = 10 * time.Second
+
+// Storage bundles the storage implementations used by the OAuth handlers
+type Storage struct {
+	Clients ClientStorage
+	Tokens  TokenStorage
+	Cache   TokenCache
+	States  StateStorage
+
+	// ping checks the backend is reachable; nil for in-memory storage
+	ping func(ctx context.Context) error
+	// close releases the backend's connections; nil when there are none
+	close func()
+}
+
+// Ping reports whether the storage backend is reachable, for readiness checks
+func (s *Storage) Ping(ctx context.Context) error {
+	if s.ping == nil {
+		return nil
+	}
+	return s.ping(ctx)
+}
+
+// closer is implemented by stores that run background work until closed
+type closer interface {
+	Close()
+}
+
+// Close stops the in-memory stores' background cleanup and closes the backend's connections
+// Call it once the server has stopped serving requests
+func (s *Storage) Close() {
+	for _, store := range []any{s.Clients, s.Tokens, s.Cache, s.States} {
+		if c, ok := store.(closer); ok {
+			c.Close()
+		}
+	}
+	if s.close != nil {
+		s.close()
+	}
+}
+
+// NewStorage creates the storage selected by cfg.StorageBackend, with the default clients registered
+// Redis and Postgres connections are pooled; pool sizes can be tuned with the URL's
+// pool_size or pool_max_conns parameters
+func NewStorage(ctx context.Context, cfg *Config) (*Storage, error) {
+	storage, err := newStorage(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	storage.tolerateClockSkew(cfg)
+	return storage, nil
+}
+
+// newStorage creates the storage selected by cfg.StorageBackend
+func newStorage(ctx context.Context, cfg *Config) (*Storage, error) {
+	switch cfg.StorageBackend {
+	case "", StorageBackendMemory:
+		return &Storage{
+			Clients: NewInMemoryClientStorageWithDefaults(),
+			Tokens:  NewInMemoryTokenStorage(),
+			Cache:   NewInMemoryTokenCache(),
+			States:  NewStateStore(cfg.AuthStateTTL),
+		}, nil
+
+	case StorageBackendDynamoDB:
+		awsCfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load AWS SDK config: %w", err)
+		}
+
+		client := dynamodb.NewFromConfig(awsCfg)
+		storage := NewDynamoDBStorage(client, cfg.DynamoDBTableName)
+		if err := registerDefaultClients(storage); err != nil {
+			return nil, err
+		}
+		log.Printf("Using DynamoDB storage (table %s)", cfg.DynamoDBTableName)
+
+		return &Storage{
+			Clients: storage,
+			Tokens:  storage,
+			Cache:   storage,
+			States:  NewDynamoDBStateStore(client, cfg.DynamoDBTableName, cfg.AuthStateTTL),
+			ping:    storage.Ping,
+		}, nil
+
+	case StorageBackendRedis:
+		opts, err := redis.ParseURL(cfg.RedisURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		client := redis.NewClient(opts)
+
+		pingCtx, cancel := context.WithTimeout(ctx, redisConnectTimeout)
+		defer cancel()
+		if err := client.Ping(pingCtx).Err(); err != nil {
+			_ = client.Close()
+			return nil, fmt.Errorf("unable to connect to Redis: %w", err)
+		}
+
+		storage := NewRedisStorage(client, cfg.RedisKeyPrefix)
+		if err := registerDefaultClients(storage); err != nil {
+			_ = client.Close()
+			return nil, err
+		}
+		log.Printf("Using Redis storage (%s, key prefix %q)", opts.Addr, cfg.RedisKeyPrefix)
+
+		return &Storage{
+			Clients: storage,
+			Tokens:  storage,
+			Cache:   storage,
+			States:  NewRedisStateStore(client, cfg.RedisKeyPrefix, cfg.AuthStateTTL),
+			ping: func(ctx context.Context) error {
+				if err := client.Ping(ctx).Err(); err != nil {
+					return fmt.Errorf("unable to reach Redis: %w", err)
+				}
+				return nil
+			},
+			close: func() { _ = client.Close() },
+		}, nil
+
+	case StorageBackendPostgres:
+		pool, err := pgxpool.New(ctx, cfg.PostgresURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid POSTGRES_URL: %w", err)
+		}
+
+		setupCtx, cancel := context.WithTimeout(ctx, postgresConnectTimeout)
+		defer cancel()
+		if err := pool.Ping(setupCtx); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("unable to connect to Postgres: %w", err)
+		}
+		if err := CreatePostgresTable(setupCtx, pool, cfg.PostgresTableName); err != nil {
+			pool.Close()
+			return nil, err
+		}
+
+		storage := NewPostgresStorage(pool, cfg.PostgresTableName)
+		if err := registerDefaultClients(storage); err != nil {
+			pool.Close()
+			return nil, err
+		}
+		log.Printf("Using Postgres storage (%s, table %s, up to %d connections)",
+			pool.Config().ConnConfig.Host, cfg.PostgresTableName, pool.Config().MaxConns)
+
+		return &Storage{
+			Clients: storage,
+			Tokens:  storage,
+			Cache:   storage,
+			States:  NewPostgresStateStore(pool, cfg.PostgresTableName, cfg.AuthStateTTL),
+			ping: func(ctx context.Context) error {
+				if err := pool.Ping(ctx); err != nil {
+					return fmt.Errorf("unable to reach Postgres: %w", err)
+				}
+				return nil
+			},
+			close: pool.Close,
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage backend: %s", cfg.StorageBackend)
+	}
+}
+
+// clockSetter is implemented by stores whose expiry checks use an injectable clock
+type clockSetter interface {
+	SetClock(c clock.Clock)
+}
+
+// tolerateClockSkew makes expiry checks allow for cfg.ClockSkew, so tokens, codes, and states
+// written by an instance whose clock runs ahead aren't expired early by this one
+func (s *Storage) tolerateClockSkew(cfg *Config) {
+	if cfg.ClockSkew <= 0 {
+		return
+	}
+	for _, store := range []any{s.Clients, s.Tokens, s.States} {
+		if setter, ok := store.(clockSetter); ok {
+			setter.SetClock(cfg.expiryClock())
+		}
+	}
+}
+
+// registerDefaultClients stores any default client that isn't registered yet
+func registerDefaultClients(storage ClientStorage) error {
+	for _, client := range DefaultClients() {
+		if _, err := storage.GetClient(client.ClientID); err == nil {
+			continue
+		}
+		if err := storage.StoreClient(client); err != nil {
+			return fmt.Errorf("failed to register default client %s: %w", client.ClientID, err)
+		}
+	}
+	return nil
+}
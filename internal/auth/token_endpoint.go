@@ -0,0 +1,449 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/audit"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/telemetry"
+)
+
+// TokenEndpointHandler handles OAuth 2.1 token requests
+type TokenEndpointHandler struct {
+	config        *Config
+	clientStorage ClientStorage
+	tokenStorage  TokenStorage
+	jwtIssuer     *JWTIssuer
+}
+
+// NewTokenEndpointHandler creates a new token endpoint handler
+func NewTokenEndpointHandler(config *Config, clientStorage ClientStorage, tokenStorage TokenStorage) *TokenEndpointHandler {
+	return &TokenEndpointHandler{
+		config:        config,
+		clientStorage: clientStorage,
+		tokenStorage:  tokenStorage,
+	}
+}
+
+// SetJWTIssuer makes the handler issue signed JWT access tokens instead of storing opaque ones
+func (h *TokenEndpointHandler) SetJWTIssuer(issuer *JWTIssuer) {
+	h.jwtIssuer = issuer
+}
+
+// ServeHTTP implements http.Handler
+func (h *TokenEndpointHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	telemetry.Handler("oauth.token", http.HandlerFunc(h.serveToken)).ServeHTTP(w, r)
+}
+
+// serveToken issues an access token for an authorization code or, for allowed confidential
+// clients, for the client's own credentials
+func (h *TokenEndpointHandler) serveToken(w http.ResponseWriter, r *http.Request) {
+	// Only allow POST requests
+	if r.Method != http.MethodPost {
+		h.sendError(w, "invalid_request", "Only POST method is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Parse form data
+	if err := r.ParseForm(); err != nil {
+		h.sendError(w, "invalid_request", "Invalid form data", http.StatusBadRequest)
+		return
+	}
+
+	grantType := r.FormValue("grant_type")
+	trace.SpanFromContext(r.Context()).SetAttributes(
+		attribute.String("oauth.grant_type", grantType),
+		attribute.String("oauth.client_id", r.FormValue("client_id")),
+	)
+	switch grantType {
+	case "authorization_code":
+		h.exchangeAuthorizationCode(w, r)
+	case "client_credentials":
+		h.issueClientCredentialsToken(w, r)
+	case DeviceCodeGrantType:
+		h.exchangeDeviceCode(w, r)
+	default:
+		h.sendError(w, "unsupported_grant_type", "Only authorization_code, client_credentials, and device_code grant types are supported", http.StatusBadRequest)
+	}
+}
+
+// exchangeAuthorizationCode exchanges an authorization code for an access token
+func (h *TokenEndpointHandler) exchangeAuthorizationCode(w http.ResponseWriter, r *http.Request) {
+	code := r.FormValue("code")
+	if code == "" {
+		h.sendError(w, "invalid_request", "code is required", http.StatusBadRequest)
+		return
+	}
+
+	clientID := r.FormValue("client_id")
+	if clientID == "" {
+		h.sendError(w, "invalid_request", "client_id is required", http.StatusBadRequest)
+		return
+	}
+
+	codeVerifier := r.FormValue("code_verifier")
+	if codeVerifier == "" {
+		h.sendError(w, "invalid_request", "code_verifier is required (PKCE)", http.StatusBadRequest)
+		return
+	}
+
+	redirectURI := r.FormValue("redirect_uri")
+	if redirectURI == "" {
+		h.sendError(w, "invalid_request", "redirect_uri is required", http.StatusBadRequest)
+		return
+	}
+
+	// Validate client
+	client, err := h.clientStorage.GetClient(clientID)
+	if err != nil || client == nil {
+		log.Printf("Unknown client_id in token request: %s", clientID)
+		auditFailure(r, audit.UnknownClient, clientID)
+		h.sendError(w, "invalid_client", "Unknown client_id", http.StatusUnauthorized)
+		return
+	}
+
+	// Retrieve auth code info
+	authCodeInfo, err := h.tokenStorage.GetAuthCode(code)
+	if err != nil {
+		log.Printf("Invalid or expired authorization code")
+		h.sendError(w, "invalid_grant", "Invalid or expired authorization code", http.StatusBadRequest)
+		return
+	}
+
+	// Verify client_id matches
+	if authCodeInfo.ClientID != clientID {
+		log.Printf("client_id mismatch: expected %s, got %s", authCodeInfo.ClientID, clientID)
+		h.sendError(w, "invalid_grant", "client_id mismatch", http.StatusBadRequest)
+		return
+	}
+
+	// Verify redirect_uri matches
+	if authCodeInfo.RedirectURI != redirectURI {
+		log.Printf("redirect_uri mismatch: expected %s, got %s", authCodeInfo.RedirectURI, redirectURI)
+		h.sendError(w, "invalid_grant", "redirect_uri mismatch", http.StatusBadRequest)
+		return
+	}
+
+	// Verify PKCE code_verifier
+	if !verifyPKCE(codeVerifier, authCodeInfo.CodeChallenge, authCodeInfo.CodeChallengeMethod) {
+		log.Printf("PKCE verification failed")
+		auditFailure(r, audit.PKCEFailed, clientID)
+		h.sendError(w, "invalid_grant", "PKCE verification failed", http.StatusBadRequest)
+		return
+	}
+
+	// Redeem the authorization code (one-time use); of several concurrent requests that passed
+	// the checks above, only one gets it
+	if _, err := h.tokenStorage.ConsumeAuthCode(code); err != nil {
+		log.Printf("Authorization code was already redeemed")
+		h.sendError(w, "invalid_grant", "Invalid or expired authorization code", http.StatusBadRequest)
+		return
+	}
+
+	now := h.config.now()
+	tokenInfo := &AccessTokenInfo{
+		ClientID:          clientID,
+		Scope:             authCodeInfo.Scope,
+		Resource:          authCodeInfo.Resource,
+		GitHubAccessToken: authCodeInfo.GitHubAccessToken,
+		ExpiresAt:         now.Add(h.config.TokenExpiryDuration),
+		CreatedAt:         now,
+	}
+	h.recordClientUse(client, now)
+	h.issueToken(w, r, tokenInfo, authCodeInfo.Subject)
+}
+
+// exchangeDeviceCode exchanges a device code for an access token once the user has approved it
+// Until then the client is told to keep polling, and to slow down if it polls too often
+func (h *TokenEndpointHandler) exchangeDeviceCode(w http.ResponseWriter, r *http.Request) {
+	deviceCode := r.FormValue("device_code")
+	if deviceCode == "" {
+		h.sendError(w, "invalid_request", "device_code is required", http.StatusBadRequest)
+		return
+	}
+
+	clientID := r.FormValue("client_id")
+	if clientID == "" {
+		h.sendError(w, "invalid_request", "client_id is required", http.StatusBadRequest)
+		return
+	}
+
+	client, err := h.clientStorage.GetClient(clientID)
+	if err != nil || client == nil {
+		auditFailure(r, audit.UnknownClient, clientID)
+		h.sendError(w, "invalid_client", "Unknown client_id", http.StatusUnauthorized)
+		return
+	}
+
+	info, err := h.tokenStorage.GetAuthCode(devicePrefix + deviceCode)
+	if err != nil {
+		h.sendError(w, "expired_token", "Invalid or expired device code", http.StatusBadRequest)
+		return
+	}
+	if info.ClientID != clientID {
+		log.Printf("client_id mismatch: expected %s, got %s", info.ClientID, clientID)
+		h.sendError(w, "invalid_grant", "client_id mismatch", http.StatusBadRequest)
+		return
+	}
+
+	if info.Denied {
+		if err := h.tokenStorage.DeleteAuthCode(devicePrefix + deviceCode); err != nil {
+			log.Printf("Failed to delete device code: %v", err)
+		}
+		h.forgetDevicePolls(deviceCode)
+		h.sendError(w, "access_denied", "The user may not connect", http.StatusBadRequest)
+		return
+	}
+
+	now := h.config.now()
+	if info.GitHubAccessToken == "" {
+		h.pollPendingDeviceCode(w, deviceCode, info, now)
+		return
+	}
+
+	// Redeem the device code; if a concurrent poll got there first, this one is too late
+	info, err = h.tokenStorage.ConsumeAuthCode(devicePrefix + deviceCode)
+	if err != nil {
+		h.sendError(w, "expired_token", "Invalid or expired device code", http.StatusBadRequest)
+		return
+	}
+	h.forgetDevicePolls(deviceCode)
+
+	tokenInfo := &AccessTokenInfo{
+		ClientID:          clientID,
+		Scope:             info.Scope,
+		Resource:          info.Resource,
+		GitHubAccessToken: info.GitHubAccessToken,
+		ExpiresAt:         now.Add(h.config.TokenExpiryDuration),
+		CreatedAt:         now,
+	}
+	h.recordClientUse(client, now)
+	h.issueToken(w, r, tokenInfo, info.Subject)
+}
+
+// pollPendingDeviceCode records a poll of a device code the user hasn't decided on yet, telling
+// the client to slow down if it polled within its interval, which then grows by devicePollInterval
+// (RFC 8628 section 3.5)
+// Polls are recorded under their own key, so they can't overwrite a concurrent approval or denial
+func (h *TokenEndpointHandler) pollPendingDeviceCode(w http.ResponseWriter, deviceCode string, info *AuthCodeInfo, now time.Time) {
+	poll := &AuthCodeInfo{
+		ClientID:     info.ClientID,
+		PollInterval: devicePollInterval,
+		ExpiresAt:    info.ExpiresAt,
+		CreatedAt:    now,
+	}
+	tooSoon := false
+	if last, err := h.tokenStorage.GetAuthCode(devicePollPrefix + deviceCode); err == nil {
+		poll.PollInterval = last.PollInterval
+		tooSoon = now.Sub(last.LastPolledAt) < last.PollInterval
+	}
+	if tooSoon {
+		poll.PollInterval += devicePollInterval
+	}
+	poll.LastPolledAt = now
+	if err := h.tokenStorage.StoreAuthCode(devicePollPrefix+deviceCode, poll); err != nil {
+		log.Printf("Failed to store device poll: %v", err)
+	}
+
+	if tooSoon {
+		h.sendError(w, "slow_down", fmt.Sprintf("Polling too often; wait %d seconds between requests", int(poll.PollInterval.Seconds())), http.StatusBadRequest)
+		return
+	}
+	h.sendError(w, "authorization_pending", "The user hasn't approved the device yet", http.StatusBadRequest)
+}
+
+// forgetDevicePolls deletes the poll record of a device code that has been redeemed or denied
+func (h *TokenEndpointHandler) forgetDevicePolls(deviceCode string) {
+	if err := h.tokenStorage.DeleteAuthCode(devicePollPrefix + deviceCode); err != nil {
+		log.Printf("Failed to delete device poll: %v", err)
+	}
+}
+
+// issueClientCredentialsToken issues a token to a confidential client acting on its own behalf,
+// e.g. CI calling tools without a browser; the token is tied to the client, not a GitHub user
+func (h *TokenEndpointHandler) issueClientCredentialsToken(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.FormValue("client_id"), r.FormValue("client_secret")
+	}
+	if clientID == "" || clientSecret == "" {
+		h.sendError(w, "invalid_client", "Client authentication is required", http.StatusUnauthorized)
+		return
+	}
+
+	client, err := h.clientStorage.GetClient(clientID)
+	if err != nil || client == nil || client.ClientSecret == "" {
+		auditFailure(r, audit.UnknownClient, clientID)
+		h.sendError(w, "invalid_client", "Unknown or public client", http.StatusUnauthorized)
+		return
+	}
+	if valid, err := h.clientStorage.ValidateClientSecret(clientID, clientSecret); err != nil || !valid {
+		log.Printf("[SECURITY] Invalid client secret for %s", clientID)
+		h.sendError(w, "invalid_client", "Invalid client credentials", http.StatusUnauthorized)
+		return
+	}
+	if !slices.Contains(client.Metadata.GrantTypes, "client_credentials") || !h.config.AllowsClientCredentials(clientID) {
+		h.sendError(w, "unauthorized_client", "Client is not allowed to use the client_credentials grant", http.StatusBadRequest)
+		return
+	}
+
+	// Clients get the scopes they registered, or the subset they ask for
+	registered := strings.Fields(client.Metadata.Scope)
+	scopes := registered
+	if requested := r.FormValue("scope"); requested != "" {
+		scopes = strings.Fields(requested)
+	}
+	for _, scope := range scopes {
+		if !slices.Contains(registered, scope) || !h.config.IsScopeSupported(scope) {
+			h.sendError(w, "invalid_scope", fmt.Sprintf("Scope '%s' is not allowed for this client", scope), http.StatusBadRequest)
+			return
+		}
+	}
+	// Anyone can register a client asking for mcp:admin, so only the operator can grant it
+	if slices.Contains(scopes, adminScope) && !h.config.AllowsClientCredentialsAdmin(clientID) {
+		log.Printf("[SECURITY] Dropped %s from the client_credentials token of %s, which is not in CLIENT_CREDENTIALS_ADMIN_CLIENTS", adminScope, clientID)
+		scopes = slices.DeleteFunc(slices.Clone(scopes), func(scope string) bool { return scope == adminScope })
+		if len(scopes) == 0 {
+			h.sendError(w, "invalid_scope", fmt.Sprintf("Scope '%s' is not allowed for this client", adminScope), http.StatusBadRequest)
+			return
+		}
+	}
+
+	now := h.config.now()
+	tokenInfo := &AccessTokenInfo{
+		ClientID:  clientID,
+		Scope:     strings.Join(scopes, " "),
+		Resource:  r.FormValue("resource"),
+		GrantType: "client_credentials",
+		ExpiresAt: now.Add(h.config.TokenExpiryDuration),
+		CreatedAt: now,
+	}
+	log.Printf("[OAUTH] Issued client_credentials token to %s (scope %q)", clientID, tokenInfo.Scope)
+	h.recordClientUse(client, now)
+	h.issueToken(w, r, tokenInfo, ServiceClientSubject(clientID))
+}
+
+// clientUseResolution is how often a client's last use is recorded, so busy clients don't
+// rewrite their registration on every token
+const clientUseResolution = 24 * time.Hour
+
+// recordClientUse records that client was issued a token and pushes back its expiry
+func (h *TokenEndpointHandler) recordClientUse(client *OAuthClient, now time.Time) {
+	if client.LastUsedAt != nil && now.Sub(*client.LastUsedAt) < clientUseResolution {
+		return
+	}
+	used := *client
+	used.LastUsedAt = &now
+	if client.ExpiresAt != nil {
+		used.ExpiresAt = h.config.clientExpiry(client, now)
+	}
+	if err := h.clientStorage.StoreClient(&used); err != nil {
+		log.Printf("Failed to record use of client %s: %v", client.ClientID, err)
+	}
+}
+
+// auditFailure records a failed token request of eventType, which the anomaly detector watches
+func auditFailure(r *http.Request, eventType, clientID string) {
+	audit.Default.Record(r, audit.Event{Type: eventType, ClientID: clientID, GrantType: r.FormValue("grant_type")})
+}
+
+// issueToken creates, stores, and returns an access token for tokenInfo, and audits the issuance
+func (h *TokenEndpointHandler) issueToken(w http.ResponseWriter, r *http.Request, tokenInfo *AccessTokenInfo, subject string) {
+	var accessToken string
+	var err error
+	if h.jwtIssuer != nil {
+		// JWTs carry everything needed to verify them, so nothing is stored
+		accessToken, err = h.jwtIssuer.Issue(tokenInfo, subject)
+		if err != nil {
+			log.Printf("Failed to issue JWT access token: %v", err)
+			h.sendError(w, "server_error", "Failed to generate access token", http.StatusInternalServerError)
+			return
+		}
+	} else {
+		// Generate access token
+		accessToken, err = generateRandomString(43) // 43 bytes = ~256 bits
+		if err != nil {
+			log.Printf("Failed to generate access token: %v", err)
+			h.sendError(w, "server_error", "Failed to generate access token", http.StatusInternalServerError)
+			return
+		}
+
+		// Store access token, with its subject so operators can see who holds it
+		tokenInfo.Subject = subject
+		if err := h.tokenStorage.StoreAccessToken(accessToken, tokenInfo); err != nil {
+			log.Printf("Failed to store access token: %v", err)
+			h.sendError(w, "server_error", "Failed to store access token", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	audit.Default.Record(r, audit.Event{
+		Type:      audit.TokenIssued,
+		ClientID:  tokenInfo.ClientID,
+		User:      subject,
+		GrantType: r.FormValue("grant_type"),
+	})
+
+	// Return token response
+	response := map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(h.config.TokenExpiryDuration.Seconds()),
+		"scope":        tokenInfo.Scope,
+	}
+
+	if tokenInfo.Resource != "" {
+		response["resource"] = tokenInfo.Resource
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode token response: %v", err)
+	}
+}
+
+// sendError sends an OAuth error response
+func (h *TokenEndpointHandler) sendError(w http.ResponseWriter, errorCode, errorDescription string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	response := map[string]string{
+		"error":             errorCode,
+		"error_description": errorDescription,
+	}
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode error response: %v", err)
+	}
+}
+
+// verifyPKCE verifies the PKCE code_verifier against the code_challenge
+func verifyPKCE(codeVerifier, codeChallenge, method string) bool {
+	if method != "S256" {
+		return false
+	}
+
+	// Compute SHA256 hash of code_verifier
+	hash := sha256.Sum256([]byte(codeVerifier))
+	computed := base64.RawURLEncoding.EncodeToString(hash[:])
+
+	return computed == codeChallenge
+}
@@ -15,15 +15,22 @@ import (
 // TokenProxyHandler proxies token requests to GitHub to avoid CORS issues
 type TokenProxyHandler struct {
 	config *Config
+	budget *GitHubBudget
 }
 
 // NewTokenProxyHandler creates a new token proxy handler
 func NewTokenProxyHandler(config *Config) *TokenProxyHandler {
 	return &TokenProxyHandler{
 		config: config,
+		budget: NewGitHubBudget(config.GitHubAPIBudgetReserve),
 	}
 }
 
+// SetGitHubBudget makes the proxied token requests spend budget, normally the verifier's
+func (h *TokenProxyHandler) SetGitHubBudget(budget *GitHubBudget) {
+	h.budget = budget
+}
+
 // ServeHTTP implements http.Handler
 func (h *TokenProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	// Only allow POST requests
@@ -40,8 +47,9 @@ func (h *TokenProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	// Forward the request to GitHub's token endpoint
 	formData := url.Values{}
-	formData.Set("client_id", h.config.GitHubClientID)
-	formData.Set("client_secret", h.config.GitHubClientSecret)
+	clientID, clientSecret := h.config.GitHubCredentials()
+	formData.Set("client_id", clientID)
+	formData.Set("client_secret", clientSecret)
 	formData.Set("code", r.FormValue("code"))
 	formData.Set("redirect_uri", r.FormValue("redirect_uri"))
 	formData.Set("code_verifier", r.FormValue("code_verifier"))
@@ -58,8 +66,7 @@ func (h *TokenProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	req.Header.Set("Accept", "application/json")
 
 	// Send request to GitHub
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := doGitHub(h.budget, &http.Client{}, req)
 	if err != nil {
 		http.Error(w, "Failed to exchange token", http.StatusInternalServerError)
 		return
@@ -116,7 +123,8 @@ func (h *AuthorizeProxyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 
 	// Ensure client_id is set
 	if query.Get("client_id") == "" {
-		query.Set("client_id", h.config.GitHubClientID)
+		clientID, _ := h.config.GitHubCredentials()
+		query.Set("client_id", clientID)
 	}
 
 	authURL.RawQuery = query.Encode()
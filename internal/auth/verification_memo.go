@@ -0,0 +1,96 @@
+package auth
+
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// verificationMemo remembers token verification results for the lifetime of one HTTP request,
+// so stacked middleware and handlers verifying the same token only validate it once
+type verificationMemo struct {
+	mu      sync.Mutex
+	results map[string]memoizedVerification
+}
+
+// memoizedVerification is the outcome of one Verify call
+type memoizedVerification struct {
+	info *auth.TokenInfo
+	err  error
+}
+
+// verificationMemoKey is the context key for the request's verificationMemo
+type verificationMemoKey struct{}
+
+// WithVerificationMemo returns a context in which Verify results are memoized
+// It returns ctx unchanged if it already carries a memo, so nested middleware share one
+func WithVerificationMemo(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(verificationMemoKey{}).(*verificationMemo); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, verificationMemoKey{}, &verificationMemo{results: make(map[string]memoizedVerification)})
+}
+
+// verificationMemoFrom returns the memo installed by WithVerificationMemo, or nil
+func verificationMemoFrom(ctx context.Context) *verificationMemo {
+	memo, _ := ctx.Value(verificationMemoKey{}).(*verificationMemo)
+	return memo
+}
+
+// get returns the memoized result for a token hash
+func (m *verificationMemo) get(tokenHash string) (memoizedVerification, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result, ok := m.results[tokenHash]
+	return result, ok
+}
+
+// put memoizes the result for a token hash
+func (m *verificationMemo) put(tokenHash string, result memoizedVerification) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[tokenHash] = result
+}
+
+// VerifierStats counts token verifications
+type VerifierStats struct {
+	// Verifications is how many tokens were actually validated
+	Verifications int64 `json:"verifications"`
+
+	// DuplicatesAvoided is how many repeat verifications within a request were answered from its memo
+	DuplicatesAvoided int64 `json:"duplicate_verifications_avoided"`
+
+	// CacheHits and CacheMisses count GitHub validation results found or not found in the token cache
+	CacheHits   int64 `json:"cache_hits"`
+	CacheMisses int64 `json:"cache_misses"`
+}
+
+// VerifierStatsHandler reports a verifier's counters as JSON
+type VerifierStatsHandler struct {
+	verifier *GitHubTokenVerifier
+}
+
+// NewVerifierStatsHandler creates a new handler for the given verifier
+func NewVerifierStatsHandler(verifier *GitHubTokenVerifier) *VerifierStatsHandler {
+	return &VerifierStatsHandler{verifier: verifier}
+}
+
+// ServeHTTP implements http.Handler
+func (h *VerifierStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.verifier.Stats()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,242 @@
+// Package breaker provides circuit breakers for calls to upstream APIs, so a failing
+// dependency is given time to recover instead of being hammered by every request
+package breaker
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// ErrOpen is returned when a call is refused because the circuit is open
+var ErrOpen = errors.New("circuit breaker open")
+
+// State is the state of a circuit breaker
+type State string
+
+const (
+	// StateClosed lets every call through
+	StateClosed State = "closed"
+
+	// StateOpen refuses every call until the cooldown has passed
+	StateOpen State = "open"
+
+	// StateHalfOpen lets a single trial call through to test whether the upstream has recovered
+	StateHalfOpen State = "half-open"
+)
+
+// Breaker opens after a number of consecutive failures and lets a trial call through
+// once the cooldown has passed
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	clock     clock.Clock
+
+	mu        sync.Mutex
+	state     State
+	failures  int
+	openedAt  time.Time
+	lastError string
+	trial     bool
+}
+
+// Status is a snapshot of a circuit breaker's state
+type Status struct {
+	Name      string    `json:"name"`
+	State     State     `json:"state"`
+	Failures  int       `json:"consecutive_failures"`
+	OpenedAt  time.Time `json:"opened_at,omitzero"`
+	RetryAt   time.Time `json:"retry_at,omitzero"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// New creates a breaker that opens after threshold consecutive failures and registers it
+// in the default registry, replacing any breaker with the same name
+func New(name string, threshold int, cooldown time.Duration) *Breaker {
+	b := &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		clock:     clock.System{},
+		state:     StateClosed,
+	}
+	Default.register(b)
+	return b
+}
+
+// SetClock replaces the clock used to time the cooldown (used in tests)
+func (b *Breaker) SetClock(c clock.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = c
+}
+
+// Name returns the breaker's name
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Allow returns ErrOpen if the call should not be made
+// Every allowed call must be followed by Success or Failure
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		retryAt := b.openedAt.Add(b.cooldown)
+		if b.clock.Now().Before(retryAt) {
+			return fmt.Errorf("%w: %s until %s", ErrOpen, b.name, retryAt.Format(time.RFC3339))
+		}
+		b.state = StateHalfOpen
+		b.trial = true
+		return nil
+	case StateHalfOpen:
+		if b.trial {
+			return fmt.Errorf("%w: %s is testing recovery", ErrOpen, b.name)
+		}
+		b.trial = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success records a successful call and closes the circuit
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != StateClosed {
+		log.Printf("[BREAKER] %s closed after successful call", b.name)
+	}
+	b.state = StateClosed
+	b.failures = 0
+	b.trial = false
+}
+
+// Failure records a failed call and opens the circuit once the threshold is reached
+func (b *Breaker) Failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if err != nil {
+		b.lastError = err.Error()
+	}
+
+	if b.state == StateHalfOpen || b.failures >= b.threshold {
+		if b.state != StateOpen {
+			log.Printf("[BREAKER] %s opened after %d consecutive failures: %s", b.name, b.failures, b.lastError)
+		}
+		b.state = StateOpen
+		b.openedAt = b.clock.Now()
+	}
+}
+
+// Cancel records that an allowed call was never made, such as one refused by a rate limit
+// budget, so it counts as neither a success nor a failure and frees a half-open trial
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// Do runs fn if the circuit allows it and records the outcome
+func (b *Breaker) Do(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	if err != nil {
+		b.Failure(err)
+	} else {
+		b.Success()
+	}
+	return err
+}
+
+// Reset manually closes the circuit
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = StateClosed
+	b.failures = 0
+	b.trial = false
+	b.lastError = ""
+	log.Printf("[BREAKER] %s manually reset", b.name)
+}
+
+// Status returns a snapshot of the breaker's state
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := Status{
+		Name:      b.name,
+		State:     b.state,
+		Failures:  b.failures,
+		LastError: b.lastError,
+	}
+	if b.state != StateClosed {
+		status.OpenedAt = b.openedAt
+		status.RetryAt = b.openedAt.Add(b.cooldown)
+	}
+	return status
+}
+
+// Registry tracks every circuit breaker so their state can be reported and reset
+type Registry struct {
+	mu       sync.Mutex
+	breakers map[string]*Breaker
+}
+
+// Default is the registry New adds breakers to
+var Default = &Registry{breakers: make(map[string]*Breaker)}
+
+func (r *Registry) register(b *Breaker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.breakers[b.name] = b
+}
+
+// Statuses returns the state of every registered breaker, sorted by name
+func (r *Registry) Statuses() []Status {
+	r.mu.Lock()
+	breakers := make([]*Breaker, 0, len(r.breakers))
+	for _, b := range r.breakers {
+		breakers = append(breakers, b)
+	}
+	r.mu.Unlock()
+
+	statuses := make([]Status, 0, len(breakers))
+	for _, b := range breakers {
+		statuses = append(statuses, b.Status())
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// Reset manually closes the named breaker
+func (r *Registry) Reset(name string) error {
+	r.mu.Lock()
+	b, ok := r.breakers[name]
+	r.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("unknown circuit breaker: %s", name)
+	}
+	b.Reset()
+	return nil
+}
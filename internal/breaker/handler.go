@@ -0,0 +1,36 @@
+package breaker
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// StatusHandler reports circuit breaker state on GET and resets a breaker on POST ?name=<breaker>
+type StatusHandler struct {
+	registry *Registry
+}
+
+// NewStatusHandler creates a new handler for the given registry
+func NewStatusHandler(registry *Registry) *StatusHandler {
+	return &StatusHandler{registry: registry}
+}
+
+// ServeHTTP implements http.Handler
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost:
+		if err := h.registry.Reset(r.URL.Query().Get("name")); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.registry.Statuses()); err != nil {
+		http.Error(w, "Failed to encode status", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,30 @@
+// Package clock provides an injectable source of the current time so expiry
+// logic can be tested without sleeping
+package clock
+
+import "time"
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the Clock backed by the operating system's wall clock
+type System struct{}
+
+// Now returns the current local time
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Behind is a Clock reading Offset behind Clock, for expiry checks that tolerate clock skew: a
+// deadline set by a host whose clock runs up to Offset ahead isn't passed early
+type Behind struct {
+	Clock  Clock
+	Offset time.Duration
+}
+
+// Now returns the wrapped clock's time minus Offset
+func (b Behind) Now() time.Time {
+	return b.Clock.Now().Add(-b.Offset)
+}
@@ -0,0 +1,168 @@
+// Package cors implements per-route Cross-Origin Resource Sharing policies with real preflight handling
+package cors
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Wildcard allows any origin when listed in Policy.AllowedOrigins
+const Wildcard = "*"
+
+// Policy describes which cross-origin requests a route accepts
+type Policy struct {
+	// AllowedOrigins lists exact origins (scheme://host[:port]), subdomain patterns such as
+	// https://*.example.com, or Wildcard
+	AllowedOrigins []string
+
+	// AllowedMethods lists the methods a preflight may request
+	AllowedMethods []string
+
+	// AllowedHeaders lists the request headers a preflight may request (case-insensitive)
+	AllowedHeaders []string
+
+	// ExposedHeaders lists the response headers scripts may read
+	ExposedHeaders []string
+
+	// AllowCredentials lets browsers send cookies and Authorization to explicitly listed origins
+	// It is never sent for origins only matched by Wildcard
+	AllowCredentials bool
+
+	// MaxAge is how long browsers may cache a preflight result (not sent if zero)
+	MaxAge time.Duration
+}
+
+// matchOrigin reports whether origin is allowed and whether it was listed explicitly
+func (p Policy) matchOrigin(origin string) (allowed, explicit bool) {
+	for _, allowedOrigin := range p.AllowedOrigins {
+		if allowedOrigin == origin || matchSubdomain(allowedOrigin, origin) {
+			return true, true
+		}
+		if allowedOrigin == Wildcard {
+			allowed = true
+		}
+	}
+	return allowed, false
+}
+
+// matchSubdomain reports whether origin matches a pattern like https://*.example.com, which allows
+// subdomains at any depth with the same scheme and port, but not example.com itself
+func matchSubdomain(pattern, origin string) bool {
+	scheme, domain, ok := strings.Cut(pattern, "://*.")
+	if !ok {
+		return false
+	}
+	host, ok := strings.CutPrefix(origin, scheme+"://")
+	if !ok {
+		return false
+	}
+	subdomain, ok := strings.CutSuffix(host, "."+domain)
+	return ok && subdomain != "" && !strings.ContainsAny(subdomain, ":/@")
+}
+
+func (p Policy) methodAllowed(method string) bool {
+	for _, allowed := range p.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// headersAllowed reports whether every header in a comma-separated Access-Control-Request-Headers is allowed
+func (p Policy) headersAllowed(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		found := false
+		for _, allowed := range p.AllowedHeaders {
+			if strings.EqualFold(allowed, header) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// setOriginHeaders writes the headers shared by preflight and actual responses
+func (p Policy) setOriginHeaders(h http.Header, origin string, explicit bool) {
+	if explicit {
+		h.Set("Access-Control-Allow-Origin", origin)
+		if p.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+	} else {
+		h.Set("Access-Control-Allow-Origin", Wildcard)
+	}
+}
+
+// Handler wraps next with the policy
+// Preflight requests are answered here and never reach next; disallowed preflights get 403
+// Other requests always reach next, with CORS headers only if their origin is allowed
+func (p Policy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+
+		requestedMethod := r.Header.Get("Access-Control-Request-Method")
+		if r.Method == http.MethodOptions && origin != "" && requestedMethod != "" {
+			p.preflight(w, r, origin, requestedMethod)
+			return
+		}
+
+		if origin != "" {
+			if allowed, explicit := p.matchOrigin(origin); allowed {
+				p.setOriginHeaders(w.Header(), origin, explicit)
+				if len(p.ExposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", strings.Join(p.ExposedHeaders, ", "))
+				}
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// preflight answers a CORS preflight request
+func (p Policy) preflight(w http.ResponseWriter, r *http.Request, origin, requestedMethod string) {
+	h := w.Header()
+	h.Add("Vary", "Access-Control-Request-Method")
+	h.Add("Vary", "Access-Control-Request-Headers")
+
+	allowed, explicit := p.matchOrigin(origin)
+	requestedHeaders := r.Header.Get("Access-Control-Request-Headers")
+	if !allowed || !p.methodAllowed(requestedMethod) || !p.headersAllowed(requestedHeaders) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	p.setOriginHeaders(h, origin, explicit)
+	h.Set("Access-Control-Allow-Methods", strings.Join(p.AllowedMethods, ", "))
+	if len(p.AllowedHeaders) > 0 {
+		h.Set("Access-Control-Allow-Headers", strings.Join(p.AllowedHeaders, ", "))
+	}
+	if p.MaxAge > 0 {
+		h.Set("Access-Control-Max-Age", strconv.Itoa(int(p.MaxAge.Seconds())))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ParseOrigins splits a comma-separated origin list, dropping blanks and trailing slashes
+func ParseOrigins(list string) []string {
+	var origins []string
+	for _, origin := range strings.Split(list, ",") {
+		origin = strings.TrimSuffix(strings.TrimSpace(origin), "/")
+		if origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
@@ -0,0 +1,63 @@
+// Package docs serves the project's documentation (tool usage, authentication, and the HTTP API)
+// from Markdown embedded in the binary, as MCP resources and as HTML pages
+package docs
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed pages/*.md
+var pages embed.FS
+
+// ErrNotFound is returned for names that don't match a page
+var ErrNotFound = errors.New("documentation page not found")
+
+// Page is a Markdown document; its name is the file name without .md
+type Page struct {
+	Name    string
+	Title   string // The first "# " heading, or the name if there is none
+	Content string
+}
+
+// List returns every page, sorted by name
+func List() []Page {
+	entries, _ := fs.ReadDir(pages, "pages")
+	list := make([]Page, 0, len(entries))
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".md"); ok {
+			if page, err := Get(name); err == nil {
+				list = append(list, page)
+			}
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Get returns the page called name
+func Get(name string) (Page, error) {
+	if name == "" || strings.ContainsAny(name, `/\.`) {
+		return Page{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	data, err := pages.ReadFile("pages/" + name + ".md")
+	if err != nil {
+		return Page{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	content := string(data)
+	return Page{Name: name, Title: title(name, content), Content: content}, nil
+}
+
+// title returns the first level-one heading of content, or name
+func title(name, content string) string {
+	for line := range strings.Lines(content) {
+		if heading, ok := strings.CutPrefix(line, "# "); ok {
+			return strings.TrimSpace(heading)
+		}
+	}
+	return name
+}
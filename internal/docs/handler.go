@@ -0,0 +1,70 @@
+package docs
+
+import (
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// pageTemplate wraps a rendered page with navigation between pages
+var pageTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<nav>{{range .Pages}}<a href="/docs/{{.Name}}">{{.Title}}</a> {{end}}</nav>
+<main>
+{{.Body}}
+</main>
+</body>
+</html>
+`))
+
+// pageTemplateData fills pageTemplate
+type pageTemplateData struct {
+	Title string
+	Pages []Page
+	Body  template.HTML
+}
+
+// Handler serves the pages as HTML at /docs/<name>, with /docs listing them
+type Handler struct{}
+
+// NewHandler creates a handler for the documentation pages
+func NewHandler() *Handler {
+	return &Handler{}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pages := List()
+	data := pageTemplateData{Title: "Documentation", Pages: pages}
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/docs"), "/")
+	if name == "" {
+		var b strings.Builder
+		b.WriteString("<h1>Documentation</h1>\n<ul>\n")
+		for _, page := range pages {
+			b.WriteString(`<li><a href="/docs/` + page.Name + `">` + template.HTMLEscapeString(page.Title) + "</a></li>\n")
+		}
+		b.WriteString("</ul>\n")
+		data.Body = template.HTML(b.String())
+	} else {
+		page, err := Get(name)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		data.Title = page.Title
+		data.Body = template.HTML(RenderHTML(page.Content))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, data); err != nil {
+		log.Printf("Failed to render documentation page %s: %v", name, err)
+	}
+}
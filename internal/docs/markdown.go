@@ -0,0 +1,142 @@
+package docs
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// The Markdown the embedded pages use: headings, paragraphs, fenced code, lists, tables, and
+// inline code, bold text, and links
+var (
+	orderedItem = regexp.MustCompile(`^\d+\. `)
+	tableRule   = regexp.MustCompile(`^\|(\s*:?-+:?\s*\|)+$`)
+	inlineLink  = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	inlineBold  = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	slugInvalid = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// RenderHTML converts a page's Markdown to HTML; links to other pages (name.md) point to their
+// HTML versions
+func RenderHTML(markdown string) string {
+	var b strings.Builder
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+	// open is the list element being written, "ul" or "ol"
+	open := ""
+	var paragraph []string
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			b.WriteString("<p>" + renderInline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeList := func() {
+		if open != "" {
+			b.WriteString("</li>\n</" + open + ">\n")
+			open = ""
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			flush()
+			closeList()
+
+		case strings.HasPrefix(trimmed, "```"):
+			flush()
+			closeList()
+			b.WriteString("<pre><code>")
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				b.WriteString(html.EscapeString(lines[i]) + "\n")
+			}
+			b.WriteString("</code></pre>\n")
+
+		case strings.HasPrefix(trimmed, "#"):
+			flush()
+			closeList()
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			text := strings.TrimSpace(trimmed[level:])
+			tag := "h" + string(rune('0'+min(level, 6)))
+			b.WriteString("<" + tag + ` id="` + slug(text) + `">` + renderInline(text) + "</" + tag + ">\n")
+
+		case strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && tableRule.MatchString(strings.ReplaceAll(strings.TrimSpace(lines[i+1]), " ", "")):
+			flush()
+			closeList()
+			b.WriteString("<table>\n<thead>" + tableRow(trimmed, "th") + "</thead>\n<tbody>\n")
+			for i += 2; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				b.WriteString(tableRow(strings.TrimSpace(lines[i]), "td"))
+			}
+			i--
+			b.WriteString("</tbody>\n</table>\n")
+
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || orderedItem.MatchString(trimmed):
+			flush()
+			list, item := "ul", trimmed[2:]
+			if loc := orderedItem.FindStringIndex(trimmed); loc != nil {
+				list, item = "ol", trimmed[loc[1]:]
+			}
+			if open != list {
+				closeList()
+				b.WriteString("<" + list + ">\n<li>")
+				open = list
+			} else {
+				b.WriteString("</li>\n<li>")
+			}
+			b.WriteString(renderInline(item))
+
+		case open != "":
+			// A wrapped list item
+			b.WriteString(" " + renderInline(trimmed))
+
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flush()
+	closeList()
+	return b.String()
+}
+
+// tableRow renders a "| a | b |" row with cells of kind tag
+func tableRow(line, tag string) string {
+	cells := strings.Split(strings.Trim(line, "|"), "|")
+	var b strings.Builder
+	b.WriteString("<tr>")
+	for _, cell := range cells {
+		b.WriteString("<" + tag + ">" + renderInline(strings.TrimSpace(cell)) + "</" + tag + ">")
+	}
+	b.WriteString("</tr>\n")
+	return b.String()
+}
+
+// renderInline escapes text and renders its code spans, bold text, and links
+func renderInline(text string) string {
+	var b strings.Builder
+	for i, part := range strings.Split(text, "`") {
+		if i%2 == 1 {
+			b.WriteString("<code>" + html.EscapeString(part) + "</code>")
+			continue
+		}
+		escaped := html.EscapeString(part)
+		escaped = inlineLink.ReplaceAllStringFunc(escaped, func(link string) string {
+			match := inlineLink.FindStringSubmatch(link)
+			href := match[2]
+			if name, ok := strings.CutSuffix(href, ".md"); ok && !strings.Contains(name, ":") {
+				href = name
+			}
+			return `<a href="` + href + `">` + match[1] + "</a>"
+		})
+		b.WriteString(inlineBold.ReplaceAllString(escaped, "<strong>$1</strong>"))
+	}
+	return b.String()
+}
+
+// slug turns a heading into an anchor, e.g. "Signing in" into "signing-in"
+func slug(heading string) string {
+	return strings.Trim(slugInvalid.ReplaceAllString(strings.ToLower(heading), "-"), "-")
+}
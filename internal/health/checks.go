@@ -0,0 +1,95 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+)
+
+// LatencyCheck times ping: an error is unhealthy, and taking longer than slow is degraded
+func LatencyCheck(ping func(ctx context.Context) error, slow time.Duration) Check {
+	return func(ctx context.Context) Result {
+		start := time.Now()
+		err := ping(ctx)
+		latency := time.Since(start)
+
+		result := Result{
+			Status:  StatusOK,
+			Metrics: map[string]any{"latency_ms": latency.Milliseconds()},
+		}
+		switch {
+		case err != nil:
+			result.Status = StatusUnhealthy
+			result.Detail = err.Error()
+		case latency > slow:
+			result.Status = StatusDegraded
+			result.Detail = fmt.Sprintf("took %v, more than %v", latency.Round(time.Millisecond), slow)
+		}
+		return result
+	}
+}
+
+// HitRateCheck reports a cache's hit rate since startup as degraded when it falls below
+// minRate, once there have been at least minLookups lookups to judge from
+func HitRateCheck(counts func() (hits, misses int64), minRate float64, minLookups int64) Check {
+	return func(ctx context.Context) Result {
+		hits, misses := counts()
+		lookups := hits + misses
+
+		result := Result{
+			Status:  StatusOK,
+			Metrics: map[string]any{"hits": hits, "misses": misses},
+		}
+		if lookups == 0 {
+			return result
+		}
+		rate := float64(hits) / float64(lookups)
+		result.Metrics["hit_rate"] = rate
+		if lookups >= minLookups && rate < minRate {
+			result.Status = StatusDegraded
+			result.Detail = fmt.Sprintf("hit rate %.0f%% is below %.0f%%", rate*100, minRate*100)
+		}
+		return result
+	}
+}
+
+// BudgetCheck reports a rate limit budget as degraded while its lowest remaining allowance is at
+// or below reserve; lowest returns that allowance, or ok false while nothing is tracked
+func BudgetCheck(lowest func() (remaining, limit int, ok bool), reserve int) Check {
+	return func(ctx context.Context) Result {
+		result := Result{Status: StatusOK, Metrics: map[string]any{"reserve": reserve}}
+		remaining, limit, ok := lowest()
+		if !ok {
+			return result
+		}
+		result.Metrics["lowest_remaining"] = remaining
+		result.Metrics["limit"] = limit
+		if remaining <= reserve {
+			result.Status = StatusDegraded
+			result.Detail = fmt.Sprintf("%d/%d calls left, at or below the reserve of %d", remaining, limit, reserve)
+		}
+		return result
+	}
+}
+
+// BreakerCheck reports the registry's circuit breakers as degraded while any of them isn't closed
+func BreakerCheck(registry *breaker.Registry) Check {
+	return func(ctx context.Context) Result {
+		result := Result{Status: StatusOK}
+		var open []string
+		for _, status := range registry.Statuses() {
+			if status.State != breaker.StateClosed {
+				open = append(open, status.Name)
+			}
+		}
+		if len(open) > 0 {
+			result.Status = StatusDegraded
+			result.Detail = "circuit open for " + strings.Join(open, ", ")
+		}
+		result.Metrics = map[string]any{"open": len(open)}
+		return result
+	}
+}
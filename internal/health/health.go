@@ -0,0 +1,167 @@
+// Package health reports the state of the server's subsystems, both as the plain word the load
+// balancer's health check matches and as JSON detail for monitors
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status is the state of a subsystem or of the whole server
+type Status string
+
+const (
+	// StatusOK means everything works as expected
+	StatusOK Status = "ok"
+
+	// StatusDegraded means the server still serves requests, but slower or with reduced function
+	StatusDegraded Status = "degraded"
+
+	// StatusUnhealthy means the server can't serve requests
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// severity orders statuses so the worst one can be picked
+var severity = map[Status]int{
+	StatusOK:        0,
+	StatusDegraded:  1,
+	StatusUnhealthy: 2,
+}
+
+// checkTimeout bounds each check, so a hung subsystem is reported instead of hanging the check
+const checkTimeout = 2 * time.Second
+
+// Result is the outcome of one check
+type Result struct {
+	Status Status `json:"status"`
+
+	// Detail explains a status other than ok
+	Detail string `json:"detail,omitempty"`
+
+	// Metrics are the measurements the status was decided from
+	Metrics map[string]any `json:"metrics,omitempty"`
+}
+
+// Check reports the state of one subsystem
+type Check func(ctx context.Context) Result
+
+// Report is the state of every subsystem; Status is the worst of them
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Checker runs a set of named checks
+type Checker struct {
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// New creates a checker with no checks, which reports ok
+func New() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+// Register adds a check under name, replacing any check with the same name
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks[name] = check
+}
+
+// Report runs every check concurrently
+func (c *Checker) Report(ctx context.Context) Report {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := run(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[name] = result
+			if severity[result.Status] > severity[report.Status] {
+				report.Status = result.Status
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// run runs check with checkTimeout, reporting a check that doesn't return in time as unhealthy
+func run(ctx context.Context, check Check) Result {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	done := make(chan Result, 1)
+	go func() { done <- check(ctx) }()
+	select {
+	case result := <-done:
+		if _, known := severity[result.Status]; !known {
+			result.Status = StatusUnhealthy
+		}
+		return result
+	case <-ctx.Done():
+		return Result{Status: StatusUnhealthy, Detail: "check timed out after " + checkTimeout.String()}
+	}
+}
+
+// ServeHTTP implements http.Handler
+// The body is "OK", "DEGRADED" or "UNHEALTHY" for load balancer string matching, or the full
+// report with Accept: application/json or ?format=json
+// Degraded still answers 200 so the instance stays in service; unhealthy answers 503
+func (c *Checker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report := c.Report(r.Context())
+
+	status := http.StatusOK
+	if report.Status == StatusUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+
+	if wantsJSON(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(report)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(strings.ToUpper(string(report.Status))))
+	for _, name := range failing(report) {
+		_, _ = w.Write([]byte("\n" + name + ": " + string(report.Checks[name].Status)))
+	}
+}
+
+// wantsJSON reports whether the caller asked for the JSON report
+func wantsJSON(r *http.Request) bool {
+	return r.URL.Query().Get("format") == "json" || strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+// failing returns the names of the checks that aren't ok, sorted
+func failing(report Report) []string {
+	var names []string
+	for name, result := range report.Checks {
+		if result.Status != StatusOK {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,211 @@
+// Package instance identifies where this server is running (region, availability zone, and
+// instance or task), so logs and metrics from multi-region deployments can be told apart
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultIMDSEndpoint is the EC2 instance metadata service
+const DefaultIMDSEndpoint = "http://169.254.169.254"
+
+// imdsTokenTTL is how long the IMDSv2 session token requested for detection stays valid
+const imdsTokenTTL = "60"
+
+// Metadata describes the instance serving requests; empty fields are unknown
+type Metadata struct {
+	Region           string `json:"region,omitempty"`
+	AvailabilityZone string `json:"availability_zone,omitempty"`
+	// InstanceID is the EC2 instance ID, or the ECS task ID on Fargate
+	InstanceID string `json:"instance_id,omitempty"`
+	Cluster    string `json:"cluster,omitempty"`
+}
+
+// String formats the metadata for log lines, e.g. "us-east-1/us-east-1a/i-0abc"
+func (m Metadata) String() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{m.Region, m.AvailabilityZone, m.InstanceID} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// Tag adds the known fields to a structured log event
+func (m Metadata) Tag(event map[string]any) map[string]any {
+	if m.Region != "" {
+		event["region"] = m.Region
+	}
+	if m.AvailabilityZone != "" {
+		event["availability_zone"] = m.AvailabilityZone
+	}
+	if m.InstanceID != "" {
+		event["instance_id"] = m.InstanceID
+	}
+	return event
+}
+
+var (
+	currentMu sync.RWMutex
+	current   Metadata
+)
+
+// Current returns the metadata detected at startup
+func Current() Metadata {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// SetCurrent replaces the metadata returned by Current
+func SetCurrent(m Metadata) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	current = m
+}
+
+// Detector looks up instance metadata from the ECS task metadata endpoint or, outside ECS, from IMDSv2
+type Detector struct {
+	Client *http.Client
+
+	// ECSMetadataURI is the task metadata v4 endpoint (ECS_CONTAINER_METADATA_URI_V4); empty outside ECS
+	ECSMetadataURI string
+
+	// IMDSEndpoint is the EC2 instance metadata service; empty skips it
+	IMDSEndpoint string
+}
+
+// NewDetectorFromEnv creates a detector for the environment the process runs in
+// IMDS_ENABLED=false skips the instance metadata service, avoiding its timeout outside AWS
+func NewDetectorFromEnv() *Detector {
+	detector := &Detector{
+		Client:         &http.Client{Timeout: 2 * time.Second},
+		ECSMetadataURI: os.Getenv("ECS_CONTAINER_METADATA_URI_V4"),
+	}
+	if enabled := os.Getenv("IMDS_ENABLED"); enabled != "false" && enabled != "0" {
+		detector.IMDSEndpoint = DefaultIMDSEndpoint
+	}
+	return detector
+}
+
+// Detect returns whatever metadata could be found, falling back to AWS_REGION for the region
+// Lookup failures are returned alongside the partial metadata, since they are never fatal
+func (d *Detector) Detect(ctx context.Context) (Metadata, error) {
+	var m Metadata
+	var err error
+
+	switch {
+	case d.ECSMetadataURI != "":
+		m, err = d.detectECS(ctx)
+	case d.IMDSEndpoint != "":
+		m, err = d.detectIMDS(ctx)
+	}
+
+	if m.Region == "" {
+		m.Region = os.Getenv("AWS_REGION")
+	}
+	if m.Region == "" {
+		m.Region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	return m, err
+}
+
+// ecsTaskMetadata is the part of the ECS task metadata v4 response we use
+type ecsTaskMetadata struct {
+	Cluster          string `json:"Cluster"`
+	TaskARN          string `json:"TaskARN"`
+	AvailabilityZone string `json:"AvailabilityZone"`
+}
+
+// detectECS reads the task metadata endpoint; the region comes from the task ARN
+// (arn:aws:ecs:<region>:<account>:task/<cluster>/<task-id>)
+func (d *Detector) detectECS(ctx context.Context) (Metadata, error) {
+	var task ecsTaskMetadata
+	if err := d.getJSON(ctx, d.ECSMetadataURI+"/task", nil, &task); err != nil {
+		return Metadata{}, fmt.Errorf("failed to read ECS task metadata: %w", err)
+	}
+
+	m := Metadata{
+		AvailabilityZone: task.AvailabilityZone,
+		Cluster:          task.Cluster,
+	}
+	if arnParts := strings.Split(task.TaskARN, ":"); len(arnParts) >= 6 {
+		m.Region = arnParts[3]
+	}
+	if idx := strings.LastIndex(task.TaskARN, "/"); idx != -1 {
+		m.InstanceID = task.TaskARN[idx+1:]
+	}
+	if idx := strings.LastIndex(m.Cluster, "/"); idx != -1 {
+		m.Cluster = m.Cluster[idx+1:]
+	}
+	return m, nil
+}
+
+// imdsIdentityDocument is the part of the EC2 instance identity document we use
+type imdsIdentityDocument struct {
+	Region           string `json:"region"`
+	AvailabilityZone string `json:"availabilityZone"`
+	InstanceID       string `json:"instanceId"`
+}
+
+// detectIMDS reads the instance identity document using an IMDSv2 session token
+func (d *Detector) detectIMDS(ctx context.Context) (Metadata, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, d.IMDSEndpoint+"/latest/api/token", nil)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", imdsTokenTTL)
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("failed to get IMDS token: %w", err)
+	}
+	token, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return Metadata{}, fmt.Errorf("failed to get IMDS token: status %d", resp.StatusCode)
+	}
+
+	var doc imdsIdentityDocument
+	header := http.Header{"X-aws-ec2-metadata-token": {string(token)}}
+	if err := d.getJSON(ctx, d.IMDSEndpoint+"/latest/dynamic/instance-identity/document", header, &doc); err != nil {
+		return Metadata{}, fmt.Errorf("failed to read instance identity document: %w", err)
+	}
+
+	return Metadata{
+		Region:           doc.Region,
+		AvailabilityZone: doc.AvailabilityZone,
+		InstanceID:       doc.InstanceID,
+	}, nil
+}
+
+// getJSON fetches url and decodes the JSON response into value
+func (d *Detector) getJSON(ctx context.Context, url string, header http.Header, value any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
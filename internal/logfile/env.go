@@ -0,0 +1,66 @@
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// OpenFromEnv opens the log file for a stream configured with environment variables named
+// after prefix (e.g. ACCESS or APP):
+//
+//	<PREFIX>_LOG_FILE             path to write to (the stream is not written to a file when unset)
+//	<PREFIX>_LOG_MAX_SIZE_MB      rotate once the file reaches this size
+//	<PREFIX>_LOG_ROTATE_INTERVAL  rotate once the file is this old (e.g. 24h)
+//	<PREFIX>_LOG_COMPRESS         gzip rotated files when true
+//	<PREFIX>_LOG_MAX_BACKUPS      number of rotated files to keep (0 keeps all)
+//
+// It returns nil if <PREFIX>_LOG_FILE is unset or "-"
+func OpenFromEnv(prefix string) (*File, error) {
+	path := os.Getenv(prefix + "_LOG_FILE")
+	if path == "" || path == "-" {
+		return nil, nil
+	}
+
+	opts, err := OptionsFromEnv(prefix)
+	if err != nil {
+		return nil, err
+	}
+	return OpenWithOptions(path, opts)
+}
+
+// OptionsFromEnv reads the rotation options for a stream, see OpenFromEnv
+func OptionsFromEnv(prefix string) (Options, error) {
+	var opts Options
+
+	if value := os.Getenv(prefix + "_LOG_MAX_SIZE_MB"); value != "" {
+		mb, err := strconv.Atoi(value)
+		if err != nil || mb < 0 {
+			return opts, fmt.Errorf("invalid %s_LOG_MAX_SIZE_MB: %q", prefix, value)
+		}
+		opts.MaxSize = int64(mb) * 1024 * 1024
+	}
+
+	if value := os.Getenv(prefix + "_LOG_ROTATE_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval < 0 {
+			return opts, fmt.Errorf("invalid %s_LOG_ROTATE_INTERVAL: %q", prefix, value)
+		}
+		opts.Interval = interval
+	}
+
+	if value := os.Getenv(prefix + "_LOG_COMPRESS"); value != "" {
+		opts.Compress = value == "true" || value == "1"
+	}
+
+	if value := os.Getenv(prefix + "_LOG_MAX_BACKUPS"); value != "" {
+		backups, err := strconv.Atoi(value)
+		if err != nil || backups < 0 {
+			return opts, fmt.Errorf("invalid %s_LOG_MAX_BACKUPS: %q", prefix, value)
+		}
+		opts.MaxBackups = backups
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,241 @@
+// Package logfile provides append-only log files with optional size and time based
+// rotation and gzip compression of rotated files. Files can also be reopened after an
+// external tool such as logrotate has moved them.
+package logfile
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is appended to rotated file names; it sorts chronologically
+const backupTimeFormat = "20060102T150405.000000000"
+
+// Options controls rotation; the zero value never rotates
+type Options struct {
+	// MaxSize rotates the file before a write would make it larger than this many bytes
+	MaxSize int64
+
+	// Interval rotates the file once it has been open this long
+	Interval time.Duration
+
+	// Compress gzips rotated files
+	Compress bool
+
+	// MaxBackups is the number of rotated files kept; zero keeps all of them
+	MaxBackups int
+}
+
+// File is an append-only log file that is safe for concurrent writes
+type File struct {
+	mu       sync.Mutex
+	path     string
+	opts     Options
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// Open opens (or creates) the log file at path for appending, without rotation
+func Open(path string) (*File, error) {
+	return OpenWithOptions(path, Options{})
+}
+
+// OpenWithOptions opens (or creates) the log file at path, rotating it as configured by opts
+func OpenWithOptions(path string, opts Options) (*File, error) {
+	f := &File{path: path, opts: opts, now: time.Now}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file %s: %w", f.path, err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file %s: %w", f.path, err)
+	}
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+	return nil
+}
+
+// Write implements io.Writer
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.shouldRotate(int64(len(p))) {
+		if err := f.rotate(); err != nil {
+			// Keep logging to the current file rather than losing lines
+			log.New(os.Stderr, "", log.LstdFlags).Printf("Failed to rotate log file: %v", err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *File) shouldRotate(incoming int64) bool {
+	if f.opts.MaxSize > 0 && f.size > 0 && f.size+incoming > f.opts.MaxSize {
+		return true
+	}
+	return f.opts.Interval > 0 && f.now().Sub(f.openedAt) >= f.opts.Interval
+}
+
+// rotate moves the current file aside, reopens path, and compresses and prunes backups
+func (f *File) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file %s: %w", f.path, err)
+	}
+
+	backup := f.backupName()
+	if err := os.Rename(f.path, backup); err != nil {
+		// Reopen the original file so writes can continue
+		if openErr := f.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rename log file %s: %w", f.path, err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	go func() {
+		if f.opts.Compress {
+			if err := compressFile(backup); err != nil {
+				log.New(os.Stderr, "", log.LstdFlags).Printf("Failed to compress %s: %v", backup, err)
+			}
+		}
+		f.pruneBackups()
+	}()
+
+	return nil
+}
+
+// backupName returns an unused name for the next rotated file
+func (f *File) backupName() string {
+	base := f.path + "." + f.now().UTC().Format(backupTimeFormat)
+	backup := base
+	for i := 1; ; i++ {
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			if _, err := os.Stat(backup + ".gz"); os.IsNotExist(err) {
+				return backup
+			}
+		}
+		backup = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// Backups returns the rotated files for this log, oldest first
+func (f *File) Backups() ([]string, error) {
+	matches, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	// Skip compression temp files
+	backups := matches[:0]
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".tmp") {
+			backups = append(backups, match)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+func (f *File) pruneBackups() {
+	if f.opts.MaxBackups <= 0 {
+		return
+	}
+	backups, err := f.Backups()
+	if err != nil || len(backups) <= f.opts.MaxBackups {
+		return
+	}
+	for _, backup := range backups[:len(backups)-f.opts.MaxBackups] {
+		_ = os.Remove(backup)
+	}
+}
+
+// compressFile gzips path to path.gz and removes the original
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+
+	if err := os.Rename(tmp, path+".gz"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Path returns the path the file is written to
+func (f *File) Path() string {
+	return f.path
+}
+
+// Rotate rotates the file immediately
+func (f *File) Rotate() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rotate()
+}
+
+// Reopen closes the file and opens path again, so writes go to a new file after rotation
+func (f *File) Reopen() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file %s: %w", f.path, err)
+	}
+	return f.open()
+}
+
+// Close closes the file
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
@@ -0,0 +1,164 @@
+// Package maintenance implements a switch that takes the MCP endpoint out of service
+// while keeping the process healthy for the load balancer
+package maintenance
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultMessage is shown to clients when maintenance is enabled without a message
+const defaultMessage = "Server under maintenance, please try again later"
+
+// retryAfterSeconds is sent to clients in the Retry-After header
+const retryAfterSeconds = "60"
+
+// jsonRPCServerError is the JSON-RPC implementation-defined server error code used for maintenance
+const jsonRPCServerError = -32000
+
+// Status describes the current maintenance state
+type Status struct {
+	Enabled bool      `json:"enabled"`
+	Message string    `json:"message,omitempty"`
+	Since   time.Time `json:"since,omitzero"`
+}
+
+// Mode is a maintenance switch that can be toggled at runtime
+type Mode struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+// New creates a maintenance switch, initially disabled
+func New() *Mode {
+	return &Mode{}
+}
+
+// Enable turns maintenance on with a message for clients
+func (m *Mode) Enable(message string) {
+	if message == "" {
+		message = defaultMessage
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.status.Enabled {
+		m.status.Since = time.Now()
+	}
+	m.status.Enabled = true
+	m.status.Message = message
+	log.Printf("[MAINTENANCE] Enabled: %s", message)
+}
+
+// Disable turns maintenance off
+func (m *Mode) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.status.Enabled {
+		log.Printf("[MAINTENANCE] Disabled after %v", time.Since(m.status.Since).Round(time.Second))
+	}
+	m.status = Status{}
+}
+
+// Status returns the current maintenance state
+func (m *Mode) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+// Middleware rejects every request with a structured maintenance error while maintenance is enabled
+func (m *Mode) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := m.Status()
+		if !status.Enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Shaped like a JSON-RPC error so MCP clients can surface the message
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      nil,
+			"error": map[string]any{
+				"code":    jsonRPCServerError,
+				"message": "server under maintenance",
+				"data":    status,
+			},
+		})
+	})
+}
+
+// readinessCheckTimeout bounds each dependency check, so a hung backend fails readiness
+// before the load balancer's health check times out
+const readinessCheckTimeout = 2 * time.Second
+
+// Check reports an error when a dependency the server needs, like storage, is unreachable
+type Check func(ctx context.Context) error
+
+// ReadinessHandler reports 200 when the server is ready for traffic, and 503 during maintenance
+// or while any of checks fails
+func (m *Mode) ReadinessHandler(checks ...Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := m.Status()
+		response := map[string]any{
+			"ready":       !status.Enabled,
+			"maintenance": status,
+		}
+		for _, check := range checks {
+			ctx, cancel := context.WithTimeout(r.Context(), readinessCheckTimeout)
+			err := check(ctx)
+			cancel()
+			if err != nil {
+				response["ready"] = false
+				response["error"] = err.Error()
+				break
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if response["ready"] == false {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
+
+// AdminHandler reports the maintenance state on GET and changes it on POST
+// with a JSON body of {"enabled": bool, "message": string}
+func (m *Mode) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var req struct {
+				Enabled bool   `json:"enabled"`
+				Message string `json:"message"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "Invalid request body", http.StatusBadRequest)
+				return
+			}
+			if req.Enabled {
+				m.Enable(req.Message)
+			} else {
+				m.Disable()
+			}
+		default:
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(m.Status())
+	})
+}
@@ -0,0 +1,265 @@
+package metering
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// Export formats
+const (
+	// FormatCSV writes one row per record, with a header
+	FormatCSV = "csv"
+
+	// FormatStripe writes Stripe billing meter events as JSON lines, one per meter and record,
+	// with the GitHub login as stripe_customer_id
+	FormatStripe = "stripe"
+)
+
+// Stripe meter event names
+const (
+	stripeMessagesEvent  = "mcp_messages"
+	stripeToolCallsEvent = "mcp_tool_calls"
+)
+
+// defaultInterval is how often usage is exported when METERING_INTERVAL_SECONDS is unset
+const defaultInterval = time.Hour
+
+// WriteCSV writes records to w as CSV
+func WriteCSV(w io.Writer, records []Record) error {
+	writer := csv.NewWriter(w)
+	_ = writer.Write([]string{"period_start", "period_end", "user", "client_id", "messages", "tool_calls"})
+	for _, r := range records {
+		_ = writer.Write([]string{
+			r.PeriodStart.UTC().Format(time.RFC3339),
+			r.PeriodEnd.UTC().Format(time.RFC3339),
+			r.User,
+			r.ClientID,
+			strconv.FormatInt(r.Messages, 10),
+			strconv.FormatInt(r.ToolCalls, 10),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// stripeMeterEvent is a Stripe billing meter event, as accepted by POST /v1/billing/meter_events
+type stripeMeterEvent struct {
+	EventName  string            `json:"event_name"`
+	Timestamp  int64             `json:"timestamp"`
+	Identifier string            `json:"identifier"`
+	Payload    map[string]string `json:"payload"`
+}
+
+// WriteStripe writes records to w as Stripe meter events, skipping meters with nothing to report
+// Identifiers are stable per period, so importing a file twice doesn't bill twice
+func WriteStripe(w io.Writer, records []Record) error {
+	encoder := json.NewEncoder(w)
+	for _, r := range records {
+		for _, meter := range []struct {
+			event string
+			value int64
+		}{{stripeMessagesEvent, r.Messages}, {stripeToolCallsEvent, r.ToolCalls}} {
+			if meter.value == 0 {
+				continue
+			}
+			event := stripeMeterEvent{
+				EventName:  meter.event,
+				Timestamp:  r.PeriodEnd.Unix(),
+				Identifier: fmt.Sprintf("%s:%s:%d:%s", r.User, r.ClientID, r.PeriodStart.Unix(), meter.event),
+				Payload: map[string]string{
+					"stripe_customer_id": r.User,
+					"client_id":          r.ClientID,
+					"value":              strconv.FormatInt(meter.value, 10),
+				},
+			}
+			if err := encoder.Encode(event); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Sink stores exported usage files
+type Sink interface {
+	Put(ctx context.Context, name string, data []byte) error
+}
+
+// DirSink writes usage files to a local directory
+type DirSink string
+
+// Put implements Sink
+func (d DirSink) Put(_ context.Context, name string, data []byte) error {
+	if err := os.MkdirAll(string(d), 0o755); err != nil {
+		return fmt.Errorf("failed to create usage directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(string(d), name), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write usage file: %w", err)
+	}
+	return nil
+}
+
+// S3Sink uploads usage files to an S3 bucket, signing requests with SigV4
+type S3Sink struct {
+	Bucket      string
+	Prefix      string
+	Region      string
+	Credentials aws.CredentialsProvider
+	// Endpoint replaces the regional S3 endpoint, with the bucket in the path (used in tests)
+	Endpoint string
+	Client   *http.Client
+}
+
+// Put implements Sink
+func (s *S3Sink) Put(ctx context.Context, name string, data []byte) error {
+	key := path.Join(s.Prefix, name)
+	objectURL := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.Bucket, s.Region, key)
+	if s.Endpoint != "" {
+		objectURL = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.Endpoint, "/"), s.Bucket, key)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create S3 request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := s.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	if err := v4.NewSigner().SignHTTP(ctx, creds, req, payloadHash, "s3", s.Region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign S3 request: %w", err)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload to S3: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("S3 returned status %d: %s", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// Exporter periodically flushes a meter and stores its records in a sink
+type Exporter struct {
+	Meter    *Meter
+	Sink     Sink
+	Format   string
+	Interval time.Duration
+}
+
+// Export flushes the meter and stores the period's records, if there are any
+// Records of a failed export are lost, so usage is never billed twice
+func (e *Exporter) Export(ctx context.Context) error {
+	records := e.Meter.Flush()
+	if len(records) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	write, ext := WriteCSV, "csv"
+	if e.Format == FormatStripe {
+		write, ext = WriteStripe, "jsonl"
+	}
+	if err := write(&buf, records); err != nil {
+		return fmt.Errorf("failed to encode usage: %w", err)
+	}
+
+	const stamp = "20060102T150405Z"
+	name := fmt.Sprintf("usage-%s-%s.%s", records[0].PeriodStart.UTC().Format(stamp), records[0].PeriodEnd.UTC().Format(stamp), ext)
+	if err := e.Sink.Put(ctx, name, buf.Bytes()); err != nil {
+		return err
+	}
+	log.Printf("[METERING] Exported %d usage records to %s", len(records), name)
+	return nil
+}
+
+// Run exports usage every Interval for the life of the process
+func (e *Exporter) Run() {
+	ticker := time.NewTicker(e.Interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := e.Export(ctx); err != nil {
+			log.Printf("[METERING] Failed to export usage: %v", err)
+		}
+		cancel()
+	}
+}
+
+// FromEnv creates an exporter for meter from METERING_EXPORT (a directory, file:// URL, or
+// s3://bucket/prefix URL), METERING_FORMAT, and METERING_INTERVAL_SECONDS
+// It returns nil when METERING_EXPORT is unset
+func FromEnv(ctx context.Context, meter *Meter) (*Exporter, error) {
+	destination := os.Getenv("METERING_EXPORT")
+	if destination == "" {
+		return nil, nil
+	}
+
+	exporter := &Exporter{Meter: meter, Format: FormatCSV, Interval: defaultInterval}
+	if format := os.Getenv("METERING_FORMAT"); format != "" {
+		if format != FormatCSV && format != FormatStripe {
+			return nil, fmt.Errorf("invalid METERING_FORMAT %q: use csv or stripe", format)
+		}
+		exporter.Format = format
+	}
+	if intervalStr := os.Getenv("METERING_INTERVAL_SECONDS"); intervalStr != "" {
+		seconds, err := strconv.Atoi(intervalStr)
+		if err != nil || seconds <= 0 {
+			return nil, fmt.Errorf("invalid METERING_INTERVAL_SECONDS %q", intervalStr)
+		}
+		exporter.Interval = time.Duration(seconds) * time.Second
+	}
+
+	parsed, err := url.Parse(destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid METERING_EXPORT: %w", err)
+	}
+	switch parsed.Scheme {
+	case "s3":
+		awsCfg, err := config.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load AWS SDK config: %w", err)
+		}
+		exporter.Sink = &S3Sink{
+			Bucket:      parsed.Host,
+			Prefix:      strings.TrimPrefix(parsed.Path, "/"),
+			Region:      awsCfg.Region,
+			Credentials: awsCfg.Credentials,
+			Client:      &http.Client{Timeout: 30 * time.Second},
+		}
+	case "file":
+		exporter.Sink = DirSink(parsed.Path)
+	case "":
+		exporter.Sink = DirSink(destination)
+	default:
+		return nil, fmt.Errorf("invalid METERING_EXPORT %q: use a directory or an s3:// URL", destination)
+	}
+	return exporter, nil
+}
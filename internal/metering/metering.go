@@ -0,0 +1,135 @@
+// Package metering counts MCP messages and tool calls per user and client, and periodically
+// exports the totals as usage records for internal chargeback of the shared deployment
+package metering
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// Record is a user's usage through one client during a metering period
+type Record struct {
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+	User        string    `json:"user"`
+	ClientID    string    `json:"client_id"`
+	Messages    int64     `json:"messages"`
+	ToolCalls   int64     `json:"tool_calls"`
+}
+
+// usageKey identifies the usage a Record totals
+type usageKey struct {
+	user     string
+	clientID string
+}
+
+// Meter counts usage for the current period in memory, until it is flushed
+type Meter struct {
+	mu    sync.Mutex
+	clock clock.Clock
+	start time.Time
+	usage map[usageKey]*Record
+}
+
+// New creates a meter whose first period starts now
+func New() *Meter {
+	return &Meter{
+		clock: clock.System{},
+		start: time.Now(),
+		usage: make(map[usageKey]*Record),
+	}
+}
+
+// SetClock replaces the clock used to time periods (used in tests), restarting the current period
+func (m *Meter) SetClock(c clock.Clock) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.clock = c
+	m.start = c.Now()
+}
+
+// RecordMessage counts an MCP message from user through clientID
+func (m *Meter) RecordMessage(user, clientID string) {
+	m.record(user, clientID, func(r *Record) { r.Messages++ })
+}
+
+// RecordToolCall counts a tool call by user through clientID
+func (m *Meter) RecordToolCall(user, clientID string) {
+	m.record(user, clientID, func(r *Record) { r.ToolCalls++ })
+}
+
+// record applies update to the usage of user through clientID; anonymous usage isn't metered
+func (m *Meter) record(user, clientID string, update func(*Record)) {
+	if user == "" {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := usageKey{user: user, clientID: clientID}
+	r, ok := m.usage[key]
+	if !ok {
+		r = &Record{User: user, ClientID: clientID}
+		m.usage[key] = r
+	}
+	update(r)
+}
+
+// Flush ends the current period and returns its records, ordered by user and client
+func (m *Meter) Flush() []Record {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	end := m.clock.Now()
+	records := make([]Record, 0, len(m.usage))
+	for _, r := range m.usage {
+		r.PeriodStart, r.PeriodEnd = m.start, end
+		records = append(records, *r)
+	}
+	m.start = end
+	m.usage = make(map[usageKey]*Record)
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].User != records[j].User {
+			return records[i].User < records[j].User
+		}
+		return records[i].ClientID < records[j].ClientID
+	})
+	return records
+}
+
+// clientID returns the OAuth client the caller of req authenticated through, or ""
+func clientID(req mcp.Request) string {
+	extra := req.GetExtra()
+	if extra == nil || extra.TokenInfo == nil {
+		return ""
+	}
+	id, _ := extra.TokenInfo.Extra["client_id"].(string)
+	return id
+}
+
+// Middleware counts every request as a message, and tool calls as well, in meter
+// A nil meter meters nothing
+func Middleware(meter *Meter) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		if meter == nil {
+			return next
+		}
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			user, client := activity.User(req), clientID(req)
+			meter.RecordMessage(user, client)
+			if _, ok := req.(*mcp.CallToolRequest); ok {
+				meter.RecordToolCall(user, client)
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Package policy evaluates organization-specific authorization rules for tool calls, beyond the
+// scopes each tool declares, so rules like "interns cannot read AWS costs" need no code changes
+package policy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Input describes the tool call being authorized; it is the input document OPA policies see
+type Input struct {
+	// User is the GitHub login of the caller, empty for unauthenticated calls
+	User     string          `json:"user,omitempty"`
+	ClientID string          `json:"client_id,omitempty"`
+	Scopes   []string        `json:"scopes"`
+	Tool     string          `json:"tool"`
+	Args     json.RawMessage `json:"args,omitempty"`
+}
+
+// Decision is the outcome of evaluating a policy
+type Decision struct {
+	Allow bool `json:"allow"`
+	// Reason explains a denial to the caller
+	Reason string `json:"reason,omitempty"`
+}
+
+// Engine evaluates a policy for a tool call
+// An error means no decision could be made; callers deny the call rather than skip the policy
+type Engine interface {
+	Evaluate(ctx context.Context, input Input) (Decision, error)
+}
+
+// OPA evaluates a rule loaded into an Open Policy Agent server (typically a sidecar) through its
+// Data API, e.g. http://localhost:8181/v1/data/mcp/authz
+// The rule may be a boolean, or an object with allow and reason fields
+type OPA struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewOPAFromEnv creates an OPA engine for POLICY_OPA_URL, or returns nil when it isn't set
+func NewOPAFromEnv() *OPA {
+	url := os.Getenv("POLICY_OPA_URL")
+	if url == "" {
+		return nil
+	}
+	return &OPA{URL: url, Client: &http.Client{Timeout: 2 * time.Second}}
+}
+
+// Evaluate implements Engine
+func (o *OPA) Evaluate(ctx context.Context, input Input) (Decision, error) {
+	body, err := json.Marshal(map[string]Input{"input": input})
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to encode policy input: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.URL, bytes.NewReader(body))
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return Decision{}, fmt.Errorf("failed to call OPA: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return Decision{}, fmt.Errorf("OPA returned status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return Decision{}, fmt.Errorf("failed to decode OPA response: %w", err)
+	}
+	// OPA omits the result when the rule is undefined, usually because the policy isn't loaded
+	if len(response.Result) == 0 || string(response.Result) == "null" {
+		return Decision{}, errors.New("OPA policy is undefined")
+	}
+
+	var allow bool
+	if err := json.Unmarshal(response.Result, &allow); err == nil {
+		return Decision{Allow: allow}, nil
+	}
+	var decision Decision
+	if err := json.Unmarshal(response.Result, &decision); err != nil {
+		return Decision{}, fmt.Errorf("unexpected OPA result %s", response.Result)
+	}
+	return decision, nil
+}
@@ -62,11 +62,11 @@ func RegisterAll(server *mcp.Server) {
 	// City Time prompt
 	timePrompt := &mcp.Prompt{
 		Name:        "check-city-time",
-		Description: "Get the current time in a major US city",
+		Description: "Get the current time in a city or time zone",
 		Arguments: []*mcp.PromptArgument{
 			{
 				Name:        "city",
-				Description: "The city name (nyc, sf, or boston)",
+				Description: "The city name (e.g. nyc, Tokyo) or IANA time zone (e.g. Europe/Paris)",
 				Required:    true,
 			},
 		},
@@ -119,4 +119,65 @@ func RegisterAll(server *mcp.Server) {
 	})
 
 	log.Printf("Registered prompt: %s", fortunePrompt.Name)
+
+	// Client onboarding prompt
+	registerClientPrompt := &mcp.Prompt{
+		Name:        "register-mcp-client",
+		Description: "Guide an admin through registering a new OAuth client for this server (requires mcp:admin)",
+		Arguments: []*mcp.PromptArgument{
+			{
+				Name:        "client_name",
+				Description: "The name of the client application",
+				Required:    true,
+			},
+			{
+				Name:        "redirect_uris",
+				Description: "Comma-separated redirect URIs, if already known",
+			},
+			{
+				Name:        "client_type",
+				Description: "public (desktop or browser app), confidential (server that can keep a secret), service (no user, client_credentials), or headless (device code)",
+			},
+		},
+	}
+
+	server.AddPrompt(registerClientPrompt, func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		args := req.Params.Arguments
+		vars := variablesFor(req)
+
+		message := vars.expand("I'm {{github_user}}, an admin of the MCP server at {{server_url}} ({{active_users}} users active in the last day).\n")
+		message += "Help me register a new OAuth client named \"" + args["client_name"] + "\" with this MCP server.\n\n"
+		if uris := args["redirect_uris"]; uris != "" {
+			message += "Redirect URIs: " + uris + "\n"
+		}
+		if clientType := args["client_type"]; clientType != "" {
+			message += "Client type: " + clientType + "\n"
+		}
+		message += vars.expand("\nBefore registering, ask me for anything still missing:\n" +
+			"1. The client type, which decides the authentication method and grant types:\n" +
+			"   - public: token_endpoint_auth_method none with authorization_code and PKCE, for desktop and browser apps that can't keep a secret\n" +
+			"   - confidential: client_secret_basic with authorization_code, for servers that can keep a secret\n" +
+			"   - service: client_secret_basic with client_credentials; its client ID must also be added to CLIENT_CREDENTIALS_CLIENTS\n" +
+			"   - headless: none with urn:ietf:params:oauth:grant-type:device_code, for terminal clients without a browser\n" +
+			"2. The exact redirect URIs, which are required for the authorization code grant.\n" +
+			"3. The scopes the client needs, if not the default mcp:tools mcp:resources read:user.\n\n" +
+			"Then summarize the registration and, once I confirm, call the register-client tool. " +
+			"Don't repeat the credentials in the conversation: give me the link to the one-time credentials resource it returns, " +
+			"and remind me to read it once and store the secret in a secret manager before the link expires.\n\n" +
+			"The client discovers endpoints from {{server_url}}/.well-known/oauth-authorization-server and gets tokens from {{server_url}}/oauth/token.")
+
+		return &mcp.GetPromptResult{
+			Description: "OAuth client registration",
+			Messages: []*mcp.PromptMessage{
+				{
+					Role: "user",
+					Content: &mcp.TextContent{
+						Text: message,
+					},
+				},
+			},
+		}, nil
+	})
+
+	log.Printf("Registered prompt: %s", registerClientPrompt.Name)
 }
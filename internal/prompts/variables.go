@@ -0,0 +1,62 @@
+package prompts
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+)
+
+// activeUsersWindow is how recently a user must have been active to count in {{active_users}}
+const activeUsersWindow = 24 * time.Hour
+
+// serverURL is the URL {{server_url}} resolves to
+var (
+	serverURLMu sync.RWMutex
+	serverURL   string
+)
+
+// SetServerURL sets the URL prompts refer to as {{server_url}}
+func SetServerURL(url string) {
+	serverURLMu.Lock()
+	defer serverURLMu.Unlock()
+	serverURL = url
+}
+
+// variables are the values server-side template variables resolve to for one GetPrompt request
+type variables map[string]string
+
+// variablesFor resolves the template variables for req:
+//   - {{server_url}}: the server's canonical URL
+//   - {{github_user}}: the caller's GitHub login, or "an anonymous user"
+//   - {{active_users}}: how many users have been active in the last day
+func variablesFor(req *mcp.GetPromptRequest) variables {
+	serverURLMu.RLock()
+	url := serverURL
+	serverURLMu.RUnlock()
+
+	user := activity.User(req)
+	if user == "" {
+		user = "an anonymous user"
+	}
+
+	return variables{
+		"server_url":   url,
+		"github_user":  user,
+		"active_users": strconv.Itoa(activity.Default.ActiveUsers(activeUsersWindow)),
+	}
+}
+
+// expand replaces each {{name}} in template with its variable; unknown names are left as they are
+// Only prompt templates are expanded, never the arguments clients pass in
+func (v variables) expand(template string) string {
+	pairs := make([]string, 0, 2*len(v))
+	for name, value := range v {
+		pairs = append(pairs, "{{"+name+"}}", value)
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
@@ -0,0 +1,192 @@
+// Package ratelimit provides token bucket rate limiting keyed by access token or client IP,
+// protecting GitHub token validation and the tools behind the MCP endpoint from abuse
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+)
+
+// pruneInterval is how often buckets that have refilled completely are dropped
+const pruneInterval = time.Minute
+
+// Limiter keeps one token bucket per key: each bucket holds up to burst requests and refills
+// at perMinute requests per minute
+type Limiter struct {
+	rate  float64 // tokens per second
+	burst float64
+	clock clock.Clock
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+// bucket is the state of a single key's token bucket
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// New creates a limiter allowing perMinute requests per minute per key, with bursts of up to
+// burst requests (at least 1); a nil Limiter, returned when perMinute is 0, allows everything
+func New(perMinute, burst int) *Limiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &Limiter{
+		rate:    float64(perMinute) / 60,
+		burst:   float64(burst),
+		clock:   clock.System{},
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// SetClock replaces the clock used to refill buckets (used in tests)
+func (l *Limiter) SetClock(c clock.Clock) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.clock = c
+}
+
+// Allow takes a token from key's bucket, or reports how long until one is available
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+	return false, wait
+}
+
+// Usage reports the fraction of key's burst currently used, from 0 for a full bucket to 1 for an
+// empty one, without taking a token
+func (l *Limiter) Usage(key string) float64 {
+	if l == nil {
+		return 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		return 0
+	}
+	tokens := math.Min(l.burst, b.tokens+l.clock.Now().Sub(b.last).Seconds()*l.rate)
+	return 1 - tokens/l.burst
+}
+
+// refillTime is how long an empty bucket takes to fill up again
+func (l *Limiter) refillTime() time.Duration {
+	return time.Duration(l.burst / l.rate * float64(time.Second))
+}
+
+// prune drops buckets that have had time to refill completely, since they behave like new ones
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+
+	refill := l.refillTime()
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware limits requests per client IP and, when a bearer token is presented, per token
+// Rejected requests get 429 with Retry-After; trustForwardedFor takes the client IP from the
+// last X-Forwarded-For entry, which is the one added by the load balancer
+func Middleware(byToken, byIP *Limiter, trustForwardedFor bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := ClientIP(r, trustForwardedFor)
+			if ok, wait := byIP.Allow(ip); !ok {
+				reject(w, r, "client IP "+ip, wait)
+				return
+			}
+
+			if token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); found && token != "" {
+				key := TokenKey(token)
+				if ok, wait := byToken.Allow(key); !ok {
+					reject(w, r, "access token "+key[:12], wait)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// TokenKey is the key a bearer token is limited under; only a hash of the token is kept, like
+// every other token store
+func TokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// reject answers a rate limited request
+func reject(w http.ResponseWriter, r *http.Request, limited string, wait time.Duration) {
+	retryAfter := int(math.Ceil(wait.Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	log.Printf("[RATELIMIT] Rejected %s %s for %s, retry after %ds", r.Method, r.URL.Path, limited, retryAfter)
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+}
+
+// ClientIP returns the IP address of the client that sent r
+func ClientIP(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			hops := strings.Split(forwarded, ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
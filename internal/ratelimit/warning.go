@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+)
+
+// Warner tells users over MCP when they have used most of their token's rate limit, before
+// requests start being rejected
+// Each user is warned at most once for as long as their bucket takes to refill
+type Warner struct {
+	limiter   *Limiter
+	threshold float64
+
+	mu     sync.Mutex
+	warned map[string]time.Time
+}
+
+// NewWarner creates a warner for the per-token limiter, warning once usage reaches threshold
+// (a fraction of the burst); a nil Warner, returned for a nil limiter or a threshold of 0,
+// warns nobody
+func NewWarner(limiter *Limiter, threshold float64) *Warner {
+	if limiter == nil || threshold <= 0 {
+		return nil
+	}
+	return &Warner{
+		limiter:   limiter,
+		threshold: threshold,
+		warned:    make(map[string]time.Time),
+	}
+}
+
+// shouldWarn reports whether user, whose bucket is used up to usage, needs a warning now
+func (w *Warner) shouldWarn(user string, usage float64) bool {
+	if usage < w.threshold {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := w.limiter.clock.Now()
+	cooldown := w.limiter.refillTime()
+	if last, ok := w.warned[user]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+	for key, last := range w.warned {
+		if now.Sub(last) >= cooldown {
+			delete(w.warned, key)
+		}
+	}
+	w.warned[user] = now
+	return true
+}
+
+// Middleware sends a warning log notification to sessions whose token is close to its limit
+// Clients only receive it once they have set a log level
+func (w *Warner) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		if w == nil {
+			return next
+		}
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			session, ok := req.GetSession().(*mcp.ServerSession)
+			extra := req.GetExtra()
+			if !ok || extra == nil || extra.Header == nil {
+				return next(ctx, method, req)
+			}
+			token, found := strings.CutPrefix(extra.Header.Get("Authorization"), "Bearer ")
+			if !found || token == "" {
+				return next(ctx, method, req)
+			}
+
+			key := TokenKey(token)
+			user := activity.User(req)
+			if user == "" {
+				user = key
+			}
+			if usage := w.limiter.Usage(key); w.shouldWarn(user, usage) {
+				percent := int(math.Round(usage * 100))
+				log.Printf("[RATELIMIT] Warned %s at %d%% of the per-token limit", user, percent)
+				message := fmt.Sprintf("You have used %d%% of your rate limit of %.0f requests per minute; slow down or requests will be rejected with 429 Too Many Requests",
+					percent, w.limiter.rate*60)
+				if err := session.Log(ctx, &mcp.LoggingMessageParams{
+					Level:  "warning",
+					Logger: "ratelimit",
+					Data:   map[string]any{"message": message, "used_percent": percent},
+				}); err != nil {
+					log.Printf("Failed to send rate limit warning: %v", err)
+				}
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
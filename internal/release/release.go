@@ -0,0 +1,229 @@
+// Package release reports which version of the server is running and checks the project's
+// GitHub releases for newer ones
+package release
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+// Version is the running version, set at build time with
+// -ldflags "-X EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/release.Version=v1.2.3"
+var Version = "dev"
+
+// DefaultCheckInterval is how often a running server checks for a newer release
+const DefaultCheckInterval = 24 * time.Hour
+
+// Release is the part of a GitHub release we use
+type Release struct {
+	TagName string  `json:"tag_name"`
+	HTMLURL string  `json:"html_url"`
+	Assets  []Asset `json:"assets"`
+}
+
+// Asset is a file attached to a release
+type Asset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+// Status is what the last check found
+type Status struct {
+	Current string
+	// Latest is the newest release, or empty if no check has succeeded
+	Latest string
+	URL    string
+	// UpdateAvailable is set when Latest is newer than Current; development builds never
+	// have an update available
+	UpdateAvailable bool
+	CheckedAt       time.Time
+	Err             error
+}
+
+// Checker looks up the latest release of a GitHub repository
+type Checker struct {
+	Client *http.Client
+
+	// Repo is the GitHub repository releases are published to, as owner/name; empty disables checks
+	Repo string
+
+	// APIURL is the GitHub API base URL
+	APIURL string
+
+	mu     sync.RWMutex
+	status Status
+}
+
+// Default is the checker configured by NewCheckerFromEnv, reported by get-deployment-status
+// It is disabled until a repository is set
+var Default = &Checker{}
+
+// NewCheckerFromEnv creates a checker for UPDATE_CHECK_REPO, using GITHUB_API_URL for GitHub
+// Enterprise
+func NewCheckerFromEnv() *Checker {
+	checker := &Checker{
+		Client: &http.Client{Timeout: 10 * time.Second},
+		Repo:   os.Getenv("UPDATE_CHECK_REPO"),
+		APIURL: "https://api.github.com",
+	}
+	if apiURL := os.Getenv("GITHUB_API_URL"); apiURL != "" {
+		checker.APIURL = strings.TrimSuffix(apiURL, "/")
+	}
+	return checker
+}
+
+// Enabled reports whether a repository is configured
+func (c *Checker) Enabled() bool {
+	return c != nil && c.Repo != ""
+}
+
+// Latest fetches the latest published release
+func (c *Checker) Latest(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/repos/%s/releases/latest", c.APIURL, c.Repo), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the latest release: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("GitHub returned status %d for the latest release of %s: %s", resp.StatusCode, c.Repo, message)
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to parse the latest release: %w", err)
+	}
+	return &release, nil
+}
+
+// Check fetches the latest release and records the result for Status, logging when a newer
+// version is available
+func (c *Checker) Check(ctx context.Context) Status {
+	status := Status{Current: Version, CheckedAt: time.Now()}
+	release, err := c.Latest(ctx)
+	if err != nil {
+		status.Err = err
+		log.Printf("Warning: Update check failed: %v", err)
+	} else {
+		status.Latest = release.TagName
+		status.URL = release.HTMLURL
+		status.UpdateAvailable = Newer(release.TagName, Version)
+	}
+
+	c.mu.Lock()
+	if err != nil {
+		// Keep reporting the last release found
+		status.Latest, status.URL, status.UpdateAvailable = c.status.Latest, c.status.URL, c.status.UpdateAvailable
+	}
+	c.status = status
+	c.mu.Unlock()
+
+	if status.UpdateAvailable && err == nil {
+		log.Printf("Version %s is available (running %s): %s", status.Latest, status.Current, status.URL)
+		logUpdateAvailable(status)
+	}
+	return status
+}
+
+// Status returns what the last check found
+func (c *Checker) Status() Status {
+	if c == nil {
+		return Status{Current: Version}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	status := c.status
+	status.Current = Version
+	return status
+}
+
+// Run checks now and then every interval until ctx is done; an interval of 0 checks only once
+func (c *Checker) Run(ctx context.Context, interval time.Duration) {
+	c.Check(ctx)
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Check(ctx)
+		}
+	}
+}
+
+// logUpdateAvailable emits a [METRIC] event that log-based metrics and alarms can match
+func logUpdateAvailable(status Status) {
+	event, _ := json.Marshal(instance.Current().Tag(map[string]any{
+		"event":   "update_available",
+		"current": status.Current,
+		"latest":  status.Latest,
+	}))
+	log.Printf("[METRIC] %s", event)
+}
+
+// Newer reports whether version a is newer than b, comparing dotted numeric versions with an
+// optional "v" prefix; pre-release suffixes are ignored and anything unparseable is never newer
+func Newer(a, b string) bool {
+	pa, ok := parseVersion(a)
+	if !ok {
+		return false
+	}
+	pb, ok := parseVersion(b)
+	if !ok {
+		return false
+	}
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return false
+}
+
+// parseVersion splits "v1.2.3-rc.1" into [1 2 3]
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	if i := strings.IndexAny(version, "-+"); i >= 0 {
+		version = version[:i]
+	}
+	if version == "" {
+		return nil, false
+	}
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
@@ -0,0 +1,125 @@
+package release
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// maxBinarySize bounds how much a self-update downloads
+const maxBinarySize = 256 << 20
+
+// AssetName is the release asset holding the server binary for this platform, such as
+// server_linux_amd64; a matching AssetName()+".sha256" asset holds its checksum
+func AssetName() string {
+	return fmt.Sprintf("server_%s_%s", runtime.GOOS, runtime.GOARCH)
+}
+
+// Update replaces the binary at executable with the latest release's binary for this platform
+// when it is newer than Version, after verifying its SHA-256 checksum
+// It returns the release installed, or nil if already up to date; the new binary takes effect
+// on the next start
+// Only bare-metal installs should update themselves; containers are updated by deploying a new image
+func (c *Checker) Update(ctx context.Context, executable string) (*Release, error) {
+	release, err := c.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !Newer(release.TagName, Version) {
+		return nil, nil
+	}
+
+	var binaryURL, checksumURL string
+	for _, asset := range release.Assets {
+		switch asset.Name {
+		case AssetName():
+			binaryURL = asset.DownloadURL
+		case AssetName() + ".sha256":
+			checksumURL = asset.DownloadURL
+		}
+	}
+	if binaryURL == "" || checksumURL == "" {
+		return nil, fmt.Errorf("release %s has no %s binary with a .sha256 checksum", release.TagName, AssetName())
+	}
+
+	checksum, err := c.download(ctx, checksumURL, 1024)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checksum: %w", err)
+	}
+	// sha256sum output is "<hex>  <file name>"
+	fields := strings.Fields(string(checksum))
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty checksum for %s", AssetName())
+	}
+	binary, err := c.download(ctx, binaryURL, maxBinarySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download binary: %w", err)
+	}
+	sum := sha256.Sum256(binary)
+	if !strings.EqualFold(hex.EncodeToString(sum[:]), fields[0]) {
+		return nil, fmt.Errorf("checksum mismatch for %s %s", AssetName(), release.TagName)
+	}
+
+	if err := replaceFile(executable, binary); err != nil {
+		return nil, fmt.Errorf("failed to install %s: %w", release.TagName, err)
+	}
+	return release, nil
+}
+
+// download fetches url, failing if the body is larger than limit
+func (c *Checker) download(ctx context.Context, url string, limit int64) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", url, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d bytes", url, limit)
+	}
+	return body, nil
+}
+
+// replaceFile writes data next to path and renames it over path, so the binary is never left
+// half written; the file mode is kept
+func replaceFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".update-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,153 @@
+package resources
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/docs"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/runbooks"
+)
+
+// RegisterAll registers all resources with the MCP server
+func RegisterAll(server *mcp.Server) {
+	// Circuit breaker status resource
+	breakersResource := &mcp.Resource{
+		URI:         "status://circuit-breakers",
+		Name:        "circuit-breakers",
+		Description: "State of the circuit breakers protecting upstream APIs (reset them at /admin/circuit-breakers)",
+		MIMEType:    "application/json",
+	}
+
+	server.AddResource(breakersResource, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		data, err := json.MarshalIndent(breaker.Default.Statuses(), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{
+					URI:      breakersResource.URI,
+					MIMEType: breakersResource.MIMEType,
+					Text:     string(data),
+				},
+			},
+		}, nil
+	})
+
+	log.Printf("Registered resource: %s", breakersResource.URI)
+
+	// The caller's own activity timeline
+	activityResource := &mcp.Resource{
+		URI:         "activity://me",
+		Name:        "my-activity",
+		Description: "Your sessions and tool calls over the last 7 days, in hourly buckets",
+		MIMEType:    "application/json",
+	}
+
+	server.AddResource(activityResource, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		user := activity.User(req)
+		if user == "" {
+			return nil, fmt.Errorf("activity is only tracked for authenticated users")
+		}
+
+		data, err := json.MarshalIndent(activity.Default.Timeline(user, activity.BucketSize), "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+		return &mcp.ReadResourceResult{
+			Contents: []*mcp.ResourceContents{
+				{
+					URI:      activityResource.URI,
+					MIMEType: activityResource.MIMEType,
+					Text:     string(data),
+				},
+			},
+		}, nil
+	})
+
+	log.Printf("Registered resource: %s", activityResource.URI)
+
+	registerRunbooks(server, runbooks.FromEnv())
+	registerDocs(server)
+}
+
+// registerDocs exposes each documentation page as a docs://<name> resource
+func registerDocs(server *mcp.Server) {
+	pages := docs.List()
+	for _, page := range pages {
+		resource := &mcp.Resource{
+			URI:         "docs://" + page.Name,
+			Name:        page.Name,
+			Title:       page.Title,
+			Description: "Documentation: " + page.Title,
+			MIMEType:    "text/markdown",
+		}
+		content := page.Content
+
+		server.AddResource(resource, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{
+					{
+						URI:      resource.URI,
+						MIMEType: resource.MIMEType,
+						Text:     content,
+					},
+				},
+			}, nil
+		})
+	}
+
+	log.Printf("Registered %d documentation resources", len(pages))
+}
+
+// registerRunbooks exposes each runbook in library as a runbook://<name> resource
+// Runbooks are listed at startup but read when requested, so edits show without a restart
+func registerRunbooks(server *mcp.Server, library *runbooks.Library) {
+	available, err := library.List()
+	if err != nil {
+		log.Printf("Warning: Runbook resources unavailable: %v", err)
+		return
+	}
+
+	for _, runbook := range available {
+		resource := &mcp.Resource{
+			URI:         "runbook://" + runbook.Name,
+			Name:        runbook.Name,
+			Title:       runbook.Title,
+			Description: "Operational runbook: " + runbook.Title,
+			MIMEType:    "text/markdown",
+		}
+		name := runbook.Name
+
+		server.AddResource(resource, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			current, err := library.Get(name)
+			if errors.Is(err, runbooks.ErrNotFound) {
+				return nil, mcp.ResourceNotFoundError(resource.URI)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			return &mcp.ReadResourceResult{
+				Contents: []*mcp.ResourceContents{
+					{
+						URI:      resource.URI,
+						MIMEType: resource.MIMEType,
+						Text:     current.Content,
+					},
+				},
+			}, nil
+		})
+	}
+
+	log.Printf("Registered %d runbook resources", len(available))
+}
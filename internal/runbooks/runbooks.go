@@ -0,0 +1,101 @@
+// Package runbooks serves operational procedures written as Markdown files, so on-call
+// engineers can pull them through their MCP client during incidents
+package runbooks
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed builtin/*.md
+var builtin embed.FS
+
+// ErrNotFound is returned for names that don't match a runbook
+var ErrNotFound = errors.New("runbook not found")
+
+// Runbook is a Markdown procedure; its name is the file name without .md
+type Runbook struct {
+	Name    string
+	Title   string // The first "# " heading, or the name if there is none
+	Content string
+}
+
+// Library reads runbooks from the top level of a directory
+// Files are read on every call, so runbooks can be edited without a restart
+type Library struct {
+	fsys fs.FS
+}
+
+// New creates a library over the .md files in fsys
+func New(fsys fs.FS) *Library {
+	return &Library{fsys: fsys}
+}
+
+// FromEnv reads runbooks from RUNBOOKS_DIR, or serves the built-in ones when it is unset
+func FromEnv() *Library {
+	if dir := os.Getenv("RUNBOOKS_DIR"); dir != "" {
+		return New(os.DirFS(dir))
+	}
+	sub, _ := fs.Sub(builtin, "builtin")
+	return New(sub)
+}
+
+// List returns every runbook, sorted by name
+func (l *Library) List() ([]Runbook, error) {
+	entries, err := fs.ReadDir(l.fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading runbooks: %w", err)
+	}
+
+	runbooks := make([]Runbook, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".md")
+		if !ok || entry.IsDir() {
+			continue
+		}
+		runbook, err := l.Get(name)
+		if err != nil {
+			log.Printf("Warning: Skipping runbook %s: %v", entry.Name(), err)
+			continue
+		}
+		runbooks = append(runbooks, runbook)
+	}
+	sort.Slice(runbooks, func(i, j int) bool { return runbooks[i].Name < runbooks[j].Name })
+	return runbooks, nil
+}
+
+// Get returns the runbook called name
+// Names can't contain a path, so only files at the top of the directory are reachable
+func (l *Library) Get(name string) (Runbook, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || !fs.ValidPath(name) {
+		return Runbook{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+
+	data, err := fs.ReadFile(l.fsys, path.Clean(name)+".md")
+	if errors.Is(err, fs.ErrNotExist) {
+		return Runbook{}, fmt.Errorf("%w: %q", ErrNotFound, name)
+	}
+	if err != nil {
+		return Runbook{}, fmt.Errorf("reading runbook %s: %w", name, err)
+	}
+
+	content := string(data)
+	return Runbook{Name: name, Title: title(name, content), Content: content}, nil
+}
+
+// title returns the first level-one heading of content, or name
+func title(name, content string) string {
+	for line := range strings.Lines(content) {
+		if heading, ok := strings.CutPrefix(line, "# "); ok {
+			return strings.TrimSpace(heading)
+		}
+	}
+	return name
+}
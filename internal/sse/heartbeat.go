@@ -0,0 +1,236 @@
+// Package sse keeps long-lived Server-Sent Events streams alive through idle-timeout proxies
+package sse
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+// pingComment is an SSE comment line; clients ignore it, but it resets proxy idle timers
+var pingComment = []byte(": ping\n\n")
+
+// Heartbeat writes a ping comment on every event stream that has been silent for its interval
+// The MCP SDK writes each event with a single Write followed by Flush, so pings are only ever
+// written between events and never split one
+type Heartbeat struct {
+	interval time.Duration
+
+	active    atomic.Int64
+	opened    atomic.Int64
+	completed atomic.Int64
+	dropped   atomic.Int64
+	pings     atomic.Int64
+	resumed   atomic.Int64
+}
+
+// Stats counts the event streams seen by a Heartbeat
+type Stats struct {
+	IntervalSeconds float64 `json:"interval_seconds"`
+	Active          int64   `json:"active"`
+	Opened          int64   `json:"opened"`
+	Completed       int64   `json:"completed"`
+	Dropped         int64   `json:"dropped"`
+	Pings           int64   `json:"pings"`
+	Resumed         int64   `json:"resumed"` // Streams reopened with Last-Event-ID to replay missed events
+}
+
+// New creates a Heartbeat pinging silent streams every interval; 0 disables pings but
+// streams are still counted
+func New(interval time.Duration) *Heartbeat {
+	return &Heartbeat{interval: interval}
+}
+
+// Stats returns the current stream counters
+func (h *Heartbeat) Stats() Stats {
+	return Stats{
+		IntervalSeconds: h.interval.Seconds(),
+		Active:          h.active.Load(),
+		Opened:          h.opened.Load(),
+		Completed:       h.completed.Load(),
+		Dropped:         h.dropped.Load(),
+		Pings:           h.pings.Load(),
+		Resumed:         h.resumed.Load(),
+	}
+}
+
+// Middleware adds heartbeats to responses served as text/event-stream
+// A stream counts as dropped when the client connection went away or a ping couldn't be
+// written before the handler finished it
+func (h *Heartbeat) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream := &streamWriter{
+			ResponseWriter: w,
+			heartbeat:      h,
+			resumed:        r.Header.Get("Last-Event-ID") != "",
+			done:           make(chan struct{}),
+		}
+		start := time.Now()
+
+		next.ServeHTTP(stream, r)
+
+		if !stream.isStream() {
+			return
+		}
+		close(stream.done)
+		stream.wg.Wait()
+
+		h.active.Add(-1)
+		if r.Context().Err() != nil || stream.pingFailed.Load() {
+			h.dropped.Add(1)
+			logDroppedStream(r, time.Since(start))
+		} else {
+			h.completed.Add(1)
+		}
+	})
+}
+
+// logDroppedStream emits a [METRIC] event that log-based metrics can count
+func logDroppedStream(r *http.Request, duration time.Duration) {
+	event, _ := json.Marshal(instance.Current().Tag(map[string]any{
+		"event":       "sse_stream_dropped",
+		"method":      r.Method,
+		"path":        r.URL.Path,
+		"session":     r.Header.Get("Mcp-Session-Id"),
+		"duration_ms": duration.Milliseconds(),
+	}))
+	log.Printf("[METRIC] %s", event)
+}
+
+// streamWriter serializes the handler's writes with heartbeat pings
+type streamWriter struct {
+	http.ResponseWriter
+	heartbeat *Heartbeat
+	resumed   bool
+
+	mu          sync.Mutex
+	wroteHeader bool
+	streaming   bool
+	lastWrite   time.Time
+
+	done       chan struct{}
+	wg         sync.WaitGroup
+	pingFailed atomic.Bool
+}
+
+// isStream reports whether the response turned out to be an event stream
+func (s *streamWriter) isStream() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.streaming
+}
+
+func (s *streamWriter) WriteHeader(code int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked(code)
+}
+
+// writeHeaderLocked sends the status and starts pinging if the response is an event stream
+func (s *streamWriter) writeHeaderLocked(code int) {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+	s.ResponseWriter.WriteHeader(code)
+
+	if code != http.StatusOK || !strings.HasPrefix(s.Header().Get("Content-Type"), "text/event-stream") {
+		return
+	}
+	s.streaming = true
+	s.lastWrite = time.Now()
+	s.heartbeat.active.Add(1)
+	s.heartbeat.opened.Add(1)
+	if s.resumed {
+		s.heartbeat.resumed.Add(1)
+	}
+
+	if s.heartbeat.interval > 0 {
+		s.wg.Add(1)
+		go s.ping()
+	}
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked(http.StatusOK)
+	s.lastWrite = time.Now()
+	return s.ResponseWriter.Write(p)
+}
+
+// Flush forwards to the underlying writer so events are delivered immediately
+func (s *streamWriter) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writeHeaderLocked(http.StatusOK)
+	if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// ping writes a comment whenever the stream has been silent for the interval, until the
+// handler returns or a write fails
+func (s *streamWriter) ping() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.heartbeat.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		if time.Since(s.lastWrite) < s.heartbeat.interval {
+			s.mu.Unlock()
+			continue
+		}
+		_, err := s.ResponseWriter.Write(pingComment)
+		if err == nil {
+			if flusher, ok := s.ResponseWriter.(http.Flusher); ok {
+				flusher.Flush()
+			}
+			s.lastWrite = time.Now()
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			s.pingFailed.Store(true)
+			return
+		}
+		s.heartbeat.pings.Add(1)
+	}
+}
+
+// StatusHandler reports the heartbeat's stream counters as JSON
+type StatusHandler struct {
+	heartbeat *Heartbeat
+}
+
+// NewStatusHandler creates a new handler for the given heartbeat
+func NewStatusHandler(heartbeat *Heartbeat) *StatusHandler {
+	return &StatusHandler{heartbeat: heartbeat}
+}
+
+// ServeHTTP implements http.Handler
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.heartbeat.Stats()); err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,166 @@
+// Package telemetry exports OpenTelemetry traces over OTLP, so a tools/call can be followed from
+// the HTTP edge through token verification to the tool handler in X-Ray or Jaeger
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+// instrumentationName identifies this server's spans
+const instrumentationName = "EmmanuelDamienDustinDeploymentProject/DeploymentProject"
+
+// defaultServiceName is used unless OTEL_SERVICE_NAME is set
+const defaultServiceName = "mcp-server"
+
+// Setup installs an OTLP/HTTP trace exporter when OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set; otherwise spans are discarded
+// The exporter reads the other standard OTEL_* variables itself; call the returned function on
+// shutdown to flush pending spans
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	metadata := instance.Current()
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("cloud.region", metadata.Region),
+			attribute.String("cloud.availability_zone", metadata.AvailabilityZone),
+			attribute.String("service.instance.id", metadata.InstanceID),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	log.Printf("Exporting OpenTelemetry traces as %s", serviceName)
+	return provider.Shutdown, nil
+}
+
+// Start starts a span named name as a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err (if not nil) on span and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// ContextFromHeader returns ctx carrying the remote span described by header's trace context,
+// for code that only sees request headers, such as MCP method handlers
+func ContextFromHeader(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// InjectHeader writes the trace context of the span in ctx into header
+func InjectHeader(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// HTTPMiddleware starts a server span for every request, continuing the client's trace if it sent one
+// The request's trace headers are rewritten to point at the server span, so MCP handlers that
+// only see headers (and outbound calls forwarding them) continue this span rather than the client's
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := ContextFromHeader(r.Context(), r.Header)
+		serve(ctx, r.Method+" "+r.URL.Path, trace.SpanKindServer, w, r, next)
+	})
+}
+
+// Handler wraps next in an internal span named name that records the response status
+func Handler(name string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serve(r.Context(), name, trace.SpanKindInternal, w, r, next)
+	})
+}
+
+// serve runs next inside a new span, propagating it through the request context and headers
+func serve(ctx context.Context, name string, kind trace.SpanKind, w http.ResponseWriter, r *http.Request, next http.Handler) {
+	ctx, span := otel.Tracer(instrumentationName).Start(ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(
+			attribute.String("http.request.method", r.Method),
+			attribute.String("url.path", r.URL.Path),
+		),
+	)
+	defer span.End()
+
+	if span.SpanContext().IsValid() {
+		InjectHeader(ctx, r.Header)
+	}
+
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	next.ServeHTTP(recorder, r.WithContext(ctx))
+
+	span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+	if recorder.status >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(recorder.status))
+	}
+}
+
+// statusRecorder captures the response status for the server span
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush forwards to the underlying writer so streaming responses still work
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
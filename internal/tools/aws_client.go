@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+)
+
+// awsClient sends SigV4-signed requests to AWS JSON APIs.
+// Credentials come from the default chain, which resolves to the ECS task role in production.
+type awsClient struct {
+	mu         sync.Mutex
+	awsCfg     *aws.Config
+	httpClient *http.Client
+	signer     *v4.Signer
+}
+
+// sharedAWSClient is shared by every AWS-backed tool so credentials are only resolved once
+var sharedAWSClient = &awsClient{
+	httpClient: &http.Client{Timeout: 15 * time.Second},
+	signer:     v4.NewSigner(),
+}
+
+// SetAWSClient replaces the AWS configuration and HTTP client the AWS-backed tools use, e.g. to
+// point them at a fake AWS; nil arguments restore the default credential chain and client
+func SetAWSClient(awsCfg *aws.Config, httpClient *http.Client) {
+	sharedAWSClient.mu.Lock()
+	defer sharedAWSClient.mu.Unlock()
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 15 * time.Second}
+	}
+	sharedAWSClient.awsCfg = awsCfg
+	sharedAWSClient.httpClient = httpClient
+}
+
+// loadConfig lazily loads the AWS SDK configuration, retrying on the next call if it fails
+func (c *awsClient) loadConfig(ctx context.Context) (*aws.Config, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.awsCfg != nil {
+		return c.awsCfg, nil
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load AWS SDK config: %w", err)
+	}
+	c.awsCfg = &awsCfg
+
+	return c.awsCfg, nil
+}
+
+// region returns the region from the AWS SDK configuration
+func (c *awsClient) region(ctx context.Context) (string, error) {
+	awsCfg, err := c.loadConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	if awsCfg.Region == "" {
+		return "", fmt.Errorf("AWS region is not configured (set AWS_REGION)")
+	}
+	return awsCfg.Region, nil
+}
+
+// callJSON invokes an operation on an AWS JSON 1.1 API such as Cost Explorer, ECS, or CloudWatch Logs.
+// target is the value of the X-Amz-Target header, e.g. "AWSInsightsIndexService.GetCostAndUsage".
+func (c *awsClient) callJSON(ctx context.Context, service, region, endpoint, target string, input, output any) error {
+	awsCfg, err := c.loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", target, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", target, err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+
+	if err := c.sign(ctx, awsCfg, req, payload, service, region); err != nil {
+		return err
+	}
+
+	return c.do(req, target, func(body io.Reader) error {
+		if err := json.NewDecoder(body).Decode(output); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", target, err)
+		}
+		return nil
+	})
+}
+
+// callQuery invokes an action on an AWS Query API such as CloudFormation and decodes the XML response into output
+func (c *awsClient) callQuery(ctx context.Context, service, region, endpoint string, params url.Values, output any) error {
+	awsCfg, err := c.loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	action := params.Get("Action")
+	payload := []byte(params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create %s request: %w", action, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	if err := c.sign(ctx, awsCfg, req, payload, service, region); err != nil {
+		return err
+	}
+
+	return c.do(req, action, func(body io.Reader) error {
+		if err := xml.NewDecoder(body).Decode(output); err != nil {
+			return fmt.Errorf("failed to decode %s response: %w", action, err)
+		}
+		return nil
+	})
+}
+
+// sign adds SigV4 authentication headers to the request
+func (c *awsClient) sign(ctx context.Context, awsCfg *aws.Config, req *http.Request, payload []byte, service, region string) error {
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+
+	hash := sha256.Sum256(payload)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), service, region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign AWS request: %w", err)
+	}
+
+	return nil
+}
+
+// do sends a signed request and hands successful response bodies to decode
+func (c *awsClient) do(req *http.Request, operation string, decode func(body io.Reader) error) error {
+	c.mu.Lock()
+	httpClient := c.httpClient
+	c.mu.Unlock()
+
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		recordUpstreamCall(req.Context(), req.URL.Host, 0, time.Since(start))
+		return fmt.Errorf("%s request failed: %w", operation, err)
+	}
+	recordUpstreamCall(req.Context(), req.URL.Host, resp.StatusCode, time.Since(start))
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Printf("Failed to close response body: %v", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("%s returned status %d: %s", operation, resp.StatusCode, string(body))
+	}
+
+	return decode(resp.Body)
+}
@@ -58,10 +58,17 @@ func (tool *CalculateAPR) Action(ctx context.Context, req *mcp.CallToolRequest,
 	}, nil, nil
 }
 
+// Profile implements ProfiledTool
+func (tool *CalculateAPR) Profile() Profile {
+	return Profile{Latency: LatencyFast, Cost: CostFree}
+}
+
 func (tool *CalculateAPR) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
 	mcpToolInstance = &mcp.Tool{
 		Name:        tool.Name,
 		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
 	}
 
 	mcp.AddTool(server, mcpToolInstance, tool.Action)
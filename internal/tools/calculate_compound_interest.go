@@ -0,0 +1,127 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCompoundingYears bounds the breakdown the tool returns
+const maxCompoundingYears = 100
+
+// compoundingPeriods is how many times a year interest is added for each compounding frequency
+var compoundingPeriods = map[string]int{
+	"annually":     1,
+	"semiannually": 2,
+	"quarterly":    4,
+	"monthly":      12,
+	"daily":        365,
+}
+
+type CalculateCompoundInterest struct {
+	Name        string
+	Description string
+}
+
+// CalculateCompoundInterestParams defines the parameters for the calculate-compound-interest tool
+type CalculateCompoundInterestParams struct {
+	Principal           float64 `json:"principal" jsonschema:"The starting balance (e.g., 10000)"`
+	AnnualRate          float64 `json:"annualRate" jsonschema:"The annual interest rate in percent (e.g., 5 for 5%)"`
+	Compounding         string  `json:"compounding,omitempty" jsonschema:"How often interest is compounded: annually, semiannually, quarterly, monthly (default), or daily"`
+	MonthlyContribution float64 `json:"monthlyContribution,omitempty" jsonschema:"Amount added every month (e.g., 200), deposited at the end of each compounding period"`
+	Years               int     `json:"years" jsonschema:"How many years to grow the balance (e.g., 10)"`
+}
+
+func (tool *CalculateCompoundInterest) Action(ctx context.Context, req *mcp.CallToolRequest, params *CalculateCompoundInterestParams) (*mcp.CallToolResult, any, error) {
+	if params.Principal < 0 {
+		return nil, nil, fmt.Errorf("principal cannot be negative")
+	}
+	if params.MonthlyContribution < 0 {
+		return nil, nil, fmt.Errorf("monthly contribution cannot be negative")
+	}
+	if params.AnnualRate < 0 {
+		return nil, nil, fmt.Errorf("annual rate cannot be negative")
+	}
+	if params.Years <= 0 || params.Years > maxCompoundingYears {
+		return nil, nil, fmt.Errorf("years must be between 1 and %d", maxCompoundingYears)
+	}
+	compounding := strings.ToLower(strings.TrimSpace(params.Compounding))
+	if compounding == "" {
+		compounding = "monthly"
+	}
+	periods, ok := compoundingPeriods[compounding]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown compounding frequency %q: use annually, semiannually, quarterly, monthly, or daily", params.Compounding)
+	}
+
+	periodRate := params.AnnualRate / 100 / float64(periods)
+	deposit := params.MonthlyContribution * 12 / float64(periods)
+
+	var b strings.Builder
+	b.WriteString("Year | Contributions | Interest | Balance\n")
+	balance := params.Principal
+	totalContributions := 0.0
+	for year := 1; year <= params.Years; year++ {
+		yearInterest := 0.0
+		for range periods {
+			interest := balance * periodRate
+			yearInterest += interest
+			balance += interest + deposit
+		}
+		yearContributions := deposit * float64(periods)
+		totalContributions += yearContributions
+		fmt.Fprintf(&b, "%d | $%.2f | $%.2f | $%.2f\n", year, yearContributions, yearInterest, balance)
+	}
+	totalInterest := balance - params.Principal - totalContributions
+
+	term := fmt.Sprintf("%d years", params.Years)
+	if params.Years == 1 {
+		term = "1 year"
+	}
+
+	response := fmt.Sprintf(
+		"$%.2f at %.2f%% compounded %s with $%.2f added monthly grows to $%.2f after %s: $%.2f in contributions and $%.2f in interest.\n\n%s",
+		params.Principal,
+		params.AnnualRate,
+		compounding,
+		params.MonthlyContribution,
+		balance,
+		term,
+		totalContributions,
+		totalInterest,
+		b.String(),
+	)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: strings.TrimSpace(response)},
+		},
+	}, nil, nil
+}
+
+// Profile implements ProfiledTool
+func (tool *CalculateCompoundInterest) Profile() Profile {
+	return Profile{Latency: LatencyFast, Cost: CostFree}
+}
+
+func (tool *CalculateCompoundInterest) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &CalculateCompoundInterest{
+		Name:        "calculate-compound-interest",
+		Description: "Calculates the final balance of savings with compound interest and monthly contributions, with a yearly breakdown.",
+	})
+}
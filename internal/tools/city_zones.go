@@ -0,0 +1,192 @@
+package tools
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// cityZone is a city get-city-time knows by name
+type cityZone struct {
+	Name string
+	Zone string
+}
+
+// cityZones maps normalized city names and common abbreviations to their time zones
+var cityZones = map[string]cityZone{
+	"nyc":           {"New York City", "America/New_York"},
+	"new york":      {"New York City", "America/New_York"},
+	"new york city": {"New York City", "America/New_York"},
+	"boston":        {"Boston", "America/New_York"},
+	"washington":    {"Washington, D.C.", "America/New_York"},
+	"dc":            {"Washington, D.C.", "America/New_York"},
+	"miami":         {"Miami", "America/New_York"},
+	"atlanta":       {"Atlanta", "America/New_York"},
+	"toronto":       {"Toronto", "America/Toronto"},
+	"chicago":       {"Chicago", "America/Chicago"},
+	"dallas":        {"Dallas", "America/Chicago"},
+	"houston":       {"Houston", "America/Chicago"},
+	"mexico city":   {"Mexico City", "America/Mexico_City"},
+	"denver":        {"Denver", "America/Denver"},
+	"phoenix":       {"Phoenix", "America/Phoenix"},
+	"sf":            {"San Francisco", "America/Los_Angeles"},
+	"san francisco": {"San Francisco", "America/Los_Angeles"},
+	"la":            {"Los Angeles", "America/Los_Angeles"},
+	"los angeles":   {"Los Angeles", "America/Los_Angeles"},
+	"seattle":       {"Seattle", "America/Los_Angeles"},
+	"vancouver":     {"Vancouver", "America/Vancouver"},
+	"anchorage":     {"Anchorage", "America/Anchorage"},
+	"honolulu":      {"Honolulu", "Pacific/Honolulu"},
+	"sao paulo":     {"São Paulo", "America/Sao_Paulo"},
+	"buenos aires":  {"Buenos Aires", "America/Argentina/Buenos_Aires"},
+	"bogota":        {"Bogotá", "America/Bogota"},
+	"lima":          {"Lima", "America/Lima"},
+	"london":        {"London", "Europe/London"},
+	"dublin":        {"Dublin", "Europe/Dublin"},
+	"lisbon":        {"Lisbon", "Europe/Lisbon"},
+	"paris":         {"Paris", "Europe/Paris"},
+	"madrid":        {"Madrid", "Europe/Madrid"},
+	"amsterdam":     {"Amsterdam", "Europe/Amsterdam"},
+	"brussels":      {"Brussels", "Europe/Brussels"},
+	"berlin":        {"Berlin", "Europe/Berlin"},
+	"frankfurt":     {"Frankfurt", "Europe/Berlin"},
+	"zurich":        {"Zurich", "Europe/Zurich"},
+	"rome":          {"Rome", "Europe/Rome"},
+	"stockholm":     {"Stockholm", "Europe/Stockholm"},
+	"warsaw":        {"Warsaw", "Europe/Warsaw"},
+	"athens":        {"Athens", "Europe/Athens"},
+	"istanbul":      {"Istanbul", "Europe/Istanbul"},
+	"moscow":        {"Moscow", "Europe/Moscow"},
+	"cairo":         {"Cairo", "Africa/Cairo"},
+	"johannesburg":  {"Johannesburg", "Africa/Johannesburg"},
+	"lagos":         {"Lagos", "Africa/Lagos"},
+	"nairobi":       {"Nairobi", "Africa/Nairobi"},
+	"dubai":         {"Dubai", "Asia/Dubai"},
+	"tel aviv":      {"Tel Aviv", "Asia/Jerusalem"},
+	"mumbai":        {"Mumbai", "Asia/Kolkata"},
+	"delhi":         {"Delhi", "Asia/Kolkata"},
+	"bangalore":     {"Bangalore", "Asia/Kolkata"},
+	"singapore":     {"Singapore", "Asia/Singapore"},
+	"bangkok":       {"Bangkok", "Asia/Bangkok"},
+	"jakarta":       {"Jakarta", "Asia/Jakarta"},
+	"hong kong":     {"Hong Kong", "Asia/Hong_Kong"},
+	"shanghai":      {"Shanghai", "Asia/Shanghai"},
+	"beijing":       {"Beijing", "Asia/Shanghai"},
+	"taipei":        {"Taipei", "Asia/Taipei"},
+	"seoul":         {"Seoul", "Asia/Seoul"},
+	"tokyo":         {"Tokyo", "Asia/Tokyo"},
+	"manila":        {"Manila", "Asia/Manila"},
+	"sydney":        {"Sydney", "Australia/Sydney"},
+	"melbourne":     {"Melbourne", "Australia/Melbourne"},
+	"perth":         {"Perth", "Australia/Perth"},
+	"auckland":      {"Auckland", "Pacific/Auckland"},
+	"utc":           {"UTC", "UTC"},
+}
+
+// normalizeCity lowercases a city name and turns underscores, hyphens, and repeated spaces
+// into single spaces, so "New_York" and "new-york" match "new york"
+func normalizeCity(city string) string {
+	city = strings.NewReplacer("_", " ", "-", " ").Replace(strings.ToLower(city))
+	return strings.Join(strings.Fields(city), " ")
+}
+
+// resolveCity finds the time zone for a city name, abbreviation, or IANA time zone, allowing
+// small misspellings of city names
+func resolveCity(city string) (cityZone, *time.Location, error) {
+	key := normalizeCity(city)
+	if known, ok := cityZones[key]; ok {
+		loc, err := time.LoadLocation(known.Zone)
+		return known, loc, err
+	}
+
+	// IANA names such as Europe/Paris; LoadLocation is case sensitive and also reads files, so
+	// only names shaped like zones are tried
+	if strings.Contains(city, "/") && !strings.Contains(city, "..") {
+		// The last part of a zone is often a known city, e.g. Europe/Paris, named more readably
+		// in the table
+		key = normalizeCity(city[strings.LastIndex(city, "/")+1:])
+		known, isCity := cityZones[key]
+		if loc, err := time.LoadLocation(strings.TrimSpace(city)); err == nil {
+			if isCity && known.Zone == loc.String() {
+				return known, loc, nil
+			}
+			return cityZone{Name: loc.String(), Zone: loc.String()}, loc, nil
+		}
+		if isCity {
+			// A known city with the wrong case, e.g. europe/berlin
+			loc, err := time.LoadLocation(known.Zone)
+			return known, loc, err
+		}
+	}
+
+	if match, ok := closestCity(key); ok {
+		known := cityZones[match]
+		loc, err := time.LoadLocation(known.Zone)
+		return known, loc, err
+	}
+	return cityZone{}, nil, fmt.Errorf("unknown city or time zone %q: use an IANA time zone such as Europe/Paris or one of %s",
+		city, strings.Join(knownCities(), ", "))
+}
+
+// closestCity returns the known city name within a small edit distance of key, if exactly one
+// is closest
+func closestCity(key string) (string, bool) {
+	// Allow one typo in short names and two in longer ones
+	limit := 1
+	if len(key) >= 10 {
+		limit = 2
+	}
+	best, bestDistance, tied := "", limit+1, false
+	for name := range cityZones {
+		if len(name) <= 3 {
+			// Abbreviations are too short to guess at
+			continue
+		}
+		distance := editDistance(key, name)
+		switch {
+		case distance < bestDistance:
+			best, bestDistance, tied = name, distance, false
+		case distance == bestDistance && cityZones[name] != cityZones[best]:
+			tied = true
+		}
+	}
+	return best, best != "" && !tied
+}
+
+// editDistance counts the insertions, deletions, substitutions, and swaps of adjacent letters
+// that turn a into b (the optimal string alignment distance)
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// knownCities lists the city names and abbreviations accepted, sorted
+func knownCities() []string {
+	names := make([]string, 0, len(cityZones))
+	for name := range cityZones {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,11 @@
+package tools
+
+import "EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+
+// toolClock is the source of the current time for tools
+var toolClock clock.Clock = clock.System{}
+
+// SetClock replaces the clock tools use to tell the current time
+func SetClock(c clock.Clock) {
+	toolClock = c
+}
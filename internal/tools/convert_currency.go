@@ -0,0 +1,96 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ConvertCurrency struct {
+	Name        string
+	Description string
+}
+
+// ConvertCurrencyParams defines the parameters for the convert-currency tool
+type ConvertCurrencyParams struct {
+	Amount float64 `json:"amount" jsonschema:"Amount of money to convert"`
+	From   string  `json:"from" jsonschema:"ISO 4217 code of the currency to convert from (e.g. USD)"`
+	To     string  `json:"to" jsonschema:"ISO 4217 code of the currency to convert to (e.g. EUR)"`
+}
+
+func (tool *ConvertCurrency) Action(ctx context.Context, req *mcp.CallToolRequest, params *ConvertCurrencyParams) (*mcp.CallToolResult, any, error) {
+	from, err := currencyCode(params.From)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := currencyCode(params.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	if math.IsNaN(params.Amount) || math.IsInf(params.Amount, 0) {
+		return nil, nil, fmt.Errorf("amount must be a finite number")
+	}
+
+	rate := 1.0
+	var asOf string
+	if from != to {
+		rates, stale, err := ratesFor(ctx, from)
+		if err != nil {
+			return nil, nil, fmt.Errorf("exchange rates for %s are unavailable: %w", from, err)
+		}
+		var ok bool
+		if rate, ok = rates.rates[to]; !ok {
+			return nil, nil, fmt.Errorf("no exchange rate from %s to %s", from, to)
+		}
+		asOf = fmt.Sprintf(" (rate %g as of %s)", rate, rates.fetchedAt.UTC().Format(time.RFC3339))
+		if stale {
+			asOf = fmt.Sprintf(" (rate %g cached at %s; the rate provider is unavailable)", rate, rates.fetchedAt.UTC().Format(time.RFC3339))
+		}
+	}
+
+	response := fmt.Sprintf("%.2f %s = %.2f %s%s", params.Amount, from, params.Amount*rate, to, asOf)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: response},
+		},
+	}, nil, nil
+}
+
+// currencyCode normalizes an ISO 4217 currency code
+func currencyCode(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if len(code) != 3 || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return "", fmt.Errorf("invalid currency code %q: use a three-letter ISO 4217 code such as USD", code)
+	}
+	return code, nil
+}
+
+// Profile implements ProfiledTool; rates are cached, so most calls answer without the provider
+func (tool *ConvertCurrency) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostFree, External: true}
+}
+
+func (tool *ConvertCurrency) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &ConvertCurrency{
+		Name:        "convert-currency",
+		Description: "Convert an amount between currencies at the current exchange rate",
+	})
+}
@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type CreateIssue struct {
+	Name        string
+	Description string
+}
+
+// CreateIssueParams defines the parameters for the create-issue tool
+type CreateIssueParams struct {
+	Repo   string   `json:"repo" jsonschema:"Repository as owner/name (e.g. octocat/hello-world)"`
+	Title  string   `json:"title" jsonschema:"Issue title"`
+	Body   string   `json:"body,omitempty" jsonschema:"Issue description, in Markdown"`
+	Labels []string `json:"labels,omitempty" jsonschema:"Labels to add; labels the repository doesn't have are created if you can push to it"`
+}
+
+// githubNewIssue is the body of GitHub's create issue request
+type githubNewIssue struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+}
+
+func (tool *CreateIssue) Action(ctx context.Context, req *mcp.CallToolRequest, params *CreateIssueParams) (*mcp.CallToolResult, any, error) {
+	token, err := callerGitHubToken(req, githubRepoScope)
+	if err != nil {
+		return nil, nil, err
+	}
+	repoPath, err := githubRepo(params.Repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	title := strings.TrimSpace(params.Title)
+	if title == "" {
+		return nil, nil, fmt.Errorf("title is required")
+	}
+
+	var issue githubIssue
+	newIssue := githubNewIssue{Title: title, Body: params.Body, Labels: params.Labels}
+	if err := githubCall(ctx, token, http.MethodPost, repoPath+"/issues", nil, newIssue, &issue); err != nil {
+		return nil, nil, err
+	}
+
+	response := fmt.Sprintf("Created issue #%d in %s: %s\n%s", issue.Number, strings.TrimSpace(params.Repo), issue.Title, issue.URL)
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: response},
+		},
+	}, nil, nil
+}
+
+// ActsAsGitHubUser implements GitHubUserTool
+func (tool *CreateIssue) ActsAsGitHubUser() {}
+
+// Profile implements ProfiledTool; every call opens a new issue
+func (tool *CreateIssue) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostFree, External: true, Writes: true}
+}
+
+func (tool *CreateIssue) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &CreateIssue{
+		Name:        "create-issue",
+		Description: "Open an issue in a GitHub repository, as the GitHub user you signed in as",
+	})
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"log"
+	"os"
+	"slices"
+)
+
+// toolSelection is which tools an environment serves, from TOOLS_ENABLED and TOOLS_DISABLED
+type toolSelection struct {
+	// enabled lists the only tools served; empty serves every tool
+	enabled  []string
+	disabled []string
+
+	// persona, when set, limits the tools to those it lists
+	persona Persona
+}
+
+// toolSelectionFromEnv reads TOOLS_ENABLED and TOOLS_DISABLED (comma-separated tool names)
+func toolSelectionFromEnv() toolSelection {
+	return toolSelection{
+		enabled:  splitList(os.Getenv("TOOLS_ENABLED")),
+		disabled: splitList(os.Getenv("TOOLS_DISABLED")),
+	}
+}
+
+// allows reports whether the tool called name is served
+func (s toolSelection) allows(name string) bool {
+	if len(s.enabled) > 0 && !slices.Contains(s.enabled, name) {
+		return false
+	}
+	if s.persona.Name != "" && !slices.Contains(s.persona.Tools, name) {
+		return false
+	}
+	return !slices.Contains(s.disabled, name)
+}
+
+// warnUnknown logs names in either list, or the persona's tools, that no tool has, which are
+// most likely typos; TOOLS_ENABLED and TOOLS_DISABLED are already checked for the main server
+func (s toolSelection) warnUnknown(registered []string) {
+	lists := map[string][]string{"TOOLS_ENABLED": s.enabled, "TOOLS_DISABLED": s.disabled}
+	if s.persona.Name != "" {
+		lists = map[string][]string{"MCP_PERSONAS": s.persona.Tools}
+	}
+	for variable, names := range lists {
+		for _, name := range names {
+			if !slices.Contains(registered, name) {
+				log.Printf("Warning: %s names unknown tool %q", variable, name)
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+)
+
+// Exchange rate defaults, used when EXCHANGE_RATE_API_URL and EXCHANGE_RATE_CACHE_SECONDS are unset
+const (
+	defaultExchangeRateURL = "https://api.frankfurter.app/latest"
+	defaultRateCacheTTL    = 1 * time.Hour
+)
+
+// exchangeRateBreaker stops calls to the exchange rate API while it is failing, so cached
+// rates are served straight away
+var exchangeRateBreaker = breaker.New("exchange-rates", 5, 30*time.Second)
+
+// RateProvider fetches the current exchange rates from base to every currency it knows
+type RateProvider interface {
+	Rates(ctx context.Context, base string) (map[string]float64, error)
+}
+
+// HTTPRateProvider fetches rates from an exchange rate API that answers a GET with the base
+// currency in a "base" query parameter (or in place of {base} in URL) with JSON like
+// {"base":"USD","rates":{"EUR":0.92}}; "conversion_rates" is accepted in place of "rates"
+type HTTPRateProvider struct {
+	URL string
+	// APIKey, when set, is sent as a bearer token
+	APIKey string
+}
+
+type exchangeRateResponse struct {
+	Rates           map[string]float64 `json:"rates"`
+	ConversionRates map[string]float64 `json:"conversion_rates"`
+}
+
+// Rates implements RateProvider
+func (p *HTTPRateProvider) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	endpoint := strings.ReplaceAll(p.URL, "{base}", url.PathEscape(base))
+	if endpoint == p.URL {
+		parsed, err := url.Parse(p.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate API URL: %w", err)
+		}
+		query := parsed.Query()
+		query.Set("base", base)
+		parsed.RawQuery = query.Encode()
+		endpoint = parsed.String()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating exchange rate API request failed: %w", err)
+	}
+	if p.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.APIKey)
+	}
+
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to exchange rate API failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("exchange rate API returned status %d: %s", res.StatusCode, message)
+	}
+
+	var body exchangeRateResponse
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decoding exchange rate API response failed: %w", err)
+	}
+	rates := body.Rates
+	if rates == nil {
+		rates = body.ConversionRates
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("exchange rate API returned no rates for %s", base)
+	}
+	return rates, nil
+}
+
+// NewHTTPRateProviderFromEnv creates a provider for EXCHANGE_RATE_API_URL, authenticated with
+// EXCHANGE_RATE_API_KEY
+func NewHTTPRateProviderFromEnv() *HTTPRateProvider {
+	provider := &HTTPRateProvider{
+		URL:    os.Getenv("EXCHANGE_RATE_API_URL"),
+		APIKey: os.Getenv("EXCHANGE_RATE_API_KEY"),
+	}
+	if provider.URL == "" {
+		provider.URL = defaultExchangeRateURL
+	}
+	return provider
+}
+
+// cachedRates are the rates last fetched for a base currency
+type cachedRates struct {
+	rates     map[string]float64
+	fetchedAt time.Time
+}
+
+// exchangeRates caches rates per base currency in front of the provider, and falls back to
+// the cached rates, however old, when the provider is down
+var exchangeRates = struct {
+	mu       sync.Mutex
+	provider RateProvider
+	cache    map[string]cachedRates
+}{cache: make(map[string]cachedRates)}
+
+// SetRateProvider replaces the provider convert-currency fetches rates from and clears the
+// cache; nil goes back to the API configured in the environment
+func SetRateProvider(provider RateProvider) {
+	exchangeRates.mu.Lock()
+	defer exchangeRates.mu.Unlock()
+	exchangeRates.provider = provider
+	exchangeRates.cache = make(map[string]cachedRates)
+}
+
+// rateCacheTTL is how long fetched rates are used before being fetched again, from
+// EXCHANGE_RATE_CACHE_SECONDS
+func rateCacheTTL() (time.Duration, error) {
+	raw := os.Getenv("EXCHANGE_RATE_CACHE_SECONDS")
+	if raw == "" {
+		return defaultRateCacheTTL, nil
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds < 0 {
+		return 0, fmt.Errorf("invalid EXCHANGE_RATE_CACHE_SECONDS %q", raw)
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// ratesFor returns the rates from base, when they were fetched, and whether they are stale
+// because the provider could not be reached
+func ratesFor(ctx context.Context, base string) (cachedRates, bool, error) {
+	ttl, err := rateCacheTTL()
+	if err != nil {
+		return cachedRates{}, false, err
+	}
+
+	exchangeRates.mu.Lock()
+	defer exchangeRates.mu.Unlock()
+
+	cached, ok := exchangeRates.cache[base]
+	now := toolClock.Now()
+	if ok && now.Sub(cached.fetchedAt) < ttl {
+		return cached, false, nil
+	}
+
+	provider := exchangeRates.provider
+	if provider == nil {
+		provider = NewHTTPRateProviderFromEnv()
+	}
+	var rates map[string]float64
+	err = exchangeRateBreaker.Do(func() error {
+		var err error
+		rates, err = provider.Rates(ctx, base)
+		return err
+	})
+	if err != nil {
+		if ok {
+			log.Printf("Exchange rate provider failed, using %s rates from %s: %v", base, cached.fetchedAt.UTC().Format(time.RFC3339), err)
+			return cached, true, nil
+		}
+		return cachedRates{}, false, err
+	}
+
+	cached = cachedRates{rates: rates, fetchedAt: now}
+	exchangeRates.cache[base] = cached
+	return cached, false, nil
+}
@@ -0,0 +1,276 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Cost Explorer is a global service served from us-east-1 and bills per request,
+// so reports are cached instead of being fetched on every call.
+const (
+	costExplorerRegion   = "us-east-1"
+	costExplorerEndpoint = "https://ce.us-east-1.amazonaws.com/"
+	costReportCacheTTL   = 1 * time.Hour
+)
+
+type GetAWSCosts struct {
+	Name        string
+	Description string
+
+	mu         sync.Mutex
+	cachedAt   time.Time
+	cachedText string
+}
+
+type costTimePeriod struct {
+	Start string `json:"Start"`
+	End   string `json:"End"`
+}
+
+type costMetricValue struct {
+	Amount string `json:"Amount"`
+	Unit   string `json:"Unit"`
+}
+
+type getCostAndUsageResponse struct {
+	ResultsByTime []struct {
+		Groups []struct {
+			Keys    []string                   `json:"Keys"`
+			Metrics map[string]costMetricValue `json:"Metrics"`
+		} `json:"Groups"`
+	} `json:"ResultsByTime"`
+}
+
+type getCostForecastResponse struct {
+	Total costMetricValue `json:"Total"`
+}
+
+// costGroup is the month-to-date cost of a single Cost Explorer group (a service or record type)
+type costGroup struct {
+	Key    string
+	Amount float64
+}
+
+// RequiredScopes implements ScopedTool
+func (tool *GetAWSCosts) RequiredScopes() []string {
+	return []string{adminScope}
+}
+
+func (tool *GetAWSCosts) Action(ctx context.Context, req *mcp.CallToolRequest, params *struct{}) (*mcp.CallToolResult, any, error) {
+	if err := requireScopes(req, tool.RequiredScopes()); err != nil {
+		return nil, nil, err
+	}
+
+	if sandboxMode.Load() {
+		report, err := sandboxCostReport(toolClock.Now().UTC())
+		if err != nil {
+			return nil, nil, err
+		}
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: report},
+			},
+		}, nil, nil
+	}
+
+	tool.mu.Lock()
+	defer tool.mu.Unlock()
+
+	if tool.cachedText == "" || toolClock.Now().Sub(tool.cachedAt) > costReportCacheTTL {
+		report, err := buildCostReport(ctx, toolClock.Now().UTC())
+		if err != nil {
+			return nil, nil, err
+		}
+		tool.cachedText = report
+		tool.cachedAt = toolClock.Now()
+	}
+
+	response := tool.cachedText + fmt.Sprintf("\n(report generated %s)", tool.cachedAt.UTC().Format(time.RFC3339))
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: response},
+		},
+	}, nil, nil
+}
+
+// buildCostReport queries Cost Explorer for month-to-date spend by service, credits applied, and the month-end forecast
+func buildCostReport(ctx context.Context, now time.Time) (string, error) {
+	// Every Cost Explorer request is billed, so a bad budget is caught before making any
+	budget, err := monthlyBudget()
+	if err != nil {
+		return "", err
+	}
+
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	nextMonth := monthStart.AddDate(0, 1, 0)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// The end date is exclusive, so include today's partial usage
+	period := costTimePeriod{
+		Start: monthStart.Format(time.DateOnly),
+		End:   today.AddDate(0, 0, 1).Format(time.DateOnly),
+	}
+
+	services, err := queryCostGroups(ctx, period, "SERVICE", []string{"Usage"})
+	if err != nil {
+		return "", err
+	}
+	recordTypes, err := queryCostGroups(ctx, period, "RECORD_TYPE", nil)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "AWS spend month-to-date (%s to %s):\n", period.Start, today.Format(time.DateOnly))
+
+	var gross float64
+	for _, svc := range services {
+		gross += svc.Amount
+		if svc.Amount >= 0.01 {
+			fmt.Fprintf(&b, "- %s: $%.2f\n", svc.Key, svc.Amount)
+		}
+	}
+
+	var credits, net float64
+	for _, rt := range recordTypes {
+		net += rt.Amount
+		if rt.Key == "Credit" {
+			credits += rt.Amount
+		}
+	}
+
+	fmt.Fprintf(&b, "\nGross usage: $%.2f\n", gross)
+	fmt.Fprintf(&b, "Credits applied: $%.2f\n", credits)
+	fmt.Fprintf(&b, "Net month-to-date: $%.2f\n", net)
+
+	var forecast getCostForecastResponse
+	err = sharedAWSClient.callJSON(ctx, "ce", costExplorerRegion, costExplorerEndpoint,
+		"AWSInsightsIndexService.GetCostForecast",
+		map[string]any{
+			"TimePeriod":  costTimePeriod{Start: today.Format(time.DateOnly), End: nextMonth.Format(time.DateOnly)},
+			"Metric":      "UNBLENDED_COST",
+			"Granularity": "MONTHLY",
+		}, &forecast)
+	if err != nil {
+		// Forecasts are unavailable for new accounts without enough history
+		fmt.Fprintf(&b, "Forecast for remainder of month: unavailable (%v)\n", err)
+		writeBudget(&b, budget, net, nil)
+	} else {
+		remaining, _ := strconv.ParseFloat(forecast.Total.Amount, 64)
+		projected := net + remaining
+		fmt.Fprintf(&b, "Forecast for remainder of month: $%.2f\n", remaining)
+		fmt.Fprintf(&b, "Projected month-end total: $%.2f\n", projected)
+		writeBudget(&b, budget, net, &projected)
+	}
+
+	return b.String(), nil
+}
+
+// monthlyBudget is the monthly AWS budget in dollars from AWS_MONTHLY_BUDGET, or 0 when unset
+func monthlyBudget() (float64, error) {
+	raw := os.Getenv("AWS_MONTHLY_BUDGET")
+	if raw == "" {
+		return 0, nil
+	}
+	budget, err := strconv.ParseFloat(strings.TrimPrefix(raw, "$"), 64)
+	if err != nil || budget <= 0 {
+		return 0, fmt.Errorf("invalid AWS_MONTHLY_BUDGET %q, expected a dollar amount", raw)
+	}
+	return budget, nil
+}
+
+// writeBudget reports net spend against a monthly budget, and whether the projected month-end
+// total, when known, stays within it; nothing is written without a budget
+func writeBudget(b *strings.Builder, budget, net float64, projected *float64) {
+	if budget <= 0 {
+		return
+	}
+	fmt.Fprintf(b, "\nMonthly budget: $%.2f\n", budget)
+	fmt.Fprintf(b, "Remaining budget: $%.2f (%.0f%% used)\n", budget-net, net/budget*100)
+	if projected == nil {
+		return
+	}
+	if over := *projected - budget; over > 0 {
+		fmt.Fprintf(b, "Projected to exceed the budget by $%.2f\n", over)
+	} else {
+		fmt.Fprintf(b, "Projected to stay within the budget, with $%.2f to spare\n", -over)
+	}
+}
+
+// queryCostGroups returns month-to-date unblended cost grouped by a Cost Explorer dimension, largest first
+func queryCostGroups(ctx context.Context, period costTimePeriod, dimension string, recordTypes []string) ([]costGroup, error) {
+	input := map[string]any{
+		"TimePeriod":  period,
+		"Granularity": "MONTHLY",
+		"Metrics":     []string{"UnblendedCost"},
+		"GroupBy":     []map[string]string{{"Type": "DIMENSION", "Key": dimension}},
+	}
+	if len(recordTypes) > 0 {
+		input["Filter"] = map[string]any{
+			"Dimensions": map[string]any{"Key": "RECORD_TYPE", "Values": recordTypes},
+		}
+	}
+
+	var output getCostAndUsageResponse
+	if err := sharedAWSClient.callJSON(ctx, "ce", costExplorerRegion, costExplorerEndpoint,
+		"AWSInsightsIndexService.GetCostAndUsage", input, &output); err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64)
+	for _, result := range output.ResultsByTime {
+		for _, group := range result.Groups {
+			if len(group.Keys) == 0 {
+				continue
+			}
+			amount, err := strconv.ParseFloat(group.Metrics["UnblendedCost"].Amount, 64)
+			if err != nil {
+				continue
+			}
+			totals[group.Keys[0]] += amount
+		}
+	}
+
+	costs := make([]costGroup, 0, len(totals))
+	for name, amount := range totals {
+		costs = append(costs, costGroup{Key: name, Amount: amount})
+	}
+	sort.Slice(costs, func(i, j int) bool {
+		return costs[i].Amount > costs[j].Amount
+	})
+
+	return costs, nil
+}
+
+// Profile implements ProfiledTool; Cost Explorer bills every request
+func (tool *GetAWSCosts) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostMetered, External: true}
+}
+
+func (tool *GetAWSCosts) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &GetAWSCosts{
+		Name:        "get-aws-costs",
+		Description: "Reports month-to-date AWS spend by service, credits applied, the month-end forecast, and the remaining monthly budget (requires the mcp:admin scope).",
+	})
+}
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	// Embedded so any IANA time zone works where the system has no zone database
+	_ "time/tzdata"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetCityTime struct {
+	Name        string
+	Description string
+}
+
+// GetTimeParams defines the parameters for the cityTime tool.
+type GetCityTimeParams struct {
+	City   string `json:"city" jsonschema:"City name (e.g. Tokyo), abbreviation (nyc, sf, la), or IANA time zone (e.g. Europe/Paris); defaults to nyc"`
+	Format string `json:"format,omitempty" jsonschema:"Time format: 12h, 24h, rfc3339 (the default), or unix"`
+}
+
+// timeFormats are the formats get-city-time can reply in
+var timeFormats = []string{"12h", "24h", "rfc3339", "unix"}
+
+// getTime implements the tool that returns the current time for a given city.
+func (tool *GetCityTime) Action(ctx context.Context, req *mcp.CallToolRequest, params *GetCityTimeParams) (*mcp.CallToolResult, any, error) {
+	city := params.City
+	if city == "" {
+		city = "nyc" // Default to NYC
+	}
+
+	place, loc, err := resolveCity(city)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get current time in that location.
+	now := toolClock.Now().In(loc)
+
+	formatted, err := formatTime(now, params.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response := fmt.Sprintf("The current time in %s is %s", place.Name, formatted)
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: response},
+		},
+	}, nil, nil
+}
+
+// formatTime writes t in one of timeFormats
+func formatTime(t time.Time, format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "", "rfc3339":
+		return t.Format(time.RFC3339), nil
+	case "12h":
+		return t.Format("Monday, January 2, 2006 3:04 PM MST"), nil
+	case "24h":
+		return t.Format("Monday, January 2, 2006 15:04 MST"), nil
+	case "unix":
+		return strconv.FormatInt(t.Unix(), 10), nil
+	default:
+		return "", fmt.Errorf("unknown format %q: use one of %s", format, strings.Join(timeFormats, ", "))
+	}
+}
+
+// Profile implements ProfiledTool; time zones are embedded, so no upstream call is made
+func (tool *GetCityTime) Profile() Profile {
+	return Profile{Latency: LatencyFast, Cost: CostFree}
+}
+
+func (tool *GetCityTime) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &GetCityTime{
+		Name:        "get-city-time",
+		Description: "Get the current time in a city or IANA time zone, as 12h, 24h, RFC 3339, or Unix time",
+	})
+}
@@ -0,0 +1,372 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/health"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/release"
+)
+
+// maxDeploymentEvents is how many recent ECS service events and CloudFormation stack events are reported
+const maxDeploymentEvents = 5
+
+// maxDescribeServices is how many services ECS DescribeServices accepts per call
+const maxDescribeServices = 10
+
+// healthReport reads the answering instance's subsystem health; nil leaves it out of the report
+var healthReport func(ctx context.Context) health.Report
+
+// SetHealthReport sets where get-deployment-status reads the answering instance's health from,
+// the same report /health serves
+func SetHealthReport(report func(ctx context.Context) health.Report) {
+	healthReport = report
+}
+
+type GetDeploymentStatus struct {
+	Name        string
+	Description string
+}
+
+// GetDeploymentStatusParams defines the parameters for the get-deployment-status tool.
+type GetDeploymentStatusParams struct {
+	Service string `json:"service,omitempty" jsonschema:"Optional configured ECS service name to limit the report to (defaults to all configured services)"`
+}
+
+type describeServicesResponse struct {
+	Services []struct {
+		ServiceName    string `json:"serviceName"`
+		Status         string `json:"status"`
+		DesiredCount   int    `json:"desiredCount"`
+		RunningCount   int    `json:"runningCount"`
+		PendingCount   int    `json:"pendingCount"`
+		TaskDefinition string `json:"taskDefinition"`
+		Deployments    []struct {
+			Status         string  `json:"status"`
+			RolloutState   string  `json:"rolloutState"`
+			TaskDefinition string  `json:"taskDefinition"`
+			DesiredCount   int     `json:"desiredCount"`
+			RunningCount   int     `json:"runningCount"`
+			FailedTasks    int     `json:"failedTasks"`
+			UpdatedAt      float64 `json:"updatedAt"`
+		} `json:"deployments"`
+		Events []struct {
+			CreatedAt float64 `json:"createdAt"`
+			Message   string  `json:"message"`
+		} `json:"events"`
+	} `json:"services"`
+	Failures []struct {
+		Arn    string `json:"arn"`
+		Reason string `json:"reason"`
+	} `json:"failures"`
+}
+
+type describeStackEventsResponse struct {
+	StackEvents []struct {
+		Timestamp            time.Time `xml:"Timestamp"`
+		LogicalResourceID    string    `xml:"LogicalResourceId"`
+		ResourceType         string    `xml:"ResourceType"`
+		ResourceStatus       string    `xml:"ResourceStatus"`
+		ResourceStatusReason string    `xml:"ResourceStatusReason"`
+	} `xml:"DescribeStackEventsResult>StackEvents>member"`
+}
+
+// RequiredScopes implements ScopedTool
+func (tool *GetDeploymentStatus) RequiredScopes() []string {
+	return []string{adminScope}
+}
+
+func (tool *GetDeploymentStatus) Action(ctx context.Context, req *mcp.CallToolRequest, params *GetDeploymentStatusParams) (*mcp.CallToolResult, any, error) {
+	if err := requireScopes(req, tool.RequiredScopes()); err != nil {
+		return nil, nil, err
+	}
+
+	cluster := os.Getenv("ECS_CLUSTER_NAME")
+	services := splitList(os.Getenv("ECS_SERVICE_NAME"))
+	stacks := splitList(os.Getenv("CLOUDFORMATION_STACK_NAMES"))
+
+	if sandboxMode.Load() {
+		if cluster == "" && len(stacks) == 0 {
+			cluster = sandboxCluster
+		}
+		if cluster == "" {
+			services = nil
+		} else if len(services) == 0 {
+			services = []string{sandboxService}
+		}
+	}
+
+	// Only configured services may be described, regardless of what the task role allows
+	if params.Service != "" {
+		if len(services) == 0 {
+			return nil, nil, fmt.Errorf("no ECS services configured (set ECS_SERVICE_NAME)")
+		}
+		if !slices.Contains(services, params.Service) {
+			return nil, nil, fmt.Errorf("unknown ECS service: %s (available: %s)", params.Service, strings.Join(services, ", "))
+		}
+		services = []string{params.Service}
+	}
+
+	if sandboxMode.Load() {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: sandboxDeploymentStatus(ctx, toolClock.Now().UTC(), cluster, services, stacks)},
+			},
+		}, nil, nil
+	}
+
+	if cluster == "" && len(stacks) == 0 {
+		return nil, nil, fmt.Errorf("no deployments configured (set ECS_CLUSTER_NAME/ECS_SERVICE_NAME or CLOUDFORMATION_STACK_NAMES)")
+	}
+
+	region, err := sharedAWSClient.region(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b strings.Builder
+
+	found := 0
+	if cluster != "" && len(services) > 0 {
+		if found, err = writeECSStatus(ctx, &b, region, cluster, services); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Say so rather than answer with only the instance footer
+	if cluster != "" && found == 0 && len(stacks) == 0 {
+		fmt.Fprintf(&b, "No services/stacks found for cluster %s", cluster)
+		if len(services) == 0 {
+			b.WriteString(" (set ECS_SERVICE_NAME or CLOUDFORMATION_STACK_NAMES)")
+		}
+		b.WriteString("\n\n")
+	}
+
+	for _, stack := range stacks {
+		if err := writeStackEvents(ctx, &b, region, stack); err != nil {
+			// One missing stack should not hide the status of everything else
+			fmt.Fprintf(&b, "CloudFormation stack %s: unavailable (%v)\n\n", stack, err)
+		}
+	}
+
+	writeServedBy(&b, instance.Current())
+	writeHealth(ctx, &b)
+	writeGitHubBudget(&b, githubBudget)
+	writeVersion(&b, release.Default.Status())
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: strings.TrimSpace(b.String())},
+		},
+	}, nil, nil
+}
+
+// writeECSStatus describes the ECS services, maxDescribeServices at a time, and writes a health
+// summary for each; it returns how many of them ECS found
+func writeECSStatus(ctx context.Context, b *strings.Builder, region, cluster string, services []string) (int, error) {
+	var output describeServicesResponse
+	for batch := range slices.Chunk(services, maxDescribeServices) {
+		var page describeServicesResponse
+		err := sharedAWSClient.callJSON(ctx, "ecs", region,
+			fmt.Sprintf("https://ecs.%s.amazonaws.com/", region),
+			"AmazonEC2ContainerServiceV20141113.DescribeServices",
+			map[string]any{"cluster": cluster, "services": batch},
+			&page)
+		if err != nil {
+			return 0, err
+		}
+		output.Services = append(output.Services, page.Services...)
+		output.Failures = append(output.Failures, page.Failures...)
+	}
+
+	for _, svc := range output.Services {
+		healthy := svc.Status == "ACTIVE" && svc.RunningCount == svc.DesiredCount && len(svc.Deployments) == 1
+		verdict := "HEALTHY"
+		if !healthy {
+			verdict = "DEGRADED"
+			if len(svc.Deployments) > 1 {
+				verdict = "DEPLOYING"
+			}
+		}
+
+		fmt.Fprintf(b, "ECS service %s (cluster %s): %s\n", svc.ServiceName, cluster, verdict)
+		fmt.Fprintf(b, "- Tasks: %d running / %d desired / %d pending\n", svc.RunningCount, svc.DesiredCount, svc.PendingCount)
+		fmt.Fprintf(b, "- Task definition: %s\n", shortARN(svc.TaskDefinition))
+
+		for _, d := range svc.Deployments {
+			fmt.Fprintf(b, "- Deployment %s (%s): %s, %d/%d running, %d failed tasks, updated %s\n",
+				d.Status, d.RolloutState, shortARN(d.TaskDefinition), d.RunningCount, d.DesiredCount, d.FailedTasks,
+				epochToTime(d.UpdatedAt).Format(time.RFC3339))
+		}
+
+		for i, event := range svc.Events {
+			if i >= maxDeploymentEvents {
+				break
+			}
+			fmt.Fprintf(b, "  [%s] %s\n", epochToTime(event.CreatedAt).Format(time.RFC3339), event.Message)
+		}
+		b.WriteString("\n")
+	}
+
+	for _, failure := range output.Failures {
+		fmt.Fprintf(b, "ECS service %s: %s\n\n", shortARN(failure.Arn), failure.Reason)
+	}
+
+	return len(output.Services), nil
+}
+
+// writeStackEvents writes the most recent CloudFormation events for a stack
+func writeStackEvents(ctx context.Context, b *strings.Builder, region, stack string) error {
+	params := url.Values{}
+	params.Set("Action", "DescribeStackEvents")
+	params.Set("Version", "2010-05-15")
+	params.Set("StackName", stack)
+
+	var output describeStackEventsResponse
+	err := sharedAWSClient.callQuery(ctx, "cloudformation", region,
+		fmt.Sprintf("https://cloudformation.%s.amazonaws.com/", region), params, &output)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(b, "CloudFormation stack %s recent events:\n", stack)
+	for i, event := range output.StackEvents {
+		if i >= maxDeploymentEvents {
+			break
+		}
+		fmt.Fprintf(b, "  [%s] %s %s (%s)", event.Timestamp.Format(time.RFC3339),
+			event.LogicalResourceID, event.ResourceStatus, event.ResourceType)
+		if event.ResourceStatusReason != "" {
+			fmt.Fprintf(b, ": %s", event.ResourceStatusReason)
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	return nil
+}
+
+// writeServedBy reports which instance answered, so reports from multi-region deployments can be told apart
+func writeServedBy(b *strings.Builder, m instance.Metadata) {
+	if m.String() == "" {
+		return
+	}
+	fmt.Fprintf(b, "Served by %s", m)
+	if m.Cluster != "" {
+		fmt.Fprintf(b, " (cluster %s)", m.Cluster)
+	}
+	b.WriteString("\n")
+}
+
+// writeHealth reports the answering instance's overall health and the checks that aren't ok
+func writeHealth(ctx context.Context, b *strings.Builder) {
+	if healthReport == nil {
+		return
+	}
+	report := healthReport(ctx)
+	fmt.Fprintf(b, "Instance health: %s", strings.ToUpper(string(report.Status)))
+
+	var problems []string
+	for name, result := range report.Checks {
+		if result.Status == health.StatusOK {
+			continue
+		}
+		problem := name + " " + string(result.Status)
+		if result.Detail != "" {
+			problem += ": " + result.Detail
+		}
+		problems = append(problems, problem)
+	}
+	sort.Strings(problems)
+	if len(problems) > 0 {
+		fmt.Fprintf(b, " (%s)", strings.Join(problems, "; "))
+	}
+	b.WriteString("\n")
+}
+
+// writeGitHubBudget reports the GitHub API rate limit left for the token closest to running out
+func writeGitHubBudget(b *strings.Builder, budget *auth.GitHubBudget) {
+	statuses := budget.Snapshot()
+	if len(statuses) == 0 {
+		fmt.Fprintf(b, "GitHub API budget: no rate limits seen yet (%d calls per token reserved for logins)\n", budget.Reserve())
+		return
+	}
+	lowest := statuses[0]
+	fmt.Fprintf(b, "GitHub API budget: %d/%d calls left for the lowest of %d token(s) until %s (%d reserved for logins)",
+		lowest.Remaining, lowest.Limit, len(statuses), lowest.Reset.UTC().Format(time.RFC3339), budget.Reserve())
+	if lowest.Remaining <= budget.Reserve() {
+		b.WriteString(", LOW")
+	}
+	b.WriteString("\n")
+}
+
+// writeVersion reports the running version and, once checked, whether a newer release exists
+func writeVersion(b *strings.Builder, status release.Status) {
+	fmt.Fprintf(b, "Version %s", status.Current)
+	switch {
+	case status.UpdateAvailable:
+		fmt.Fprintf(b, " (update available: %s, %s)", status.Latest, status.URL)
+	case status.Latest != "":
+		fmt.Fprintf(b, " (latest release: %s)", status.Latest)
+	}
+	b.WriteString("\n")
+}
+
+// splitList splits a comma-separated environment value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
+
+// shortARN trims an ARN down to its resource name, e.g. "task-definition/app:42" becomes "app:42"
+func shortARN(arn string) string {
+	if idx := strings.LastIndex(arn, "/"); idx != -1 {
+		return arn[idx+1:]
+	}
+	return arn
+}
+
+// epochToTime converts the fractional epoch seconds used by AWS JSON APIs to a time.Time
+func epochToTime(seconds float64) time.Time {
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC()
+}
+
+// Profile implements ProfiledTool
+func (tool *GetDeploymentStatus) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostFree, External: true}
+}
+
+func (tool *GetDeploymentStatus) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &GetDeploymentStatus{
+		Name:        "get-deployment-status",
+		Description: "Reports ECS service/task health and recent CloudFormation stack events for the configured deployment (requires the mcp:admin scope).",
+	})
+}
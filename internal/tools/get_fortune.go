@@ -6,12 +6,18 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
 )
 
-type GetFortune struct{
-	Name string
+// fortuneBreaker stops calls to the fortune API while it is failing
+var fortuneBreaker = breaker.New("fortune", 5, 30*time.Second)
+
+type GetFortune struct {
+	Name        string
 	Description string
 }
 
@@ -25,10 +31,33 @@ type FortuneAPIResponse struct {
 }
 
 func (tool *GetFortune) Action(ctx context.Context, req *mcp.CallToolRequest, params *struct{}) (*mcp.CallToolResult, any, error) {
-	res, err := http.Get("https://aphorismcookie.herokuapp.com/")
+	if sandboxMode.Load() {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: sandboxFortune},
+			},
+		}, nil, nil
+	}
+
+	fortuneReq, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://aphorismcookie.herokuapp.com/", nil)
 	if err != nil {
+		return nil, nil, fmt.Errorf("creating fortune API request failed: %w", err)
+	}
+
+	if err := fortuneBreaker.Allow(); err != nil {
+		return nil, nil, err
+	}
+
+	res, err := httpClient.Do(fortuneReq)
+	if err != nil {
+		fortuneBreaker.Failure(err)
 		return nil, nil, fmt.Errorf("connecting to fortune API failed!: %s", err)
 	}
+	if res.StatusCode >= http.StatusInternalServerError {
+		fortuneBreaker.Failure(fmt.Errorf("fortune API returned status %d", res.StatusCode))
+	} else {
+		fortuneBreaker.Success()
+	}
 
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
@@ -53,10 +82,17 @@ func (tool *GetFortune) Action(ctx context.Context, req *mcp.CallToolRequest, pa
 	}, nil, nil
 }
 
+// Profile implements ProfiledTool
+func (tool *GetFortune) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostFree, External: true}
+}
+
 func (tool *GetFortune) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
 	mcpToolInstance = &mcp.Tool{
-		Name: tool.Name,
+		Name:        tool.Name,
 		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
 	}
 
 	mcp.AddTool(server, mcpToolInstance, tool.Action)
@@ -66,7 +102,7 @@ func (tool *GetFortune) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool)
 
 func init() {
 	tools = append(tools, &GetFortune{
-		Name: "get-fortune",
+		Name:        "get-fortune",
 		Description: "Gets a random fortune from aphorismcookie.herokuapp.com",
 	})
 }
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type GetRepoIssues struct {
+	Name        string
+	Description string
+}
+
+// GetRepoIssuesParams defines the parameters for the get-repo-issues tool
+type GetRepoIssuesParams struct {
+	Repo   string `json:"repo" jsonschema:"Repository as owner/name (e.g. octocat/hello-world)"`
+	State  string `json:"state,omitempty" jsonschema:"open (default), closed, or all"`
+	Labels string `json:"labels,omitempty" jsonschema:"Comma-separated labels every issue must have (e.g. bug,ui)"`
+	Limit  int    `json:"limit,omitempty" jsonschema:"How many issues to list (default 30, at most 100)"`
+}
+
+// githubIssue is the part of GitHub's issue response the GitHub tools report
+type githubIssue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	URL    string `json:"html_url"`
+	User   struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Comments  int       `json:"comments"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// PullRequest is set when the issue is a pull request, which the issues API also returns
+	PullRequest *struct{} `json:"pull_request"`
+}
+
+func (tool *GetRepoIssues) Action(ctx context.Context, req *mcp.CallToolRequest, params *GetRepoIssuesParams) (*mcp.CallToolResult, any, error) {
+	token, err := callerGitHubToken(req, githubRepoScope)
+	if err != nil {
+		return nil, nil, err
+	}
+	repoPath, err := githubRepo(params.Repo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := url.Values{}
+	query.Set("state", strings.ToLower(strings.TrimSpace(params.State)))
+	if query.Get("state") == "" {
+		query.Set("state", "open")
+	}
+	switch query.Get("state") {
+	case "open", "closed", "all":
+	default:
+		return nil, nil, fmt.Errorf("invalid state %q: use open, closed, or all", params.State)
+	}
+	if labels := strings.Join(splitList(params.Labels), ","); labels != "" {
+		query.Set("labels", labels)
+	}
+	query.Set("per_page", strconv.Itoa(githubResultLimit(params.Limit)))
+
+	var issues []githubIssue
+	if err := githubCall(ctx, token, http.MethodGet, repoPath+"/issues", query, nil, &issues); err != nil {
+		return nil, nil, err
+	}
+
+	var b strings.Builder
+	var count int
+	for _, issue := range issues {
+		if issue.PullRequest != nil {
+			continue
+		}
+		count++
+		fmt.Fprintf(&b, "- #%d %s (%s, by %s, %d comments, updated %s)", issue.Number, issue.Title, issue.State, issue.User.Login, issue.Comments, issue.UpdatedAt.Format(time.DateOnly))
+		if len(issue.Labels) > 0 {
+			names := make([]string, 0, len(issue.Labels))
+			for _, label := range issue.Labels {
+				names = append(names, label.Name)
+			}
+			fmt.Fprintf(&b, " [%s]", strings.Join(names, ", "))
+		}
+		fmt.Fprintf(&b, "\n  %s\n", issue.URL)
+	}
+
+	response := fmt.Sprintf("%d %s issues in %s:\n%s", count, query.Get("state"), strings.TrimSpace(params.Repo), b.String())
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: strings.TrimSpace(response)},
+		},
+	}, nil, nil
+}
+
+// ActsAsGitHubUser implements GitHubUserTool
+func (tool *GetRepoIssues) ActsAsGitHubUser() {}
+
+// Profile implements ProfiledTool; results are the caller's own view of GitHub
+func (tool *GetRepoIssues) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostFree, External: true, PerCaller: true}
+}
+
+func (tool *GetRepoIssues) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &GetRepoIssues{
+		Name:        "get-repo-issues",
+		Description: "List the issues of a GitHub repository (pull requests are left out), as the GitHub user you signed in as",
+	})
+}
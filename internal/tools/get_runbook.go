@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/runbooks"
+)
+
+type GetRunbook struct {
+	Name        string
+	Description string
+}
+
+// GetRunbookParams defines the parameters for the get-runbook tool.
+type GetRunbookParams struct {
+	Name string `json:"name,omitempty" jsonschema:"Runbook to return (omit to list the available runbooks)"`
+}
+
+func (tool *GetRunbook) Action(ctx context.Context, req *mcp.CallToolRequest, params *GetRunbookParams) (*mcp.CallToolResult, any, error) {
+	library := runbooks.FromEnv()
+
+	var text string
+	if params.Name == "" {
+		available, err := library.List()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%d runbooks available:\n", len(available))
+		for _, runbook := range available {
+			fmt.Fprintf(&b, "- %s: %s\n", runbook.Name, runbook.Title)
+		}
+		text = b.String()
+	} else {
+		runbook, err := library.Get(params.Name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w (call without a name to list runbooks)", err)
+		}
+		text = runbook.Content
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: text},
+		},
+	}, nil, nil
+}
+
+// Profile implements ProfiledTool
+func (tool *GetRunbook) Profile() Profile {
+	return Profile{Latency: LatencyFast, Cost: CostFree}
+}
+
+func (tool *GetRunbook) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &GetRunbook{
+		Name:        "get-runbook",
+		Description: "Returns an operational runbook (Markdown) by name, or lists the available runbooks when no name is given.",
+	})
+}
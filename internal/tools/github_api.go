@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+)
+
+// githubRepoScope is the GitHub scope the repository tools need, requested at login when
+// GITHUB_REPO_ACCESS is set
+const githubRepoScope = "repo"
+
+// maxGitHubResults bounds how many repositories or issues a tool lists
+const maxGitHubResults = 100
+
+// githubToolsBreaker stops the GitHub tools from calling GitHub while it is failing; token
+// verification has its own breaker
+var githubToolsBreaker = breaker.New("github-tools", 5, 30*time.Second)
+
+// githubBudget is the GitHub API rate limit budget the GitHub tools spend
+var githubBudget = auth.NewGitHubBudget(0)
+
+// SetGitHubBudget makes the GitHub tools spend budget, normally the token verifier's, so the
+// calls they make with a user's token count against the same rate limit as verifying it
+func SetGitHubBudget(budget *auth.GitHubBudget) {
+	githubBudget = budget
+}
+
+// GitHubUserTool is implemented by tools that call GitHub with the caller's own GitHub token
+type GitHubUserTool interface {
+	ActsAsGitHubUser()
+}
+
+// jwtAccessTokens is set when the server issues JWT access tokens, which carry no GitHub token,
+// so the GitHub user tools are hidden and refuse calls
+var jwtAccessTokens atomic.Bool
+
+// SetJWTAccessTokens tells the GitHub user tools whether the server issues JWT access tokens
+// (TOKEN_FORMAT=jwt); it must be set before tools are registered to hide them
+func SetJWTAccessTokens(enabled bool) {
+	jwtAccessTokens.Store(enabled)
+}
+
+// githubRepoName is what an owner/name repository looks like
+var githubRepoName = regexp.MustCompile(`^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`)
+
+// githubAPIURL is the GitHub API the GitHub tools call
+var (
+	githubAPIURLMu sync.RWMutex
+	githubAPIURL   = "https://api.github.com"
+)
+
+// SetGitHubAPIURL sets the GitHub API the GitHub tools call, e.g. for GitHub Enterprise or the sandbox
+func SetGitHubAPIURL(apiURL string) {
+	githubAPIURLMu.Lock()
+	defer githubAPIURLMu.Unlock()
+	githubAPIURL = strings.TrimSuffix(apiURL, "/")
+}
+
+// callerGitHubToken returns the GitHub token the caller signed in with, as long as GitHub
+// granted it scope
+// Only opaque access tokens carry one; JWT and client_credentials tokens act without GitHub
+func callerGitHubToken(req *mcp.CallToolRequest, scope string) (string, error) {
+	if jwtAccessTokens.Load() {
+		return "", fmt.Errorf("this tool acts on GitHub as you, but this server issues JWT access tokens, which carry no GitHub token; it is only available with TOKEN_FORMAT=opaque")
+	}
+	if req == nil || req.Extra == nil || req.Extra.TokenInfo == nil {
+		return "", fmt.Errorf("this tool acts on GitHub as you, so it needs an access token from signing in with GitHub")
+	}
+	token, _ := req.Extra.TokenInfo.Extra["github_token"].(string)
+	if token == "" {
+		return "", fmt.Errorf("this tool acts on GitHub as you, but your access token doesn't carry a GitHub token; sign in with GitHub and an opaque token")
+	}
+	scopes, _ := req.Extra.TokenInfo.Extra["github_scopes"].([]string)
+	if !slices.Contains(scopes, scope) {
+		return "", fmt.Errorf("this tool requires the GitHub %s scope; sign in again once the server requests it (GITHUB_REPO_ACCESS)", scope)
+	}
+	return token, nil
+}
+
+// githubRepo checks repo is an owner/name repository and returns it as an API path
+func githubRepo(repo string) (string, error) {
+	repo = strings.TrimSpace(repo)
+	if !githubRepoName.MatchString(repo) {
+		return "", fmt.Errorf("invalid repository %q: use owner/name, e.g. octocat/hello-world", repo)
+	}
+	owner, name, _ := strings.Cut(repo, "/")
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(name), nil
+}
+
+// githubResultLimit applies the default of 30 and the maximum to a requested number of results
+func githubResultLimit(limit int) int {
+	if limit <= 0 {
+		return 30
+	}
+	return min(limit, maxGitHubResults)
+}
+
+// githubCall calls the GitHub API as the user owning token, encoding body as JSON when set and
+// decoding the response into out
+func githubCall(ctx context.Context, token, method, path string, query url.Values, body, out any) error {
+	githubAPIURLMu.RLock()
+	endpoint := githubAPIURL + path
+	githubAPIURLMu.RUnlock()
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding GitHub API request failed: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	// Tool calls are non-critical, so a user's last calls before the reserve go to keeping them signed in
+	req, err := http.NewRequestWithContext(auth.WithNonCriticalGitHubCalls(ctx), method, endpoint, reader)
+	if err != nil {
+		return fmt.Errorf("creating GitHub API request failed: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if err := githubToolsBreaker.Allow(); err != nil {
+		return err
+	}
+	res, err := githubBudget.Client(httpClient).Do(req)
+	if errors.Is(err, auth.ErrGitHubBudgetExhausted) {
+		githubToolsBreaker.Cancel()
+		return fmt.Errorf("GitHub API call refused to keep you signed in: %w", err)
+	}
+	if err != nil {
+		githubToolsBreaker.Failure(err)
+		return fmt.Errorf("connecting to GitHub API failed: %w", err)
+	}
+	defer func() { _ = res.Body.Close() }()
+	// Missing repositories and denied access are the caller's problem, not GitHub failing
+	if res.StatusCode >= http.StatusInternalServerError {
+		githubToolsBreaker.Failure(fmt.Errorf("GitHub API returned status %d", res.StatusCode))
+	} else {
+		githubToolsBreaker.Success()
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		var apiError struct {
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(res.Body, 4096)).Decode(&apiError)
+		if apiError.Message == "" {
+			apiError.Message = http.StatusText(res.StatusCode)
+		}
+		return fmt.Errorf("GitHub API returned status %d: %s", res.StatusCode, apiError.Message)
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding GitHub API response failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package tools
+
+import (
+	"net/http"
+	"time"
+)
+
+// httpClient is the shared client used by tools that call external APIs
+// Its transport forwards the calling client's tracing headers
+var httpClient = &http.Client{
+	Timeout:   10 * time.Second,
+	Transport: &tracingTransport{},
+}
+
+// SetHTTPClient replaces the client tools use for outbound HTTP requests and returns the client
+// it replaced, so tests recording and replaying upstream API responses can put it back
+func SetHTTPClient(client *http.Client) *http.Client {
+	previous := httpClient
+	if _, traced := client.Transport.(*tracingTransport); traced {
+		// A client returned by an earlier call already forwards tracing headers
+		httpClient = client
+		return previous
+	}
+	traced := *client
+	traced.Transport = &tracingTransport{base: client.Transport}
+	httpClient = &traced
+	return previous
+}
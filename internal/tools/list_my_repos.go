@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type ListMyRepos struct {
+	Name        string
+	Description string
+}
+
+// ListMyReposParams defines the parameters for the list-my-repos tool
+type ListMyReposParams struct {
+	Visibility string `json:"visibility,omitempty" jsonschema:"all (default), public, or private"`
+	Sort       string `json:"sort,omitempty" jsonschema:"updated (default), pushed, created, or full_name"`
+	Limit      int    `json:"limit,omitempty" jsonschema:"How many repositories to list (default 30, at most 100)"`
+}
+
+// githubRepository is the part of GitHub's repository response list-my-repos reports
+type githubRepository struct {
+	FullName    string    `json:"full_name"`
+	Description string    `json:"description"`
+	Private     bool      `json:"private"`
+	Stars       int       `json:"stargazers_count"`
+	OpenIssues  int       `json:"open_issues_count"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (tool *ListMyRepos) Action(ctx context.Context, req *mcp.CallToolRequest, params *ListMyReposParams) (*mcp.CallToolResult, any, error) {
+	token, err := callerGitHubToken(req, githubRepoScope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := url.Values{}
+	query.Set("visibility", strings.ToLower(strings.TrimSpace(params.Visibility)))
+	if query.Get("visibility") == "" {
+		query.Set("visibility", "all")
+	}
+	switch query.Get("visibility") {
+	case "all", "public", "private":
+	default:
+		return nil, nil, fmt.Errorf("invalid visibility %q: use all, public, or private", params.Visibility)
+	}
+	query.Set("sort", strings.ToLower(strings.TrimSpace(params.Sort)))
+	if query.Get("sort") == "" {
+		query.Set("sort", "updated")
+	}
+	switch query.Get("sort") {
+	case "updated", "pushed", "created", "full_name":
+	default:
+		return nil, nil, fmt.Errorf("invalid sort %q: use updated, pushed, created, or full_name", params.Sort)
+	}
+	query.Set("per_page", strconv.Itoa(githubResultLimit(params.Limit)))
+
+	var repos []githubRepository
+	if err := githubCall(ctx, token, http.MethodGet, "/user/repos", query, nil, &repos); err != nil {
+		return nil, nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d repositories:\n", len(repos))
+	for _, repo := range repos {
+		visibility := "public"
+		if repo.Private {
+			visibility = "private"
+		}
+		fmt.Fprintf(&b, "- %s (%s, %d stars, %d open issues, updated %s)", repo.FullName, visibility, repo.Stars, repo.OpenIssues, repo.UpdatedAt.Format(time.DateOnly))
+		if repo.Description != "" {
+			fmt.Fprintf(&b, ": %s", repo.Description)
+		}
+		b.WriteString("\n")
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: strings.TrimSpace(b.String())},
+		},
+	}, nil, nil
+}
+
+// ActsAsGitHubUser implements GitHubUserTool
+func (tool *ListMyRepos) ActsAsGitHubUser() {}
+
+// Profile implements ProfiledTool; results are the caller's own view of GitHub
+func (tool *ListMyRepos) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostFree, External: true, PerCaller: true}
+}
+
+func (tool *ListMyRepos) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &ListMyRepos{
+		Name:        "list-my-repos",
+		Description: "List the GitHub repositories you can access, as the GitHub user you signed in as",
+	})
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// oneTimeSecretTTL is how long an unread one-time secret is kept when no state store is configured
+const oneTimeSecretTTL = 10 * time.Minute
+
+// oneTimeSecretPrefix starts the URI of every one-time secret resource
+const oneTimeSecretPrefix = "secret://credentials/"
+
+// oneTimeSecretKey prefixes the state store key of every one-time secret, so it can't be
+// mistaken for an authorization flow's state
+const oneTimeSecretKey = "secret:"
+
+// oneTimeSecrets hands credentials to a user through a resource that can be read once, so they
+// aren't left in the tool call transcript
+// Secrets live in the auth state store, so with a shared storage backend the read can reach any
+// instance; the store expires them with its state lifetime
+type oneTimeSecrets struct {
+	mu    sync.RWMutex
+	store auth.StateStorage
+	ttl   time.Duration
+}
+
+// credentialSecrets holds credentials issued by register-client
+var credentialSecrets = &oneTimeSecrets{}
+
+// SetSecretStore keeps one-time secrets, such as register-client's credentials, in store, which
+// expires its states after ttl
+func SetSecretStore(store auth.StateStorage, ttl time.Duration) {
+	credentialSecrets.mu.Lock()
+	defer credentialSecrets.mu.Unlock()
+	credentialSecrets.store = store
+	credentialSecrets.ttl = ttl
+}
+
+// storage returns the configured store and its lifetime, falling back to one in memory
+func (s *oneTimeSecrets) storage() (auth.StateStorage, time.Duration) {
+	s.mu.RLock()
+	store, ttl := s.store, s.ttl
+	s.mu.RUnlock()
+	if store != nil {
+		return store, ttl
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.store == nil {
+		s.store, s.ttl = auth.NewStateStore(oneTimeSecretTTL), oneTimeSecretTTL
+	}
+	return s.store, s.ttl
+}
+
+// lifetime is how long a secret put now stays readable
+func (s *oneTimeSecrets) lifetime() time.Duration {
+	_, ttl := s.storage()
+	if ttl <= 0 {
+		return oneTimeSecretTTL
+	}
+	return ttl
+}
+
+// put stores text for owner and returns the URI it can be read from once
+func (s *oneTimeSecrets) put(owner, text string) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	store, _ := s.storage()
+	key := hex.EncodeToString(id)
+	if err := store.Store(oneTimeSecretKey+key, &auth.AuthState{Subject: owner, Secret: text, CreatedAt: toolClock.Now()}); err != nil {
+		return "", err
+	}
+	return oneTimeSecretPrefix + key, nil
+}
+
+// take returns and forgets the secret at uri, if it hasn't expired and user created it
+// Anyone else is told it doesn't exist and doesn't use it up
+func (s *oneTimeSecrets) take(uri, user string) (string, bool) {
+	key, ok := strings.CutPrefix(uri, oneTimeSecretPrefix)
+	if !ok || key == "" {
+		return "", false
+	}
+
+	store, _ := s.storage()
+	secret, ok := store.Get(oneTimeSecretKey + key)
+	if !ok || secret.Secret == "" || secret.Subject != user {
+		return "", false
+	}
+	// Only one of several concurrent readers gets it
+	secret, ok = store.Consume(oneTimeSecretKey + key)
+	if !ok {
+		return "", false
+	}
+	return secret.Secret, true
+}
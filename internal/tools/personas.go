@@ -0,0 +1,71 @@
+package tools
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// personaPathPrefix is where persona servers are mounted, one path segment per persona
+const personaPathPrefix = "/mcp/"
+
+// personaName is what a persona name may look like, as it becomes a URL path segment
+var personaName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Persona is a focused MCP server with its own tool set, served from the same process as the
+// main server so different audiences can be given different servers
+type Persona struct {
+	Name string
+
+	// Tools lists the only tools the persona serves
+	Tools []string
+
+	// Scopes a token needs to connect to the persona; mcp:tools when none are given
+	Scopes []string
+}
+
+// Path is the URL path the persona's MCP endpoint is served at
+func (p Persona) Path() string {
+	return personaPathPrefix + p.Name
+}
+
+// ParsePersonas parses semicolon-separated name=tool,tool entries, each optionally followed by
+// the space-separated scopes it requires in parentheses, e.g.
+// "tools=get-city-time,get-fortune;admin(mcp:tools mcp:admin)=get-aws-costs,tail-logs"
+func ParsePersonas(spec string) ([]Persona, error) {
+	var personas []Persona
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		head, toolList, found := strings.Cut(entry, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid persona %q, expected name=tool,tool", entry)
+		}
+		persona := Persona{Name: strings.TrimSpace(head), Tools: splitList(toolList), Scopes: []string{"mcp:tools"}}
+		if name, scopes, ok := strings.Cut(persona.Name, "("); ok {
+			scopes, closed := strings.CutSuffix(scopes, ")")
+			if !closed || len(strings.Fields(scopes)) == 0 {
+				return nil, fmt.Errorf("invalid persona %q, expected name(scope scope)=tool,tool", entry)
+			}
+			persona.Name = strings.TrimSpace(name)
+			persona.Scopes = strings.Fields(scopes)
+		}
+
+		if !personaName.MatchString(persona.Name) {
+			return nil, fmt.Errorf("invalid persona name %q, use lowercase letters, digits, and dashes", persona.Name)
+		}
+		if seen[persona.Name] {
+			return nil, fmt.Errorf("persona %q is listed more than once", persona.Name)
+		}
+		if len(persona.Tools) == 0 {
+			return nil, fmt.Errorf("persona %q has no tools", persona.Name)
+		}
+		seen[persona.Name] = true
+		personas = append(personas, persona)
+	}
+	return personas, nil
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/policy"
+)
+
+// errPolicyUnavailable is returned when the policy engine can't make a decision
+var errPolicyUnavailable = errors.New("authorization policy unavailable")
+
+// PolicyMiddleware asks engine whether each tools/call may run, after scopes have been checked
+// Calls are denied when the engine fails, so an unreachable policy never grants access; a nil
+// engine allows everything
+func PolicyMiddleware(engine policy.Engine) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		if engine == nil {
+			return next
+		}
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok || call.Params == nil {
+				return next(ctx, method, req)
+			}
+
+			input := policyInput(call)
+			decision, err := engine.Evaluate(ctx, input)
+			if err != nil {
+				log.Printf("[POLICY] Denied %s for %q: %v", input.Tool, input.User, err)
+				return nil, fmt.Errorf("tool %s: %w", input.Tool, errPolicyUnavailable)
+			}
+			if !decision.Allow {
+				log.Printf("[POLICY] Denied %s for %q: %s", input.Tool, input.User, decision.Reason)
+				if decision.Reason != "" {
+					return nil, fmt.Errorf("tool %s denied by policy: %s", input.Tool, decision.Reason)
+				}
+				return nil, fmt.Errorf("tool %s denied by policy", input.Tool)
+			}
+			return next(ctx, method, req)
+		}
+	}
+}
+
+// policyInput describes call for the policy engine
+func policyInput(call *mcp.CallToolRequest) policy.Input {
+	input := policy.Input{
+		Tool:   call.Params.Name,
+		Args:   call.Params.Arguments,
+		Scopes: []string{},
+		User:   callerLogin(call),
+	}
+	if call.Extra != nil && call.Extra.TokenInfo != nil {
+		info := call.Extra.TokenInfo
+		input.Scopes = info.Scopes
+		input.ClientID, _ = info.Extra["client_id"].(string)
+	}
+	return input
+}
@@ -0,0 +1,89 @@
+package tools
+
+import (
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Latency classes tell clients how long a tool call usually takes
+const (
+	// LatencyFast tools answer locally, in well under a second
+	LatencyFast = "fast"
+	// LatencyModerate tools call an upstream API and usually answer within a few seconds
+	LatencyModerate = "moderate"
+	// LatencySlow tools may take ten seconds or more
+	LatencySlow = "slow"
+)
+
+// Cost hints tell clients whether a call costs money beyond the server itself
+const (
+	CostFree = "free"
+	// CostMetered tools call APIs billed per request, such as AWS Cost Explorer
+	CostMetered = "metered"
+)
+
+// Profile describes how expensive a tool is to call, so LLM clients and orchestrators can plan
+// calls; it is sent in the tool's _meta as "latency" and "cost", and its annotations
+type Profile struct {
+	Latency string `json:"latency"`
+	Cost    string `json:"cost"`
+	// External is set for tools that reach systems outside the server
+	External bool `json:"external"`
+	// Writes is set for tools that change state; they only ever add to it
+	Writes bool `json:"writes"`
+	// PerCaller is set for tools whose results depend on who calls them, so they are never
+	// served from another caller's cached result
+	PerCaller bool `json:"per_caller"`
+}
+
+// ProfiledTool is implemented by tools that describe their latency and cost
+type ProfiledTool interface {
+	Profile() Profile
+}
+
+// Meta returns the _meta fields advertising the profile
+func (p Profile) Meta() mcp.Meta {
+	return mcp.Meta{"latency": p.Latency, "cost": p.Cost}
+}
+
+// Annotations returns the standard tool annotations implied by the profile
+func (p Profile) Annotations() *mcp.ToolAnnotations {
+	external := p.External
+	annotations := &mcp.ToolAnnotations{
+		ReadOnlyHint:   !p.Writes,
+		IdempotentHint: !p.Writes,
+		OpenWorldHint:  &external,
+	}
+	if p.Writes {
+		destructive := false
+		annotations.DestructiveHint = &destructive
+	}
+	return annotations
+}
+
+// toolProfiles maps registered tool names to their profiles
+var (
+	toolProfilesMu sync.RWMutex
+	toolProfiles   = make(map[string]Profile)
+)
+
+// ToolProfile returns the profile the registered tool name declares, if any
+func ToolProfile(name string) (Profile, bool) {
+	toolProfilesMu.RLock()
+	defer toolProfilesMu.RUnlock()
+	profile, ok := toolProfiles[name]
+	return profile, ok
+}
+
+// recordProfile remembers the profile declared by tool, registered as name
+func recordProfile(name string, tool MCPRegisterableTool) {
+	profiled, ok := tool.(ProfiledTool)
+	if !ok {
+		return
+	}
+
+	toolProfilesMu.Lock()
+	defer toolProfilesMu.Unlock()
+	toolProfiles[name] = profiled.Profile()
+}
@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// ClientRegistrar registers OAuth clients for register-client; *auth.RegistrationHandler is one
+type ClientRegistrar interface {
+	Register(req auth.ClientRegistrationRequest) (auth.ClientRegistrationResponse, error)
+}
+
+// clientRegistrar is nil while OAuth is disabled, which leaves register-client unavailable
+var clientRegistrar ClientRegistrar
+
+// SetClientRegistrar sets where register-client registers clients
+func SetClientRegistrar(registrar ClientRegistrar) {
+	clientRegistrar = registrar
+}
+
+type RegisterClient struct {
+	Name        string
+	Description string
+}
+
+// RegisterClientParams defines the parameters for the register-client tool.
+type RegisterClientParams struct {
+	ClientName   string   `json:"client_name" jsonschema:"Human-readable name shown to users on the consent page"`
+	RedirectURIs []string `json:"redirect_uris,omitempty" jsonschema:"Redirect URIs for the authorization code grant; not needed for client_credentials or device code clients"`
+	AuthMethod   string   `json:"token_endpoint_auth_method,omitempty" jsonschema:"none for public clients (desktop and browser apps using PKCE), or client_secret_basic or client_secret_post for confidential clients that can keep a secret"`
+	GrantTypes   []string `json:"grant_types,omitempty" jsonschema:"Grant types, e.g. authorization_code (the default), client_credentials, or urn:ietf:params:oauth:grant-type:device_code"`
+	Scope        string   `json:"scope,omitempty" jsonschema:"Space-separated scopes the client may request (defaults to mcp:tools mcp:resources read:user)"`
+}
+
+// RequiredScopes implements ScopedTool
+func (tool *RegisterClient) RequiredScopes() []string {
+	return []string{adminScope}
+}
+
+func (tool *RegisterClient) Action(ctx context.Context, req *mcp.CallToolRequest, params *RegisterClientParams) (*mcp.CallToolResult, any, error) {
+	if err := requireScopes(req, tool.RequiredScopes()); err != nil {
+		return nil, nil, err
+	}
+	if clientRegistrar == nil {
+		return nil, nil, fmt.Errorf("client registration is unavailable because OAuth is disabled")
+	}
+	if strings.TrimSpace(params.ClientName) == "" {
+		return nil, nil, fmt.Errorf("client_name is required")
+	}
+
+	registration, err := clientRegistrar.Register(auth.ClientRegistrationRequest{
+		ClientName:              params.ClientName,
+		RedirectURIs:            params.RedirectURIs,
+		TokenEndpointAuthMethod: params.AuthMethod,
+		GrantTypes:              params.GrantTypes,
+		Scope:                   params.Scope,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("registering %s: %w", params.ClientName, err)
+	}
+
+	// The secrets go in a one-time resource rather than the result, which the client may keep
+	credentials, _ := json.MarshalIndent(map[string]string{
+		"client_id":                 registration.ClientID,
+		"client_secret":             registration.ClientSecret,
+		"registration_access_token": registration.RegistrationAccessToken,
+		"registration_client_uri":   registration.RegistrationClientURI,
+	}, "", "  ")
+	user := activity.User(req)
+	uri, err := credentialSecrets.put(user, string(credentials))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to store the credentials: %w", err)
+	}
+	log.Printf("Admin %s registered client %s (%s)", user, registration.ClientID, params.ClientName)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Registered %s as client %s.\n", registration.ClientName, registration.ClientID)
+	fmt.Fprintf(&b, "- Authentication: %s\n", registration.TokenEndpointAuthMethod)
+	fmt.Fprintf(&b, "- Grant types: %s\n", strings.Join(registration.GrantTypes, ", "))
+	if len(registration.RedirectURIs) > 0 {
+		fmt.Fprintf(&b, "- Redirect URIs: %s\n", strings.Join(registration.RedirectURIs, ", "))
+	}
+	fmt.Fprintf(&b, "- Scope: %s\n", registration.Scope)
+	fmt.Fprintf(&b, "\nThe credentials can be read once from %s within %v; they can't be shown again.\n", uri, credentialSecrets.lifetime())
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: b.String()},
+			&mcp.ResourceLink{
+				URI:         uri,
+				Name:        "client-credentials",
+				Description: "Credentials for " + registration.ClientName + "; readable once",
+				MIMEType:    "application/json",
+			},
+		},
+	}, nil, nil
+}
+
+// readCredentials serves a one-time credentials resource to the admin who registered the client
+func readCredentials(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	text, ok := credentialSecrets.take(req.Params.URI, activity.User(req))
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(req.Params.URI)
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{
+				URI:      req.Params.URI,
+				MIMEType: "application/json",
+				Text:     text,
+			},
+		},
+	}, nil
+}
+
+// Profile implements ProfiledTool
+func (tool *RegisterClient) Profile() Profile {
+	return Profile{Latency: LatencyFast, Cost: CostFree, Writes: true}
+}
+
+func (tool *RegisterClient) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: oneTimeSecretPrefix + "{id}",
+		Name:        "client-credentials",
+		Description: "Credentials issued by register-client, readable once by the admin who registered the client",
+		MIMEType:    "application/json",
+	}, readCredentials)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &RegisterClient{
+		Name:        "register-client",
+		Description: "Registers an OAuth client and returns a link to its credentials, which can be read once (requires the mcp:admin scope).",
+	})
+}
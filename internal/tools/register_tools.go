@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type MCPRegisterableTool interface {
+	Register(server *mcp.Server) (mcpToolInstance *mcp.Tool)
+}
+
+var tools []MCPRegisterableTool
+
+// RegisterAll registers every tool with the server except those turned off by TOOLS_ENABLED or
+// TOOLS_DISABLED, and logs the tools served
+func RegisterAll(server *mcp.Server) {
+	served, registered := register(server, toolSelectionFromEnv())
+	log.Printf("Serving %d of %d tools: %s", len(served), registered, strings.Join(served, ", "))
+}
+
+// RegisterPersona registers the persona's tools with its server, except those turned off by
+// TOOLS_ENABLED or TOOLS_DISABLED
+func RegisterPersona(server *mcp.Server, persona Persona) {
+	selection := toolSelectionFromEnv()
+	selection.persona = persona
+	served, _ := register(server, selection)
+	log.Printf("Persona %s at %s serves %d tools: %s", persona.Name, persona.Path(), len(served), strings.Join(served, ", "))
+}
+
+// register adds the tools selection allows to server and returns their names, along with how
+// many tools there are
+func register(server *mcp.Server, selection toolSelection) (served []string, registered int) {
+	var names []string
+	for _, tool := range tools {
+		mcpToolInstance := tool.Register(server)
+		names = append(names, mcpToolInstance.Name)
+		if !selection.allows(mcpToolInstance.Name) {
+			server.RemoveTools(mcpToolInstance.Name)
+			if selection.persona.Name == "" {
+				log.Printf("Disabled tool: %s", mcpToolInstance.Name)
+			}
+			continue
+		}
+		if _, ok := tool.(GitHubUserTool); ok && jwtAccessTokens.Load() {
+			server.RemoveTools(mcpToolInstance.Name)
+			if selection.persona.Name == "" {
+				log.Printf("Hidden tool: %s (JWT access tokens carry no GitHub token)", mcpToolInstance.Name)
+			}
+			continue
+		}
+		served = append(served, mcpToolInstance.Name)
+		recordScopes(mcpToolInstance.Name, tool)
+		recordProfile(mcpToolInstance.Name, tool)
+
+		if selection.persona.Name != "" {
+			continue
+		}
+		if scopes := RequiredScopes(mcpToolInstance.Name); len(scopes) > 0 {
+			log.Printf("Registered tool: %s (requires %s)", mcpToolInstance.Name, strings.Join(scopes, ", "))
+		} else {
+			log.Printf("Registered tool: %s", mcpToolInstance.Name)
+		}
+	}
+
+	selection.warnUnknown(names)
+	return served, len(names)
+}
@@ -0,0 +1,235 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxCachedResults bounds how many tool results the cache holds at once
+const maxCachedResults = 1000
+
+// ParseCacheTTLs parses comma-separated tool=duration entries, e.g. "get-fortune=60s,convert-currency=5m"
+func ParseCacheTTLs(spec string) (map[string]time.Duration, error) {
+	ttls := make(map[string]time.Duration)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		tool, value, found := strings.Cut(entry, "=")
+		tool, value = strings.TrimSpace(tool), strings.TrimSpace(value)
+		if !found || tool == "" || value == "" {
+			return nil, fmt.Errorf("invalid cache TTL %q, expected tool=duration", entry)
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("invalid cache TTL %q, duration must be positive, e.g. 60s or 5m", entry)
+		}
+		ttls[tool] = ttl
+	}
+	return ttls, nil
+}
+
+// CacheStats counts how a tool's calls were answered by the result cache
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// Entries is how many of the tool's results are cached, including expired ones not yet evicted
+	Entries int `json:"entries"`
+}
+
+// cachedResult is a tool result and when it stops being served
+type cachedResult struct {
+	tool    string
+	result  *mcp.CallToolResult
+	expires time.Time
+}
+
+// ResultCache answers repeated tools/call requests from earlier results, for the tools given a TTL
+// Results are keyed by tool name and a hash of the arguments, plus the caller for tools whose
+// results depend on who calls them; errors and tools that write are never cached
+type ResultCache struct {
+	ttls map[string]time.Duration
+
+	mu      sync.Mutex
+	results map[string]cachedResult
+	stats   map[string]*CacheStats
+}
+
+// NewResultCache creates a cache for the tools in ttls; an empty map caches nothing
+func NewResultCache(ttls map[string]time.Duration) *ResultCache {
+	return &ResultCache{
+		ttls:    ttls,
+		results: make(map[string]cachedResult),
+		stats:   make(map[string]*CacheStats),
+	}
+}
+
+// Middleware serves cached results for tools/call requests and caches the results of misses
+// It belongs after scope and policy checks, so a cached result is only served to callers allowed
+// to call the tool
+func (c *ResultCache) Middleware() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		if c == nil || len(c.ttls) == 0 {
+			return next
+		}
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok || call.Params == nil {
+				return next(ctx, method, req)
+			}
+			tool := call.Params.Name
+			ttl, ok := c.ttls[tool]
+			if !ok {
+				return next(ctx, method, req)
+			}
+			profile, _ := ToolProfile(tool)
+			if profile.Writes {
+				return next(ctx, method, req)
+			}
+
+			key := cacheKey(call, profile)
+			if result, ok := c.get(tool, key); ok {
+				return result, nil
+			}
+
+			result, err := next(ctx, method, req)
+			if toolResult, ok := result.(*mcp.CallToolResult); ok && err == nil && !toolResult.IsError {
+				c.put(key, cachedResult{tool: tool, result: toolResult, expires: toolClock.Now().Add(ttl)})
+			}
+			return result, err
+		}
+	}
+}
+
+// cacheKey identifies a call by tool and arguments, and by caller for per-caller tools
+// Arguments are decoded and encoded again so the order of their fields doesn't matter
+func cacheKey(call *mcp.CallToolRequest, profile Profile) string {
+	arguments := []byte(call.Params.Arguments)
+	var decoded any
+	if json.Unmarshal(arguments, &decoded) == nil {
+		if canonical, err := json.Marshal(decoded); err == nil {
+			arguments = canonical
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(call.Params.Name + "\x00"))
+	if profile.PerCaller {
+		hash.Write([]byte(callerLogin(call) + "\x00"))
+	}
+	hash.Write(arguments)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// get returns a copy of the unexpired result cached under key, counting the hit or miss
+func (c *ResultCache) get(tool, key string) (*mcp.CallToolResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.toolStats(tool)
+	cached, ok := c.results[key]
+	if !ok || !toolClock.Now().Before(cached.expires) {
+		stats.Misses++
+		return nil, false
+	}
+	stats.Hits++
+	result := *cached.result
+	return &result, true
+}
+
+// put caches result under key, first evicting expired results when the cache is full; results
+// that still don't fit aren't cached
+func (c *ResultCache) put(key string, result cachedResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.results[key]; !ok && len(c.results) >= maxCachedResults {
+		now := toolClock.Now()
+		for k, cached := range c.results {
+			if !now.Before(cached.expires) {
+				c.remove(k)
+			}
+		}
+		if len(c.results) >= maxCachedResults {
+			return
+		}
+	}
+	if _, ok := c.results[key]; !ok {
+		c.toolStats(result.tool).Entries++
+	}
+	c.results[key] = result
+}
+
+// remove drops the result cached under key; callers hold c.mu
+func (c *ResultCache) remove(key string) {
+	cached, ok := c.results[key]
+	if !ok {
+		return
+	}
+	delete(c.results, key)
+	c.toolStats(cached.tool).Entries--
+}
+
+// toolStats returns the counters of tool, creating them on first use; callers hold c.mu
+func (c *ResultCache) toolStats(tool string) *CacheStats {
+	stats, ok := c.stats[tool]
+	if !ok {
+		stats = &CacheStats{}
+		c.stats[tool] = stats
+	}
+	return stats
+}
+
+// Stats returns the counters of every tool the cache has seen calls for
+func (c *ResultCache) Stats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make(map[string]CacheStats, len(c.stats))
+	for tool, toolStats := range c.stats {
+		stats[tool] = *toolStats
+	}
+	return stats
+}
+
+// CacheStatsHandler reports a result cache's counters and TTLs as JSON
+type CacheStatsHandler struct {
+	cache *ResultCache
+}
+
+// NewCacheStatsHandler creates a new handler for the given cache
+func NewCacheStatsHandler(cache *ResultCache) *CacheStatsHandler {
+	return &CacheStatsHandler{cache: cache}
+}
+
+// ServeHTTP implements http.Handler
+func (h *CacheStatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	ttls := make(map[string]int64, len(h.cache.ttls))
+	for tool, ttl := range h.cache.ttls {
+		ttls[tool] = int64(ttl.Seconds())
+	}
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(struct {
+		TTLs  map[string]int64      `json:"ttl_seconds"`
+		Tools map[string]CacheStats `json:"tools"`
+	}{ttls, h.cache.Stats()})
+	if err != nil {
+		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Rollout limits a tool to some users while it is being introduced
+type Rollout struct {
+	// Percent of users, picked by a stable hash of tool and login, who see the tool
+	Percent int
+	// Users see the tool regardless of Percent
+	Users []string
+}
+
+// Exposes reports whether user sees the tool named tool; anonymous callers only see tools
+// that are fully rolled out
+func (r Rollout) Exposes(tool, user string) bool {
+	if r.Percent >= 100 {
+		return true
+	}
+	if user == "" {
+		return false
+	}
+	for _, allowed := range r.Users {
+		if strings.EqualFold(allowed, user) {
+			return true
+		}
+	}
+
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(tool + "\x00" + strings.ToLower(user)))
+	return int(hash.Sum32()%100) < r.Percent
+}
+
+// ParseRollouts parses comma-separated tool=N% and tool=@login entries; a tool may be listed
+// several times, e.g. "tail-logs=10%,tail-logs=@octocat"
+func ParseRollouts(spec string) (map[string]Rollout, error) {
+	rollouts := make(map[string]Rollout)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		tool, target, found := strings.Cut(entry, "=")
+		tool, target = strings.TrimSpace(tool), strings.TrimSpace(target)
+		if !found || tool == "" || target == "" {
+			return nil, fmt.Errorf("invalid rollout %q, expected tool=N%% or tool=@login", entry)
+		}
+
+		rollout := rollouts[tool]
+		if user, ok := strings.CutPrefix(target, "@"); ok {
+			rollout.Users = append(rollout.Users, user)
+		} else {
+			percent, err := strconv.Atoi(strings.TrimSuffix(target, "%"))
+			if err != nil || percent < 0 || percent > 100 {
+				return nil, fmt.Errorf("invalid rollout %q, percentage must be between 0 and 100", entry)
+			}
+			rollout.Percent = percent
+		}
+		rollouts[tool] = rollout
+	}
+	return rollouts, nil
+}
+
+// RolloutMiddleware hides tools from users outside their rollout: they are left out of
+// tools/list and calls to them fail as if the tool didn't exist
+func RolloutMiddleware(rollouts map[string]Rollout) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		if len(rollouts) == 0 {
+			return next
+		}
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			user := callerLogin(req)
+
+			if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil {
+				if rollout, limited := rollouts[call.Params.Name]; limited && !rollout.Exposes(call.Params.Name, user) {
+					return nil, fmt.Errorf("unknown tool %q", call.Params.Name)
+				}
+			}
+
+			result, err := next(ctx, method, req)
+			if list, ok := result.(*mcp.ListToolsResult); ok && err == nil {
+				visible := make([]*mcp.Tool, 0, len(list.Tools))
+				for _, tool := range list.Tools {
+					if rollout, limited := rollouts[tool.Name]; !limited || rollout.Exposes(tool.Name, user) {
+						visible = append(visible, tool)
+					}
+				}
+				list.Tools = visible
+			}
+			return result, err
+		}
+	}
+}
+
+// callerLogin returns the GitHub login of the caller of req, or "" if it is unauthenticated
+func callerLogin(req mcp.Request) string {
+	extra := req.GetExtra()
+	if extra == nil || extra.TokenInfo == nil {
+		return ""
+	}
+	login, _ := extra.TokenInfo.Extra["subject"].(string)
+	return login
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/release"
+)
+
+// sandboxMode makes tools that call external APIs answer with canned data, for demos and
+// frontend development without network access or an AWS account
+var sandboxMode atomic.Bool
+
+// Canned names reported in the sandbox when the real ones aren't configured
+const (
+	sandboxCluster  = "sandbox-cluster"
+	sandboxService  = "sandbox-service"
+	sandboxLogGroup = "/ecs/sandbox"
+)
+
+// sandboxFortune is the fortune get-fortune always returns in the sandbox
+const sandboxFortune = "A journey of a thousand miles begins with a single step."
+
+// sandboxUSDRates are the exchange rates from USD convert-currency uses in the sandbox
+var sandboxUSDRates = map[string]float64{
+	"USD": 1,
+	"EUR": 0.9,
+	"GBP": 0.8,
+	"JPY": 150,
+	"CAD": 1.35,
+	"AUD": 1.5,
+}
+
+// SetSandbox turns sandbox mode on or off for every tool
+func SetSandbox(enabled bool) {
+	sandboxMode.Store(enabled)
+	if enabled {
+		SetRateProvider(sandboxRates{})
+	} else {
+		SetRateProvider(nil)
+	}
+}
+
+// sandboxRates implements RateProvider with sandboxUSDRates, crossing through USD
+type sandboxRates struct{}
+
+func (sandboxRates) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	fromUSD, ok := sandboxUSDRates[base]
+	if !ok {
+		return nil, fmt.Errorf("the sandbox has no rates for %s", base)
+	}
+	rates := make(map[string]float64, len(sandboxUSDRates))
+	for currency, rate := range sandboxUSDRates {
+		rates[currency] = rate / fromUSD
+	}
+	return rates, nil
+}
+
+// sandboxCostReport is the get-aws-costs report in the sandbox, against AWS_MONTHLY_BUDGET if set
+func sandboxCostReport(now time.Time) (string, error) {
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var b strings.Builder
+	fmt.Fprintf(&b, "AWS spend month-to-date (%s to %s):\n", monthStart.Format(time.DateOnly), now.Format(time.DateOnly))
+	b.WriteString("- Amazon Elastic Container Service: $42.17\n")
+	b.WriteString("- Elastic Load Balancing: $16.20\n")
+	b.WriteString("- Amazon CloudWatch: $3.85\n")
+	b.WriteString("\nGross usage: $62.22\n")
+	b.WriteString("Credits applied: $-10.00\n")
+	b.WriteString("Net month-to-date: $52.22\n")
+	b.WriteString("Forecast for remainder of month: $48.00\n")
+	b.WriteString("Projected month-end total: $100.22\n")
+
+	budget, err := monthlyBudget()
+	if err != nil {
+		return "", err
+	}
+	projected := 100.22
+	writeBudget(&b, budget, 52.22, &projected)
+	return b.String(), nil
+}
+
+// sandboxDeploymentStatus is the get-deployment-status report in the sandbox
+func sandboxDeploymentStatus(ctx context.Context, now time.Time, cluster string, services, stacks []string) string {
+	var b strings.Builder
+	for _, service := range services {
+		fmt.Fprintf(&b, "ECS service %s (cluster %s): HEALTHY\n", service, cluster)
+		b.WriteString("- Tasks: 2 running / 2 desired / 0 pending\n")
+		fmt.Fprintf(&b, "- Task definition: %s:7\n", service)
+		fmt.Fprintf(&b, "- Deployment PRIMARY (COMPLETED): %s:7, 2/2 running, 0 failed tasks, updated %s\n",
+			service, now.Add(-2*time.Hour).Format(time.RFC3339))
+		fmt.Fprintf(&b, "  [%s] (service %s) has reached a steady state.\n\n", now.Add(-time.Hour).Format(time.RFC3339), service)
+	}
+	for _, stack := range stacks {
+		fmt.Fprintf(&b, "CloudFormation stack %s recent events:\n", stack)
+		fmt.Fprintf(&b, "  [%s] %s UPDATE_COMPLETE (AWS::CloudFormation::Stack)\n\n", now.Add(-2*time.Hour).Format(time.RFC3339), stack)
+	}
+	writeServedBy(&b, instance.Current())
+	writeHealth(ctx, &b)
+	writeVersion(&b, release.Default.Status())
+	return strings.TrimSpace(b.String())
+}
+
+// sandboxLogLines are the events tail-logs reads in the sandbox, oldest first, with how long
+// before now each was logged
+var sandboxLogLines = []struct {
+	ago     time.Duration
+	message string
+}{
+	{12 * time.Minute, "MCP server listening on 0.0.0.0:8080"},
+	{9 * time.Minute, "Tool: get-city-time | User: octocat | Duration: 2ms"},
+	{6 * time.Minute, "ERROR Failed to call GitHub API: context deadline exceeded"},
+	{4 * time.Minute, "Tool: get-fortune | User: hubot | Duration: 180ms"},
+	{time.Minute, "Status: 500 POST /mcp"},
+}
+
+// sandboxLogEvents writes the sandbox log events of the last minutes matching filter, a
+// plain substring, up to limit
+func sandboxLogEvents(now time.Time, logGroup, filter string, minutes, limit int) string {
+	var lines []string
+	for _, line := range sandboxLogLines {
+		if line.ago > time.Duration(minutes)*time.Minute || !strings.Contains(line.message, strings.Trim(filter, `"`)) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("[%s] sandbox/app/1: %s\n", now.Add(-line.ago).UTC().Format(time.RFC3339), line.message))
+	}
+	if len(lines) > limit {
+		lines = lines[len(lines)-limit:]
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d events from %s in the last %d minutes", len(lines), logGroup, minutes)
+	if filter != "" {
+		fmt.Fprintf(&b, " matching %q", filter)
+	}
+	b.WriteString(":\n")
+	b.WriteString(strings.Join(lines, ""))
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// adminScope is required by tools that expose operational or billing data
+const adminScope = "mcp:admin"
+
+// ScopedTool is implemented by tools that need scopes beyond the mcp:tools scope every MCP
+// request already requires
+type ScopedTool interface {
+	RequiredScopes() []string
+}
+
+// toolScopes maps registered tool names to the scopes they declare
+var (
+	toolScopesMu sync.RWMutex
+	toolScopes   = make(map[string][]string)
+)
+
+// RequiredScopes returns the scopes the registered tool name declares, if any
+func RequiredScopes(name string) []string {
+	toolScopesMu.RLock()
+	defer toolScopesMu.RUnlock()
+	return toolScopes[name]
+}
+
+// recordScopes remembers the scopes declared by tool, registered as name
+func recordScopes(name string, tool MCPRegisterableTool) {
+	scoped, ok := tool.(ScopedTool)
+	if !ok {
+		return
+	}
+
+	toolScopesMu.Lock()
+	defer toolScopesMu.Unlock()
+	toolScopes[name] = scoped.RequiredScopes()
+}
+
+// ScopeMiddleware rejects tools/call requests for tools whose declared scopes the caller's access
+// token doesn't cover, before the tool runs
+func ScopeMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil {
+			if err := requireScopes(call, RequiredScopes(call.Params.Name)); err != nil {
+				return nil, fmt.Errorf("tool %s: %w", call.Params.Name, err)
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// requireScopes returns an error unless the caller's access token was granted every scope
+func requireScopes(req *mcp.CallToolRequest, scopes []string) error {
+	for _, scope := range scopes {
+		if req == nil || req.Extra == nil || req.Extra.TokenInfo == nil {
+			return fmt.Errorf("this tool requires an access token with the %s scope", scope)
+		}
+		if !slices.Contains(req.Extra.TokenInfo.Scopes, scope) {
+			return fmt.Errorf("this tool requires the %s scope", scope)
+		}
+	}
+	return nil
+}
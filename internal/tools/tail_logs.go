@@ -0,0 +1,224 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Limits keep a single call from pulling an unbounded amount of log data into the client's context
+const (
+	defaultTailMinutes = 15
+	maxTailMinutes     = 24 * 60
+	defaultTailLimit   = 50
+	maxTailLimit       = 500
+)
+
+type TailLogs struct {
+	Name        string
+	Description string
+}
+
+// TailLogsParams defines the parameters for the tail-logs tool.
+type TailLogsParams struct {
+	LogGroup      string `json:"logGroup,omitempty" jsonschema:"Log group to read (defaults to the first configured log group)"`
+	FilterPattern string `json:"filterPattern,omitempty" jsonschema:"CloudWatch Logs filter pattern (e.g. ERROR or \"Status: 500\")"`
+	Minutes       int    `json:"minutes,omitempty" jsonschema:"How many minutes back to search (default 15, max 1440)"`
+	Limit         int    `json:"limit,omitempty" jsonschema:"Maximum number of events to return per page (default 50, max 500)"`
+	NextToken     string `json:"nextToken,omitempty" jsonschema:"Pagination token returned by a previous call; the next page covers the same time window"`
+}
+
+// tailLogsPage is what a tail-logs nextToken carries: CloudWatch Logs page tokens are only valid
+// for the log group, filter pattern, and time window of the call that returned them
+type tailLogsPage struct {
+	LogGroup      string `json:"logGroup"`
+	FilterPattern string `json:"filterPattern,omitempty"`
+	StartTime     int64  `json:"startTime"`
+	EndTime       int64  `json:"endTime"`
+	Token         string `json:"token"`
+}
+
+// encodeTailLogsPage returns page as an opaque nextToken
+func encodeTailLogsPage(page tailLogsPage) string {
+	encoded, _ := json.Marshal(page)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// decodeTailLogsPage reads a nextToken returned by encodeTailLogsPage
+func decodeTailLogsPage(token string) (tailLogsPage, error) {
+	var page tailLogsPage
+	decoded, err := base64.RawURLEncoding.DecodeString(token)
+	if err == nil {
+		err = json.Unmarshal(decoded, &page)
+	}
+	if err != nil || page.Token == "" {
+		return page, fmt.Errorf("invalid nextToken: pass the nextToken returned by the previous tail-logs call")
+	}
+	return page, nil
+}
+
+type filterLogEventsResponse struct {
+	Events []struct {
+		LogStreamName string `json:"logStreamName"`
+		Timestamp     int64  `json:"timestamp"`
+		Message       string `json:"message"`
+	} `json:"events"`
+	NextToken string `json:"nextToken"`
+}
+
+// RequiredScopes implements ScopedTool
+func (tool *TailLogs) RequiredScopes() []string {
+	return []string{adminScope}
+}
+
+func (tool *TailLogs) Action(ctx context.Context, req *mcp.CallToolRequest, params *TailLogsParams) (*mcp.CallToolResult, any, error) {
+	if err := requireScopes(req, tool.RequiredScopes()); err != nil {
+		return nil, nil, err
+	}
+
+	allowedGroups := splitList(os.Getenv("CLOUDWATCH_LOG_GROUPS"))
+	if len(allowedGroups) == 0 && sandboxMode.Load() {
+		allowedGroups = []string{sandboxLogGroup}
+	}
+	if len(allowedGroups) == 0 {
+		return nil, nil, fmt.Errorf("no log groups configured (set CLOUDWATCH_LOG_GROUPS)")
+	}
+
+	logGroup := params.LogGroup
+	if logGroup == "" {
+		logGroup = allowedGroups[0]
+	}
+	// Only configured log groups may be read, regardless of what the task role allows
+	if !slices.Contains(allowedGroups, logGroup) {
+		return nil, nil, fmt.Errorf("unknown log group: %s (available: %s)", logGroup, strings.Join(allowedGroups, ", "))
+	}
+
+	minutes := params.Minutes
+	if minutes <= 0 {
+		minutes = defaultTailMinutes
+	}
+	if minutes > maxTailMinutes {
+		return nil, nil, fmt.Errorf("minutes cannot exceed %d", maxTailMinutes)
+	}
+
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultTailLimit
+	}
+	if limit > maxTailLimit {
+		return nil, nil, fmt.Errorf("limit cannot exceed %d", maxTailLimit)
+	}
+
+	if sandboxMode.Load() {
+		return &mcp.CallToolResult{
+			Content: []mcp.Content{
+				&mcp.TextContent{Text: sandboxLogEvents(toolClock.Now(), logGroup, params.FilterPattern, minutes, limit)},
+			},
+		}, nil, nil
+	}
+
+	region, err := sharedAWSClient.region(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Later pages keep the window of the first call, however long ago it was made
+	now := toolClock.Now()
+	page := tailLogsPage{
+		LogGroup:      logGroup,
+		FilterPattern: params.FilterPattern,
+		StartTime:     now.Add(-time.Duration(minutes) * time.Minute).UnixMilli(),
+		EndTime:       now.UnixMilli(),
+	}
+	if params.NextToken != "" {
+		page, err = decodeTailLogsPage(params.NextToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		if page.LogGroup != logGroup {
+			return nil, nil, fmt.Errorf("nextToken belongs to log group %s, not %s", page.LogGroup, logGroup)
+		}
+		if page.FilterPattern != params.FilterPattern {
+			return nil, nil, fmt.Errorf("nextToken belongs to filter pattern %q, not %q", page.FilterPattern, params.FilterPattern)
+		}
+		minutes = int(time.Duration(page.EndTime-page.StartTime) * time.Millisecond / time.Minute)
+	}
+
+	input := map[string]any{
+		"logGroupName": logGroup,
+		"startTime":    page.StartTime,
+		"endTime":      page.EndTime,
+		"limit":        limit,
+	}
+	if params.FilterPattern != "" {
+		input["filterPattern"] = params.FilterPattern
+	}
+	if page.Token != "" {
+		input["nextToken"] = page.Token
+	}
+
+	var output filterLogEventsResponse
+	err = sharedAWSClient.callJSON(ctx, "logs", region,
+		fmt.Sprintf("https://logs.%s.amazonaws.com/", region),
+		"Logs_20140328.FilterLogEvents", input, &output)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d events from %s in the last %d minutes", len(output.Events), logGroup, minutes)
+	if params.FilterPattern != "" {
+		fmt.Fprintf(&b, " matching %q", params.FilterPattern)
+	}
+	b.WriteString(":\n")
+
+	for _, event := range output.Events {
+		fmt.Fprintf(&b, "[%s] %s: %s\n",
+			time.UnixMilli(event.Timestamp).UTC().Format(time.RFC3339),
+			event.LogStreamName,
+			strings.TrimRight(event.Message, "\n"))
+	}
+
+	if output.NextToken != "" {
+		page.Token = output.NextToken
+		fmt.Fprintf(&b, "\nMore events available. Call again with nextToken: %s\n", encodeTailLogsPage(page))
+	}
+
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{Text: b.String()},
+		},
+	}, nil, nil
+}
+
+// Profile implements ProfiledTool
+func (tool *TailLogs) Profile() Profile {
+	return Profile{Latency: LatencyModerate, Cost: CostFree, External: true}
+}
+
+func (tool *TailLogs) Register(server *mcp.Server) (mcpToolInstance *mcp.Tool) {
+	mcpToolInstance = &mcp.Tool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		Annotations: tool.Profile().Annotations(),
+		Meta:        tool.Profile().Meta(),
+	}
+
+	mcp.AddTool(server, mcpToolInstance, tool.Action)
+
+	return
+}
+
+func init() {
+	tools = append(tools, &TailLogs{
+		Name:        "tail-logs",
+		Description: "Returns recent CloudWatch Logs events for the configured log groups, with optional filter pattern and pagination (requires the mcp:admin scope).",
+	})
+}
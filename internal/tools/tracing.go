@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/telemetry"
+)
+
+// traceHeaders are the inbound request headers forwarded on outbound calls made by tools
+var traceHeaders = []string{"Traceparent", "Tracestate", "X-Request-Id"}
+
+type traceHeadersKey struct{}
+
+// WithTraceHeaders returns a context carrying the tracing headers found in header
+func WithTraceHeaders(ctx context.Context, header http.Header) context.Context {
+	trace := make(http.Header)
+	for _, name := range traceHeaders {
+		if value := header.Get(name); value != "" {
+			trace.Set(name, value)
+		}
+	}
+	if len(trace) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, traceHeadersKey{}, trace)
+}
+
+// TraceHeadersFromContext returns the tracing headers stored by WithTraceHeaders, if any
+func TraceHeadersFromContext(ctx context.Context) http.Header {
+	trace, _ := ctx.Value(traceHeadersKey{}).(http.Header)
+	return trace
+}
+
+// TracingMiddleware starts a span for each MCP method, continuing the HTTP request's trace, and
+// copies tracing headers into the context passed to tool Actions so outbound calls are children
+// of that span and correlate with the originating client trace
+func TracingMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
+		var header http.Header
+		if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+			header = extra.Header.Clone()
+			ctx = telemetry.ContextFromHeader(ctx, header)
+		}
+
+		name := method
+		attrs := []attribute.KeyValue{attribute.String("rpc.method", method)}
+		if call, ok := req.(*mcp.CallToolRequest); ok && call.Params != nil {
+			name += " " + call.Params.Name
+			attrs = append(attrs, attribute.String("mcp.tool", call.Params.Name))
+		}
+		ctx, span := telemetry.Start(ctx, name, attrs...)
+		defer func() {
+			if err == nil {
+				if toolResult, ok := result.(*mcp.CallToolResult); ok && toolResult.IsError {
+					span.SetAttributes(attribute.Bool("mcp.tool_error", true))
+				}
+			}
+			telemetry.End(span, err)
+		}()
+
+		if header != nil {
+			if span.SpanContext().IsValid() {
+				telemetry.InjectHeader(ctx, header)
+			}
+			ctx = WithTraceHeaders(ctx, header)
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// tracingTransport adds the tracing headers from the request context to outbound requests
+// and records how long each request took for slow call detection
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	trace := TraceHeadersFromContext(req.Context())
+	if len(trace) > 0 {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		for name, values := range trace {
+			if req.Header.Get(name) == "" {
+				req.Header[name] = values
+			}
+		}
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	recordUpstreamCall(req.Context(), req.URL.Host, status, time.Since(start))
+
+	return resp, err
+}
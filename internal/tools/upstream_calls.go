@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+// UpstreamCall records a single outbound request made while handling a tool call
+type UpstreamCall struct {
+	Host     string        `json:"host"`
+	Status   int           `json:"status"`
+	Duration time.Duration `json:"duration_ms"`
+}
+
+// MarshalJSON reports the duration in milliseconds
+func (c UpstreamCall) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Host     string `json:"host"`
+		Status   int    `json:"status"`
+		Duration int64  `json:"duration_ms"`
+	}{c.Host, c.Status, c.Duration.Milliseconds()})
+}
+
+type upstreamCallsKey struct{}
+
+type upstreamCalls struct {
+	mu    sync.Mutex
+	calls []UpstreamCall
+}
+
+// recordUpstreamCall adds an outbound request to the tool call in ctx, if it is being timed
+func recordUpstreamCall(ctx context.Context, host string, status int, duration time.Duration) {
+	recorder, ok := ctx.Value(upstreamCallsKey{}).(*upstreamCalls)
+	if !ok {
+		return
+	}
+	recorder.mu.Lock()
+	defer recorder.mu.Unlock()
+	recorder.calls = append(recorder.calls, UpstreamCall{Host: host, Status: status, Duration: duration})
+}
+
+// SlowCallMiddleware times every tool call and logs the ones exceeding threshold with the
+// tool name, session, and the outbound requests the tool made, plus an [ALERT] event
+// A threshold of zero disables detection
+func SlowCallMiddleware(threshold time.Duration) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			callReq, ok := req.(*mcp.CallToolRequest)
+			if threshold <= 0 || !ok {
+				return next(ctx, method, req)
+			}
+
+			recorder := &upstreamCalls{}
+			ctx = context.WithValue(ctx, upstreamCallsKey{}, recorder)
+
+			start := time.Now()
+			result, err := next(ctx, method, req)
+			duration := time.Since(start)
+
+			if duration >= threshold {
+				sessionID := ""
+				if callReq.Session != nil {
+					sessionID = callReq.Session.ID()
+				}
+				recorder.mu.Lock()
+				calls := recorder.calls
+				recorder.mu.Unlock()
+
+				logSlowToolCall(callReq.Params.Name, sessionID, duration, threshold, calls)
+			}
+
+			return result, err
+		}
+	}
+}
+
+func logSlowToolCall(tool, sessionID string, duration, threshold time.Duration, calls []UpstreamCall) {
+	upstream := make([]string, 0, len(calls))
+	for _, call := range calls {
+		upstream = append(upstream, fmt.Sprintf("%s %d in %v", call.Host, call.Status, call.Duration))
+	}
+	if len(upstream) == 0 {
+		upstream = append(upstream, "none")
+	}
+
+	log.Printf("[SLOW] Tool: %s | Session: %s | Duration: %v (threshold %v) | Upstream: %s",
+		tool, sessionID, duration, threshold, strings.Join(upstream, ", "))
+
+	event, _ := json.Marshal(instance.Current().Tag(map[string]any{
+		"event":        "slow_tool_call",
+		"tool":         tool,
+		"session":      sessionID,
+		"duration_ms":  duration.Milliseconds(),
+		"threshold_ms": threshold.Milliseconds(),
+		"upstream":     calls,
+	}))
+	log.Printf("[ALERT] %s", event)
+}
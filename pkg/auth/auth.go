@@ -0,0 +1,128 @@
+// Package auth is the public API of the server's OAuth support: the storage interfaces other
+// backends implement, the records they store, and the configuration, token verifier, and HTTP
+// middleware for protecting an MCP endpoint
+//
+// The OAuth handlers and the storage implementations are internal; NewStorage returns the
+// backend selected by STORAGE_BACKEND. Implementations of the storage interfaces can be checked
+// with the contracts in the testsupport package.
+package auth
+
+import (
+	"context"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// Storage interfaces
+
+// ClientStorage stores registered OAuth clients
+type ClientStorage = auth.ClientStorage
+
+// TokenStorage stores authorization codes and access tokens
+type TokenStorage = auth.TokenStorage
+
+// TokenCache caches GitHub token validation results
+type TokenCache = auth.TokenCache
+
+// StateStorage stores the state of authorization flows between the authorize and callback requests
+type StateStorage = auth.StateStorage
+
+// Storage bundles the storage used by the OAuth handlers
+type Storage = auth.Storage
+
+// Stored records
+
+// OAuthClient is a registered OAuth client
+type OAuthClient = auth.OAuthClient
+
+// ClientRegistrationRequest is the metadata a client registered with
+type ClientRegistrationRequest = auth.ClientRegistrationRequest
+
+// AuthCodeInfo is what an authorization code was issued for
+type AuthCodeInfo = auth.AuthCodeInfo
+
+// AccessTokenInfo is what an access token was issued for
+type AccessTokenInfo = auth.AccessTokenInfo
+
+// StoredAccessToken is an access token as listed to operators, named by its ID
+type StoredAccessToken = auth.StoredAccessToken
+
+// ErrAccessTokenNotFound is returned by TokenStorage.RevokeAccessTokenByID for unknown and
+// expired tokens
+var ErrAccessTokenNotFound = auth.ErrAccessTokenNotFound
+
+// AccessTokenID returns the ID an access token is stored, listed, and revoked under
+func AccessTokenID(token string) string {
+	return auth.AccessTokenID(token)
+}
+
+// AuthState is an authorization flow waiting for the GitHub callback
+type AuthState = auth.AuthState
+
+// TokenValidationResult is a cached GitHub token validation
+type TokenValidationResult = auth.TokenValidationResult
+
+// Configuration
+
+// Config holds the OAuth configuration
+type Config = auth.Config
+
+// ConfigVariable describes an environment variable the server reads
+type ConfigVariable = auth.ConfigVariable
+
+// ValidationReport collects every configuration problem
+type ValidationReport = auth.ValidationReport
+
+// ValidationIssue is a single configuration problem
+type ValidationIssue = auth.ValidationIssue
+
+// Severity classifies how serious a configuration problem is
+type Severity = auth.Severity
+
+const (
+	// SeverityFatal problems stop the server from starting with OAuth
+	SeverityFatal = auth.SeverityFatal
+
+	// SeverityWarning problems are allowed but probably not what the operator intended
+	SeverityWarning = auth.SeverityWarning
+)
+
+// DefaultConfig returns the configuration used when no environment variables are set
+func DefaultConfig() *Config {
+	return auth.DefaultConfig()
+}
+
+// LoadConfigFromEnv loads the configuration from environment variables
+func LoadConfigFromEnv() (*Config, error) {
+	return auth.LoadConfigFromEnv()
+}
+
+// ConfigSchema describes every environment variable the server reads
+func ConfigSchema() []ConfigVariable {
+	return auth.ConfigSchema()
+}
+
+// NewStorage creates the storage backend selected by cfg.StorageBackend
+func NewStorage(ctx context.Context, cfg *Config) (*Storage, error) {
+	return auth.NewStorage(ctx, cfg)
+}
+
+// Verifying tokens
+
+// GitHubTokenVerifier verifies the access tokens issued by the server, implementing the MCP
+// SDK's auth.TokenVerifier
+type GitHubTokenVerifier = auth.GitHubTokenVerifier
+
+// NewGitHubTokenVerifier creates a verifier for the tokens in tokenStorage, caching GitHub
+// validations in cache
+func NewGitHubTokenVerifier(config *Config, cache TokenCache, tokenStorage TokenStorage) *GitHubTokenVerifier {
+	return auth.NewGitHubTokenVerifier(config, cache, tokenStorage)
+}
+
+// Middleware protects HTTP handlers with the verifier's tokens
+type Middleware = auth.Middleware
+
+// NewMiddleware creates middleware verifying tokens with verifier
+func NewMiddleware(config *Config, verifier *GitHubTokenVerifier) *Middleware {
+	return auth.NewMiddleware(config, verifier)
+}
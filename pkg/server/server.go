@@ -0,0 +1,70 @@
+// Package server is the public API for building the MCP server: it creates servers with the
+// repo's tools, prompts, and resources, either every tool or a persona's, behind the middleware
+// the caller chooses
+//
+// How tools, prompts, and resources are implemented is internal; TOOLS_ENABLED and
+// TOOLS_DISABLED still turn tools off as they do for the server binary.
+package server
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/prompts"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/resources"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+)
+
+// defaultVersion is the version reported when Options doesn't give one
+const defaultVersion = "1.0.0"
+
+// Persona is a focused MCP server with its own tool set
+type Persona = tools.Persona
+
+// ParsePersonas parses the MCP_PERSONAS format, semicolon-separated name=tool,tool entries, each
+// optionally followed by the space-separated scopes it requires in parentheses
+func ParsePersonas(spec string) ([]Persona, error) {
+	return tools.ParsePersonas(spec)
+}
+
+// Options configures a server created by New
+type Options struct {
+	// Name is the implementation name reported to clients
+	Name string
+
+	// Version is the implementation version reported to clients; 1.0.0 when empty
+	Version string
+
+	// Persona limits the server to the persona's tools; every tool is served when nil
+	Persona *Persona
+
+	// Middleware is added as receiving middleware, outermost first
+	Middleware []mcp.Middleware
+}
+
+// New creates an MCP server with opts' middleware, serving the tools opts selects along with
+// every prompt and resource
+func New(opts Options) *mcp.Server {
+	version := opts.Version
+	if version == "" {
+		version = defaultVersion
+	}
+	server := mcp.NewServer(&mcp.Implementation{
+		Name:    opts.Name,
+		Version: version,
+	}, nil)
+	server.AddReceivingMiddleware(opts.Middleware...)
+
+	if opts.Persona != nil {
+		tools.RegisterPersona(server, *opts.Persona)
+	} else {
+		tools.RegisterAll(server)
+	}
+	prompts.RegisterAll(server)
+	resources.RegisterAll(server)
+	return server
+}
+
+// SetServerURL sets the server's canonical URL, which prompts refer to as {{server_url}}
+func SetServerURL(url string) {
+	prompts.SetServerURL(url)
+}
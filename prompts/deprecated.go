@@ -0,0 +1,17 @@
+// Package prompts is the former import path of the server's prompts package, kept so existing
+// imports keep building.
+//
+// Deprecated: Use the public API in
+// EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/server, whose servers serve every prompt.
+package prompts
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	internalprompts "EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/prompts"
+)
+
+// Deprecated: Servers created by server.New already serve every prompt.
+func RegisterAll(s *mcp.Server) {
+	internalprompts.RegisterAll(s)
+}
@@ -0,0 +1,53 @@
+package tests
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/accesslog"
+)
+
+func TestAccessLogCombinedFormat(t *testing.T) {
+	var out bytes.Buffer
+	handler := accesslog.New(&out, accesslog.Combined).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/register?x=1", nil)
+	req.RemoteAddr = "203.0.113.7:52000"
+	req.Header.Set("Referer", "https://vscode.dev/")
+	req.Header.Set("User-Agent", `Agent "quoted"`)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	pattern := regexp.MustCompile(`^203\.0\.113\.7 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "POST /register\?x=1 HTTP/1\.1" 201 5 "https://vscode\.dev/" "Agent \\"quoted\\""\n$`)
+	if !pattern.MatchString(out.String()) {
+		t.Errorf("Unexpected combined log line: %q", out.String())
+	}
+}
+
+func TestAccessLogCommonFormat(t *testing.T) {
+	var out bytes.Buffer
+	handler := accesslog.New(&out, accesslog.Common).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.RemoteAddr = "198.51.100.1:1234"
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	pattern := regexp.MustCompile(`^198\.51\.100\.1 - - \[[^\]]+\] "GET /health HTTP/1\.1" 200 -\n$`)
+	if !pattern.MatchString(out.String()) {
+		t.Errorf("Unexpected common log line: %q", out.String())
+	}
+}
+
+func TestAccessLogParseFormat(t *testing.T) {
+	if _, err := accesslog.ParseFormat("Combined"); err != nil {
+		t.Errorf("Combined should be accepted: %v", err)
+	}
+	if _, err := accesslog.ParseFormat("json"); err == nil {
+		t.Errorf("Unknown formats should be rejected")
+	}
+}
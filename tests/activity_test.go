@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestActivityTimelineBuckets(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 6, 1, 10, 15, 0, 0, time.UTC))
+	tracker := activity.New()
+	tracker.SetClock(clock)
+
+	tracker.RecordSession("octocat")
+	tracker.RecordToolCall("octocat", "get-fortune")
+	clock.Advance(50 * time.Minute)
+	tracker.RecordToolCall("octocat", "get-fortune")
+	tracker.RecordToolCall("octocat", "get-city-time")
+	tracker.RecordToolCall("", "get-fortune")
+
+	hourly := tracker.Timeline("octocat", activity.BucketSize)
+	if len(hourly) != 2 {
+		t.Fatalf("Expected 2 hourly buckets, got %+v", hourly)
+	}
+	if hourly[0].Sessions != 1 || hourly[0].ToolCalls["get-fortune"] != 1 || hourly[1].ToolCalls["get-fortune"] != 1 {
+		t.Errorf("Unexpected hourly buckets %+v", hourly)
+	}
+
+	daily := tracker.Timeline("octocat", 24*time.Hour)
+	if len(daily) != 1 || daily[0].ToolCalls["get-fortune"] != 2 || daily[0].ToolCalls["get-city-time"] != 1 {
+		t.Errorf("Unexpected daily bucket %+v", daily)
+	}
+
+	summary := tracker.Summary()
+	if len(summary) != 1 || summary[0].User != "octocat" || summary[0].ToolCalls != 3 || summary[0].Sessions != 1 {
+		t.Errorf("Anonymous calls should not be tracked, got summary %+v", summary)
+	}
+
+	// Activity older than the retention window is dropped
+	clock.Advance(activity.Retention + time.Hour)
+	tracker.RecordSession("hubot")
+	if timeline := tracker.Timeline("octocat", activity.BucketSize); len(timeline) != 0 {
+		t.Errorf("Expected expired activity to be dropped, got %+v", timeline)
+	}
+}
+
+func TestActivityMiddlewareAndHandler(t *testing.T) {
+	tracker := activity.New()
+	handler := activity.Middleware(tracker)(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+
+	extra := &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{
+		Scopes:     []string{"mcp:tools"},
+		Expiration: time.Now().Add(time.Hour),
+		Extra:      map[string]any{"subject": "octocat"},
+	}}
+	_, _ = handler(context.TODO(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get-fortune"}, Extra: extra})
+
+	rec := httptest.NewRecorder()
+	activity.NewStatusHandler(tracker).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/activity?user=octocat&bucket=day", nil))
+	var timeline []activity.Bucket
+	if err := json.NewDecoder(rec.Body).Decode(&timeline); err != nil {
+		t.Fatalf("Failed to decode timeline: %v", err)
+	}
+	if len(timeline) != 1 || timeline[0].ToolCalls["get-fortune"] != 1 {
+		t.Errorf("Expected the tool call in the timeline, got %+v", timeline)
+	}
+
+	rec = httptest.NewRecorder()
+	activity.NewStatusHandler(tracker).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/activity?user=octocat&bucket=week", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown bucket, got %d", rec.Code)
+	}
+}
+
+func TestActivityActiveUsers(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 6, 1, 10, 15, 0, 0, time.UTC))
+	tracker := activity.New()
+	tracker.SetClock(clock)
+
+	tracker.RecordSession("octocat")
+	clock.Advance(12 * time.Hour)
+	tracker.RecordSession("hubot")
+	tracker.RecordToolCall("hubot", "get-fortune")
+
+	if active := tracker.ActiveUsers(24 * time.Hour); active != 2 {
+		t.Errorf("Expected 2 active users in the last day, got %d", active)
+	}
+	if active := tracker.ActiveUsers(time.Hour); active != 1 {
+		t.Errorf("Expected 1 active user in the last hour, got %d", active)
+	}
+	clock.Advance(48 * time.Hour)
+	if active := tracker.ActiveUsers(24 * time.Hour); active != 0 {
+		t.Errorf("Expected no active users after two quiet days, got %d", active)
+	}
+}
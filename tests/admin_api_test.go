@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/adminapi"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+func TestAdminClientsListAndDelete(t *testing.T) {
+	clients := newMemoryClients(t)
+	if err := clients.StoreClient(&auth.OAuthClient{
+		ClientID:     "confidential",
+		ClientSecret: "hashed-secret",
+		Metadata:     auth.ClientRegistrationRequest{ClientName: "Dashboard", RedirectURIs: []string{"https://example.com/cb"}},
+	}); err != nil {
+		t.Fatalf("StoreClient failed: %v", err)
+	}
+	handler := adminapi.NewClientsHandler(clients)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/clients", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "hashed-secret") {
+		t.Errorf("Client list exposes the client secret: %s", rec.Body.String())
+	}
+	var listed []adminapi.ClientSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("Failed to decode clients: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ClientName != "Dashboard" || listed[0].Public {
+		t.Errorf("Unexpected clients: %+v", listed)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/clients?client_id=confidential", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if _, err := clients.GetClient("confidential"); err == nil {
+		t.Errorf("Deleted client is still stored")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/clients?client_id=confidential", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown client, got %d", rec.Code)
+	}
+}
+
+func TestAdminClientsListsStaleClients(t *testing.T) {
+	clients := newMemoryClients(t)
+	longAgo := time.Now().Add(-90 * 24 * time.Hour)
+	recently := time.Now().Add(-time.Hour)
+	for _, client := range []*auth.OAuthClient{
+		{ClientID: "abandoned", CreatedAt: longAgo, AutoRegistered: true},
+		{ClientID: "active", CreatedAt: longAgo, LastUsedAt: &recently},
+		{ClientID: "new", CreatedAt: recently},
+	} {
+		if err := clients.StoreClient(client); err != nil {
+			t.Fatalf("StoreClient failed: %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	adminapi.NewClientsHandler(clients).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/clients?stale=true", nil))
+	var listed []adminapi.ClientSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("Failed to decode clients: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ClientID != "abandoned" || !listed[0].Stale || !listed[0].AutoRegistered {
+		t.Errorf("Expected only the abandoned client, got %+v", listed)
+	}
+}
+
+func TestAdminTokenRevocation(t *testing.T) {
+	tokens := newMemoryTokens(t)
+	if err := tokens.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{ClientID: "vscode", ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("StoreAccessToken failed: %v", err)
+	}
+	handler := adminapi.NewTokensHandler(tokens)
+
+	revoke := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/tokens/revoke", strings.NewReader(`{"token":"mcp-token"}`)))
+		return rec.Code
+	}
+	if status := revoke(); status != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", status)
+	}
+	if _, err := tokens.GetAccessToken("mcp-token"); err == nil {
+		t.Errorf("Revoked token is still valid")
+	}
+	if status := revoke(); status != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a revoked token, got %d", status)
+	}
+}
+
+func TestAdminTokenListingAndRevocationByID(t *testing.T) {
+	tokens := newMemoryTokens(t)
+	err := tokens.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Subject:           "octocat",
+		Scope:             "mcp:tools",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("StoreAccessToken failed: %v", err)
+	}
+	handler := adminapi.NewTokensHandler(tokens)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/tokens", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if strings.Contains(rec.Body.String(), "mcp-token") || strings.Contains(rec.Body.String(), "github-token") {
+		t.Errorf("Token listing exposes a token: %s", rec.Body.String())
+	}
+	var listed []adminapi.TokenSummary
+	if err := json.Unmarshal(rec.Body.Bytes(), &listed); err != nil {
+		t.Fatalf("Failed to decode tokens: %v", err)
+	}
+	if len(listed) != 1 || listed[0].ClientID != "vscode" || listed[0].User != "octocat" || listed[0].Scope != "mcp:tools" {
+		t.Fatalf("Unexpected token listing %+v", listed)
+	}
+
+	revoke := func(body string) int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/tokens/revoke", strings.NewReader(body)))
+		return rec.Code
+	}
+	id, _ := json.Marshal(listed[0].ID)
+	if status := revoke(`{"id":` + string(id) + `}`); status != http.StatusNoContent {
+		t.Fatalf("Expected status 204 revoking by ID, got %d", status)
+	}
+	if _, err := tokens.GetAccessToken("mcp-token"); err == nil {
+		t.Errorf("Token revoked by ID is still valid")
+	}
+
+	// JWTs aren't stored, so revoking one must not look like it worked
+	if status := revoke(`{"token":"header.payload.signature"}`); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 revoking a JWT, got %d", status)
+	}
+	if status := revoke(`{}`); status != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without an id or token, got %d", status)
+	}
+}
+
+func TestAdminStaticToken(t *testing.T) {
+	config := auth.DefaultConfig()
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), newMemoryTokens(t))
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name        string
+		staticToken string
+		bearer      string
+		status      int
+	}{
+		{"static token", "operator-secret", "operator-secret", http.StatusOK},
+		{"wrong token", "operator-secret", "guess", http.StatusUnauthorized},
+		{"static token disabled", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := auth.NewMiddleware(config, verifier).RequireAdmin(tt.staticToken)(ok)
+			req := httptest.NewRequest(http.MethodGet, "/admin/sessions", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+		})
+	}
+}
+
+// connectBoundSession connects a client as octocat to server, behind bindings
+func connectBoundSession(t *testing.T, server *mcp.Server, bindings *auth.SessionBindings) *mcp.ClientSession {
+	t.Helper()
+	verifier := func(ctx context.Context, token string, req *http.Request) (*sdkauth.TokenInfo, error) {
+		return &sdkauth.TokenInfo{
+			Scopes:     []string{"mcp:tools"},
+			Expiration: time.Now().Add(time.Hour),
+			Extra:      map[string]any{"subject": "octocat"},
+		}, nil
+	}
+	mcpHandler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	httpServer := httptest.NewServer(sdkauth.RequireBearerToken(verifier, nil)(bindings.Middleware(mcpHandler)))
+	t.Cleanup(httpServer.Close)
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint: httpServer.URL,
+		HTTPClient: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("Authorization", "Bearer mcp-token")
+			return http.DefaultTransport.RoundTrip(r)
+		})},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+func TestAdminSessionsListAndDisconnect(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	bindings := auth.NewSessionBindings(30 * time.Minute)
+	session := connectBoundSession(t, server, bindings)
+
+	handler := adminapi.NewSessionsHandler(bindings, server)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/sessions", nil))
+	var sessions []auth.BoundSession
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("Failed to decode sessions: %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != session.ID() || sessions[0].User != "octocat" {
+		t.Fatalf("Unexpected sessions: %+v", sessions)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/sessions?id="+session.ID(), nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if err := session.Ping(context.Background(), nil); err == nil {
+		t.Errorf("Disconnected session still answers pings")
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/sessions?id="+session.ID(), nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for a disconnected session, got %d", rec.Code)
+	}
+}
+
+func TestAdminSessionsIncludePersonaSessions(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	persona := mcp.NewServer(&mcp.Implementation{Name: "test-ops", Version: "1.0.0"}, nil)
+	bindings := auth.NewSessionBindings(30 * time.Minute)
+	mainSession := connectBoundSession(t, server, bindings)
+	personaSession := connectBoundSession(t, persona, bindings)
+
+	handler := adminapi.NewSessionsHandler(bindings, server, persona)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/sessions", nil))
+	var sessions []auth.BoundSession
+	if err := json.Unmarshal(rec.Body.Bytes(), &sessions); err != nil {
+		t.Fatalf("Failed to decode sessions: %v", err)
+	}
+	listed := make(map[string]bool)
+	for _, session := range sessions {
+		listed[session.ID] = true
+	}
+	if len(sessions) != 2 || !listed[mainSession.ID()] || !listed[personaSession.ID()] {
+		t.Fatalf("Expected the main and persona sessions, got %+v", sessions)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/sessions?id="+personaSession.ID(), nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204 for the persona session, got %d", rec.Code)
+	}
+	if err := personaSession.Ping(context.Background(), nil); err == nil {
+		t.Errorf("Disconnected persona session still answers pings")
+	}
+	if err := mainSession.Ping(context.Background(), nil); err != nil {
+		t.Errorf("Expected the main session to stay connected, got %v", err)
+	}
+}
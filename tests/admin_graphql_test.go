@@ -0,0 +1,88 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/adminapi"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// graphQLResponse is the standard GraphQL-over-HTTP response
+type graphQLResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string   `json:"message"`
+		Path    []string `json:"path"`
+	} `json:"errors"`
+}
+
+// queryGraphQL runs query against handler as octocat with scopes
+func queryGraphQL(t *testing.T, handler http.Handler, scopes []string, query string) graphQLResponse {
+	t.Helper()
+	verifier := func(ctx context.Context, token string, req *http.Request) (*auth.TokenInfo, error) {
+		return &auth.TokenInfo{
+			Scopes:     scopes,
+			Expiration: time.Now().Add(time.Hour),
+			Extra:      map[string]any{"subject": "octocat"},
+		}, nil
+	}
+
+	body, _ := json.Marshal(map[string]string{"query": query})
+	req := httptest.NewRequest(http.MethodPost, "/admin/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Authorization", "Bearer token")
+	rec := httptest.NewRecorder()
+	auth.RequireBearerToken(verifier, nil)(handler).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response graphQLResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode GraphQL response: %v", err)
+	}
+	return response
+}
+
+func TestGraphQLFieldAuthorization(t *testing.T) {
+	tracker := activity.New()
+	tracker.RecordToolCall("octocat", "get-fortune")
+	tracker.RecordToolCall("hubot", "tail-logs")
+
+	schema, err := adminapi.NewSchema(adminapi.Sources{
+		Clients:  newMemoryClientsWithDefaults(t),
+		Activity: tracker,
+	})
+	if err != nil {
+		t.Fatalf("NewSchema failed: %v", err)
+	}
+	handler := adminapi.NewHandler(schema)
+	query := `{ me { login toolCalls timeline(bucket: "day") { toolCalls { tool calls } } } clients { clientId public } }`
+
+	// Without mcp:admin, the caller's own activity resolves and admin fields fail individually
+	response := queryGraphQL(t, handler, []string{"mcp:tools"}, query)
+	if !strings.Contains(string(response.Data["me"]), `"login":"octocat"`) || !strings.Contains(string(response.Data["me"]), `"tool":"get-fortune"`) {
+		t.Errorf("Expected octocat's activity, got %s", response.Data["me"])
+	}
+	if string(response.Data["clients"]) != "null" || len(response.Errors) != 1 || response.Errors[0].Path[0] != "clients" {
+		t.Errorf("Expected clients to be denied, got %s with errors %+v", response.Data["clients"], response.Errors)
+	}
+
+	response = queryGraphQL(t, handler, []string{"mcp:tools", "mcp:admin"}, `{ clients { clientId public } usage { login } }`)
+	if len(response.Errors) != 0 {
+		t.Fatalf("Unexpected errors for an admin: %+v", response.Errors)
+	}
+	if string(response.Data["clients"]) != `[{"clientId":"vscode","public":true}]` {
+		t.Errorf("Unexpected clients %s", response.Data["clients"])
+	}
+	if !strings.Contains(string(response.Data["usage"]), "hubot") {
+		t.Errorf("Expected every user in usage, got %s", response.Data["usage"])
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// grantedScope signs login in through the callback asking for scope and returns the scope of
+// the authorization code issued
+func grantedScope(t *testing.T, config *auth.Config, login, scope string) string {
+	t.Helper()
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/user":
+			_ = json.NewEncoder(w).Encode(map[string]any{"login": login, "id": 1})
+		case "/login/oauth/access_token":
+			_, _ = fmt.Fprint(w, `{"access_token":"gh-token","token_type":"bearer"}`)
+		case "/orgs/acme/teams/platform/memberships/" + login:
+			if login == "monalisa" {
+				_, _ = fmt.Fprint(w, `{"state":"active"}`)
+				return
+			}
+			fallthrough
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"message":"Not Found"}`)
+		}
+	}))
+	t.Cleanup(github.Close)
+	config.GitHubTokenURL = github.URL + "/login/oauth/access_token"
+	config.GitHubAPIURL = github.URL
+
+	states := newStateStore(t, 0)
+	tokens := newMemoryTokens(t)
+	callback := auth.NewCallbackHandler(config, states, tokens)
+	if err := states.Store("state", &auth.AuthState{
+		ClientID:    "vscode",
+		RedirectURI: "http://127.0.0.1:33418",
+		Scope:       scope,
+		State:       "client-state",
+		CreatedAt:   time.Now(),
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	callback.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/callback?code=github-code&state=state", nil))
+	location, _ := url.Parse(rec.Header().Get("Location"))
+	info, err := tokens.GetAuthCode(location.Query().Get("code"))
+	if rec.Code != http.StatusFound || err != nil {
+		t.Fatalf("Expected an authorization code, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	return info.Scope
+}
+
+func TestAdminScopeOnlyGrantedToAdmins(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.AdminGitHubUsers = []string{"OctoCat"}
+	config.AdminGitHubTeams = []string{"acme/platform"}
+
+	if scope := grantedScope(t, config, "octocat", "mcp:tools mcp:admin"); scope != "mcp:tools mcp:admin" {
+		t.Errorf("Expected a listed admin to be granted mcp:admin, got %q", scope)
+	}
+	if scope := grantedScope(t, config, "monalisa", "mcp:tools mcp:admin"); scope != "mcp:tools mcp:admin" {
+		t.Errorf("Expected an admin team member to be granted mcp:admin, got %q", scope)
+	}
+	if scope := grantedScope(t, config, "hubot", "mcp:tools mcp:admin"); scope != "mcp:tools" {
+		t.Errorf("Expected mcp:admin to be dropped for other users, got %q", scope)
+	}
+}
+
+func TestAdminScopeGrantedToNobodyByDefault(t *testing.T) {
+	config := auth.DefaultConfig()
+
+	if scope := grantedScope(t, config, "octocat", "mcp:admin mcp:tools"); scope != "mcp:tools" {
+		t.Errorf("Expected mcp:admin to be dropped without ADMIN_GITHUB_USERS or ADMIN_GITHUB_TEAMS, got %q", scope)
+	}
+}
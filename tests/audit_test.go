@@ -0,0 +1,298 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/adminapi"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/audit"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/logfile"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// useAuditStore points the default audit log at store for the rest of the test
+func useAuditStore(t *testing.T, store audit.Store) {
+	t.Helper()
+	audit.Default.SetStore(store)
+	t.Cleanup(func() { audit.Default.SetStore(audit.NewMemoryStore(100)) })
+}
+
+func TestTokenEventsAreAudited(t *testing.T) {
+	useAuditStore(t, audit.NewMemoryStore(100))
+
+	config := auth.DefaultConfig()
+	clients := newMemoryClients(t)
+	tokens := newMemoryTokens(t)
+	clientID, clientSecret := registerServiceClient(t, config, clients, "mcp:tools")
+	config.ClientCredentialsClients = []string{clientID}
+
+	rec := requestClientCredentialsToken(auth.NewTokenEndpointHandler(config, clients, tokens), clientID, clientSecret, "mcp:tools")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Token request returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &token)
+
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), tokens)
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	if _, err := verifier.Verify(context.Background(), "unknown-token", req); err == nil {
+		t.Fatalf("Expected an unknown token to fail verification")
+	}
+
+	rec = httptest.NewRecorder()
+	adminapi.NewTokensHandler(tokens).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/tokens/revoke",
+		strings.NewReader(`{"token":"`+token.AccessToken+`"}`)))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Revocation returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	events, _ := audit.Default.Query(audit.Filter{})
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 audit events, got %+v", events)
+	}
+	revoked, failed, issued := events[0], events[1], events[2]
+	if issued.Type != audit.TokenIssued || issued.ClientID != clientID || issued.User != auth.ServiceClientSubject(clientID) ||
+		issued.GrantType != "client_credentials" || issued.IP != "192.0.2.1" {
+		t.Errorf("Unexpected issuance event %+v", issued)
+	}
+	if failed.Type != audit.ValidationFailed || failed.IP != "192.0.2.1" || !strings.Contains(failed.Detail, "not found") {
+		t.Errorf("Unexpected validation failure event %+v", failed)
+	}
+	if revoked.Type != audit.TokenRevoked || revoked.ClientID != clientID {
+		t.Errorf("Unexpected revocation event %+v", revoked)
+	}
+}
+
+func TestAuditHandlerFilters(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	log := audit.New(audit.NewMemoryStore(100))
+	log.SetClock(clock)
+	for _, e := range []audit.Event{
+		{Type: audit.TokenIssued, ClientID: "vscode", User: "octocat"},
+		{Type: audit.TokenIssued, ClientID: "cursor", User: "hubot"},
+		{Type: audit.TokenRevoked, ClientID: "vscode"},
+		{Type: audit.TokenIssued, ClientID: "vscode", User: "octocat"},
+	} {
+		log.Record(nil, e)
+		clock.Advance(time.Hour)
+	}
+
+	handler := audit.NewHandler(log)
+	query := func(params string) []audit.Event {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/audit?"+params, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Query %q returned %d: %s", params, rec.Code, rec.Body.String())
+		}
+		var response struct {
+			Events []audit.Event `json:"events"`
+		}
+		_ = json.Unmarshal(rec.Body.Bytes(), &response)
+		return response.Events
+	}
+
+	if events := query("user=octocat"); len(events) != 2 || !events[0].Time.Equal(propertyEpoch.Add(3*time.Hour)) {
+		t.Errorf("Expected octocat's two issuances, newest first, got %+v", events)
+	}
+	if events := query("client_id=vscode&type=token_revoked"); len(events) != 1 {
+		t.Errorf("Expected one revocation for vscode, got %+v", events)
+	}
+	if events := query("since=" + propertyEpoch.Add(90*time.Minute).Format(time.RFC3339) + "&limit=1"); len(events) != 1 || events[0].Type != audit.TokenIssued {
+		t.Errorf("Expected the latest event only, got %+v", events)
+	}
+	for _, params := range []string{"since=yesterday", "limit=0", "limit=100000"} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/audit?"+params, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %q, got %d", params, rec.Code)
+		}
+	}
+}
+
+func TestAuditFileStoreQueriesRotatedFiles(t *testing.T) {
+	file, err := logfile.OpenWithOptions(filepath.Join(t.TempDir(), "audit.log"), logfile.Options{Compress: true})
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	store := audit.NewFileStore(file)
+
+	_ = store.Append(audit.Event{Type: audit.TokenIssued, ClientID: "vscode", User: "octocat"})
+	if err := file.Rotate(); err != nil {
+		t.Fatalf("Failed to rotate audit log: %v", err)
+	}
+	_ = store.Append(audit.Event{Type: audit.TokenRevoked, ClientID: "vscode"})
+
+	// Compression happens in the background after rotation
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		backups, _ := file.Backups()
+		if len(backups) == 1 && strings.HasSuffix(backups[0], ".gz") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Rotated audit log was not compressed: %v", backups)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	events, err := store.Query(audit.Filter{ClientID: "vscode"})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != audit.TokenRevoked || events[1].User != "octocat" {
+		t.Errorf("Expected both events, newest first, got %+v", events)
+	}
+}
+
+func TestAuditWebhookDeliversSignedEvents(t *testing.T) {
+	type delivery struct {
+		header http.Header
+		body   []byte
+	}
+	deliveries := make(chan delivery, 10)
+	attempts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		// The first attempt fails, so the event is retried
+		if attempts++; attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		deliveries <- delivery{header: r.Header.Clone(), body: body}
+	}))
+	defer receiver.Close()
+
+	t.Setenv("AUDIT_WEBHOOK_URLS", receiver.URL)
+	t.Setenv("AUDIT_WEBHOOK_SECRET", "s3cret")
+	webhooks, err := audit.WebhooksFromEnv()
+	if err != nil || len(webhooks) != 1 {
+		t.Fatalf("Expected one webhook, got %v, %v", webhooks, err)
+	}
+	log := audit.New(audit.NewMemoryStore(10))
+	log.AddHook(webhooks[0])
+
+	// Failed validations aren't sent by default
+	log.Record(nil, audit.Event{Type: audit.ValidationFailed})
+	log.Record(nil, audit.Event{Type: audit.TokenIssued, ClientID: "vscode", User: "octocat"})
+
+	var got delivery
+	select {
+	case got = <-deliveries:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Webhook was not delivered")
+	}
+	timestamp := got.header.Get(audit.WebhookTimestampHeader)
+	if signature := got.header.Get(audit.WebhookSignatureHeader); signature != "sha256="+audit.SignWebhook([]byte("s3cret"), timestamp, got.body) {
+		t.Errorf("Signature %q doesn't match the body", signature)
+	}
+	if got.header.Get(audit.WebhookIDHeader) == "" {
+		t.Errorf("Expected a delivery ID")
+	}
+	var event audit.Event
+	if err := json.Unmarshal(got.body, &event); err != nil || event.Type != audit.TokenIssued || event.User != "octocat" {
+		t.Errorf("Unexpected event %s", got.body)
+	}
+	select {
+	case extra := <-deliveries:
+		t.Errorf("Expected only the issuance to be sent, also got %s", extra.body)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAuditWebhooksFromEnvValidates(t *testing.T) {
+	for _, env := range []map[string]string{
+		{"AUDIT_WEBHOOK_URLS": "https://siem.example.com/hook"},
+		{"AUDIT_WEBHOOK_URLS": "siem.example.com/hook", "AUDIT_WEBHOOK_SECRET": "s"},
+		{"AUDIT_WEBHOOK_URLS": "https://siem.example.com/hook", "AUDIT_WEBHOOK_SECRET": "s", "AUDIT_WEBHOOK_EVENTS": "token_refreshed"},
+	} {
+		t.Setenv("AUDIT_WEBHOOK_URLS", "")
+		t.Setenv("AUDIT_WEBHOOK_SECRET", "")
+		t.Setenv("AUDIT_WEBHOOK_EVENTS", "")
+		for name, value := range env {
+			t.Setenv(name, value)
+		}
+		if _, err := audit.WebhooksFromEnv(); err == nil {
+			t.Errorf("Expected an error for %v", env)
+		}
+	}
+}
+
+func TestDetectorRaisesAnomaliesOncePerWindow(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	log := audit.New(audit.NewMemoryStore(100))
+	log.SetClock(clock)
+	log.AddHook(audit.NewDetector(log, audit.Thresholds{Window: time.Minute, PKCEFailures: 3, UnknownClientsPerIP: 2}))
+
+	anomalies := func() []audit.Event {
+		events, _ := log.Query(audit.Filter{Type: audit.AnomalyDetected})
+		return events
+	}
+	fromIP := func(ip string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/token", nil)
+		req.RemoteAddr = ip + ":40000"
+		return req
+	}
+
+	// Failures spread out beyond the window don't add up
+	for range 3 {
+		log.Record(nil, audit.Event{Type: audit.PKCEFailed})
+		clock.Advance(31 * time.Second)
+	}
+	if got := anomalies(); len(got) != 0 {
+		t.Fatalf("Expected no anomaly for spread out failures, got %+v", got)
+	}
+
+	for range 5 {
+		log.Record(nil, audit.Event{Type: audit.PKCEFailed})
+	}
+	if got := anomalies(); len(got) != 1 || !strings.HasPrefix(got[0].Detail, "pkce_failure_spike") {
+		t.Fatalf("Expected one PKCE anomaly, got %+v", got)
+	}
+
+	// Unknown clients are counted per IP
+	log.Record(fromIP("198.51.100.1"), audit.Event{Type: audit.UnknownClient, ClientID: "guess-1"})
+	log.Record(fromIP("198.51.100.2"), audit.Event{Type: audit.UnknownClient, ClientID: "guess-2"})
+	if got := anomalies(); len(got) != 1 {
+		t.Fatalf("Expected single failures from two IPs not to raise an anomaly, got %+v", got)
+	}
+	log.Record(fromIP("198.51.100.1"), audit.Event{Type: audit.UnknownClient, ClientID: "guess-3"})
+	if got := anomalies(); len(got) != 2 || !strings.Contains(got[0].Detail, "198.51.100.1") {
+		t.Fatalf("Expected an unknown client anomaly for 198.51.100.1, got %+v", got)
+	}
+
+	// Once the window passes, an ongoing attack raises a new anomaly
+	clock.Advance(time.Minute)
+	for range 3 {
+		log.Record(nil, audit.Event{Type: audit.PKCEFailed})
+	}
+	if got := anomalies(); len(got) != 3 {
+		t.Errorf("Expected another PKCE anomaly after the window, got %+v", got)
+	}
+}
+
+func TestTokenEndpointAuditsUnknownClients(t *testing.T) {
+	useAuditStore(t, audit.NewMemoryStore(100))
+
+	config := auth.DefaultConfig()
+	handler := auth.NewTokenEndpointHandler(config, newMemoryClients(t), newMemoryTokens(t))
+	if rec := requestClientCredentialsToken(handler, "unknown", "secret", ""); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected 401 for an unknown client, got %d", rec.Code)
+	}
+
+	events, _ := audit.Default.Query(audit.Filter{Type: audit.UnknownClient})
+	if len(events) != 1 || events[0].ClientID != "unknown" || events[0].IP != "192.0.2.1" {
+		t.Errorf("Expected the unknown client to be audited, got %+v", events)
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	b := breaker.New("test-recovers", 2, time.Minute)
+	b.SetClock(clock)
+
+	upstreamErr := errors.New("upstream down")
+	for i := 0; i < 2; i++ {
+		if err := b.Do(func() error { return upstreamErr }); !errors.Is(err, upstreamErr) {
+			t.Fatalf("Expected upstream error, got %v", err)
+		}
+	}
+
+	if b.Status().State != breaker.StateOpen {
+		t.Fatalf("Expected breaker to be open, got %s", b.Status().State)
+	}
+	if err := b.Allow(); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("Expected ErrOpen while open, got %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Expected a trial call after the cooldown, got %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, breaker.ErrOpen) {
+		t.Errorf("Only one trial call should be allowed while half-open, got %v", err)
+	}
+
+	b.Success()
+	if b.Status().State != breaker.StateClosed {
+		t.Errorf("Expected breaker to close after a successful trial, got %s", b.Status().State)
+	}
+}
+
+func TestBreakerStatusHandlerResets(t *testing.T) {
+	b := breaker.New("test-reset", 1, time.Hour)
+	b.Failure(errors.New("upstream down"))
+
+	handler := breaker.NewStatusHandler(breaker.Default)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/circuit-breakers?name=test-reset", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var statuses []breaker.Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("Failed to decode statuses: %v", err)
+	}
+	for _, status := range statuses {
+		if status.Name == "test-reset" && status.State != breaker.StateClosed {
+			t.Errorf("Expected reset breaker to be closed, got %s", status.State)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/circuit-breakers?name=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown breaker, got %d", rec.Code)
+	}
+}
@@ -6,7 +6,7 @@ import (
 	"strings"
 	"testing"
 
-	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func compoundInterest(t *testing.T, params *tools.CalculateCompoundInterestParams) string {
+	t.Helper()
+	tool := tools.CalculateCompoundInterest{}
+	result, _, err := tool.Action(context.TODO(), &mcp.CallToolRequest{}, params)
+	if err != nil {
+		t.Fatalf("Calling calculate-compound-interest resulted in an error: %v", err)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func TestCompoundInterestAnnually(t *testing.T) {
+	text := compoundInterest(t, &tools.CalculateCompoundInterestParams{
+		Principal:   1000,
+		AnnualRate:  10,
+		Compounding: "annually",
+		Years:       2,
+	})
+
+	if !strings.Contains(text, "grows to $1210.00 after 2 years") {
+		t.Errorf("Expected $1000 at 10%% annually to reach $1210.00, got %q", text)
+	}
+	for _, row := range []string{"1 | $0.00 | $100.00 | $1100.00", "2 | $0.00 | $110.00 | $1210.00"} {
+		if !strings.Contains(text, row) {
+			t.Errorf("Expected breakdown row %q, got %q", row, text)
+		}
+	}
+}
+
+func TestCompoundInterestWithContributions(t *testing.T) {
+	text := compoundInterest(t, &tools.CalculateCompoundInterestParams{
+		Principal:           0,
+		AnnualRate:          12,
+		MonthlyContribution: 100,
+		Years:               1,
+	})
+
+	// Twelve $100 deposits at 1% a month, each made at the end of the month: 100 * (1.01^12 - 1) / 0.01
+	if !strings.Contains(text, "grows to $1268.25 after 1 year: $1200.00 in contributions and $68.25 in interest") {
+		t.Errorf("Unexpected monthly compounding result: %q", text)
+	}
+}
+
+func TestCompoundInterestValidation(t *testing.T) {
+	tool := tools.CalculateCompoundInterest{}
+	for name, params := range map[string]*tools.CalculateCompoundInterestParams{
+		"negative principal": {Principal: -1, AnnualRate: 5, Years: 1},
+		"no years":           {Principal: 100, AnnualRate: 5},
+		"too many years":     {Principal: 100, AnnualRate: 5, Years: 101},
+		"unknown frequency":  {Principal: 100, AnnualRate: 5, Years: 1, Compounding: "hourly"},
+	} {
+		if _, _, err := tool.Action(context.TODO(), &mcp.CallToolRequest{}, params); err == nil {
+			t.Errorf("Expected an error for %s", name)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+func TestClientConfigIncludesAuthHints(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.ServerURL = "https://mcp.example.com"
+	config.OAuthEnabled = true
+	config.EnableDCR = true
+
+	clientConfig := auth.NewClientConfig(config)
+	if clientConfig.Auth == nil {
+		t.Fatal("Expected auth hints when OAuth is enabled")
+	}
+	if clientConfig.Auth.ResourceMetadataURL != "https://mcp.example.com/.well-known/oauth-protected-resource" {
+		t.Errorf("Unexpected resource metadata URL %q", clientConfig.Auth.ResourceMetadataURL)
+	}
+	if clientConfig.Auth.RegistrationEndpoint == "" {
+		t.Errorf("Expected the registration endpoint when DCR is enabled")
+	}
+	if len(clientConfig.Auth.PreregisteredClients) == 0 || clientConfig.Auth.PreregisteredClients[0].ClientID != "vscode" {
+		t.Errorf("Expected the pre-registered vscode client, got %+v", clientConfig.Auth.PreregisteredClients)
+	}
+
+	config.OAuthEnabled = false
+	if auth.NewClientConfig(config).Auth != nil {
+		t.Errorf("Expected no auth hints when OAuth is disabled")
+	}
+}
+
+func TestClientConfigHandlerServesSnippets(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.ServerURL = "https://mcp.example.com"
+	handler := auth.NewClientConfigHandler(config)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/client-config?client=vscode", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", rec.Code)
+	}
+	var vscode struct {
+		Servers map[string]struct {
+			Type string `json:"type"`
+			URL  string `json:"url"`
+		} `json:"servers"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&vscode); err != nil {
+		t.Fatalf("Failed to decode mcp.json snippet: %v", err)
+	}
+	if server := vscode.Servers["deployment-project"]; server.Type != "http" || server.URL != "https://mcp.example.com" {
+		t.Errorf("Unexpected mcp.json snippet %+v", vscode)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/client-config?client=emacs", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown client, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// registerServiceClient registers a confidential client limited to client_credentials through DCR
+func registerServiceClient(t *testing.T, config *auth.Config, clients auth.ClientStorage, scope string) (clientID, clientSecret string) {
+	t.Helper()
+	body := `{"client_name":"ci","grant_types":["client_credentials"],"token_endpoint_auth_method":"client_secret_basic","scope":"` + scope + `"}`
+	rec := httptest.NewRecorder()
+	auth.NewRegistrationHandler(config, clients).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Registration returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response auth.ClientRegistrationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode registration response: %v", err)
+	}
+	return response.ClientID, response.ClientSecret
+}
+
+func requestClientCredentialsToken(handler http.Handler, clientID, clientSecret, scope string) *httptest.ResponseRecorder {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, clientSecret)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestClientCredentialsTokenVerifiesAsClient(t *testing.T) {
+	config := auth.DefaultConfig()
+	clients := newMemoryClients(t)
+	tokens := newMemoryTokens(t)
+	clientID, clientSecret := registerServiceClient(t, config, clients, "mcp:tools mcp:resources")
+	config.ClientCredentialsClients = []string{clientID}
+
+	rec := requestClientCredentialsToken(auth.NewTokenEndpointHandler(config, clients, tokens), clientID, clientSecret, "mcp:tools")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Token request returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var response struct {
+		AccessToken string `json:"access_token"`
+		Scope       string `json:"scope"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode token response: %v", err)
+	}
+	if response.Scope != "mcp:tools" {
+		t.Errorf("Expected scope mcp:tools, got %q", response.Scope)
+	}
+
+	// The verifier must not call GitHub for a client token
+	config.GitHubAPIURL = "http://127.0.0.1:0"
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), tokens)
+	info, err := verifier.Verify(context.TODO(), response.AccessToken, nil)
+	if err != nil {
+		t.Fatalf("Verify resulted in an error: %v", err)
+	}
+	if subject := info.Extra["subject"]; subject != "client:"+clientID {
+		t.Errorf("Expected subject client:%s, got %v", clientID, subject)
+	}
+	if len(info.Scopes) != 1 || info.Scopes[0] != "mcp:tools" {
+		t.Errorf("Expected scopes [mcp:tools], got %v", info.Scopes)
+	}
+}
+
+func TestClientCredentialsRejections(t *testing.T) {
+	config := auth.DefaultConfig()
+	clients := newMemoryClientsWithDefaults(t)
+	clientID, clientSecret := registerServiceClient(t, config, clients, "mcp:tools")
+	unlistedID, unlistedSecret := registerServiceClient(t, config, clients, "mcp:tools")
+	config.ClientCredentialsClients = []string{clientID, "vscode"}
+	handler := auth.NewTokenEndpointHandler(config, clients, newMemoryTokens(t))
+
+	tests := []struct {
+		name         string
+		clientID     string
+		clientSecret string
+		scope        string
+		status       int
+		error        string
+	}{
+		{"wrong secret", clientID, "wrong", "", http.StatusUnauthorized, "invalid_client"},
+		{"public client", "vscode", "anything", "", http.StatusUnauthorized, "invalid_client"},
+		{"client not allowed", unlistedID, unlistedSecret, "", http.StatusBadRequest, "unauthorized_client"},
+		{"unregistered scope", clientID, clientSecret, "mcp:tools mcp:resources", http.StatusBadRequest, "invalid_scope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := requestClientCredentialsToken(handler, tt.clientID, tt.clientSecret, tt.scope)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, rec.Code, rec.Body.String())
+			}
+			var response map[string]string
+			_ = json.Unmarshal(rec.Body.Bytes(), &response)
+			if response["error"] != tt.error {
+				t.Errorf("Expected error %s, got %q", tt.error, response["error"])
+			}
+		})
+	}
+}
+
+// clientCredentialsScope requests a client_credentials token and returns its scope
+func clientCredentialsScope(t *testing.T, handler http.Handler, clientID, clientSecret, scope string) string {
+	t.Helper()
+	rec := requestClientCredentialsToken(handler, clientID, clientSecret, scope)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Token request returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var response struct {
+		Scope string `json:"scope"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode token response: %v", err)
+	}
+	return response.Scope
+}
+
+func TestClientCredentialsAdminScopeNeedsOperatorApproval(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.ScopesSupported = append(config.ScopesSupported, "mcp:admin")
+	clients := newMemoryClients(t)
+	clientID, clientSecret := registerServiceClient(t, config, clients, "mcp:tools mcp:admin")
+	config.ClientCredentialsClients = []string{clientID}
+	handler := auth.NewTokenEndpointHandler(config, clients, newMemoryTokens(t))
+
+	if scope := clientCredentialsScope(t, handler, clientID, clientSecret, ""); scope != "mcp:tools" {
+		t.Errorf("Expected mcp:admin to be dropped from the registered scopes, got %q", scope)
+	}
+	if scope := clientCredentialsScope(t, handler, clientID, clientSecret, "mcp:tools mcp:admin"); scope != "mcp:tools" {
+		t.Errorf("Expected mcp:admin to be dropped from the requested scopes, got %q", scope)
+	}
+	if rec := requestClientCredentialsToken(handler, clientID, clientSecret, "mcp:admin"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a request for only mcp:admin to be rejected, got %d", rec.Code)
+	}
+
+	config.ClientCredentialsAdminClients = []string{clientID}
+	if scope := clientCredentialsScope(t, handler, clientID, clientSecret, ""); scope != "mcp:tools mcp:admin" {
+		t.Errorf("Expected a client in CLIENT_CREDENTIALS_ADMIN_CLIENTS to be granted mcp:admin, got %q", scope)
+	}
+}
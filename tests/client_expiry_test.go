@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+func TestClientRegistrationExpiresUnlessUsed(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	config := auth.DefaultConfig()
+	config.Clock = clock
+	clients := newMemoryClients(t)
+	clients.SetClock(clock)
+	clientID, clientSecret := registerServiceClient(t, config, clients, "mcp:tools")
+	config.ClientCredentialsClients = []string{clientID}
+	handler := auth.NewTokenEndpointHandler(config, clients, newMemoryTokens(t))
+
+	client, err := clients.GetClient(clientID)
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+	if client.ExpiresAt == nil || !client.ExpiresAt.Equal(propertyEpoch.Add(config.ClientTTL)) {
+		t.Fatalf("Expected the registration to expire after %s, got %v", config.ClientTTL, client.ExpiresAt)
+	}
+
+	// Being issued a token pushes the expiry back
+	clock.Advance(60 * 24 * time.Hour)
+	if rec := requestClientCredentialsToken(handler, clientID, clientSecret, ""); rec.Code != http.StatusOK {
+		t.Fatalf("Token request returned %d: %s", rec.Code, rec.Body.String())
+	}
+	clock.Advance(60 * 24 * time.Hour)
+	client, err = clients.GetClient(clientID)
+	if err != nil {
+		t.Fatalf("Expected a recently used client to be kept: %v", err)
+	}
+	if client.LastUsedAt == nil || !client.LastUsedAt.Equal(propertyEpoch.Add(60*24*time.Hour)) {
+		t.Errorf("Expected the last use to be recorded, got %v", client.LastUsedAt)
+	}
+
+	clock.Advance(31 * 24 * time.Hour)
+	if _, err := clients.GetClient(clientID); err == nil {
+		t.Fatalf("Expected the unused client to have expired")
+	}
+	if rec := requestClientCredentialsToken(handler, clientID, clientSecret, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected an expired client to be rejected, got %d", rec.Code)
+	}
+
+	// Once swept, the client is gone even if the clock were turned back
+	clients.Sweep()
+	clients.SetClock(testsupport.NewFakeClock(propertyEpoch))
+	if _, err := clients.GetClient(clientID); err == nil {
+		t.Errorf("Expected the sweep to delete the expired client")
+	}
+}
+
+func TestClientTTLZeroKeepsRegistrations(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.ClientTTL = 0
+	clients := newMemoryClients(t)
+	clientID, _ := registerServiceClient(t, config, clients, "mcp:tools")
+
+	client, err := clients.GetClient(clientID)
+	if err != nil {
+		t.Fatalf("GetClient failed: %v", err)
+	}
+	if client.ExpiresAt != nil {
+		t.Errorf("Expected no expiry with CLIENT_TTL_SECONDS=0, got %v", client.ExpiresAt)
+	}
+}
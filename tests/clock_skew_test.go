@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+func TestStorageToleratesClockSkew(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	config := auth.DefaultConfig()
+	config.Clock = clock
+	config.ClockSkew = 30 * time.Second
+	storage, err := auth.NewStorage(context.Background(), config)
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	defer storage.Close()
+
+	expiresAt := propertyEpoch.Add(time.Minute)
+	_ = storage.Tokens.StoreAccessToken("token", &auth.AccessTokenInfo{ClientID: "vscode", ExpiresAt: expiresAt, GrantType: "client_credentials"})
+	_ = storage.Tokens.StoreAuthCode("code", &auth.AuthCodeInfo{ClientID: "vscode", ExpiresAt: expiresAt})
+	_ = storage.States.Store("state", &auth.AuthState{ClientID: "vscode", CreatedAt: propertyEpoch.Add(time.Minute - config.AuthStateTTL)})
+
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), storage.Tokens)
+
+	// Within the tolerance everything is still accepted, and the SDK is told so
+	clock.Set(expiresAt.Add(20 * time.Second))
+	info, err := verifier.Verify(context.Background(), "token", nil)
+	if err != nil {
+		t.Fatalf("Expected the token to verify within the skew tolerance: %v", err)
+	}
+	if !info.Expiration.Equal(expiresAt.Add(config.ClockSkew)) {
+		t.Errorf("Expected the reported expiration to include the tolerance, got %v", info.Expiration)
+	}
+	if _, err := storage.Tokens.GetAuthCode("code"); err != nil {
+		t.Errorf("Expected the authorization code to be accepted within the skew tolerance: %v", err)
+	}
+	if _, ok := storage.States.Get("state"); !ok {
+		t.Errorf("Expected the state to be accepted within the skew tolerance")
+	}
+
+	clock.Set(expiresAt.Add(31 * time.Second))
+	if _, err := verifier.Verify(context.Background(), "token", nil); err == nil {
+		t.Errorf("Expected the token to expire past the skew tolerance")
+	}
+	if _, err := storage.Tokens.GetAuthCode("code"); err == nil {
+		t.Errorf("Expected the authorization code to expire past the skew tolerance")
+	}
+	if _, ok := storage.States.Get("state"); ok {
+		t.Errorf("Expected the state to expire past the skew tolerance")
+	}
+}
+
+func TestJWTLeeway(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	issuer := newTestJWTIssuer(t, clock)
+	issuer.SetLeeway(30 * time.Second)
+
+	token, err := issuer.Issue(&auth.AccessTokenInfo{
+		ClientID:  "vscode",
+		Scope:     "mcp:tools",
+		ExpiresAt: propertyEpoch.Add(time.Hour),
+		CreatedAt: propertyEpoch,
+	}, "octocat")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+
+	clock.Set(propertyEpoch.Add(time.Hour + 20*time.Second))
+	if _, err := issuer.Verify(token); err != nil {
+		t.Errorf("Expected the token to verify within the leeway: %v", err)
+	}
+	clock.Set(propertyEpoch.Add(time.Hour + 40*time.Second))
+	if _, err := issuer.Verify(token); err == nil {
+		t.Errorf("Expected the token to expire past the leeway")
+	}
+}
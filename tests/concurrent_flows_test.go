@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// These tests are most useful under the race detector: go test -race ./tests/
+
+func TestParallelAuthorizationFlows(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"gh-%s","token_type":"bearer"}`, r.FormValue("code"))
+	}))
+	defer github.Close()
+
+	config := auth.DefaultConfig()
+	config.GitHubTokenURL = github.URL
+	clients := newMemoryClientsWithDefaults(t)
+	tokens := newMemoryTokens(t)
+	authorize := auth.NewAuthorizationHandlerWithStateStorage(config, clients, newStateStore(t, config.AuthStateTTL))
+	callback := auth.NewCallbackHandler(config, authorize.GetStateStore(), tokens)
+	token := auth.NewTokenEndpointHandler(config, clients, tokens)
+
+	const flows = 25
+	var wg sync.WaitGroup
+	errs := make(chan error, flows)
+	for i := range flows {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codeVerifier := strings.Repeat(fmt.Sprintf("%02d", i), 22)
+
+			query := url.Values{}
+			query.Set("response_type", "code")
+			query.Set("client_id", "vscode")
+			query.Set("redirect_uri", "http://127.0.0.1:33418")
+			query.Set("code_challenge", pkceChallenge(codeVerifier))
+			query.Set("code_challenge_method", "S256")
+			rec := httptest.NewRecorder()
+			authorize.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query.Encode(), nil))
+			location, _ := url.Parse(rec.Header().Get("Location"))
+			if rec.Code != http.StatusFound || location == nil {
+				errs <- fmt.Errorf("flow %d: authorize returned %d", i, rec.Code)
+				return
+			}
+
+			callbackQuery := url.Values{}
+			callbackQuery.Set("code", fmt.Sprintf("github-code-%d", i))
+			callbackQuery.Set("state", location.Query().Get("state"))
+			rec = httptest.NewRecorder()
+			callback.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/callback?"+callbackQuery.Encode(), nil))
+			location, _ = url.Parse(rec.Header().Get("Location"))
+			if rec.Code != http.StatusFound || location == nil || location.Query().Get("code") == "" {
+				errs <- fmt.Errorf("flow %d: callback returned %d: %s", i, rec.Code, rec.Body.String())
+				return
+			}
+
+			if status := redeemCode(token, location.Query().Get("code"), codeVerifier); status != http.StatusOK {
+				errs <- fmt.Errorf("flow %d: token endpoint returned %d", i, status)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if codes, issued := tokens.Len(); codes != 0 || issued != flows {
+		t.Errorf("Expected 0 codes and %d tokens after the flows, got %d and %d", flows, codes, issued)
+	}
+}
+
+func TestConcurrentRedemptionsOfOneCode(t *testing.T) {
+	for _, backend := range tokenStorageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			clock := testsupport.NewFakeClock(propertyEpoch)
+			storage := backend.new(t, clock)
+			config := auth.DefaultConfig()
+			config.Clock = clock
+			handler := auth.NewTokenEndpointHandler(config, newMemoryClientsWithDefaults(t), storage)
+
+			codeVerifier := strings.Repeat("v", 43)
+			err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{
+				ClientID:            "vscode",
+				RedirectURI:         "http://127.0.0.1:33418",
+				Scope:               "mcp:tools",
+				CodeChallenge:       pkceChallenge(codeVerifier),
+				CodeChallengeMethod: "S256",
+				GitHubAccessToken:   "github-token",
+				ExpiresAt:           clock.Now().Add(10 * time.Minute),
+				CreatedAt:           clock.Now(),
+			})
+			if err != nil {
+				t.Fatalf("Failed to store auth code: %v", err)
+			}
+
+			const redemptions = 20
+			var wg sync.WaitGroup
+			statuses := make(chan int, redemptions)
+			for range redemptions {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					statuses <- redeemCode(handler, "code", codeVerifier)
+				}()
+			}
+			wg.Wait()
+			close(statuses)
+
+			succeeded := 0
+			for status := range statuses {
+				if status == http.StatusOK {
+					succeeded++
+				}
+			}
+			if succeeded != 1 {
+				t.Errorf("Expected exactly one redemption to succeed, got %d", succeeded)
+			}
+		})
+	}
+}
+
+func TestInMemoryStoresSweepConcurrently(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	states := newStateStore(t, 10*time.Minute)
+	states.SetClock(clock)
+	tokens := newMemoryTokens(t)
+	tokens.SetClock(clock)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			key := fmt.Sprintf("key-%d", i)
+			_ = states.Store(key, &auth.AuthState{CreatedAt: clock.Now()})
+			_ = tokens.StoreAuthCode(key, &auth.AuthCodeInfo{ExpiresAt: clock.Now().Add(time.Minute)})
+			_ = tokens.StoreAccessToken(key, &auth.AccessTokenInfo{ExpiresAt: clock.Now().Add(time.Hour)})
+			states.Get(key)
+			_, _ = tokens.GetAuthCode(key)
+			_, _ = tokens.GetAccessToken(key)
+			if i%2 == 0 {
+				_ = states.Delete(key)
+				_ = tokens.DeleteAuthCode(key)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			states.Sweep()
+			tokens.Sweep()
+		}()
+	}
+	wg.Wait()
+
+	if states.Len() != 25 {
+		t.Errorf("Expected 25 states, got %d", states.Len())
+	}
+
+	// Expired entries stay until a sweep, since writes no longer clean up
+	clock.Advance(11 * time.Minute)
+	_ = states.Store("fresh", &auth.AuthState{CreatedAt: clock.Now()})
+	if states.Len() != 26 {
+		t.Errorf("Expected expired states to stay until swept, got %d states", states.Len())
+	}
+	states.Sweep()
+	tokens.Sweep()
+	if states.Len() != 1 {
+		t.Errorf("Expected only the fresh state after sweeping, got %d", states.Len())
+	}
+	if codes, issued := tokens.Len(); codes != 0 || issued != 50 {
+		t.Errorf("Expected 0 codes and 50 tokens after sweeping, got %d and %d", codes, issued)
+	}
+
+	clock.Advance(time.Hour)
+	tokens.Sweep()
+	if codes, issued := tokens.Len(); codes != 0 || issued != 0 {
+		t.Errorf("Expected everything swept, got %d codes and %d tokens", codes, issued)
+	}
+}
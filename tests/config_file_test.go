@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// writeConfigFile writes a config file named name and returns its path
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return path
+}
+
+// unsetEnv unsets names for the rest of the test, and whatever a config file set along with them
+func unsetEnv(t *testing.T, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		t.Setenv(name, "")
+		_ = os.Unsetenv(name)
+	}
+	empty := writeConfigFile(t, "empty.json", "{}")
+	t.Cleanup(func() { _ = auth.ApplyConfigFile(empty) })
+}
+
+func TestConfigFileIsOverriddenByEnv(t *testing.T) {
+	unsetEnv(t, "OAUTH_ENABLED", "OAUTH_SCOPES_SUPPORTED", "TOKEN_EXPIRY_SECONDS", "GITHUB_CLIENT_ID")
+	t.Setenv("GITHUB_CLIENT_ID", "from-env")
+
+	path := writeConfigFile(t, "config.yaml", `
+OAUTH_ENABLED: true
+oauth_scopes_supported:
+  - mcp:tools
+  - read:user
+TOKEN_EXPIRY_SECONDS: 600
+GITHUB_CLIENT_ID: from-file
+`)
+	if err := auth.ApplyConfigFile(path); err != nil {
+		t.Fatalf("ApplyConfigFile failed: %v", err)
+	}
+	config, err := auth.LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	if !config.OAuthEnabled || config.TokenExpiryDuration != 10*time.Minute ||
+		!slices.Equal(config.ScopesSupported, []string{"mcp:tools", "read:user"}) {
+		t.Errorf("Expected the file's settings, got enabled=%v expiry=%v scopes=%v", config.OAuthEnabled, config.TokenExpiryDuration, config.ScopesSupported)
+	}
+	if config.GitHubClientID != "from-env" {
+		t.Errorf("Expected the environment to override the file, got %q", config.GitHubClientID)
+	}
+
+	// Reloading an edited file drops the settings it no longer has
+	if err := os.WriteFile(path, []byte(`{"OAUTH_ENABLED": false}`), 0o600); err != nil {
+		t.Fatalf("Failed to rewrite config file: %v", err)
+	}
+	if err := auth.ApplyConfigFile(path); err != nil {
+		t.Fatalf("ApplyConfigFile failed on reload: %v", err)
+	}
+	if _, set := os.LookupEnv("TOKEN_EXPIRY_SECONDS"); set || os.Getenv("OAUTH_ENABLED") != "false" {
+		t.Errorf("Expected the reloaded file to replace the earlier values")
+	}
+	if os.Getenv("GITHUB_CLIENT_ID") != "from-env" {
+		t.Errorf("Expected the environment value to survive a reload")
+	}
+}
+
+func TestConfigFileValidationNamesKeys(t *testing.T) {
+	unsetEnv(t, "OAUTH_ENABLED", "TOKEN_EXPIRY_SECONDS", "OAUTH_REDIRECT_URIS", "RATE_LIMIT_TOKEN_PER_MINUTE", "ACCESS_LOG_ROTATE_INTERVAL")
+
+	path := writeConfigFile(t, "config.json", `{
+		"OAUTH_ENABLED": "maybe",
+		"TOKEN_EXPIRY_SECONDS": "soon",
+		"OAUTH_REDIRECT_URIS": {"vscode": "https://vscode.dev/redirect"},
+		"RATE_LIMIT_TOKEN_PER_MINUTE": "lots",
+		"ACCESS_LOG_ROTATE_INTERVAL": "daily",
+		"bad key": "x"
+	}`)
+	err := auth.ApplyConfigFile(path)
+	if err == nil {
+		t.Fatalf("Expected an invalid config file to be rejected")
+	}
+	for _, key := range []string{"OAUTH_ENABLED", "TOKEN_EXPIRY_SECONDS", "OAUTH_REDIRECT_URIS", "RATE_LIMIT_TOKEN_PER_MINUTE", "ACCESS_LOG_ROTATE_INTERVAL", `"bad key"`} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("Expected the error to name %s, got %v", key, err)
+		}
+	}
+	if _, set := os.LookupEnv("OAUTH_ENABLED"); set {
+		t.Errorf("Expected nothing to be set from an invalid file")
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+func TestConfigReloadSwapsCredentialsAndScopes(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.GitHubClientID = "old-id"
+	config.GitHubClientSecret = "old-secret"
+
+	next := auth.DefaultConfig()
+	next.GitHubClientID = "old-id"
+	next.GitHubClientSecret = "new-secret"
+	next.ScopesSupported = []string{"mcp:tools"}
+	next.AllowedRedirectURIs = append(next.AllowedRedirectURIs, "https://editor.example.com/callback")
+
+	changed := config.Reload(next)
+	if !slices.Equal(changed, []string{"GITHUB_CLIENT_SECRET", "OAUTH_REDIRECT_URIS", "OAUTH_SCOPES_SUPPORTED"}) {
+		t.Errorf("Unexpected changed settings %v", changed)
+	}
+	if id, secret := config.GitHubCredentials(); id != "old-id" || secret != "new-secret" {
+		t.Errorf("Expected the rotated secret, got %q, %q", id, secret)
+	}
+	if !config.IsRedirectURIAllowed("https://editor.example.com/callback") {
+		t.Errorf("Expected the new redirect URI to be allowed")
+	}
+	if config.IsScopeSupported("mcp:resources") {
+		t.Errorf("Expected mcp:resources to no longer be supported")
+	}
+
+	rec := httptest.NewRecorder()
+	auth.NewAuthServerMetadataHandler(config).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-authorization-server", nil))
+	var metadata struct {
+		ScopesSupported []string `json:"scopes_supported"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &metadata)
+	if !slices.Equal(metadata.ScopesSupported, []string{"mcp:tools"}) {
+		t.Errorf("Expected metadata to advertise the reloaded scopes, got %v", metadata.ScopesSupported)
+	}
+
+	if changed := config.Reload(next); len(changed) != 0 {
+		t.Errorf("Expected nothing to change on an identical reload, got %v", changed)
+	}
+}
+
+func TestConfigReloadFromEnvKeepsConfigOnError(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.GitHubClientID = "id"
+	config.GitHubClientSecret = "secret"
+
+	t.Setenv("OAUTH_ENABLED", "true")
+	t.Setenv("MCP_SERVER_URL", "http://localhost:8080")
+	t.Setenv("GITHUB_CLIENT_ID", "")
+	t.Setenv("GITHUB_CLIENT_SECRET", "")
+	t.Setenv("GITHUB_OAUTH_SECRET_NAME", "")
+	if _, err := config.ReloadFromEnv(); err == nil {
+		t.Fatalf("Expected a reload without a client ID to fail")
+	}
+	if id, secret := config.GitHubCredentials(); id != "id" || secret != "secret" {
+		t.Errorf("Expected the current credentials to be kept, got %q, %q", id, secret)
+	}
+
+	t.Setenv("GITHUB_CLIENT_ID", "id")
+	t.Setenv("GITHUB_CLIENT_SECRET", "rotated")
+	if changed, err := config.ReloadFromEnv(); err != nil || !slices.Equal(changed, []string{"GITHUB_CLIENT_SECRET"}) {
+		t.Errorf("Expected only the secret to change, got %v, %v", changed, err)
+	}
+}
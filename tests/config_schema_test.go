@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+func TestConfigSchemaUsesRuntimeDefaults(t *testing.T) {
+	schema := make(map[string]auth.ConfigVariable)
+	for _, variable := range auth.ConfigSchema() {
+		if variable.Description == "" {
+			t.Errorf("%s has no description", variable.Name)
+		}
+		schema[variable.Name] = variable
+	}
+
+	tests := []struct {
+		name     string
+		varType  string
+		expected string
+	}{
+		{"MCP_SERVER_URL", "string", "http://localhost:8080"},
+		{"TOKEN_EXPIRY_SECONDS", "seconds", "3600"},
+		{"OAUTH_SCOPES_SUPPORTED", "list", "mcp:tools,mcp:resources,read:user"},
+		{"ENABLE_DCR", "bool", "true"},
+		{"GITHUB_API_BUDGET_RESERVE", "int", "100"},
+	}
+
+	for _, tt := range tests {
+		variable, ok := schema[tt.name]
+		if !ok {
+			t.Errorf("%s missing from schema", tt.name)
+			continue
+		}
+		if variable.Type != tt.varType || variable.Default != tt.expected {
+			t.Errorf("%s: got type %q default %q, want %q %q", tt.name, variable.Type, variable.Default, tt.varType, tt.expected)
+		}
+	}
+
+	if secret := schema["GITHUB_CLIENT_SECRET"]; !secret.Secret || secret.Default != "" {
+		t.Errorf("GITHUB_CLIENT_SECRET should be marked secret without a default: %+v", secret)
+	}
+}
+
+func TestConfigSchemaHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	auth.NewConfigSchemaHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/config-schema", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var schema []auth.ConfigVariable
+	if err := json.Unmarshal(rec.Body.Bytes(), &schema); err != nil {
+		t.Fatalf("Failed to decode schema: %v", err)
+	}
+	if len(schema) != len(auth.ConfigSchema()) {
+		t.Errorf("Handler returned %d variables, want %d", len(schema), len(auth.ConfigSchema()))
+	}
+}
+
+// environmentKeys finds the environment variables read by the non-test code under root: the
+// string literals passed to os.Getenv, os.LookupEnv, and the *FromEnv helpers
+func environmentKeys(t *testing.T, root string) map[string]string {
+	t.Helper()
+	keys := make(map[string]string)
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Test code and its helpers aren't configuration
+			if name := d.Name(); path != root && (strings.HasPrefix(name, ".") || name == "tests" || name == "testsupport") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 {
+				return true
+			}
+			var name string
+			switch fun := call.Fun.(type) {
+			case *ast.Ident:
+				name = fun.Name
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			}
+			literal, ok := call.Args[0].(*ast.BasicLit)
+			if !ok || literal.Kind != token.STRING {
+				return true
+			}
+			key, _ := strconv.Unquote(literal.Value)
+			position := fset.Position(call.Pos()).String()
+			switch {
+			case name == "OpenFromEnv":
+				// logfile.OpenFromEnv takes the prefix of the stream's variables
+				for _, suffix := range []string{"_LOG_FILE", "_LOG_MAX_SIZE_MB", "_LOG_ROTATE_INTERVAL", "_LOG_COMPRESS", "_LOG_MAX_BACKUPS"} {
+					keys[key+suffix] = position
+				}
+			case name == "Getenv" || name == "LookupEnv" || strings.HasSuffix(name, "FromEnv"):
+				keys[key] = position
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan the source: %v", err)
+	}
+	return keys
+}
+
+func TestConfigSchemaCoversEveryEnvironmentVariable(t *testing.T) {
+	keys := environmentKeys(t, "..")
+	if _, ok := keys["TOOL_CACHE_TTLS"]; !ok {
+		t.Fatalf("Expected the scan to find TOOL_CACHE_TTLS, found %d variables", len(keys))
+	}
+
+	schema := make(map[string]bool)
+	for _, variable := range auth.ConfigSchema() {
+		if schema[variable.Name] {
+			t.Errorf("%s is described more than once", variable.Name)
+		}
+		schema[variable.Name] = true
+		if variable.Description == "" || variable.Type == "" {
+			t.Errorf("%s needs a type and description: %+v", variable.Name, variable)
+		}
+	}
+	for key, position := range keys {
+		if !schema[key] {
+			t.Errorf("%s, read at %s, is missing from the config schema", key, position)
+		}
+	}
+}
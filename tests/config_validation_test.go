@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+func TestConfigValidationReportCollectsAllIssues(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.OAuthEnabled = true
+	config.AllowPublicClients = false
+	config.ScopesSupported = nil
+	config.TokenExpiryDuration = 0
+
+	report := config.ValidationReport()
+
+	fields := make(map[string]auth.Severity)
+	for _, issue := range report.Issues {
+		fields[issue.Field] = issue.Severity
+	}
+
+	for _, field := range []string{"GITHUB_CLIENT_ID", "GITHUB_CLIENT_SECRET", "OAUTH_SCOPES_SUPPORTED", "TOKEN_EXPIRY_SECONDS"} {
+		if fields[field] != auth.SeverityFatal {
+			t.Errorf("Expected a fatal issue for %s, got report:\n%s", field, report)
+		}
+	}
+
+	if err := config.Validate(); err == nil {
+		t.Errorf("Validate should fail when the report has fatal issues")
+	}
+}
+
+func TestConfigValidationReportWarningsAreNotFatal(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.ServerURL = "http://mcp.example.com"
+	config.TokenExpiryDuration = 7 * 24 * time.Hour
+
+	report := config.ValidationReport()
+
+	if report.HasFatal() {
+		t.Fatalf("Expected no fatal issues, got:\n%s", report)
+	}
+	if len(report.Warnings()) < 2 {
+		t.Errorf("Expected warnings for the HTTP server URL and long token expiry, got:\n%s", report)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("Validate should pass with only warnings: %v", err)
+	}
+}
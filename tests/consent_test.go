@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// consentIDPattern finds the consent ID in the consent page's form
+var consentIDPattern = regexp.MustCompile(`name="consent" value="([^"]+)"`)
+
+// consentID returns the consent ID on the consent page in rec
+func consentID(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	match := consentIDPattern.FindStringSubmatch(rec.Body.String())
+	if rec.Code != http.StatusOK || match == nil {
+		t.Fatalf("Expected the consent page, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	return match[1]
+}
+
+// decideConsent posts the user's decision on the consent page to handler at path
+func decideConsent(handler http.Handler, path, consentID, action string) *httptest.ResponseRecorder {
+	form := url.Values{"consent": {consentID}, "action": {action}}
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConsentIsAskedBeforeGitHub(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"gh-%s","token_type":"bearer"}`, r.FormValue("code"))
+	}))
+	defer github.Close()
+
+	config := auth.DefaultConfig()
+	config.GitHubTokenURL = github.URL
+	config.GitHubAPIURL = github.URL
+	clients := newMemoryClientsWithDefaults(t)
+	authorize := auth.NewAuthorizationHandlerWithStateStorage(config, clients, newStateStore(t, config.AuthStateTTL))
+	authorize.EnableConsent()
+	callback := auth.NewCallbackHandler(config, authorize.GetStateStore(), newMemoryTokens(t))
+
+	// start sends the user to the authorization endpoint for a new flow
+	start := func(scope string) *httptest.ResponseRecorder {
+		query := url.Values{}
+		query.Set("response_type", "code")
+		query.Set("client_id", "vscode")
+		query.Set("redirect_uri", "http://127.0.0.1:33418")
+		query.Set("scope", scope)
+		query.Set("state", "client-state")
+		query.Set("code_challenge", pkceChallenge(strings.Repeat("v", 43)))
+		query.Set("code_challenge_method", "S256")
+		rec := httptest.NewRecorder()
+		authorize.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query.Encode(), nil))
+		return rec
+	}
+
+	rec := start("mcp:tools")
+	page := rec.Body.String()
+	if !strings.Contains(page, "Visual Studio Code") || !strings.Contains(page, "http://127.0.0.1:33418") || !strings.Contains(page, "Call tools on your behalf") {
+		t.Errorf("Expected the page to name the client, redirect URI, and scopes, got %s", page)
+	}
+	if rec.Header().Get("Location") != "" || rec.Header().Get("X-Frame-Options") != "DENY" {
+		t.Errorf("Expected an unframeable page instead of a redirect to GitHub, got %v", rec.Header())
+	}
+	denied := consentID(t, rec)
+
+	// A pending consent can't be completed by replaying it as a callback state
+	replay := httptest.NewRecorder()
+	callback.ServeHTTP(replay, httptest.NewRequest(http.MethodGet, "/oauth/callback?code=x&state=consent:"+denied, nil))
+	if replay.Code != http.StatusBadRequest {
+		t.Errorf("Expected a consent ID to be rejected as callback state, got %d", replay.Code)
+	}
+
+	rec = decideConsent(authorize, "/oauth/authorize", denied, "deny")
+	location, _ := url.Parse(rec.Header().Get("Location"))
+	if rec.Code != http.StatusFound || location.Query().Get("error") != "access_denied" || location.Query().Get("state") != "client-state" {
+		t.Errorf("Expected access_denied at the client, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := decideConsent(authorize, "/oauth/authorize", denied, "approve"); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a consent ID to be redeemed once, got %d", rec.Code)
+	}
+
+	// Only an approval sends the user to GitHub, whose callback then completes the flow
+	rec = decideConsent(authorize, "/oauth/authorize", consentID(t, start("mcp:tools")), "approve")
+	location, _ = url.Parse(rec.Header().Get("Location"))
+	if rec.Code != http.StatusFound || !strings.HasPrefix(rec.Header().Get("Location"), config.GitHubAuthURL) || location.Query().Get("state") == "" {
+		t.Fatalf("Expected a redirect to GitHub after approving, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	rec = httptest.NewRecorder()
+	callback.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/callback?code=github-code&state="+url.QueryEscape(location.Query().Get("state")), nil))
+	location, _ = url.Parse(rec.Header().Get("Location"))
+	if rec.Code != http.StatusFound || location.Query().Get("code") == "" || location.Query().Get("state") != "client-state" {
+		t.Errorf("Expected a code at the client after GitHub, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+}
+
+func TestConsentIsRememberedPerUserClientAndScopes(t *testing.T) {
+	// The GitHub user is the code GitHub returned to the callback
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/user" {
+			_, _ = fmt.Fprintf(w, `{"login":%q}`, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer gh-"))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"access_token":"gh-%s","token_type":"bearer"}`, r.FormValue("code"))
+	}))
+	defer github.Close()
+
+	config := auth.DefaultConfig()
+	config.GitHubTokenURL = github.URL + "/login/oauth/access_token"
+	config.GitHubAPIURL = github.URL
+	config.GitHubClientSecret = "github-secret"
+	authorize := auth.NewAuthorizationHandlerWithStateStorage(config, newMemoryClientsWithDefaults(t), newStateStore(t, config.AuthStateTTL))
+	authorize.EnableConsent()
+	callback := auth.NewCallbackHandler(config, authorize.GetStateStore(), newMemoryTokens(t))
+
+	// start sends the user to the authorization endpoint for a new flow, with the browser's cookies
+	start := func(scope string, cookies []*http.Cookie) *httptest.ResponseRecorder {
+		query := url.Values{}
+		query.Set("response_type", "code")
+		query.Set("client_id", "vscode")
+		query.Set("redirect_uri", "http://127.0.0.1:33418")
+		query.Set("scope", scope)
+		query.Set("code_challenge", pkceChallenge(strings.Repeat("v", 43)))
+		query.Set("code_challenge_method", "S256")
+		req := httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+query.Encode(), nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		authorize.ServeHTTP(rec, req)
+		return rec
+	}
+	// signIn completes the flow GitHub was sent to in rec as login
+	signIn := func(rec *httptest.ResponseRecorder, login string) *httptest.ResponseRecorder {
+		location, _ := url.Parse(rec.Header().Get("Location"))
+		if rec.Code != http.StatusFound || !strings.HasPrefix(location.String(), config.GitHubAuthURL) {
+			t.Fatalf("Expected a redirect to GitHub, got %d %s", rec.Code, location)
+		}
+		callbackRec := httptest.NewRecorder()
+		callback.ServeHTTP(callbackRec, httptest.NewRequest(http.MethodGet, "/oauth/callback?code="+login+"&state="+url.QueryEscape(location.Query().Get("state")), nil))
+		return callbackRec
+	}
+
+	rec := signIn(decideConsent(authorize, "/oauth/authorize", consentID(t, start("mcp:tools mcp:resources", nil)), "approve"), "octocat")
+	cookies := rec.Result().Cookies()
+	if location, _ := url.Parse(rec.Header().Get("Location")); location.Query().Get("code") == "" || len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("Expected a code and a cookie remembering the approval, got %s %v", rec.Header().Get("Location"), cookies)
+	}
+
+	// The same client and the same or fewer scopes skip the page; more scopes ask again
+	rec = signIn(start("mcp:tools", cookies), "octocat")
+	if location, _ := url.Parse(rec.Header().Get("Location")); location.Query().Get("code") == "" {
+		t.Errorf("Expected a remembered consent to complete the flow, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	consentID(t, start("mcp:tools read:user", cookies))
+
+	// Another GitHub user signing in with the remembered consent hasn't approved anything
+	rec = signIn(start("mcp:tools", cookies), "mallory")
+	if location, _ := url.Parse(rec.Header().Get("Location")); location.Query().Get("error") != "access_denied" {
+		t.Errorf("Expected access_denied for another user, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	if cleared := rec.Result().Cookies(); len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("Expected the remembered consent to be cleared, got %v", cleared)
+	}
+
+	// A cookie this server didn't sign is ignored
+	forged := *cookies[0]
+	forged.Value = strings.Replace(forged.Value, ".", ".x", 1)
+	consentID(t, start("mcp:tools", []*http.Cookie{&forged}))
+}
+
+func TestDeviceConsentIsAskedBeforeGitHub(t *testing.T) {
+	config := auth.DefaultConfig()
+	clients := newMemoryClients(t)
+	tokens := newMemoryTokens(t)
+	states := newStateStore(t, 0)
+	if err := clients.StoreClient(&auth.OAuthClient{
+		ClientID: "cli",
+		Metadata: auth.ClientRegistrationRequest{ClientName: "Terminal", GrantTypes: []string{auth.DeviceCodeGrantType}},
+	}); err != nil {
+		t.Fatalf("StoreClient failed: %v", err)
+	}
+	verification := auth.NewDeviceVerificationHandler(config, states)
+	verification.EnableConsent(clients, tokens)
+
+	// startDevice begins a device flow and enters its user code on the verification page
+	startDevice := func() (string, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/device_authorization", strings.NewReader("client_id=cli&scope=mcp:tools"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		auth.NewDeviceAuthorizationHandler(config, clients, tokens, states).ServeHTTP(rec, req)
+		var device struct {
+			DeviceCode string `json:"device_code"`
+			UserCode   string `json:"user_code"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &device); err != nil {
+			t.Fatalf("Failed to decode device authorization response: %v", err)
+		}
+
+		req = httptest.NewRequest(http.MethodPost, "/oauth/device", strings.NewReader(url.Values{"user_code": {device.UserCode}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec = httptest.NewRecorder()
+		verification.ServeHTTP(rec, req)
+		return device.DeviceCode, rec
+	}
+
+	deviceCode, rec := startDevice()
+	if page := rec.Body.String(); !strings.Contains(page, "Terminal") || !strings.Contains(page, "Call tools on your behalf") || !strings.Contains(page, `action="/oauth/device"`) {
+		t.Errorf("Expected the consent page for the device's client, got %s", page)
+	}
+
+	// Denying tells the device on its next poll
+	rec = decideConsent(verification, "/oauth/device", consentID(t, rec), "deny")
+	if rec.Code != http.StatusOK || rec.Header().Get("Location") != "" {
+		t.Errorf("Expected the user to stay off GitHub after denying, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	token := auth.NewTokenEndpointHandler(config, clients, tokens)
+	if _, body := pollDeviceToken(t, token, "cli", deviceCode); body["error"] != "access_denied" {
+		t.Errorf("Expected access_denied for a denied device, got %v", body)
+	}
+
+	_, rec = startDevice()
+	rec = decideConsent(verification, "/oauth/device", consentID(t, rec), "approve")
+	if rec.Code != http.StatusFound || !strings.HasPrefix(rec.Header().Get("Location"), config.GitHubAuthURL) {
+		t.Errorf("Expected a redirect to GitHub after approving, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+}
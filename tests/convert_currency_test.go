@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// fakeRateProvider serves fixed rates, or fails while down
+type fakeRateProvider struct {
+	rates map[string]float64
+	down  bool
+	calls int
+}
+
+func (p *fakeRateProvider) Rates(ctx context.Context, base string) (map[string]float64, error) {
+	p.calls++
+	if p.down {
+		return nil, errors.New("provider is down")
+	}
+	return p.rates, nil
+}
+
+// convertCurrency calls convert-currency and returns its text
+func convertCurrency(t *testing.T, params tools.ConvertCurrencyParams) (string, error) {
+	t.Helper()
+	tool := tools.ConvertCurrency{}
+	result, _, err := tool.Action(context.Background(), &mcp.CallToolRequest{}, &params)
+	if err != nil {
+		return "", err
+	}
+	return result.Content[0].(*mcp.TextContent).Text, nil
+}
+
+func TestConvertCurrencyCachesAndFallsBack(t *testing.T) {
+	t.Setenv("EXCHANGE_RATE_CACHE_SECONDS", "")
+	fakeClock := testsupport.NewFakeClock(propertyEpoch)
+	tools.SetClock(fakeClock)
+	provider := &fakeRateProvider{rates: map[string]float64{"EUR": 0.5}}
+	tools.SetRateProvider(provider)
+	t.Cleanup(func() {
+		tools.SetRateProvider(nil)
+		tools.SetClock(clock.System{})
+	})
+
+	text, err := convertCurrency(t, tools.ConvertCurrencyParams{Amount: 100, From: "usd", To: "EUR"})
+	if err != nil || !strings.HasPrefix(text, "100.00 USD = 50.00 EUR") {
+		t.Fatalf("Unexpected conversion %q, %v", text, err)
+	}
+
+	fakeClock.Advance(30 * time.Minute)
+	provider.rates = map[string]float64{"EUR": 0.8}
+	if text, _ := convertCurrency(t, tools.ConvertCurrencyParams{Amount: 10, From: "USD", To: "EUR"}); provider.calls != 1 || !strings.HasPrefix(text, "10.00 USD = 5.00 EUR") {
+		t.Errorf("Expected cached rates within the TTL, got %q after %d calls", text, provider.calls)
+	}
+
+	// Once the cache expires a failing provider falls back to the old rates
+	fakeClock.Advance(time.Hour)
+	provider.down = true
+	text, err = convertCurrency(t, tools.ConvertCurrencyParams{Amount: 10, From: "USD", To: "EUR"})
+	if err != nil || !strings.HasPrefix(text, "10.00 USD = 5.00 EUR") || !strings.Contains(text, "unavailable") {
+		t.Errorf("Expected stale rates while the provider is down, got %q, %v", text, err)
+	}
+
+	provider.down = false
+	if text, _ := convertCurrency(t, tools.ConvertCurrencyParams{Amount: 10, From: "USD", To: "EUR"}); !strings.HasPrefix(text, "10.00 USD = 8.00 EUR") {
+		t.Errorf("Expected fresh rates once the provider recovers, got %q", text)
+	}
+
+	// Without cached rates there is nothing to fall back to
+	provider.down = true
+	if _, err := convertCurrency(t, tools.ConvertCurrencyParams{Amount: 10, From: "GBP", To: "EUR"}); err == nil {
+		t.Errorf("Expected an error without cached rates")
+	}
+	for _, params := range []tools.ConvertCurrencyParams{
+		{Amount: 1, From: "US", To: "EUR"},
+		{Amount: 1, From: "USD", To: "EU1"},
+		{Amount: 1, From: "USD", To: "JPY"},
+	} {
+		if _, err := convertCurrency(t, params); err == nil {
+			t.Errorf("Expected an error for %+v", params)
+		}
+	}
+}
+
+func TestHTTPRateProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Query().Get("base") == "USD":
+			_, _ = w.Write([]byte(`{"base":"USD","rates":{"EUR":0.92}}`))
+		case r.URL.Path == "/v6/latest/GBP":
+			_, _ = w.Write([]byte(`{"base_code":"GBP","conversion_rates":{"EUR":1.17}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Setenv("EXCHANGE_RATE_API_URL", server.URL+"/latest")
+	t.Setenv("EXCHANGE_RATE_API_KEY", "key")
+	rates, err := tools.NewHTTPRateProviderFromEnv().Rates(context.Background(), "USD")
+	if err != nil || rates["EUR"] != 0.92 {
+		t.Errorf("Expected rates from the base query parameter, got %v, %v", rates, err)
+	}
+
+	provider := &tools.HTTPRateProvider{URL: server.URL + "/v6/latest/{base}", APIKey: "key"}
+	if rates, err := provider.Rates(context.Background(), "GBP"); err != nil || rates["EUR"] != 1.17 {
+		t.Errorf("Expected conversion_rates from the {base} URL, got %v, %v", rates, err)
+	}
+	provider.APIKey = ""
+	if _, err := provider.Rates(context.Background(), "GBP"); err == nil {
+		t.Errorf("Expected an error when the API rejects the request")
+	}
+}
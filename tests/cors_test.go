@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/cors"
+)
+
+var mcpCORSPolicy = cors.Policy{
+	AllowedOrigins:   []string{"http://localhost:6274"},
+	AllowedMethods:   []string{"GET", "POST", "DELETE", "OPTIONS"},
+	AllowedHeaders:   []string{"Content-Type", "Authorization", "Mcp-Session-Id"},
+	ExposedHeaders:   []string{"Mcp-Session-Id"},
+	AllowCredentials: true,
+	MaxAge:           2 * time.Hour,
+}
+
+// corsRequest sends a request through policy to a handler that records whether it was reached
+func corsRequest(policy cors.Policy, req *http.Request) (*httptest.ResponseRecorder, bool) {
+	reached := false
+	handler := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached = true
+		w.Header().Set("Mcp-Session-Id", "session")
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, reached
+}
+
+func preflightRequest(origin, method, headers string) *http.Request {
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+	return req
+}
+
+func TestCORSPreflightAllowed(t *testing.T) {
+	rec, reached := corsRequest(mcpCORSPolicy, preflightRequest("http://localhost:6274", "POST", "content-type, mcp-session-id"))
+
+	if reached {
+		t.Errorf("Preflight reached the wrapped handler")
+	}
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+
+	expected := map[string]string{
+		"Access-Control-Allow-Origin":      "http://localhost:6274",
+		"Access-Control-Allow-Credentials": "true",
+		"Access-Control-Allow-Methods":     "GET, POST, DELETE, OPTIONS",
+		"Access-Control-Allow-Headers":     "Content-Type, Authorization, Mcp-Session-Id",
+		"Access-Control-Max-Age":           "7200",
+	}
+	for header, want := range expected {
+		if got := rec.Header().Get(header); got != want {
+			t.Errorf("%s: expected %q, got %q", header, want, got)
+		}
+	}
+}
+
+func TestCORSPreflightRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *http.Request
+	}{
+		{"unknown origin", preflightRequest("https://evil.example", "POST", "")},
+		{"origin substring", preflightRequest("http://localhost:627", "POST", "")},
+		{"method", preflightRequest("http://localhost:6274", "PUT", "")},
+		{"header", preflightRequest("http://localhost:6274", "POST", "X-Custom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec, reached := corsRequest(mcpCORSPolicy, tt.request)
+			if reached {
+				t.Errorf("Rejected preflight reached the wrapped handler")
+			}
+			if rec.Code != http.StatusForbidden {
+				t.Errorf("Expected status 403, got %d", rec.Code)
+			}
+			if rec.Header().Get("Access-Control-Allow-Origin") != "" {
+				t.Errorf("Rejected preflight included Access-Control-Allow-Origin")
+			}
+		})
+	}
+}
+
+func TestCORSExposesHeadersOnActualRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Origin", "http://localhost:6274")
+
+	rec, reached := corsRequest(mcpCORSPolicy, req)
+	if !reached {
+		t.Fatalf("Request did not reach the wrapped handler")
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != "Mcp-Session-Id" {
+		t.Errorf("Expected Mcp-Session-Id to be exposed, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Expected Vary: Origin, got %q", got)
+	}
+}
+
+func TestCORSWildcardNeverAllowsCredentials(t *testing.T) {
+	policy := cors.Policy{
+		AllowedOrigins:   []string{cors.Wildcard},
+		AllowedMethods:   []string{"POST"},
+		AllowedHeaders:   []string{"Content-Type"},
+		AllowCredentials: true,
+	}
+
+	rec, _ := corsRequest(policy, preflightRequest("https://client.example", "POST", "Content-Type"))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected wildcard origin, got %q", got)
+	}
+	if rec.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("Credentials allowed for a wildcard origin")
+	}
+}
+
+func TestCORSSubdomainPattern(t *testing.T) {
+	policy := mcpCORSPolicy
+	policy.AllowedOrigins = []string{"https://*.example.com"}
+
+	for origin, allowed := range map[string]bool{
+		"https://chat.example.com":      true,
+		"https://eu.chat.example.com":   true,
+		"https://example.com":           false,
+		"http://chat.example.com":       false,
+		"https://chat.example.com:8443": false,
+		"https://evilexample.com":       false,
+		"https://example.com.evil.net":  false,
+	} {
+		rec, _ := corsRequest(policy, preflightRequest(origin, "POST", "Content-Type"))
+		if got := rec.Code == http.StatusNoContent; got != allowed {
+			t.Errorf("Origin %s: expected allowed %t, got status %d", origin, allowed, rec.Code)
+			continue
+		}
+		// Listed patterns are explicit, so the origin is echoed with credentials
+		if allowed && (rec.Header().Get("Access-Control-Allow-Origin") != origin || rec.Header().Get("Access-Control-Allow-Credentials") != "true") {
+			t.Errorf("Origin %s: expected it echoed with credentials, got %v", origin, rec.Header())
+		}
+	}
+}
+
+func TestCORSPlainOptionsReachesHandler(t *testing.T) {
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+
+	if _, reached := corsRequest(mcpCORSPolicy, req); !reached {
+		t.Errorf("OPTIONS without a preflight header did not reach the wrapped handler")
+	}
+}
+
+func TestCORSParseOrigins(t *testing.T) {
+	origins := cors.ParseOrigins(" https://a.example/, ,http://localhost:6274")
+	if len(origins) != 2 || origins[0] != "https://a.example" || origins[1] != "http://localhost:6274" {
+		t.Errorf("Unexpected origins: %v", origins)
+	}
+}
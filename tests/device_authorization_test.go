@@ -0,0 +1,172 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// pollDeviceToken requests a token for deviceCode and returns the status and decoded body
+func pollDeviceToken(t *testing.T, handler http.Handler, clientID, deviceCode string) (int, map[string]any) {
+	t.Helper()
+	form := url.Values{}
+	form.Set("grant_type", auth.DeviceCodeGrantType)
+	form.Set("device_code", deviceCode)
+	form.Set("client_id", clientID)
+	req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode token response: %v", err)
+	}
+	return rec.Code, body
+}
+
+func TestDeviceAuthorizationFlow(t *testing.T) {
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"access_token":"gh-%s","token_type":"bearer"}`, r.FormValue("code"))
+	}))
+	defer github.Close()
+
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	config := auth.DefaultConfig()
+	config.Clock = clock
+	config.GitHubTokenURL = github.URL
+	clients := newMemoryClients(t)
+	clients.SetClock(clock)
+	tokens := newMemoryTokens(t)
+	tokens.SetClock(clock)
+	states := newStateStore(t, config.AuthStateTTL)
+	states.SetClock(clock)
+
+	// Register a public client for the device grant, without redirect URIs
+	registerBody := `{"client_name":"cli","grant_types":["` + auth.DeviceCodeGrantType + `"],"token_endpoint_auth_method":"none"}`
+	rec := httptest.NewRecorder()
+	auth.NewRegistrationHandler(config, clients).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(registerBody)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Registration returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var registration auth.ClientRegistrationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &registration); err != nil {
+		t.Fatalf("Failed to decode registration response: %v", err)
+	}
+	clientID := registration.ClientID
+
+	form := url.Values{}
+	form.Set("client_id", clientID)
+	form.Set("scope", "mcp:tools")
+	req := httptest.NewRequest(http.MethodPost, "/oauth/device_authorization", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec = httptest.NewRecorder()
+	auth.NewDeviceAuthorizationHandler(config, clients, tokens, states).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Device authorization returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var device struct {
+		DeviceCode      string `json:"device_code"`
+		UserCode        string `json:"user_code"`
+		VerificationURI string `json:"verification_uri"`
+		Interval        int    `json:"interval"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &device); err != nil {
+		t.Fatalf("Failed to decode device authorization response: %v", err)
+	}
+	if device.VerificationURI != config.ServerURL+"/oauth/device" || device.Interval != 5 {
+		t.Errorf("Unexpected device authorization response: %s", rec.Body.String())
+	}
+
+	token := auth.NewTokenEndpointHandler(config, clients, tokens)
+	if status, body := pollDeviceToken(t, token, clientID, device.DeviceCode); status != http.StatusBadRequest || body["error"] != "authorization_pending" {
+		t.Errorf("Expected authorization_pending, got %d %v", status, body)
+	}
+	if _, body := pollDeviceToken(t, token, clientID, device.DeviceCode); body["error"] != "slow_down" {
+		t.Errorf("Expected slow_down when polling again at once, got %v", body)
+	}
+	// Each slow_down adds 5 seconds to the interval the client must keep
+	clock.Advance(5 * time.Second)
+	if _, body := pollDeviceToken(t, token, clientID, device.DeviceCode); body["error"] != "slow_down" {
+		t.Errorf("Expected slow_down when polling 5 seconds after a slow_down, got %v", body)
+	}
+	clock.Advance(15 * time.Second)
+	if _, body := pollDeviceToken(t, token, clientID, device.DeviceCode); body["error"] != "authorization_pending" {
+		t.Errorf("Expected authorization_pending once the longer interval passed, got %v", body)
+	}
+
+	// The callback only accepts states issued by the verification page, not user codes
+	callback := auth.NewCallbackHandler(config, states, tokens)
+	rec = httptest.NewRecorder()
+	callback.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/callback?code=x&state=device:"+strings.ReplaceAll(device.UserCode, "-", ""), nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a user code to be rejected as callback state, got %d", rec.Code)
+	}
+
+	// Users may type the code in lower case and without the dash
+	verification := auth.NewDeviceVerificationHandler(config, states)
+	enter := func(userCode string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		form.Set("user_code", userCode)
+		req := httptest.NewRequest(http.MethodPost, "/oauth/device", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		verification.ServeHTTP(rec, req)
+		return rec
+	}
+	rec = enter(strings.ToLower(strings.ReplaceAll(device.UserCode, "-", "")))
+	location, _ := url.Parse(rec.Header().Get("Location"))
+	if rec.Code != http.StatusFound || location == nil || location.Query().Get("state") == "" {
+		t.Fatalf("Expected a redirect to GitHub, got %d %s", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := enter(device.UserCode); rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected a user code to be redeemed only once, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	callback.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/oauth/callback?code=github-code&state="+url.QueryEscape(location.Query().Get("state")), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Callback returned %d: %s", rec.Code, rec.Body.String())
+	}
+
+	clock.Advance(5 * time.Second)
+	status, body := pollDeviceToken(t, token, clientID, device.DeviceCode)
+	if status != http.StatusOK || body["access_token"] == "" || body["scope"] != "mcp:tools" {
+		t.Fatalf("Expected an access token, got %d %v", status, body)
+	}
+	info, err := tokens.GetAccessToken(body["access_token"].(string))
+	if err != nil || info.GitHubAccessToken != "gh-github-code" {
+		t.Errorf("Expected the token to carry the user's GitHub token, got %+v (%v)", info, err)
+	}
+
+	if _, body := pollDeviceToken(t, token, clientID, device.DeviceCode); body["error"] != "expired_token" {
+		t.Errorf("Expected the device code to be redeemed only once, got %v", body)
+	}
+}
+
+func TestDeviceAuthorizationRequiresRegisteredGrant(t *testing.T) {
+	config := auth.DefaultConfig()
+	handler := auth.NewDeviceAuthorizationHandler(config, newMemoryClientsWithDefaults(t), newMemoryTokens(t), newStateStore(t, 0))
+
+	for clientID, expected := range map[string]string{"vscode": "unauthorized_client", "unknown": "invalid_client"} {
+		req := httptest.NewRequest(http.MethodPost, "/oauth/device_authorization", strings.NewReader("client_id="+clientID))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var body map[string]string
+		_ = json.Unmarshal(rec.Body.Bytes(), &body)
+		if body["error"] != expected {
+			t.Errorf("Expected %s for client %s, got %d %v", expected, clientID, rec.Code, body)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/docs"
+)
+
+func TestDocsHandlerServesPages(t *testing.T) {
+	handler := docs.NewHandler()
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	// The metadata's documentation link resolves
+	rec := httptest.NewRecorder()
+	auth.NewProtectedResourceMetadataHandler(auth.DefaultConfig()).ServeHTTP(rec,
+		httptest.NewRequest(http.MethodGet, "/.well-known/oauth-protected-resource", nil))
+	var metadata struct {
+		ResourceDocumentation string `json:"resource_documentation"`
+	}
+	_ = json.Unmarshal(rec.Body.Bytes(), &metadata)
+	link, err := url.Parse(metadata.ResourceDocumentation)
+	if err != nil || link.Path != "/docs" {
+		t.Fatalf("Unexpected documentation link %q", metadata.ResourceDocumentation)
+	}
+
+	index := get(link.Path)
+	if index.Code != http.StatusOK {
+		t.Fatalf("Index returned %d", index.Code)
+	}
+	for _, page := range docs.List() {
+		if !strings.Contains(index.Body.String(), `href="/docs/`+page.Name+`"`) {
+			t.Errorf("Expected the index to link to %s", page.Name)
+		}
+	}
+
+	page := get("/docs/tools")
+	if page.Code != http.StatusOK || !strings.Contains(page.Body.String(), "<td><code>get-runbook</code></td>") {
+		t.Errorf("Expected the tools page with its table, got %d: %s", page.Code, page.Body.String())
+	}
+	if rec := get("/docs/../go.mod"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a path outside the pages, got %d", rec.Code)
+	}
+}
+
+func TestDocsPagesCoverToolsAuthAndAPI(t *testing.T) {
+	for _, name := range []string{"tools", "auth", "api"} {
+		page, err := docs.Get(name)
+		if err != nil || page.Title == name {
+			t.Errorf("Expected a titled %s page, got %+v, %v", name, page, err)
+		}
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	got := docs.RenderHTML("# Signing in\n\nSee [auth](auth.md) and **`<b>`**.\n\n1. First\n   continued\n2. Second\n\n```\n<script>\n```\n")
+	for _, want := range []string{
+		`<h1 id="signing-in">Signing in</h1>`,
+		`<a href="auth">auth</a>`,
+		"<code>&lt;b&gt;</code>",
+		"<ol>\n<li>First continued</li>\n<li>Second</li>\n</ol>",
+		"<pre><code>&lt;script&gt;\n</code></pre>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Expected %q in:\n%s", want, got)
+		}
+	}
+}
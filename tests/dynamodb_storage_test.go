@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+func TestDynamoDBStorageNeverStoresRawTokens(t *testing.T) {
+	table := testsupport.NewFakeDynamoDB()
+	storage := auth.NewDynamoDBStorage(table, "tokens")
+
+	err := storage.StoreAccessToken("raw-bearer-token", &auth.AccessTokenInfo{
+		ClientID:  "vscode",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+
+	output, err := table.Scan(context.TODO(), &dynamodb.ScanInput{})
+	if err != nil {
+		t.Fatalf("Scan failed: %v", err)
+	}
+	for _, item := range output.Items {
+		if strings.Contains(fmt.Sprint(item["pk"]), "raw-bearer-token") {
+			t.Errorf("Raw token used as a DynamoDB key")
+		}
+	}
+}
+
+func TestDynamoDBStorageListsClientsAcrossPages(t *testing.T) {
+	storage := auth.NewDynamoDBStorage(testsupport.NewFakeDynamoDB(), "clients")
+
+	for i := 0; i < 5; i++ {
+		if err := storage.StoreClient(&auth.OAuthClient{ClientID: fmt.Sprintf("client-%d", i)}); err != nil {
+			t.Fatalf("StoreClient failed: %v", err)
+		}
+	}
+	if err := storage.StoreAccessToken("token", &auth.AccessTokenInfo{ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatalf("StoreAccessToken failed: %v", err)
+	}
+
+	clients, err := storage.ListClients()
+	if err != nil {
+		t.Fatalf("ListClients failed: %v", err)
+	}
+	if len(clients) != 5 {
+		t.Errorf("Expected 5 clients, got %d", len(clients))
+	}
+}
+
+func TestDynamoDBStateSharedAcrossInstances(t *testing.T) {
+	table := testsupport.NewFakeDynamoDB()
+	first := auth.NewDynamoDBStateStore(table, "mcp", 5*time.Minute)
+	second := auth.NewDynamoDBStateStore(table, "mcp", 5*time.Minute)
+
+	// Device user codes and pending consents are kept as states too
+	for _, state := range []string{"oauth-state", "device:BCDFGHJK", "consent:abc"} {
+		if err := first.Store(state, &auth.AuthState{ClientID: "vscode", CreatedAt: time.Now()}); err != nil {
+			t.Fatalf("Store %s failed: %v", state, err)
+		}
+		if authState, ok := second.Get(state); !ok || authState.ClientID != "vscode" {
+			t.Errorf("State %s stored by one instance was not visible to another", state)
+		}
+	}
+}
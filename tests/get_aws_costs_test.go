@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGetAWSCostsRequiresAdminScope(t *testing.T) {
+	tool := tools.GetAWSCosts{}
+
+	_, _, err := tool.Action(
+		context.TODO(),
+		&mcp.CallToolRequest{
+			Extra: &mcp.RequestExtra{
+				TokenInfo: &auth.TokenInfo{
+					Scopes:     []string{"mcp:tools"},
+					Expiration: time.Now().Add(time.Hour),
+				},
+			},
+		},
+		&struct{}{},
+	)
+
+	if err == nil {
+		t.Errorf("Calling tool \"%s\" without the mcp:admin scope should have failed", tool.Name)
+	}
+}
+
+// awsCostsAdmin is a get-aws-costs call from an mcp:admin token
+var awsCostsAdmin = &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{
+	Scopes:     []string{"mcp:tools", "mcp:admin"},
+	Expiration: time.Now().Add(time.Hour),
+}}}
+
+// handleCostAndUsage answers GetCostAndUsage with the given amounts for each group of the
+// requested dimension
+func handleCostAndUsage(fake *testsupport.FakeAWS, amounts map[string]map[string]string) {
+	fake.Handle("AWSInsightsIndexService.GetCostAndUsage", func(input map[string]any) (any, error) {
+		dimension := input["GroupBy"].([]any)[0].(map[string]any)["Key"].(string)
+		var groups []map[string]any
+		for key, amount := range amounts[dimension] {
+			groups = append(groups, map[string]any{
+				"Keys":    []string{key},
+				"Metrics": map[string]any{"UnblendedCost": map[string]string{"Amount": amount, "Unit": "USD"}},
+			})
+		}
+		return map[string]any{"ResultsByTime": []any{map[string]any{"Groups": groups}}}, nil
+	})
+}
+
+func TestGetAWSCostsRejectsInvalidBudgetBeforeCallingAWS(t *testing.T) {
+	t.Setenv("AWS_MONTHLY_BUDGET", "lots")
+	fake := useFakeAWS(t)
+	handleCostAndUsage(fake, nil)
+
+	tool := tools.GetAWSCosts{}
+	_, _, err := tool.Action(context.TODO(), awsCostsAdmin, &struct{}{})
+	if err == nil || !strings.Contains(err.Error(), "AWS_MONTHLY_BUDGET") {
+		t.Errorf("Expected the invalid budget to be rejected, got %v", err)
+	}
+	if calls := fake.Calls(); len(calls) != 0 {
+		t.Errorf("Expected no billed Cost Explorer calls, got %d", len(calls))
+	}
+}
+
+func TestGetAWSCostsReportsBudgetWithoutForecast(t *testing.T) {
+	t.Setenv("AWS_MONTHLY_BUDGET", "$200")
+	tools.SetClock(testsupport.NewFakeClock(time.Date(2025, 6, 15, 10, 0, 0, 0, time.UTC)))
+	t.Cleanup(func() { tools.SetClock(clock.System{}) })
+	fake := useFakeAWS(t)
+	handleCostAndUsage(fake, map[string]map[string]string{
+		"SERVICE":     {"Amazon Elastic Container Service": "120.50", "AWS Secrets Manager": "4.50"},
+		"RECORD_TYPE": {"Usage": "125.00", "Credit": "-25.00"},
+	})
+	fake.Handle("AWSInsightsIndexService.GetCostForecast", func(input map[string]any) (any, error) {
+		return nil, errors.New("insufficient amount of historical data")
+	})
+
+	tool := tools.GetAWSCosts{}
+	result, _, err := tool.Action(context.TODO(), awsCostsAdmin, &struct{}{})
+	text := toolText(t, result, err)
+
+	for _, want := range []string{
+		"AWS spend month-to-date (2025-06-01 to 2025-06-15):\n",
+		"- Amazon Elastic Container Service: $120.50\n",
+		"Credits applied: $-25.00\n",
+		"Net month-to-date: $100.00\n",
+		"Forecast for remainder of month: unavailable (",
+		"Monthly budget: $200.00\nRemaining budget: $100.00 (50% used)\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the report to contain %q, got %q", want, text)
+		}
+	}
+	if strings.Contains(text, "Projected") {
+		t.Errorf("Expected no projection without a forecast, got %q", text)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Expected two cost queries and a forecast, got %d calls", len(calls))
+	}
+	period := calls[0].Input["TimePeriod"].(map[string]any)
+	if period["Start"] != "2025-06-01" || period["End"] != "2025-06-16" {
+		t.Errorf("Expected the month-to-date period including today, got %v", period)
+	}
+}
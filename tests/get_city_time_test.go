@@ -2,11 +2,15 @@ package tests
 
 import (
 	"context"
-	"testing"
 	"encoding/json"
+	"strconv"
 	"strings"
+	"testing"
+	"time"
 
-	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
@@ -88,3 +92,71 @@ func TestGetCityTimeInSanFrancisco(t *testing.T) {
 		t.Errorf("Calling tool \"%s\" with \"nyc\" as the city returned the wrong data: %s", tool.Name, data["text"].(string))
 	}
 }
+
+// cityTimeText calls get-city-time at propertyEpoch and returns its reply
+func cityTimeText(t *testing.T, params *tools.GetCityTimeParams) (string, error) {
+	t.Helper()
+	tools.SetClock(testsupport.NewFakeClock(propertyEpoch))
+	t.Cleanup(func() { tools.SetClock(clock.System{}) })
+
+	tool := tools.GetCityTime{}
+	result, _, err := tool.Action(context.TODO(), &mcp.CallToolRequest{}, params)
+	if err != nil {
+		return "", err
+	}
+	return result.Content[0].(*mcp.TextContent).Text, nil
+}
+
+func TestGetCityTimeResolvesAnyCityOrZone(t *testing.T) {
+	for _, tt := range []struct {
+		city, want string
+	}{
+		{"Tokyo", "The current time in Tokyo is"},
+		{"Europe/Paris", "The current time in Paris is"},
+		{"America/Argentina/Buenos_Aires", "The current time in Buenos Aires is"},
+		{"Australia/Lord_Howe", "The current time in Australia/Lord_Howe is"},
+		{"europe/berlin", "The current time in Berlin is"},
+		{"new_york", "The current time in New York City is"},
+		{"Sinagpore", "The current time in Singapore is"},
+	} {
+		text, err := cityTimeText(t, &tools.GetCityTimeParams{City: tt.city})
+		if err != nil || !strings.HasPrefix(text, tt.want) {
+			t.Errorf("City %q: expected %q, got %q, %v", tt.city, tt.want, text, err)
+		}
+	}
+}
+
+func TestGetCityTimeFormats(t *testing.T) {
+	for format, want := range map[string]string{
+		"":        propertyEpoch.In(mustLoadLocation(t, "Asia/Tokyo")).Format(time.RFC3339),
+		"unix":    strconv.FormatInt(propertyEpoch.Unix(), 10),
+		"24h":     propertyEpoch.In(mustLoadLocation(t, "Asia/Tokyo")).Format("15:04 JST"),
+		"12h":     propertyEpoch.In(mustLoadLocation(t, "Asia/Tokyo")).Format("3:04 PM JST"),
+		"RFC3339": propertyEpoch.In(mustLoadLocation(t, "Asia/Tokyo")).Format(time.RFC3339),
+	} {
+		text, err := cityTimeText(t, &tools.GetCityTimeParams{City: "tokyo", Format: format})
+		if err != nil || !strings.HasSuffix(text, want) {
+			t.Errorf("Format %q: expected a reply ending in %q, got %q, %v", format, want, text, err)
+		}
+	}
+}
+
+func TestGetCityTimeListsValidOptions(t *testing.T) {
+	_, err := cityTimeText(t, &tools.GetCityTimeParams{City: "Atlantis"})
+	if err == nil || !strings.Contains(err.Error(), "Europe/Paris") || !strings.Contains(err.Error(), "tokyo") {
+		t.Errorf("Expected an unknown city to list valid options, got %v", err)
+	}
+	_, err = cityTimeText(t, &tools.GetCityTimeParams{City: "nyc", Format: "iso"})
+	if err == nil || !strings.Contains(err.Error(), "12h, 24h, rfc3339, unix") {
+		t.Errorf("Expected an unknown format to list valid formats, got %v", err)
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("Failed to load %s: %v", name, err)
+	}
+	return loc
+}
@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/health"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// deploymentStatusAdmin is a get-deployment-status call from an mcp:admin token
+var deploymentStatusAdmin = &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{
+	Scopes:     []string{"mcp:tools", "mcp:admin"},
+	Expiration: time.Now().Add(time.Hour),
+}}}
+
+func TestGetDeploymentStatusWithoutConfiguration(t *testing.T) {
+	t.Setenv("ECS_CLUSTER_NAME", "")
+	t.Setenv("ECS_SERVICE_NAME", "")
+	t.Setenv("CLOUDFORMATION_STACK_NAMES", "")
+
+	tool := tools.GetDeploymentStatus{}
+
+	_, _, err := tool.Action(
+		context.TODO(),
+		deploymentStatusAdmin,
+		&tools.GetDeploymentStatusParams{},
+	)
+
+	if err == nil {
+		t.Errorf("Calling tool \"%s\" without any configured deployments should have failed", tool.Name)
+	}
+}
+
+func TestGetDeploymentStatusReportsInstanceHealth(t *testing.T) {
+	unsetEnv(t, "ECS_CLUSTER_NAME", "ECS_SERVICE_NAME", "CLOUDFORMATION_STACK_NAMES")
+	tools.SetSandbox(true)
+	t.Cleanup(func() { tools.SetSandbox(false) })
+	tools.SetHealthReport(func(context.Context) health.Report {
+		return health.Report{Status: health.StatusDegraded, Checks: map[string]health.Result{
+			"storage":          {Status: health.StatusOK},
+			"circuit-breakers": {Status: health.StatusDegraded, Detail: "github-tools open"},
+		}}
+	})
+	t.Cleanup(func() { tools.SetHealthReport(nil) })
+
+	tool := tools.GetDeploymentStatus{}
+	result, _, err := tool.Action(context.TODO(), deploymentStatusAdmin, &tools.GetDeploymentStatusParams{})
+	text := toolText(t, result, err)
+
+	if !strings.Contains(text, "Instance health: DEGRADED (circuit-breakers degraded: github-tools open)\n") {
+		t.Errorf("Expected the instance health and its failing check, got %q", text)
+	}
+}
+
+func TestGetDeploymentStatusRequiresAdminScope(t *testing.T) {
+	unsetEnv(t, "ECS_CLUSTER_NAME", "ECS_SERVICE_NAME", "CLOUDFORMATION_STACK_NAMES")
+	tools.SetSandbox(true)
+	t.Cleanup(func() { tools.SetSandbox(false) })
+
+	tool := tools.GetDeploymentStatus{}
+	user := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{
+		Scopes:     []string{"mcp:tools"},
+		Expiration: time.Now().Add(time.Hour),
+	}}}
+	if _, _, err := tool.Action(context.TODO(), user, &tools.GetDeploymentStatusParams{}); err == nil {
+		t.Errorf("Calling tool \"%s\" without mcp:admin should have failed", tool.Name)
+	}
+}
+
+func TestGetDeploymentStatusRejectsUnconfiguredService(t *testing.T) {
+	t.Setenv("ECS_CLUSTER_NAME", "app-cluster")
+	t.Setenv("ECS_SERVICE_NAME", "app,worker")
+	t.Setenv("CLOUDFORMATION_STACK_NAMES", "")
+	tools.SetSandbox(true)
+	t.Cleanup(func() { tools.SetSandbox(false) })
+
+	tool := tools.GetDeploymentStatus{}
+	_, _, err := tool.Action(context.TODO(), deploymentStatusAdmin, &tools.GetDeploymentStatusParams{Service: "someone-elses-service"})
+	if err == nil || !strings.Contains(err.Error(), "available: app, worker") {
+		t.Errorf("Expected a service that isn't configured to be rejected, got %v", err)
+	}
+
+	result, _, err := tool.Action(context.TODO(), deploymentStatusAdmin, &tools.GetDeploymentStatusParams{Service: "worker"})
+	text := toolText(t, result, err)
+	if !strings.Contains(text, "worker (cluster app-cluster)") || strings.Contains(text, "ECS service app ") {
+		t.Errorf("Expected only the worker service to be reported, got %q", text)
+	}
+}
+
+func TestGetDeploymentStatusDescribesServicesInBatchesOfTen(t *testing.T) {
+	var services []string
+	for i := 1; i <= 12; i++ {
+		services = append(services, fmt.Sprintf("svc-%d", i))
+	}
+	t.Setenv("ECS_CLUSTER_NAME", "app-cluster")
+	t.Setenv("ECS_SERVICE_NAME", strings.Join(services, ","))
+	t.Setenv("CLOUDFORMATION_STACK_NAMES", "")
+
+	fake := useFakeAWS(t)
+	fake.Handle("AmazonEC2ContainerServiceV20141113.DescribeServices", func(input map[string]any) (any, error) {
+		requested := input["services"].([]any)
+		if len(requested) > 10 {
+			return nil, fmt.Errorf("services can have at most 10 items")
+		}
+		var described []map[string]any
+		for _, name := range requested {
+			described = append(described, map[string]any{"serviceName": name, "status": "ACTIVE", "desiredCount": 1, "runningCount": 1})
+		}
+		return map[string]any{"services": described}, nil
+	})
+
+	tool := tools.GetDeploymentStatus{}
+	result, _, err := tool.Action(context.TODO(), deploymentStatusAdmin, &tools.GetDeploymentStatusParams{})
+	text := toolText(t, result, err)
+
+	calls := fake.Calls()
+	if len(calls) != 2 || len(calls[0].Input["services"].([]any)) != 10 || len(calls[1].Input["services"].([]any)) != 2 {
+		t.Errorf("Expected DescribeServices calls for 10 and 2 services, got %+v", calls)
+	}
+	if !strings.Contains(text, "ECS service svc-1 (cluster app-cluster)") || !strings.Contains(text, "ECS service svc-12 (cluster app-cluster)") {
+		t.Errorf("Expected every service to be reported, got %q", text)
+	}
+}
+
+func TestGetDeploymentStatusSaysWhenNothingIsFound(t *testing.T) {
+	t.Setenv("ECS_CLUSTER_NAME", "app-cluster")
+	t.Setenv("ECS_SERVICE_NAME", "app")
+	t.Setenv("CLOUDFORMATION_STACK_NAMES", "")
+
+	fake := useFakeAWS(t)
+	fake.Handle("AmazonEC2ContainerServiceV20141113.DescribeServices", func(input map[string]any) (any, error) {
+		return map[string]any{"failures": []map[string]any{
+			{"arn": "arn:aws:ecs:us-east-1:123456789012:service/app-cluster/app", "reason": "MISSING"},
+		}}, nil
+	})
+
+	tool := tools.GetDeploymentStatus{}
+	result, _, err := tool.Action(context.TODO(), deploymentStatusAdmin, &tools.GetDeploymentStatusParams{})
+	text := toolText(t, result, err)
+	if !strings.Contains(text, "ECS service app: MISSING") || !strings.Contains(text, "No services/stacks found for cluster app-cluster") {
+		t.Errorf("Expected the missing service and an explicit empty result, got %q", text)
+	}
+}
@@ -2,15 +2,19 @@ package tests
 
 import (
 	"context"
-	"testing"
 	"encoding/json"
+	"testing"
 
-	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 func TestGetFortune(t *testing.T) {
+	recorder := testsupport.NewTestRecorder(t, "get_fortune")
+	useToolsHTTPClient(t, recorder.Client())
+
 	tool := tools.GetFortune{}
 
 	result, _, err := tool.Action(
@@ -20,7 +24,7 @@ func TestGetFortune(t *testing.T) {
 	)
 
 	if err != nil {
-		t.Errorf("Calling tool \"%s\" resulted in an error: %s", tool.Name, err)
+		t.Fatalf("Calling tool \"%s\" resulted in an error: %s", tool.Name, err)
 	}
 
 	var data map[string]interface{}
@@ -0,0 +1,68 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/runbooks"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestGetRunbookListsBuiltinRunbooks(t *testing.T) {
+	t.Setenv("RUNBOOKS_DIR", "")
+	tool := tools.GetRunbook{}
+
+	result, _, err := tool.Action(context.TODO(), &mcp.CallToolRequest{}, &tools.GetRunbookParams{})
+	if err != nil {
+		t.Fatalf("Listing runbooks failed: %v", err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	if !strings.Contains(text, "- maintenance-mode: Put the server in maintenance mode") {
+		t.Errorf("Expected the built-in maintenance runbook to be listed, got %q", text)
+	}
+}
+
+func TestGetRunbookReadsConfiguredDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "failover.md"), []byte("# Fail over the database\n\n1. Promote the replica\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a runbook"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("RUNBOOKS_DIR", dir)
+	tool := tools.GetRunbook{}
+
+	result, _, err := tool.Action(context.TODO(), &mcp.CallToolRequest{}, &tools.GetRunbookParams{Name: "failover"})
+	if err != nil {
+		t.Fatalf("Reading runbook failed: %v", err)
+	}
+	if text := result.Content[0].(*mcp.TextContent).Text; !strings.Contains(text, "Promote the replica") {
+		t.Errorf("Expected the runbook content, got %q", text)
+	}
+
+	list, err := runbooks.FromEnv().List()
+	if err != nil || len(list) != 1 || list[0].Title != "Fail over the database" {
+		t.Errorf("Expected only the Markdown runbook to be listed, got %+v (%v)", list, err)
+	}
+}
+
+func TestGetRunbookRejectsPaths(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "secret.md"), []byte("# Secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	library := runbooks.New(os.DirFS(filepath.Join(dir, "runbooks")))
+
+	for _, name := range []string{"../secret", "/etc/passwd", "..", "missing"} {
+		if _, err := library.Get(name); !errors.Is(err, runbooks.ErrNotFound) {
+			t.Errorf("Expected %q to be not found, got %v", name, err)
+		}
+	}
+}
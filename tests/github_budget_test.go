@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+func TestLowGitHubBudgetOnlyBrieflyReusesValidations(t *testing.T) {
+	// GitHub accepts the token once, reporting a budget below the reserve, then says it was revoked
+	var calls int
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "50")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+		if calls > 1 {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"login": "octocat", "id": 1})
+	}))
+	t.Cleanup(github.Close)
+
+	clock := testsupport.NewFakeClock(time.Now())
+	config := auth.DefaultConfig()
+	config.GitHubAPIURL = github.URL
+	config.TokenExpiryDuration = time.Minute
+	cache := newMemoryCache(t)
+	cache.SetClock(clock)
+	tokenStorage := newMemoryTokens(t)
+	err := tokenStorage.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Scope:             "mcp:tools",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(24 * time.Hour),
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+	verifier := auth.NewGitHubTokenVerifier(config, cache, tokenStorage)
+	verifier.SetHTTPClient(github.Client())
+
+	if _, err := verifier.Verify(context.TODO(), "mcp-token", nil); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	// Once the cached validation expires, the low budget skips re-verification for a few minutes
+	clock.Advance(2 * time.Minute)
+	if _, err := verifier.Verify(context.TODO(), "mcp-token", nil); err != nil || calls != 1 {
+		t.Errorf("Expected the last known good validation to be reused, got %v after %d GitHub calls", err, calls)
+	}
+
+	// After that GitHub is asked again, even on a low budget, and the revoked token is rejected
+	clock.Advance(4 * time.Minute)
+	if _, err := verifier.Verify(context.TODO(), "mcp-token", nil); err == nil || calls != 2 {
+		t.Errorf("Expected the revoked token to be rejected after re-verification, got %v after %d GitHub calls", err, calls)
+	}
+
+	rec := httptest.NewRecorder()
+	auth.NewGitHubBudgetHandler(verifier.Budget()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/github-budget", nil))
+	var body struct {
+		Reserve int                       `json:"reserve"`
+		Tokens  []auth.GitHubBudgetStatus `json:"tokens"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode budget: %v", err)
+	}
+	if body.Reserve != config.GitHubAPIBudgetReserve || len(body.Tokens) != 1 || body.Tokens[0].Remaining != 50 || body.Tokens[0].Limit != 5000 {
+		t.Errorf("Unexpected budget response %+v", body)
+	}
+}
+
+func TestGitHubBudgetRecoversAfterReset(t *testing.T) {
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := testsupport.NewFakeClock(t0)
+	budget := auth.NewGitHubBudget(10)
+	budget.SetClock(clock)
+
+	header := http.Header{}
+	header.Set("X-RateLimit-Limit", "5000")
+	header.Set("X-RateLimit-Remaining", "0")
+	header.Set("X-RateLimit-Reset", fmt.Sprint(t0.Add(time.Hour).Unix()))
+	budget.Observe("github-token", header)
+
+	if err := budget.Allow("github-token", true); !errors.Is(err, auth.ErrGitHubBudgetExhausted) {
+		t.Errorf("Expected an exhausted budget to refuse critical calls, got %v", err)
+	}
+
+	// Once the window resets, the full budget is available again
+	clock.Advance(time.Hour + time.Second)
+	if err := budget.Allow("github-token", false); err != nil {
+		t.Errorf("Expected calls to be allowed after the reset, got %v", err)
+	}
+
+	// The reset window is dropped the next time any response is observed
+	header.Set("X-RateLimit-Remaining", "4999")
+	header.Set("X-RateLimit-Reset", fmt.Sprint(t0.Add(2*time.Hour).Unix()))
+	budget.Observe("other-token", header)
+	if statuses := budget.Snapshot(); len(statuses) != 1 || statuses[0].Remaining != 4999 {
+		t.Errorf("Expected only the other token's window to be tracked, got %+v", statuses)
+	}
+}
+
+func TestGitHubToolsSpendTheSharedBudget(t *testing.T) {
+	var calls int
+	github := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "50")
+		w.Header().Set("X-RateLimit-Reset", fmt.Sprint(time.Now().Add(time.Hour).Unix()))
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(github.Close)
+	useToolsHTTPClient(t, github.Client())
+	tools.SetGitHubAPIURL(github.URL)
+	t.Cleanup(func() { tools.SetGitHubAPIURL("https://api.github.com") })
+	budget := auth.NewGitHubBudget(100)
+	tools.SetGitHubBudget(budget)
+	t.Cleanup(func() { tools.SetGitHubBudget(auth.NewGitHubBudget(0)) })
+
+	tool := tools.ListMyRepos{}
+	if _, _, err := tool.Action(context.TODO(), githubToolRequest("repo"), &tools.ListMyReposParams{}); err != nil {
+		t.Fatalf("Expected the first call to go through, got %v", err)
+	}
+	if statuses := budget.Snapshot(); len(statuses) != 1 || statuses[0].Remaining != 50 {
+		t.Fatalf("Expected the tool call's rate limit to be recorded, got %+v", statuses)
+	}
+
+	// Tool calls are non-critical, so the reserve is kept for verifying the user
+	_, _, err := tool.Action(context.TODO(), githubToolRequest("repo"), &tools.ListMyReposParams{})
+	if !errors.Is(err, auth.ErrGitHubBudgetExhausted) || calls != 1 {
+		t.Errorf("Expected the call to be refused without reaching GitHub, got %v after %d calls", err, calls)
+	}
+	if err := budget.Allow("github-token", true); err != nil {
+		t.Errorf("Expected critical calls to still be allowed, got %v", err)
+	}
+}
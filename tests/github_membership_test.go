@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// fakeGitHubMembership serves /user for every token and reports active membership only for the
+// given org and team membership paths
+func fakeGitHubMembership(t *testing.T, login string, activePaths ...string) *httptest.Server {
+	active := make(map[string]bool, len(activePaths))
+	for _, path := range activePaths {
+		active[path] = true
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/user":
+			_ = json.NewEncoder(w).Encode(map[string]any{"login": login, "id": 1})
+		case active[r.URL.Path]:
+			_ = json.NewEncoder(w).Encode(map[string]string{"state": "active"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// membershipRequest sends an authenticated request through RequireAuth with the given policy
+func membershipRequest(t *testing.T, server *httptest.Server, orgs, teams []string) *httptest.ResponseRecorder {
+	config := auth.DefaultConfig()
+	config.GitHubAPIURL = server.URL
+	config.GitHubAllowedOrgs = orgs
+	config.GitHubAllowedTeams = teams
+
+	tokenStorage := newMemoryTokens(t)
+	err := tokenStorage.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Scope:             "mcp:tools",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(time.Hour),
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), tokenStorage)
+	handler := auth.NewMiddleware(config, verifier).RequireAuth([]string{"mcp:tools"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Authorization", "Bearer mcp-token")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGitHubMembershipAllowsOrgMember(t *testing.T) {
+	server := fakeGitHubMembership(t, "octocat", "/user/memberships/orgs/acme")
+
+	if rec := membershipRequest(t, server, []string{"other", "acme"}, nil); rec.Code != http.StatusOK {
+		t.Errorf("Org member got status %d, expected 200: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGitHubMembershipAllowsTeamMember(t *testing.T) {
+	server := fakeGitHubMembership(t, "octocat", "/orgs/acme/teams/platform/memberships/octocat")
+
+	if rec := membershipRequest(t, server, []string{"other"}, []string{"acme/platform"}); rec.Code != http.StatusOK {
+		t.Errorf("Team member got status %d, expected 200: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGitHubMembershipDeniesNonMember(t *testing.T) {
+	server := fakeGitHubMembership(t, "octocat")
+
+	rec := membershipRequest(t, server, []string{"acme"}, []string{"acme/platform"})
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("Non-member got status %d, expected 403: %s", rec.Code, rec.Body.String())
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Denial body is not JSON: %v (%q)", err, rec.Body.String())
+	}
+	if body["error"] != "access_denied" {
+		t.Errorf("Expected access_denied error, got %q", body["error"])
+	}
+	expected := "GitHub user octocat is not a member of an allowed organization or team (acme, acme/platform)"
+	if body["error_description"] != expected {
+		t.Errorf("Unexpected error description %q", body["error_description"])
+	}
+}
+
+func TestGitHubMembershipPolicyRequiresValidTeams(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.GitHubAllowedTeams = []string{"acme"}
+
+	for _, issue := range config.ValidationReport().Fatal() {
+		if issue.Field == "GITHUB_ALLOWED_TEAMS" {
+			return
+		}
+	}
+	t.Error("Expected a team without a slug to be rejected")
+}
@@ -0,0 +1,219 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// fakeGitHubRepos serves a repository list and the issues of octocat/hello-world to
+// "github-token", and records the issue created last
+func fakeGitHubRepos(t *testing.T, created *map[string]any) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("Authorization") != "Bearer github-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"message":"Bad credentials"}`))
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/user/repos":
+			if r.URL.Query().Get("visibility") != "private" || r.URL.Query().Get("per_page") != "5" {
+				t.Errorf("Unexpected repository query: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[{"full_name":"octocat/secret","description":"Private notes","private":true,"stargazers_count":1,"open_issues_count":4,"updated_at":"2025-03-01T10:00:00Z"}]`))
+		case r.Method == http.MethodGet && r.URL.Path == "/repos/octocat/hello-world/issues":
+			if r.URL.Query().Get("state") != "open" || r.URL.Query().Get("labels") != "bug,ui" {
+				t.Errorf("Unexpected issues query: %s", r.URL.RawQuery)
+			}
+			_, _ = w.Write([]byte(`[
+				{"number":7,"title":"Button is misaligned","state":"open","html_url":"https://github.com/octocat/hello-world/issues/7","user":{"login":"hubot"},"labels":[{"name":"bug"},{"name":"ui"}],"comments":2,"updated_at":"2025-03-02T10:00:00Z"},
+				{"number":8,"title":"Fix the button","state":"open","html_url":"https://github.com/octocat/hello-world/pull/8","user":{"login":"hubot"},"labels":[],"comments":0,"updated_at":"2025-03-02T11:00:00Z","pull_request":{}}
+			]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/repos/octocat/hello-world/issues":
+			_ = json.NewDecoder(r.Body).Decode(created)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"number":9,"title":"Crash on start","state":"open","html_url":"https://github.com/octocat/hello-world/issues/9"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not Found"}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	useToolsHTTPClient(t, server.Client())
+	tools.SetGitHubAPIURL(server.URL)
+	t.Cleanup(func() { tools.SetGitHubAPIURL("https://api.github.com") })
+	return server
+}
+
+// githubToolRequest is a tools/call request from a user who signed in with a GitHub token
+// granted scopes
+func githubToolRequest(scopes ...string) *mcp.CallToolRequest {
+	return &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: &sdkauth.TokenInfo{
+		Scopes: []string{"mcp:tools"},
+		Extra: map[string]any{
+			"subject":       "octocat",
+			"github_token":  "github-token",
+			"github_scopes": scopes,
+		},
+	}}}
+}
+
+func toolText(t *testing.T, result *mcp.CallToolResult, err error) string {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Tool call failed: %v", err)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func TestListMyRepos(t *testing.T) {
+	fakeGitHubRepos(t, nil)
+
+	tool := tools.ListMyRepos{}
+	result, _, err := tool.Action(context.TODO(), githubToolRequest("read:user", "repo"), &tools.ListMyReposParams{Visibility: "Private", Limit: 5})
+	text := toolText(t, result, err)
+
+	if !strings.Contains(text, "- octocat/secret (private, 1 stars, 4 open issues, updated 2025-03-01): Private notes") {
+		t.Errorf("Unexpected repository list: %q", text)
+	}
+}
+
+func TestGetRepoIssuesLeavesOutPullRequests(t *testing.T) {
+	fakeGitHubRepos(t, nil)
+
+	tool := tools.GetRepoIssues{}
+	result, _, err := tool.Action(context.TODO(), githubToolRequest("repo"), &tools.GetRepoIssuesParams{Repo: "octocat/hello-world", Labels: "bug, ui"})
+	text := toolText(t, result, err)
+
+	if !strings.HasPrefix(text, "1 open issues in octocat/hello-world:") || !strings.Contains(text, "#7 Button is misaligned (open, by hubot, 2 comments, updated 2025-03-02) [bug, ui]") {
+		t.Errorf("Unexpected issue list: %q", text)
+	}
+	if strings.Contains(text, "#8") {
+		t.Errorf("Pull requests should be left out: %q", text)
+	}
+}
+
+func TestCreateIssue(t *testing.T) {
+	var created map[string]any
+	fakeGitHubRepos(t, &created)
+
+	tool := tools.CreateIssue{}
+	result, _, err := tool.Action(context.TODO(), githubToolRequest("repo"), &tools.CreateIssueParams{
+		Repo:   "octocat/hello-world",
+		Title:  " Crash on start ",
+		Body:   "It crashes.",
+		Labels: []string{"bug"},
+	})
+	text := toolText(t, result, err)
+
+	if text != "Created issue #9 in octocat/hello-world: Crash on start\nhttps://github.com/octocat/hello-world/issues/9" {
+		t.Errorf("Unexpected response: %q", text)
+	}
+	if created["title"] != "Crash on start" || created["body"] != "It crashes." || !slices.Equal(created["labels"].([]any), []any{"bug"}) {
+		t.Errorf("Unexpected issue sent to GitHub: %v", created)
+	}
+}
+
+func TestGitHubToolsRequireRepoScope(t *testing.T) {
+	fakeGitHubRepos(t, nil)
+
+	tool := tools.ListMyRepos{}
+	if _, _, err := tool.Action(context.TODO(), githubToolRequest("read:user"), &tools.ListMyReposParams{}); err == nil || !strings.Contains(err.Error(), "GitHub repo scope") {
+		t.Errorf("Expected the repo scope to be required, got %v", err)
+	}
+
+	// Tokens without a GitHub token, such as JWTs, can't act on GitHub
+	req := githubToolRequest("repo")
+	delete(req.Extra.TokenInfo.Extra, "github_token")
+	if _, _, err := tool.Action(context.TODO(), req, &tools.ListMyReposParams{}); err == nil {
+		t.Error("Expected an error without a GitHub token")
+	}
+
+	issues := tools.GetRepoIssues{}
+	if _, _, err := issues.Action(context.TODO(), githubToolRequest("repo"), &tools.GetRepoIssuesParams{Repo: "../etc"}); err == nil {
+		t.Error("Expected an invalid repository to be rejected")
+	}
+}
+
+func TestVerifierPassesGitHubTokenToTools(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-OAuth-Scopes", "read:user, repo")
+		_ = json.NewEncoder(w).Encode(map[string]any{"login": "octocat", "id": 1})
+	}))
+	t.Cleanup(server.Close)
+
+	config := auth.DefaultConfig()
+	config.GitHubAPIURL = server.URL
+	tokenStorage := newMemoryTokens(t)
+	err := tokenStorage.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Scope:             "mcp:tools read:user",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(time.Hour),
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), tokenStorage)
+
+	// Both the first verification and the cached one carry the GitHub token and its scopes
+	for range 2 {
+		info, err := verifier.Verify(context.TODO(), "mcp-token", nil)
+		if err != nil {
+			t.Fatalf("Verify resulted in an error: %v", err)
+		}
+		if info.Extra["github_token"] != "github-token" || !slices.Equal(info.Extra["github_scopes"].([]string), []string{"read:user", "repo"}) {
+			t.Errorf("Expected the GitHub token and scopes in the token info, got %v", info.Extra)
+		}
+	}
+}
+
+func TestGitHubToolsAreHiddenWithJWTAccessTokens(t *testing.T) {
+	tools.SetJWTAccessTokens(true)
+	t.Cleanup(func() { tools.SetJWTAccessTokens(false) })
+
+	names := listPersonaTools(t, tools.Persona{Name: "jwt", Tools: []string{"list-my-repos", "create-issue", "get-city-time"}})
+	if !slices.Equal(names, []string{"get-city-time"}) {
+		t.Errorf("Expected the GitHub user tools to be hidden, got %v", names)
+	}
+
+	// A verified JWT carries no GitHub token, so a direct call is refused with the reason
+	clock := testsupport.NewFakeClock(time.Now())
+	issuer := newTestJWTIssuer(t, clock)
+	token, err := issuer.Issue(&auth.AccessTokenInfo{
+		ClientID:  "vscode",
+		Scope:     "mcp:tools",
+		ExpiresAt: clock.Now().Add(time.Hour),
+		CreatedAt: clock.Now(),
+	}, "octocat")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	verifier := auth.NewGitHubTokenVerifier(auth.DefaultConfig(), nil, newMemoryTokens(t))
+	verifier.SetJWTIssuer(issuer)
+	info, err := verifier.Verify(context.TODO(), token, nil)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+
+	tool := tools.ListMyRepos{}
+	req := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: info}}
+	if _, _, err := tool.Action(context.TODO(), req, &tools.ListMyReposParams{}); err == nil || !strings.Contains(err.Error(), "TOKEN_FORMAT=opaque") {
+		t.Errorf("Expected JWT callers to be told the tool needs opaque tokens, got %v", err)
+	}
+}
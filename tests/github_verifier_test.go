@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+func TestGitHubTokenVerifierResolvesUser(t *testing.T) {
+	recorder := testsupport.NewTestRecorder(t, "github_user")
+
+	config := auth.DefaultConfig()
+	tokenStorage := newMemoryTokens(t)
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), tokenStorage)
+	verifier.SetHTTPClient(recorder.Client())
+
+	err := tokenStorage.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Scope:             "mcp:tools read:user",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(time.Hour),
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+
+	tokenInfo, err := verifier.Verify(context.TODO(), "mcp-token", nil)
+	if err != nil {
+		t.Fatalf("Verify resulted in an error: %v", err)
+	}
+
+	if subject := tokenInfo.Extra["subject"]; subject != "octocat" {
+		t.Errorf("Verify returned subject %v, expected octocat", subject)
+	}
+
+	// The second verification must be served from the cache, since the fixture only has one interaction
+	if _, err := verifier.Verify(context.TODO(), "mcp-token", nil); err != nil {
+		t.Errorf("Second Verify resulted in an error: %v", err)
+	}
+
+	budget := verifier.Budget().Snapshot()
+	if len(budget) != 1 || budget[0].Remaining != 4999 {
+		t.Errorf("Expected rate limit budget to be tracked from response headers, got %+v", budget)
+	}
+}
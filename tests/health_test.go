@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/breaker"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/health"
+)
+
+func staticCheck(status health.Status) health.Check {
+	return func(ctx context.Context) health.Result {
+		return health.Result{Status: status}
+	}
+}
+
+func TestHealthPlainTextForLoadBalancer(t *testing.T) {
+	checker := health.New()
+	checker.Register("storage", staticCheck(health.StatusOK))
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		checker.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+		return rec
+	}
+
+	if rec := get(); rec.Code != http.StatusOK || rec.Body.String() != "OK" {
+		t.Errorf("Expected 200 OK, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	checker.Register("token_cache", staticCheck(health.StatusDegraded))
+	rec := get()
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Body.String(), "DEGRADED\n") || !strings.Contains(rec.Body.String(), "token_cache: degraded") {
+		t.Errorf("Degraded should stay in service and name the check, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	checker.Register("storage", staticCheck(health.StatusUnhealthy))
+	rec = get()
+	if rec.Code != http.StatusServiceUnavailable || !strings.HasPrefix(rec.Body.String(), "UNHEALTHY") {
+		t.Errorf("Expected 503 UNHEALTHY, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestHealthJSONReport(t *testing.T) {
+	checker := health.New()
+	checker.Register("storage", health.LatencyCheck(func(ctx context.Context) error { return nil }, time.Second))
+	checker.Register("token_cache", health.HitRateCheck(func() (int64, int64) { return 10, 90 }, 0.5, 100))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/health?format=json", nil),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, "/health", nil)
+			r.Header.Set("Accept", "application/json")
+			return r
+		}(),
+	} {
+		rec := httptest.NewRecorder()
+		checker.ServeHTTP(rec, req)
+
+		var report health.Report
+		if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+			t.Fatalf("Expected a JSON report, got %q: %v", rec.Body.String(), err)
+		}
+		if report.Status != health.StatusDegraded {
+			t.Errorf("Expected the worst check's status, got %s", report.Status)
+		}
+		if report.Checks["storage"].Status != health.StatusOK {
+			t.Errorf("Expected storage ok, got %+v", report.Checks["storage"])
+		}
+		cache := report.Checks["token_cache"]
+		if cache.Status != health.StatusDegraded || cache.Metrics["hit_rate"] != 0.1 {
+			t.Errorf("Expected a degraded 10%% hit rate, got %+v", cache)
+		}
+	}
+}
+
+func TestHealthLatencyCheck(t *testing.T) {
+	slow := health.LatencyCheck(func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}, 5*time.Millisecond)
+	if result := slow(context.Background()); result.Status != health.StatusDegraded {
+		t.Errorf("Expected a slow ping to be degraded, got %+v", result)
+	}
+
+	failing := health.LatencyCheck(func(ctx context.Context) error { return errors.New("connection refused") }, time.Second)
+	if result := failing(context.Background()); result.Status != health.StatusUnhealthy || result.Detail != "connection refused" {
+		t.Errorf("Expected a failing ping to be unhealthy, got %+v", result)
+	}
+}
+
+func TestHealthHitRateNeedsEnoughLookups(t *testing.T) {
+	check := health.HitRateCheck(func() (int64, int64) { return 0, 10 }, 0.5, 100)
+	if result := check(context.Background()); result.Status != health.StatusOK {
+		t.Errorf("A few misses after startup shouldn't degrade health, got %+v", result)
+	}
+}
+
+func TestHealthBudgetCheck(t *testing.T) {
+	lowest := func(remaining int) func() (int, int, bool) {
+		return func() (int, int, bool) { return remaining, 5000, true }
+	}
+	if result := health.BudgetCheck(lowest(4000), 100)(context.Background()); result.Status != health.StatusOK {
+		t.Errorf("Expected a healthy budget to be ok, got %+v", result)
+	}
+	result := health.BudgetCheck(lowest(100), 100)(context.Background())
+	if result.Status != health.StatusDegraded || result.Metrics["lowest_remaining"] != 100 {
+		t.Errorf("Expected a budget down to the reserve to be degraded, got %+v", result)
+	}
+}
+
+func TestHealthBreakerCheck(t *testing.T) {
+	b := breaker.New("test-health", 1, time.Minute)
+	t.Cleanup(b.Reset)
+
+	check := health.BreakerCheck(breaker.Default)
+	if result := check(context.Background()); strings.Contains(result.Detail, "test-health") {
+		t.Fatalf("A closed breaker shouldn't be reported, got %+v", result)
+	}
+
+	b.Failure(errors.New("upstream down"))
+	result := check(context.Background())
+	if result.Status != health.StatusDegraded || !strings.Contains(result.Detail, "test-health") {
+		t.Errorf("Expected an open breaker to degrade health, got %+v", result)
+	}
+}
+
+func TestVerifierCountsTokenCacheHits(t *testing.T) {
+	server := fakeGitHubMembership(t, "octocat")
+
+	config := auth.DefaultConfig()
+	config.GitHubAPIURL = server.URL
+
+	tokenStorage := newMemoryTokens(t)
+	err := tokenStorage.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Scope:             "mcp:tools",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(time.Hour),
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), tokenStorage)
+	for range 3 {
+		if _, err := verifier.Verify(context.TODO(), "mcp-token", nil); err != nil {
+			t.Fatalf("Verify resulted in an error: %v", err)
+		}
+	}
+
+	if stats := verifier.Stats(); stats.CacheMisses != 1 || stats.CacheHits != 2 {
+		t.Errorf("Expected 1 cache miss then 2 hits, got %+v", stats)
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/instance"
+)
+
+func TestInstanceDetectsECSTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/task" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+			"Cluster": "arn:aws:ecs:eu-west-1:123456789012:cluster/mcp",
+			"TaskARN": "arn:aws:ecs:eu-west-1:123456789012:task/mcp/0123456789abcdef",
+			"AvailabilityZone": "eu-west-1b"
+		}`))
+	}))
+	defer server.Close()
+
+	detector := &instance.Detector{Client: server.Client(), ECSMetadataURI: server.URL}
+	metadata, err := detector.Detect(context.TODO())
+	if err != nil {
+		t.Fatalf("Detect resulted in an error: %v", err)
+	}
+
+	expected := instance.Metadata{
+		Region:           "eu-west-1",
+		AvailabilityZone: "eu-west-1b",
+		InstanceID:       "0123456789abcdef",
+		Cluster:          "mcp",
+	}
+	if metadata != expected {
+		t.Errorf("Detect returned %+v, expected %+v", metadata, expected)
+	}
+	if tag := metadata.String(); tag != "eu-west-1/eu-west-1b/0123456789abcdef" {
+		t.Errorf("Unexpected log tag %q", tag)
+	}
+}
+
+func TestInstanceDetectsEC2WithIMDSv2(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("imds-token"))
+		case r.URL.Path == "/latest/dynamic/instance-identity/document" && r.Header.Get("X-aws-ec2-metadata-token") == "imds-token":
+			_, _ = w.Write([]byte(`{"region": "us-west-2", "availabilityZone": "us-west-2a", "instanceId": "i-0abc"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	detector := &instance.Detector{Client: server.Client(), IMDSEndpoint: server.URL}
+	metadata, err := detector.Detect(context.TODO())
+	if err != nil {
+		t.Fatalf("Detect resulted in an error: %v", err)
+	}
+	if metadata.Region != "us-west-2" || metadata.AvailabilityZone != "us-west-2a" || metadata.InstanceID != "i-0abc" {
+		t.Errorf("Detect returned %+v", metadata)
+	}
+}
+
+func TestInstanceFallsBackToAWSRegion(t *testing.T) {
+	t.Setenv("AWS_REGION", "ap-southeast-2")
+
+	metadata, err := (&instance.Detector{}).Detect(context.TODO())
+	if err != nil {
+		t.Fatalf("Detect resulted in an error: %v", err)
+	}
+	if metadata.Region != "ap-southeast-2" {
+		t.Errorf("Expected region from AWS_REGION, got %+v", metadata)
+	}
+
+	event := metadata.Tag(map[string]any{"event": "test"})
+	if event["region"] != "ap-southeast-2" || event["instance_id"] != nil {
+		t.Errorf("Tag should only add known fields, got %v", event)
+	}
+}
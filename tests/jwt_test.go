@@ -0,0 +1,340 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/adminapi"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	jwtTestKeyOnce sync.Once
+	jwtTestKey     *rsa.PrivateKey
+)
+
+// testSigningKey returns an RSA key shared by the JWT tests, since generating one is slow
+func testSigningKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	jwtTestKeyOnce.Do(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("Failed to generate RSA key: %v", err)
+		}
+		jwtTestKey = key
+	})
+	return jwtTestKey
+}
+
+func newTestJWTIssuer(t *testing.T, clock *testsupport.FakeClock) *auth.JWTIssuer {
+	t.Helper()
+	issuer, err := auth.NewJWTIssuer(testSigningKey(t), "http://localhost:8080")
+	if err != nil {
+		t.Fatalf("NewJWTIssuer failed: %v", err)
+	}
+	issuer.SetClock(clock)
+	return issuer
+}
+
+func TestJWTAccessTokenVerifiesWithoutStorage(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	config := auth.DefaultConfig()
+	config.Clock = clock
+	config.TokenFormat = auth.TokenFormatJWT
+	issuer := newTestJWTIssuer(t, clock)
+
+	storage := newMemoryTokens(t)
+	storage.SetClock(clock)
+	handler := auth.NewTokenEndpointHandler(config, newMemoryClientsWithDefaults(t), storage)
+	handler.SetJWTIssuer(issuer)
+
+	verifier := "verifier-" + strings.Repeat("x", 40)
+	err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{
+		ClientID:            "vscode",
+		RedirectURI:         "http://127.0.0.1:33418",
+		Scope:               "mcp:tools read:user",
+		CodeChallenge:       pkceChallenge(verifier),
+		CodeChallengeMethod: "S256",
+		Resource:            "http://localhost:8080",
+		GitHubAccessToken:   "github-token",
+		Subject:             "octocat",
+		ExpiresAt:           clock.Now().Add(10 * time.Minute),
+		CreatedAt:           clock.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store auth code: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(
+		"grant_type=authorization_code&code=code&client_id=vscode&redirect_uri=http://127.0.0.1:33418&code_verifier="+verifier))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Token request failed with status %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to decode token response: %v", err)
+	}
+	if strings.Contains(response.AccessToken, "github-token") {
+		t.Errorf("JWT embeds the GitHub access token")
+	}
+
+	// A verifier with empty storage and no GitHub access must still accept the token
+	tokenVerifier := auth.NewGitHubTokenVerifier(config, nil, newMemoryTokens(t))
+	tokenVerifier.SetHTTPClient(&http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		t.Errorf("Verifier called %s", r.URL)
+		return nil, http.ErrHandlerTimeout
+	})})
+	tokenVerifier.SetJWTIssuer(issuer)
+
+	info, err := tokenVerifier.Verify(context.TODO(), response.AccessToken, nil)
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if info.Extra["subject"] != "octocat" || info.Extra["client_id"] != "vscode" || info.Extra["resource"] != "http://localhost:8080" {
+		t.Errorf("Unexpected token info: %+v", info.Extra)
+	}
+	if len(info.Scopes) != 2 || info.Scopes[0] != "mcp:tools" {
+		t.Errorf("Unexpected scopes: %v", info.Scopes)
+	}
+
+	clock.Advance(config.TokenExpiryDuration + time.Second)
+	if _, err := tokenVerifier.Verify(context.TODO(), response.AccessToken, nil); err == nil {
+		t.Errorf("Expired JWT verified")
+	}
+}
+
+func TestJWTRejectsForgedTokens(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Now())
+	issuer := newTestJWTIssuer(t, clock)
+
+	valid, err := issuer.Issue(&auth.AccessTokenInfo{
+		ClientID:  "vscode",
+		Scope:     "mcp:tools",
+		ExpiresAt: clock.Now().Add(time.Hour),
+		CreatedAt: clock.Now(),
+	}, "octocat")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	if _, err := issuer.Verify(valid); err != nil {
+		t.Fatalf("Verify rejected a valid token: %v", err)
+	}
+
+	claims := jwt.MapClaims{
+		"iss":       "http://localhost:8080",
+		"sub":       "mallory",
+		"client_id": "vscode",
+		"scope":     "mcp:tools mcp:admin",
+		"exp":       clock.Now().Add(time.Hour).Unix(),
+	}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate RSA key: %v", err)
+	}
+	otherSigned := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	otherSigned.Header["kid"] = issuer.KeyID()
+	wrongKey, _ := otherSigned.SignedString(otherKey)
+
+	hmacSigned := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	hmacSigned.Header["kid"] = issuer.KeyID()
+	hmac, _ := hmacSigned.SignedString([]byte("secret"))
+
+	unsigned, _ := jwt.NewWithClaims(jwt.SigningMethodNone, claims).SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	parts := strings.Split(valid, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	tamperedPayload := strings.Replace(string(payload), `"scope":"mcp:tools"`, `"scope":"mcp:admin"`, 1)
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(tamperedPayload)) + "." + parts[2]
+
+	for name, token := range map[string]string{
+		"wrong key": wrongKey,
+		"hmac":      hmac,
+		"none":      unsigned,
+		"tampered":  tampered,
+	} {
+		if _, err := issuer.Verify(token); err == nil {
+			t.Errorf("Verify accepted a %s token", name)
+		}
+	}
+}
+
+func TestJWTIsOnlyForThisServer(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Now())
+	issuer := newTestJWTIssuer(t, clock)
+
+	for resource, valid := range map[string]bool{
+		"":                                     true,
+		"http://localhost:8080":                true,
+		"http://localhost:8080/personas/x/mcp": true,
+		"http://localhost:8080.evil.example":   false,
+		"https://other.example/mcp":            false,
+	} {
+		token, err := issuer.Issue(&auth.AccessTokenInfo{
+			ClientID:  "vscode",
+			Scope:     "mcp:tools",
+			Resource:  resource,
+			ExpiresAt: clock.Now().Add(time.Hour),
+			CreatedAt: clock.Now(),
+		}, "octocat")
+		if err != nil {
+			t.Fatalf("Issue failed: %v", err)
+		}
+		if _, err := issuer.Verify(token); (err == nil) != valid {
+			t.Errorf("Verify of a token for %q returned %v", resource, err)
+		}
+	}
+
+	// Tokens we signed without an audience or an ID are not ours to accept
+	for name, claims := range map[string]jwt.MapClaims{
+		"no audience": {"iss": "http://localhost:8080", "jti": "id", "exp": clock.Now().Add(time.Hour).Unix()},
+		"no ID":       {"iss": "http://localhost:8080", "aud": "http://localhost:8080", "exp": clock.Now().Add(time.Hour).Unix()},
+	} {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = issuer.KeyID()
+		signed, _ := token.SignedString(testSigningKey(t))
+		if _, err := issuer.Verify(signed); err == nil {
+			t.Errorf("Verify accepted a token with %s", name)
+		}
+	}
+}
+
+func TestJWTRevocation(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Now())
+	config := auth.DefaultConfig()
+	config.Clock = clock
+	issuer := newTestJWTIssuer(t, clock)
+	tokens := newMemoryTokens(t)
+	tokens.SetClock(clock)
+	verifier := auth.NewGitHubTokenVerifier(config, nil, tokens)
+	verifier.SetJWTIssuer(issuer)
+
+	token, err := issuer.Issue(&auth.AccessTokenInfo{
+		ClientID:  "vscode",
+		Scope:     "mcp:tools",
+		ExpiresAt: clock.Now().Add(time.Hour),
+		CreatedAt: clock.Now(),
+	}, "octocat")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	if _, err := verifier.Verify(context.TODO(), token, nil); err != nil {
+		t.Fatalf("Verify failed before revocation: %v", err)
+	}
+
+	handler := adminapi.NewTokensHandler(tokens)
+	handler.SetJWTIssuer(issuer)
+	revoke := func() int {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/tokens/revoke", strings.NewReader(`{"token":"`+token+`"}`)))
+		return rec.Code
+	}
+	if status := revoke(); status != http.StatusNoContent {
+		t.Fatalf("Expected status 204 revoking a JWT, got %d", status)
+	}
+	if _, err := verifier.Verify(context.TODO(), token, nil); err == nil {
+		t.Errorf("Revoked JWT still verifies")
+	}
+	if status := revoke(); status != http.StatusNotFound {
+		t.Errorf("Expected status 404 revoking a JWT twice, got %d", status)
+	}
+
+	// The revocation is only kept as long as the token could verify
+	clock.Advance(time.Hour + time.Second)
+	tokens.Sweep()
+	if codes, _ := tokens.Len(); codes != 0 {
+		t.Errorf("Expected the revocation to be swept with the token's expiry, got %d codes", codes)
+	}
+}
+
+func TestJWKSHandler(t *testing.T) {
+	issuer := newTestJWTIssuer(t, testsupport.NewFakeClock(time.Now()))
+
+	rec := httptest.NewRecorder()
+	auth.NewJWKSHandler(issuer).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var jwks struct {
+		Keys []auth.JSONWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &jwks); err != nil {
+		t.Fatalf("Failed to decode JWKS: %v", err)
+	}
+	if len(jwks.Keys) != 1 {
+		t.Fatalf("Expected 1 key, got %d", len(jwks.Keys))
+	}
+	key := jwks.Keys[0]
+	if key.KeyID != issuer.KeyID() || key.Algorithm != "RS256" || key.KeyType != "RSA" {
+		t.Errorf("Unexpected key: %+v", key)
+	}
+	modulus, _ := base64.RawURLEncoding.DecodeString(key.Modulus)
+	if new(big.Int).SetBytes(modulus).Cmp(testSigningKey(t).N) != 0 {
+		t.Errorf("JWKS modulus doesn't match the signing key")
+	}
+}
+
+func TestAuthServerMetadataAdvertisesJWKS(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.TokenFormat = auth.TokenFormatJWT
+
+	rec := httptest.NewRecorder()
+	auth.NewAuthServerMetadataHandler(config).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/oauth-authorization-server", nil))
+
+	var metadata auth.AuthServerMetadata
+	if err := json.Unmarshal(rec.Body.Bytes(), &metadata); err != nil {
+		t.Fatalf("Failed to decode metadata: %v", err)
+	}
+	if metadata.JWKSURI != "http://localhost:8080/.well-known/jwks.json" {
+		t.Errorf("Unexpected jwks_uri %q", metadata.JWKSURI)
+	}
+}
+
+func TestJWTConfigValidation(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.TokenFormat = auth.TokenFormatJWT
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SIGNING_KEY") {
+		t.Errorf("Expected a missing signing key error, got %v", err)
+	}
+
+	config.JWTSigningKey = "not a key"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "JWT_SIGNING_KEY") {
+		t.Errorf("Expected an invalid signing key error, got %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(testSigningKey(t))
+	if err != nil {
+		t.Fatalf("Failed to encode key: %v", err)
+	}
+	config.JWTSigningKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err := config.Validate(); err != nil && strings.Contains(err.Error(), "JWT_SIGNING_KEY") {
+		t.Errorf("PKCS#8 signing key rejected: %v", err)
+	}
+
+	config.TokenFormat = "paseto"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "TOKEN_FORMAT") {
+		t.Errorf("Expected an unknown token format error, got %v", err)
+	}
+}
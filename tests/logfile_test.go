@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/logfile"
+)
+
+func TestLogFileRotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "access.log")
+	file, err := logfile.OpenWithOptions(path, logfile.Options{MaxSize: 10})
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	for _, line := range []string{"first\n", "second\n", "third\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+
+	current, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if string(current) != "third\n" {
+		t.Errorf("Expected only the latest line in the current file, got %q", current)
+	}
+
+	backups, err := file.Backups()
+	if err != nil {
+		t.Fatalf("Failed to list backups: %v", err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("Expected 2 rotated files, got %v", backups)
+	}
+}
+
+func TestLogFileCompressesAndPrunesBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	file, err := logfile.OpenWithOptions(path, logfile.Options{Compress: true, MaxBackups: 1})
+	if err != nil {
+		t.Fatalf("Failed to open log file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	for _, line := range []string{"one\n", "two\n"} {
+		if _, err := file.Write([]byte(line)); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		if err := file.Rotate(); err != nil {
+			t.Fatalf("Rotate failed: %v", err)
+		}
+	}
+
+	// Compression and pruning happen in the background
+	var backups []string
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		backups, _ = file.Backups()
+		if len(backups) == 1 && strings.HasSuffix(backups[0], ".gz") {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(backups) != 1 || !strings.HasSuffix(backups[0], ".gz") {
+		t.Fatalf("Expected a single compressed backup, got %v", backups)
+	}
+
+	compressed, err := os.Open(backups[0])
+	if err != nil {
+		t.Fatalf("Failed to open backup: %v", err)
+	}
+	defer func() { _ = compressed.Close() }()
+	reader, err := gzip.NewReader(compressed)
+	if err != nil {
+		t.Fatalf("Backup is not gzipped: %v", err)
+	}
+	content, _ := io.ReadAll(reader)
+	if string(content) != "two\n" {
+		t.Errorf("Expected the newest backup to be kept, got %q", content)
+	}
+}
+
+func TestLogFileOptionsFromEnv(t *testing.T) {
+	t.Setenv("AUDIT_LOG_MAX_SIZE_MB", "5")
+	t.Setenv("AUDIT_LOG_ROTATE_INTERVAL", "24h")
+	t.Setenv("AUDIT_LOG_COMPRESS", "true")
+	t.Setenv("AUDIT_LOG_MAX_BACKUPS", "7")
+
+	opts, err := logfile.OptionsFromEnv("AUDIT")
+	if err != nil {
+		t.Fatalf("OptionsFromEnv failed: %v", err)
+	}
+	want := logfile.Options{MaxSize: 5 * 1024 * 1024, Interval: 24 * time.Hour, Compress: true, MaxBackups: 7}
+	if opts != want {
+		t.Errorf("Got %+v, want %+v", opts, want)
+	}
+
+	t.Setenv("AUDIT_LOG_ROTATE_INTERVAL", "daily")
+	if _, err := logfile.OptionsFromEnv("AUDIT"); err == nil {
+		t.Errorf("Expected an invalid interval to be rejected")
+	}
+}
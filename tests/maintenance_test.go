@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/maintenance"
+)
+
+func TestMaintenanceModeRejectsMCPRequests(t *testing.T) {
+	mode := maintenance.New()
+	handler := mode.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected requests to pass when maintenance is off, got %d", rec.Code)
+	}
+
+	mode.Enable("Upgrading the database")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503 during maintenance, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected a Retry-After header")
+	}
+
+	var body struct {
+		Error struct {
+			Code int                `json:"code"`
+			Data maintenance.Status `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode maintenance error: %v", err)
+	}
+	if body.Error.Code != -32000 || body.Error.Data.Message != "Upgrading the database" {
+		t.Errorf("Unexpected maintenance error: %s", rec.Body.String())
+	}
+}
+
+func TestMaintenanceModeReadinessAndAdmin(t *testing.T) {
+	mode := maintenance.New()
+	admin := mode.AdminHandler()
+
+	rec := httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/maintenance", strings.NewReader(`{"enabled":true}`)))
+	if rec.Code != http.StatusOK || !mode.Status().Enabled {
+		t.Fatalf("Expected maintenance to be enabled, got status %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mode.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected readiness to fail during maintenance, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	admin.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/maintenance", strings.NewReader(`{"enabled":false}`)))
+	if mode.Status().Enabled {
+		t.Fatalf("Expected maintenance to be disabled")
+	}
+
+	rec = httptest.NewRecorder()
+	mode.ReadinessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected readiness to pass after maintenance, got %d", rec.Code)
+	}
+}
+
+func TestReadinessFailsWhenCheckFails(t *testing.T) {
+	mode := maintenance.New()
+	var storageErr error
+	ready := mode.ReadinessHandler(func(ctx context.Context) error { return storageErr })
+
+	rec := httptest.NewRecorder()
+	ready.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected readiness to pass, got %d", rec.Code)
+	}
+
+	storageErr = errors.New("unable to reach Redis")
+	rec = httptest.NewRecorder()
+	ready.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected readiness to fail while storage is unreachable, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "unable to reach Redis") {
+		t.Errorf("Readiness response doesn't explain the failure: %s", rec.Body.String())
+	}
+}
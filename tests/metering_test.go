@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/metering"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// meteredRequests sends a tool call and a listing through the metering middleware as user via client
+func meteredRequests(meter *metering.Meter, user, client string) {
+	handler := metering.Middleware(meter)(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	extra := &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{
+		Expiration: time.Now().Add(time.Hour),
+		Extra:      map[string]any{"subject": user, "client_id": client},
+	}}
+	_, _ = handler(context.TODO(), "tools/call", &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get-fortune"}, Extra: extra})
+	_, _ = handler(context.TODO(), "tools/list", &mcp.ListToolsRequest{Extra: extra})
+}
+
+func TestMeterFlushesPeriodsPerUserAndClient(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	meter := metering.New()
+	meter.SetClock(clock)
+
+	meteredRequests(meter, "octocat", "vscode")
+	meteredRequests(meter, "octocat", "vscode")
+	meteredRequests(meter, "hubot", "ci")
+	meteredRequests(meter, "", "vscode")
+	clock.Advance(time.Hour)
+
+	records := meter.Flush()
+	if len(records) != 2 {
+		t.Fatalf("Expected usage for two users, got %+v", records)
+	}
+	octocat := records[1]
+	if octocat.User != "octocat" || octocat.ClientID != "vscode" || octocat.Messages != 4 || octocat.ToolCalls != 2 {
+		t.Errorf("Unexpected usage for octocat: %+v", octocat)
+	}
+	if !octocat.PeriodStart.Equal(propertyEpoch) || !octocat.PeriodEnd.Equal(propertyEpoch.Add(time.Hour)) {
+		t.Errorf("Unexpected period %s-%s", octocat.PeriodStart, octocat.PeriodEnd)
+	}
+	if records := meter.Flush(); len(records) != 0 {
+		t.Errorf("Expected a flush to start an empty period, got %+v", records)
+	}
+}
+
+func TestUsageExportFormats(t *testing.T) {
+	records := []metering.Record{{
+		PeriodStart: propertyEpoch,
+		PeriodEnd:   propertyEpoch.Add(time.Hour),
+		User:        "octocat",
+		ClientID:    "vscode",
+		Messages:    4,
+	}}
+
+	var csv bytes.Buffer
+	if err := metering.WriteCSV(&csv, records); err != nil {
+		t.Fatalf("WriteCSV failed: %v", err)
+	}
+	expected := "period_start,period_end,user,client_id,messages,tool_calls\n2025-01-01T00:00:00Z,2025-01-01T01:00:00Z,octocat,vscode,4,0\n"
+	if csv.String() != expected {
+		t.Errorf("Unexpected CSV:\n%s", csv.String())
+	}
+
+	// Meters with nothing to report are left out
+	var stripe bytes.Buffer
+	if err := metering.WriteStripe(&stripe, records); err != nil {
+		t.Fatalf("WriteStripe failed: %v", err)
+	}
+	expected = `{"event_name":"mcp_messages","timestamp":1735693200,"identifier":"octocat:vscode:1735689600:mcp_messages","payload":{"client_id":"vscode","stripe_customer_id":"octocat","value":"4"}}` + "\n"
+	if stripe.String() != expected {
+		t.Errorf("Unexpected Stripe events:\n%s", stripe.String())
+	}
+}
+
+func TestUsageExportToDirectory(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	meter := metering.New()
+	meter.SetClock(clock)
+	dir := t.TempDir()
+	exporter := &metering.Exporter{Meter: meter, Sink: metering.DirSink(dir), Format: metering.FormatCSV}
+
+	if err := exporter.Export(context.TODO()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("Expected no file for a period without usage, got %d", len(entries))
+	}
+
+	meteredRequests(meter, "octocat", "vscode")
+	clock.Advance(time.Hour)
+	if err := exporter.Export(context.TODO()); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "usage-20250101T000000Z-20250101T010000Z.csv"))
+	if err != nil {
+		t.Fatalf("Expected a usage file: %v", err)
+	}
+	if !strings.Contains(string(data), "octocat,vscode,2,1") {
+		t.Errorf("Unexpected usage file:\n%s", data)
+	}
+}
+
+func TestUsageExportToS3(t *testing.T) {
+	var path, authorization, body string
+	s3 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, authorization = r.URL.Path, r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer s3.Close()
+
+	sink := &metering.S3Sink{
+		Bucket:      "usage",
+		Prefix:      "mcp",
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKID", "secret", ""),
+		Endpoint:    s3.URL,
+		Client:      s3.Client(),
+	}
+	if err := sink.Put(context.TODO(), "usage.csv", []byte("records")); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if path != "/usage/mcp/usage.csv" || body != "records" {
+		t.Errorf("Expected the file at /usage/mcp/usage.csv, got %s with %q", path, body)
+	}
+	if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/") {
+		t.Errorf("Expected a SigV4 signature, got %q", authorization)
+	}
+}
@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+)
+
+// listPersonaTools registers the persona's tools with a new server and returns the names it lists
+func listPersonaTools(t *testing.T, persona tools.Persona) []string {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-" + persona.Name, Version: "1.0.0"}, nil)
+	tools.RegisterPersona(server, persona)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	var names []string
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+func TestParsePersonas(t *testing.T) {
+	personas, err := tools.ParsePersonas("tools=get-city-time, get-fortune; admin(mcp:tools mcp:admin)=get-aws-costs,tail-logs;")
+	if err != nil {
+		t.Fatalf("ParsePersonas failed: %v", err)
+	}
+	if len(personas) != 2 {
+		t.Fatalf("Expected 2 personas, got %+v", personas)
+	}
+
+	general, admin := personas[0], personas[1]
+	if general.Name != "tools" || general.Path() != "/mcp/tools" || !slices.Equal(general.Tools, []string{"get-city-time", "get-fortune"}) {
+		t.Errorf("Unexpected tools persona: %+v", general)
+	}
+	if !slices.Equal(general.Scopes, []string{"mcp:tools"}) {
+		t.Errorf("Expected personas to require mcp:tools by default, got %v", general.Scopes)
+	}
+	if admin.Name != "admin" || admin.Path() != "/mcp/admin" || !slices.Equal(admin.Scopes, []string{"mcp:tools", "mcp:admin"}) {
+		t.Errorf("Unexpected admin persona: %+v", admin)
+	}
+
+	if personas, err := tools.ParsePersonas(""); err != nil || len(personas) != 0 {
+		t.Errorf("Expected no personas when unset, got %+v, %v", personas, err)
+	}
+}
+
+func TestParsePersonasInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"tools",
+		"tools=",
+		"Tools=get-fortune",
+		"a/b=get-fortune",
+		"admin(mcp:admin=get-aws-costs",
+		"admin()=get-aws-costs",
+		"tools=get-fortune;tools=get-city-time",
+	} {
+		if _, err := tools.ParsePersonas(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestPersonaServesOnlyItsTools(t *testing.T) {
+	t.Setenv("TOOLS_ENABLED", "")
+	t.Setenv("TOOLS_DISABLED", "get-fortune")
+
+	persona := tools.Persona{Name: "tools", Tools: []string{"get-city-time", "get-fortune"}}
+	if names := listPersonaTools(t, persona); !slices.Equal(names, []string{"get-city-time"}) {
+		t.Errorf("Expected only get-city-time (get-fortune is disabled), got %v", names)
+	}
+
+	// The main server still serves every other tool
+	names := listRegisteredTools(t)
+	if !slices.Contains(names, "tail-logs") || !slices.Contains(names, "get-city-time") {
+		t.Errorf("Expected the main server to keep serving every tool, got %v", names)
+	}
+}
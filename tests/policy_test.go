@@ -0,0 +1,108 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/policy"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// fakeOPA answers Data API queries with result for every input it receives
+func fakeOPA(t *testing.T, result func(input policy.Input) any) *policy.OPA {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input policy.Input `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("Failed to decode OPA query: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"result": result(body.Input)})
+	}))
+	t.Cleanup(server.Close)
+	return &policy.OPA{URL: server.URL + "/v1/data/mcp/authz", Client: server.Client()}
+}
+
+// callWithPolicy runs a tools/call through PolicyMiddleware as user and reports whether the
+// tool handler was reached
+func callWithPolicy(engine policy.Engine, user, tool string) (bool, error) {
+	reached := false
+	handler := tools.PolicyMiddleware(engine)(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		reached = true
+		return &mcp.CallToolResult{}, nil
+	})
+
+	req := &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Name: tool, Arguments: json.RawMessage(`{"days":7}`)},
+		Extra: &mcp.RequestExtra{
+			TokenInfo: &auth.TokenInfo{
+				Scopes:     []string{"mcp:tools"},
+				Expiration: time.Now().Add(time.Hour),
+				Extra:      map[string]any{"subject": user, "client_id": "vscode"},
+			},
+		},
+	}
+	_, err := handler(context.TODO(), "tools/call", req)
+	return reached, err
+}
+
+func TestPolicyDeniesByRule(t *testing.T) {
+	var seen policy.Input
+	engine := fakeOPA(t, func(input policy.Input) any {
+		seen = input
+		if input.User == "intern" && input.Tool == "get-aws-costs" {
+			return map[string]any{"allow": false, "reason": "interns cannot read AWS costs"}
+		}
+		return map[string]any{"allow": true}
+	})
+
+	reached, err := callWithPolicy(engine, "intern", "get-aws-costs")
+	if reached || err == nil || !strings.Contains(err.Error(), "interns cannot read AWS costs") {
+		t.Errorf("Expected the intern's call to be denied with the policy's reason, got %v", err)
+	}
+	if seen.ClientID != "vscode" || string(seen.Args) != `{"days":7}` || len(seen.Scopes) != 1 {
+		t.Errorf("Policy input is missing call details: %+v", seen)
+	}
+
+	if reached, err := callWithPolicy(engine, "octocat", "get-aws-costs"); !reached || err != nil {
+		t.Errorf("Expected other users to be allowed, got %v", err)
+	}
+}
+
+func TestPolicyAcceptsBooleanResult(t *testing.T) {
+	engine := fakeOPA(t, func(input policy.Input) any { return input.Tool != "tail-logs" })
+
+	if reached, err := callWithPolicy(engine, "octocat", "get-fortune"); !reached || err != nil {
+		t.Errorf("Expected get-fortune to be allowed, got %v", err)
+	}
+	if reached, _ := callWithPolicy(engine, "octocat", "tail-logs"); reached {
+		t.Errorf("Expected tail-logs to be denied")
+	}
+}
+
+func TestPolicyFailsClosed(t *testing.T) {
+	// An undefined rule has no result, as when the policy isn't loaded
+	undefined := fakeOPA(t, func(policy.Input) any { return nil })
+	if reached, err := callWithPolicy(undefined, "octocat", "get-fortune"); reached || err == nil {
+		t.Errorf("Expected an undefined policy to deny the call")
+	}
+
+	unreachable := &policy.OPA{URL: "http://127.0.0.1:1/v1/data/mcp/authz", Client: &http.Client{Timeout: time.Second}}
+	if reached, err := callWithPolicy(unreachable, "octocat", "get-fortune"); reached || err == nil {
+		t.Errorf("Expected an unreachable policy engine to deny the call")
+	}
+
+	if reached, err := callWithPolicy(nil, "octocat", "get-fortune"); !reached || err != nil {
+		t.Errorf("Expected calls to be allowed without a policy engine, got %v", err)
+	}
+}
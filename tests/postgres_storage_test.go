@@ -0,0 +1,33 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+func TestNewStoragePostgresUnreachable(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.StorageBackend = auth.StorageBackendPostgres
+	config.PostgresURL = "postgres://mcp@127.0.0.1:1/mcp?connect_timeout=1"
+
+	if _, err := auth.NewStorage(context.Background(), config); err == nil {
+		t.Errorf("NewStorage succeeded without a reachable Postgres")
+	}
+}
+
+func TestConfigValidationRequiresPostgresURL(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.StorageBackend = auth.StorageBackendPostgres
+
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "POSTGRES_URL") {
+		t.Errorf("Expected a POSTGRES_URL error, got %v", err)
+	}
+
+	config.PostgresURL = "postgres://mcp@localhost/mcp?pool_max_conns=lots"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "POSTGRES_URL") {
+		t.Errorf("Expected an invalid POSTGRES_URL error, got %v", err)
+	}
+}
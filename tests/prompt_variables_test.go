@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/activity"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/prompts"
+)
+
+func TestPromptTemplateVariables(t *testing.T) {
+	prompts.SetServerURL("https://mcp.example.com")
+	t.Cleanup(func() { prompts.SetServerURL("") })
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if r, ok := req.(*mcp.GetPromptRequest); ok {
+				r.Extra = &mcp.RequestExtra{TokenInfo: &sdkauth.TokenInfo{
+					Scopes:     []string{"mcp:tools", "mcp:admin"},
+					Expiration: time.Now().Add(time.Hour),
+					Extra:      map[string]any{"subject": "octocat"},
+				}}
+			}
+			return next(ctx, method, req)
+		}
+	})
+	prompts.RegisterAll(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil).Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	result, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "register-mcp-client",
+		Arguments: map[string]string{"client_name": "{{server_url}}"},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt failed: %v", err)
+	}
+	text := result.Messages[0].Content.(*mcp.TextContent).Text
+	active := strconv.Itoa(activity.Default.ActiveUsers(24 * time.Hour))
+	for _, want := range []string{
+		"I'm octocat, an admin of the MCP server at https://mcp.example.com (" + active + " users active",
+		"https://mcp.example.com/oauth/token",
+		// Arguments are passed through as they are
+		`named "{{server_url}}"`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected the prompt to contain %q, got %q", want, text)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	legacyauth "EmmanuelDamienDustinDeploymentProject/DeploymentProject/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	pkgauth "EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/server"
+	legacyprompts "EmmanuelDamienDustinDeploymentProject/DeploymentProject/prompts"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+	legacytools "EmmanuelDamienDustinDeploymentProject/DeploymentProject/tools"
+)
+
+// The public and deprecated packages alias the internal types, so values pass between them
+var (
+	_ auth.ClientStorage   = pkgauth.ClientStorage((*auth.InMemoryClientStorage)(nil))
+	_ *pkgauth.Config      = (*legacyauth.Config)(nil)
+	_ *auth.Storage        = (*pkgauth.Storage)(nil)
+	_ tools.Persona        = server.Persona{}
+	_ pkgauth.TokenStorage = (*legacyauth.InMemoryTokenStorage)(nil)
+	_                      = legacytools.RegisterAll
+	_                      = legacyprompts.RegisterAll
+	_                      = testsupport.RunStateStorageContract
+)
+
+// publicPackages are the packages other modules can import; everything else lives under
+// internal/ or is a command
+var publicPackages = []string{
+	"auth",
+	"pkg/auth",
+	"pkg/server",
+	"prompts",
+	"testsupport",
+	"tools",
+}
+
+func TestPublicPackagesArePinned(t *testing.T) {
+	var found []string
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel("..", path)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel == "internal" || rel == "cmd" || rel == "tests" || strings.HasPrefix(rel, ".") && rel != "." {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(rel, ".go") && !strings.HasSuffix(rel, "_test.go") {
+			if dir := filepath.ToSlash(filepath.Dir(rel)); !slices.Contains(found, dir) {
+				found = append(found, dir)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walking the module failed: %v", err)
+	}
+	slices.Sort(found)
+	if !slices.Equal(found, publicPackages) {
+		t.Errorf("Public packages changed: got %v, want %v; move implementation packages under internal/", found, publicPackages)
+	}
+}
+
+func TestServerNewServesPersonaTools(t *testing.T) {
+	t.Setenv("TOOLS_ENABLED", "")
+	t.Setenv("TOOLS_DISABLED", "")
+
+	var methods []string
+	recordMethods := func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			methods = append(methods, method)
+			return next(ctx, method, req)
+		}
+	}
+	personas, err := server.ParsePersonas("time=get-city-time")
+	if err != nil {
+		t.Fatalf("ParsePersonas failed: %v", err)
+	}
+	mcpServer := server.New(server.Options{
+		Name:       "test-time",
+		Persona:    &personas[0],
+		Middleware: []mcp.Middleware{recordMethods},
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := mcpServer.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	if info := session.InitializeResult().ServerInfo; info.Name != "test-time" || info.Version != "1.0.0" {
+		t.Errorf("Unexpected server info: %+v", info)
+	}
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	if len(result.Tools) != 1 || result.Tools[0].Name != "get-city-time" {
+		t.Errorf("Expected only the persona's get-city-time tool, got %+v", result.Tools)
+	}
+	prompts, err := session.ListPrompts(context.Background(), nil)
+	if err != nil || len(prompts.Prompts) == 0 {
+		t.Errorf("Expected the prompts to be served, got %+v, %v", prompts, err)
+	}
+	if !slices.Contains(methods, "tools/list") {
+		t.Errorf("Expected the middleware to see tools/list, got %v", methods)
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/ratelimit"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+func TestLimiterRefillsAtConfiguredRate(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	limiter := ratelimit.New(60, 2)
+	limiter.SetClock(clock)
+
+	for i := 0; i < 2; i++ {
+		if ok, _ := limiter.Allow("key"); !ok {
+			t.Fatalf("Request %d within the burst was rejected", i+1)
+		}
+	}
+
+	ok, wait := limiter.Allow("key")
+	if ok {
+		t.Fatal("Request beyond the burst was allowed")
+	}
+	if wait != time.Second {
+		t.Errorf("Expected to wait 1s for the next token at 60/min, got %v", wait)
+	}
+
+	if ok, _ := limiter.Allow("other"); !ok {
+		t.Error("Keys should have independent buckets")
+	}
+
+	clock.Advance(time.Second)
+	if ok, _ := limiter.Allow("key"); !ok {
+		t.Error("Request after the bucket refilled was rejected")
+	}
+}
+
+func TestLimiterDisabledAtZeroRate(t *testing.T) {
+	limiter := ratelimit.New(0, 1)
+	for i := 0; i < 100; i++ {
+		if ok, _ := limiter.Allow("key"); !ok {
+			t.Fatal("A disabled limiter rejected a request")
+		}
+	}
+}
+
+func TestRateLimitMiddlewareLimitsPerToken(t *testing.T) {
+	handler := ratelimit.Middleware(ratelimit.New(60, 1), nil, false)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("token-a"); rec.Code != http.StatusOK {
+		t.Fatalf("First request got status %d", rec.Code)
+	}
+	rec := send("token-a")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Second request got status %d, expected 429", rec.Code)
+	}
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", retryAfter)
+	}
+	if rec := send("token-b"); rec.Code != http.StatusOK {
+		t.Errorf("Another token should not be limited, got status %d", rec.Code)
+	}
+}
+
+func TestRateLimitMiddlewareLimitsPerIP(t *testing.T) {
+	handler := ratelimit.Middleware(nil, ratelimit.New(60, 1), true)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.RemoteAddr = "10.0.0.1:4321" // the load balancer
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := send("203.0.113.7"); code != http.StatusOK {
+		t.Fatalf("First request got status %d", code)
+	}
+	// A spoofed leading entry must not let the client escape its limit
+	if code := send("198.51.100.1, 203.0.113.7"); code != http.StatusTooManyRequests {
+		t.Errorf("Second request from the same client got status %d, expected 429", code)
+	}
+	if code := send("203.0.113.8"); code != http.StatusOK {
+		t.Errorf("Another client behind the same load balancer should not be limited, got status %d", code)
+	}
+}
+
+func TestWarnerWarnsOnceNearTheLimit(t *testing.T) {
+	clock := testsupport.NewFakeClock(propertyEpoch)
+	limiter := ratelimit.New(60, 20)
+	limiter.SetClock(clock)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(ratelimit.NewWarner(limiter, 0.5).Middleware())
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	httpServer := httptest.NewServer(ratelimit.Middleware(limiter, nil, false)(handler))
+	defer httpServer.Close()
+
+	warnings := make(chan *mcp.LoggingMessageParams, 10)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) { warnings <- req.Params },
+	})
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint: httpServer.URL,
+		HTTPClient: &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			r.Header.Set("Authorization", "Bearer mcp-token")
+			return http.DefaultTransport.RoundTrip(r)
+		})},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer session.Close()
+	if err := session.SetLoggingLevel(context.Background(), &mcp.SetLoggingLevelParams{Level: "warning"}); err != nil {
+		t.Fatalf("SetLoggingLevel failed: %v", err)
+	}
+
+	// The clock is frozen, so every request uses up part of the burst
+	for i := range 12 {
+		if err := session.Ping(context.Background(), nil); err != nil {
+			t.Fatalf("Ping %d failed: %v", i+1, err)
+		}
+	}
+
+	select {
+	case warning := <-warnings:
+		if warning.Level != "warning" || warning.Logger != "ratelimit" {
+			t.Errorf("Unexpected warning %+v", warning)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected a rate limit warning")
+	}
+	select {
+	case warning := <-warnings:
+		t.Errorf("Expected a single warning until the bucket refills, also got %+v", warning)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if usage := limiter.Usage(ratelimit.TokenKey("mcp-token")); usage < 0.5 || usage >= 1 {
+		t.Errorf("Expected usage between the warning and the limit, got %v", usage)
+	}
+}
@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+var (
+	sharedRedisOnce   sync.Once
+	sharedRedisClient *redis.Client
+	redisPrefixCount  atomic.Int64
+)
+
+// sharedRedis returns a client for an in-process Redis shared by every test in the package
+// Tests isolate themselves with uniqueRedisPrefix instead of starting a server each
+func sharedRedis() *redis.Client {
+	sharedRedisOnce.Do(func() {
+		server, err := miniredis.Run()
+		if err != nil {
+			panic(fmt.Sprintf("failed to start miniredis: %v", err))
+		}
+		sharedRedisClient = redis.NewClient(&redis.Options{Addr: server.Addr()})
+	})
+	return sharedRedisClient
+}
+
+// uniqueRedisPrefix returns a key prefix no other test uses
+func uniqueRedisPrefix() string {
+	return fmt.Sprintf("test%d:", redisPrefixCount.Add(1))
+}
+
+func TestRedisStorageNeverStoresRawTokens(t *testing.T) {
+	server := miniredis.RunT(t)
+	storage := auth.NewRedisStorage(redis.NewClient(&redis.Options{Addr: server.Addr()}), "mcp:")
+
+	err := storage.StoreAccessToken("raw-bearer-token", &auth.AccessTokenInfo{
+		ClientID:  "vscode",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+
+	for _, key := range server.Keys() {
+		if strings.Contains(key, "raw-bearer-token") {
+			t.Errorf("Raw token used as a Redis key: %s", key)
+		}
+		if !strings.HasPrefix(key, "mcp:") {
+			t.Errorf("Key %s is missing the configured prefix", key)
+		}
+	}
+}
+
+func TestRedisStorageSetsTTLs(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	storage := auth.NewRedisStorage(client, "")
+	states := auth.NewRedisStateStore(client, "", 5*time.Minute)
+
+	if err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{ExpiresAt: time.Now().Add(10 * time.Minute)}); err != nil {
+		t.Fatalf("StoreAuthCode failed: %v", err)
+	}
+	if err := storage.Set("token", &auth.TokenValidationResult{Valid: true}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := states.Store("state", &auth.AuthState{CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	if len(server.Keys()) != 3 {
+		t.Fatalf("Expected 3 keys, got %v", server.Keys())
+	}
+	for _, key := range server.Keys() {
+		if ttl := server.TTL(key); ttl <= 0 || ttl > 10*time.Minute {
+			t.Errorf("Key %s has TTL %v", key, ttl)
+		}
+	}
+
+	// Redis drops the keys itself once their TTL passes
+	server.FastForward(11 * time.Minute)
+	if len(server.Keys()) != 0 {
+		t.Errorf("Expected all keys to expire, got %v", server.Keys())
+	}
+}
+
+func TestRedisStateSharedAcrossInstances(t *testing.T) {
+	server := miniredis.RunT(t)
+	config := auth.DefaultConfig()
+	clients := newMemoryClientsWithDefaults(t)
+
+	// Each instance has its own connection, as it would behind a load balancer
+	newStates := func() auth.StateStorage {
+		return auth.NewRedisStateStore(redis.NewClient(&redis.Options{Addr: server.Addr()}), "mcp:", config.AuthStateTTL)
+	}
+	first := auth.NewAuthorizationHandlerWithStateStorage(config, clients, newStates())
+	second := newStates()
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", "vscode")
+	query.Set("redirect_uri", "http://127.0.0.1:33418")
+	query.Set("code_challenge", pkceChallenge(strings.Repeat("v", 43)))
+	query.Set("code_challenge_method", "S256")
+
+	rec := httptest.NewRecorder()
+	first.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/authorize?"+query.Encode(), nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("Expected redirect to GitHub, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatalf("Invalid redirect: %v", err)
+	}
+	state := location.Query().Get("state")
+
+	authState, ok := second.Get(state)
+	if !ok {
+		t.Fatalf("State stored by one instance was not visible to another")
+	}
+	if authState.ClientID != "vscode" {
+		t.Errorf("Expected client vscode, got %s", authState.ClientID)
+	}
+}
+
+func TestNewStorageRedisBackend(t *testing.T) {
+	server := miniredis.RunT(t)
+	config := auth.DefaultConfig()
+	config.StorageBackend = auth.StorageBackendRedis
+	config.RedisURL = "redis://" + server.Addr() + "/0"
+
+	storage, err := auth.NewStorage(context.Background(), config)
+	if err != nil {
+		t.Fatalf("NewStorage failed: %v", err)
+	}
+	defer storage.Close()
+	if _, err := storage.Clients.GetClient("vscode"); err != nil {
+		t.Errorf("Default client not registered: %v", err)
+	}
+	if err := storage.Ping(context.Background()); err != nil {
+		t.Errorf("Ping failed: %v", err)
+	}
+	server.Close()
+	if err := storage.Ping(context.Background()); err == nil {
+		t.Errorf("Ping succeeded after Redis stopped")
+	}
+
+	config.RedisURL = "redis://127.0.0.1:1/0"
+	if _, err := auth.NewStorage(context.Background(), config); err == nil {
+		t.Errorf("NewStorage succeeded without a reachable Redis")
+	}
+}
+
+func TestConfigValidationRequiresRedisURL(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.StorageBackend = auth.StorageBackendRedis
+
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "REDIS_URL") {
+		t.Errorf("Expected a REDIS_URL error, got %v", err)
+	}
+
+	config.RedisURL = "http://not-redis"
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "REDIS_URL") {
+		t.Errorf("Expected an invalid REDIS_URL error, got %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/prompts"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+)
+
+func TestRegisterClientReturnsOneTimeCredentials(t *testing.T) {
+	t.Setenv("TOOLS_ENABLED", "")
+	t.Setenv("TOOLS_DISABLED", "")
+
+	config := auth.DefaultConfig()
+	config.EnableDCR = false
+	clients := newMemoryClients(t)
+	tools.SetClientRegistrar(auth.NewRegistrationHandler(config, clients))
+	t.Cleanup(func() { tools.SetClientRegistrar(nil) })
+	// The credentials wait in the auth state store, which other instances share
+	states := newStateStore(t, time.Minute)
+	tools.SetSecretStore(states, time.Minute)
+	t.Cleanup(func() { tools.SetSecretStore(nil, 0) })
+
+	// Requests arrive as user, with the admin scope
+	user := "octocat"
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	server.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			extra := &mcp.RequestExtra{TokenInfo: &sdkauth.TokenInfo{
+				Scopes:     []string{"mcp:tools", "mcp:admin"},
+				Expiration: time.Now().Add(time.Hour),
+				Extra:      map[string]any{"subject": user},
+			}}
+			switch r := req.(type) {
+			case *mcp.CallToolRequest:
+				r.Extra = extra
+			case *mcp.ReadResourceRequest:
+				r.Extra = extra
+			}
+			return next(ctx, method, req)
+		}
+	})
+	tools.RegisterAll(server)
+	prompts.RegisterAll(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil).Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	prompt, err := session.GetPrompt(context.Background(), &mcp.GetPromptParams{
+		Name:      "register-mcp-client",
+		Arguments: map[string]string{"client_name": "CI deployer", "client_type": "service"},
+	})
+	if err != nil || !strings.Contains(prompt.Messages[0].Content.(*mcp.TextContent).Text, "register-client") {
+		t.Fatalf("Expected the prompt to lead to register-client, got %+v, %v", prompt, err)
+	}
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{
+		Name: "register-client",
+		Arguments: map[string]any{
+			"client_name":                "CI deployer",
+			"token_endpoint_auth_method": "client_secret_basic",
+			"grant_types":                []string{"client_credentials"},
+		},
+	})
+	if err != nil || result.IsError {
+		t.Fatalf("register-client failed: %+v, %v", result, err)
+	}
+	text := result.Content[0].(*mcp.TextContent).Text
+	link, ok := result.Content[1].(*mcp.ResourceLink)
+	if !ok {
+		t.Fatalf("Expected a link to the credentials, got %+v", result.Content)
+	}
+	key := "secret:" + strings.TrimPrefix(link.URI, "secret://credentials/")
+	if stored, ok := states.Get(key); !ok || stored.Subject != "octocat" || !strings.Contains(text, "within 1m0s") {
+		t.Fatalf("Expected the credentials in the state store for a minute, got %+v and %q", stored, text)
+	}
+
+	// Someone else can't read the credentials, and doesn't use them up
+	user = "hubot"
+	if _, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: link.URI}); err == nil {
+		t.Fatalf("Expected another user to be refused the credentials")
+	}
+
+	user = "octocat"
+	read, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: link.URI})
+	if err != nil {
+		t.Fatalf("Reading the credentials failed: %v", err)
+	}
+	var credentials map[string]string
+	_ = json.Unmarshal([]byte(read.Contents[0].Text), &credentials)
+	if credentials["client_secret"] == "" || credentials["registration_access_token"] == "" || strings.Contains(text, credentials["client_secret"]) {
+		t.Errorf("Expected the secrets only in the resource, got %v and %q", credentials, text)
+	}
+	if client, err := clients.GetClient(credentials["client_id"]); err != nil || client.Metadata.ClientName != "CI deployer" {
+		t.Errorf("Expected the client to be stored even with DCR disabled, got %+v, %v", client, err)
+	}
+
+	if _, err := session.ReadResource(context.Background(), &mcp.ReadResourceParams{URI: link.URI}); err == nil {
+		t.Errorf("Expected the credentials to be readable only once")
+	}
+	if _, ok := states.Get(key); ok {
+		t.Errorf("Expected reading the credentials to delete them from the state store")
+	}
+}
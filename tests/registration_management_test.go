@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// manageRegistration sends method to the registration's management URL with token
+func manageRegistration(handler http.Handler, method, clientID, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/register/"+clientID, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRegistrationManagement(t *testing.T) {
+	config := auth.DefaultConfig()
+	clients := newMemoryClients(t)
+	handler := auth.NewRegistrationHandler(config, clients)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/register",
+		strings.NewReader(`{"client_name":"editor","redirect_uris":["http://127.0.0.1:9000/callback"],"token_endpoint_auth_method":"client_secret_basic"}`)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("Registration returned %d: %s", rec.Code, rec.Body.String())
+	}
+	var registered auth.ClientRegistrationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &registered); err != nil {
+		t.Fatalf("Failed to decode registration response: %v", err)
+	}
+	if registered.RegistrationAccessToken == "" || registered.RegistrationClientURI != config.ServerURL+"/register/"+registered.ClientID {
+		t.Fatalf("Expected a registration access token and client URI, got %+v", registered)
+	}
+	clientID, token := registered.ClientID, registered.RegistrationAccessToken
+
+	rec = manageRegistration(handler, http.MethodGet, clientID, token, "")
+	var read auth.ClientRegistrationResponse
+	_ = json.Unmarshal(rec.Body.Bytes(), &read)
+	if rec.Code != http.StatusOK || read.ClientName != "editor" || read.ClientSecret != "" || read.RegistrationAccessToken != "" {
+		t.Errorf("Expected the registration without credentials, got %d %s", rec.Code, rec.Body.String())
+	}
+
+	// Wrong tokens and unknown clients look the same
+	for _, tt := range []struct{ clientID, token string }{{clientID, "wrong"}, {"unknown", token}, {"vscode", ""}} {
+		if rec := manageRegistration(handler, http.MethodGet, tt.clientID, tt.token, ""); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for client %q with token %q, got %d", tt.clientID, tt.token, rec.Code)
+		}
+	}
+
+	rec = manageRegistration(handler, http.MethodPut, clientID, token,
+		`{"client_id":"`+clientID+`","client_name":"renamed","redirect_uris":["http://127.0.0.1:9001/callback"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Update returned %d: %s", rec.Code, rec.Body.String())
+	}
+	client, _ := clients.GetClient(clientID)
+	if client.Metadata.ClientName != "renamed" || client.Metadata.RedirectURIs[0] != "http://127.0.0.1:9001/callback" {
+		t.Errorf("Expected the stored metadata to be replaced, got %+v", client.Metadata)
+	}
+	if valid, _ := clients.ValidateClientSecret(clientID, registered.ClientSecret); !valid {
+		t.Errorf("Expected the client secret to survive an update")
+	}
+
+	for _, body := range []string{
+		`{"client_id":"other","client_name":"renamed","redirect_uris":["http://127.0.0.1:9001/callback"]}`,
+		`{"client_id":"` + clientID + `","token_endpoint_auth_method":"none","redirect_uris":["http://127.0.0.1:9001/callback"]}`,
+		`{"client_id":"` + clientID + `","redirect_uris":[]}`,
+	} {
+		if rec := manageRegistration(handler, http.MethodPut, clientID, token, body); rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for update %s, got %d", body, rec.Code)
+		}
+	}
+
+	if rec := manageRegistration(handler, http.MethodDelete, clientID, token, ""); rec.Code != http.StatusNoContent {
+		t.Fatalf("Delete returned %d: %s", rec.Code, rec.Body.String())
+	}
+	if client, err := clients.GetClient(clientID); err == nil && client != nil {
+		t.Errorf("Expected the client to be deleted")
+	}
+	if rec := manageRegistration(handler, http.MethodGet, clientID, token, ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected the token to stop working once the client is deleted, got %d", rec.Code)
+	}
+}
+
+func TestRegistrationScopes(t *testing.T) {
+	config := auth.DefaultConfig()
+	config.ScopesSupported = append(config.ScopesSupported, "mcp:admin")
+	clients := newMemoryClients(t)
+	handler := auth.NewRegistrationHandler(config, clients)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/register",
+		strings.NewReader(`{"client_name":"ci","grant_types":["client_credentials"],"scope":"mcp:tools repo:delete"}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected an unsupported scope to be rejected at registration, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/register",
+		strings.NewReader(`{"client_name":"ci","grant_types":["client_credentials"],"token_endpoint_auth_method":"client_secret_basic","scope":"mcp:tools mcp:resources"}`)))
+	var registered auth.ClientRegistrationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &registered); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("Registration returned %d: %s", rec.Code, rec.Body.String())
+	}
+	clientID, token := registered.ClientID, registered.RegistrationAccessToken
+
+	// A client allowed the client_credentials grant must not grant itself mcp:admin afterwards
+	rec = manageRegistration(handler, http.MethodPut, clientID, token,
+		`{"client_id":"`+clientID+`","grant_types":["client_credentials"],"scope":"mcp:tools mcp:admin"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected widening the scopes to be rejected, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = manageRegistration(handler, http.MethodPut, clientID, token,
+		`{"client_id":"`+clientID+`","grant_types":["client_credentials"],"scope":"mcp:tools"}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected narrowing the scopes to be allowed, got %d: %s", rec.Code, rec.Body.String())
+	}
+	rec = manageRegistration(handler, http.MethodPut, clientID, token,
+		`{"client_id":"`+clientID+`","grant_types":["client_credentials"],"client_name":"renamed"}`)
+	if client, _ := clients.GetClient(clientID); rec.Code != http.StatusOK || client.Metadata.Scope != "mcp:tools" {
+		t.Errorf("Expected an update without scope to keep the current scopes, got %d %q", rec.Code, client.Metadata.Scope)
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/release"
+)
+
+// useVersion sets the running version for the rest of the test
+func useVersion(t *testing.T, version string) {
+	t.Helper()
+	previous := release.Version
+	release.Version = version
+	t.Cleanup(func() { release.Version = previous })
+}
+
+// newReleaseServer serves a latest release with the given tag, binary, and checksum
+func newReleaseServer(t *testing.T, tag string, binary []byte, checksum string) *release.Checker {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/acme/server/releases/latest":
+			_ = json.NewEncoder(w).Encode(release.Release{
+				TagName: tag,
+				HTMLURL: "https://github.com/acme/server/releases/" + tag,
+				Assets: []release.Asset{
+					{Name: release.AssetName(), DownloadURL: server.URL + "/download/binary"},
+					{Name: release.AssetName() + ".sha256", DownloadURL: server.URL + "/download/checksum"},
+				},
+			})
+		case "/download/binary":
+			_, _ = w.Write(binary)
+		case "/download/checksum":
+			_, _ = w.Write([]byte(checksum + "  " + release.AssetName() + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &release.Checker{Client: server.Client(), Repo: "acme/server", APIURL: server.URL}
+}
+
+func TestReleaseCheckReportsNewerVersion(t *testing.T) {
+	useVersion(t, "v1.2.0")
+	checker := newReleaseServer(t, "v1.10.0", nil, "")
+
+	status := checker.Check(context.Background())
+	if status.Err != nil || !status.UpdateAvailable || status.Latest != "v1.10.0" {
+		t.Fatalf("Expected v1.10.0 to be reported as an update, got %+v", status)
+	}
+	if got := checker.Status(); !got.UpdateAvailable || got.Current != "v1.2.0" {
+		t.Errorf("Expected the status to be kept, got %+v", got)
+	}
+
+	// Development builds are never told to update
+	useVersion(t, "dev")
+	if status := checker.Check(context.Background()); status.UpdateAvailable {
+		t.Errorf("Expected no update for a development build, got %+v", status)
+	}
+}
+
+func TestReleaseNewer(t *testing.T) {
+	for _, tt := range []struct {
+		a, b string
+		want bool
+	}{
+		{"v1.10.0", "v1.9.3", true},
+		{"1.2.1", "v1.2", true},
+		{"v1.2.0", "v1.2.0-rc.1", false},
+		{"v1.2.0", "v1.2.0", false},
+		{"v1.1.9", "v1.2.0", false},
+		{"nightly", "v1.0.0", false},
+		{"v2.0.0", "dev", false},
+	} {
+		if got := release.Newer(tt.a, tt.b); got != tt.want {
+			t.Errorf("Newer(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSelfUpdateVerifiesChecksum(t *testing.T) {
+	useVersion(t, "v1.0.0")
+	executable := filepath.Join(t.TempDir(), "server")
+	if err := os.WriteFile(executable, []byte("old"), 0o755); err != nil {
+		t.Fatalf("Failed to write binary: %v", err)
+	}
+
+	checker := newReleaseServer(t, "v1.1.0", []byte("new"), "0000")
+	if _, err := checker.Update(context.Background(), executable); err == nil {
+		t.Fatalf("Expected a checksum mismatch to fail the update")
+	}
+	if data, _ := os.ReadFile(executable); string(data) != "old" {
+		t.Fatalf("Expected the binary to be left alone, got %q", data)
+	}
+
+	sum := sha256.Sum256([]byte("new"))
+	checker = newReleaseServer(t, "v1.1.0", []byte("new"), hex.EncodeToString(sum[:]))
+	installed, err := checker.Update(context.Background(), executable)
+	if err != nil || installed == nil || installed.TagName != "v1.1.0" {
+		t.Fatalf("Expected v1.1.0 to be installed, got %+v, %v", installed, err)
+	}
+	data, _ := os.ReadFile(executable)
+	info, _ := os.Stat(executable)
+	if string(data) != "new" || info.Mode().Perm() != 0o755 {
+		t.Errorf("Expected the new executable binary, got %q with mode %v", data, info.Mode())
+	}
+
+	// Once current, nothing is downloaded
+	useVersion(t, "v1.1.0")
+	if installed, err := checker.Update(context.Background(), executable); installed != nil || err != nil {
+		t.Errorf("Expected no update when current, got %+v, %v", installed, err)
+	}
+}
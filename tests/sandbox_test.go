@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	sdkauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+)
+
+func TestSandboxConfigNeedsNoExternalServices(t *testing.T) {
+	unsetEnv(t, "OAUTH_ENABLED", "GITHUB_CLIENT_ID", "GITHUB_CLIENT_SECRET", "GITHUB_OAUTH_SECRET_NAME", "HOST", "PORT")
+	t.Setenv("SANDBOX", "true")
+	t.Setenv("MCP_SERVER_URL", "http://localhost:8080")
+	t.Setenv("STORAGE_BACKEND", "redis")
+	t.Setenv("GITHUB_OAUTH_SECRET_NAME", "prod/github-oauth")
+
+	config, err := auth.LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	if !config.Sandbox || !config.OAuthEnabled || config.StorageBackend != auth.StorageBackendMemory {
+		t.Errorf("Expected OAuth with in-memory storage, got %+v", config)
+	}
+	if !strings.HasPrefix(config.GitHubAuthURL, "http://localhost:8080"+auth.SandboxGitHubPath) ||
+		!strings.HasPrefix(config.GitHubTokenURL, "http://localhost:8080"+auth.SandboxGitHubPath) ||
+		!strings.HasPrefix(config.GitHubAPIURL, "http://localhost:8080"+auth.SandboxGitHubPath) {
+		t.Errorf("Expected GitHub to point at the sandbox, got %s, %s, %s", config.GitHubAuthURL, config.GitHubTokenURL, config.GitHubAPIURL)
+	}
+	if report := config.ValidationReport(); report.HasFatal() {
+		t.Errorf("Expected a valid sandbox config, got %v", report.Fatal())
+	}
+}
+
+func TestSandboxGitHubGrantsEveryLogin(t *testing.T) {
+	handler := auth.NewSandboxGitHubHandler()
+	serve := func(req *http.Request) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := serve(httptest.NewRequest(http.MethodGet, auth.SandboxGitHubPath+"/login/oauth/authorize?client_id=sandbox&state=xyz&redirect_uri="+
+		url.QueryEscape("http://localhost:8080/oauth/callback"), nil))
+	location, _ := url.Parse(rec.Header().Get("Location"))
+	if rec.Code != http.StatusFound || location == nil || location.Path != "/oauth/callback" || location.Query().Get("state") != "xyz" {
+		t.Fatalf("Expected a redirect back with the state, got %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+
+	exchange := func(code string) map[string]string {
+		req := httptest.NewRequest(http.MethodPost, auth.SandboxGitHubPath+"/login/oauth/access_token",
+			strings.NewReader(url.Values{"code": {code}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		var body map[string]string
+		_ = json.Unmarshal(serve(req).Body.Bytes(), &body)
+		return body
+	}
+	if body := exchange("guessed"); body["error"] != "bad_verification_code" {
+		t.Errorf("Expected an unknown code to be refused, got %v", body)
+	}
+	token := exchange(location.Query().Get("code"))["access_token"]
+	if token == "" {
+		t.Fatalf("Expected an access token for the sandbox code")
+	}
+
+	for _, path := range []string{"/api/user", "/api/user/memberships/orgs/acme", "/api/orgs/acme/teams/ops/memberships/" + auth.SandboxUser,
+		"/api/user/repos", "/api/repos/" + auth.SandboxUser + "/hello-world/issues"} {
+		req := httptest.NewRequest(http.MethodGet, auth.SandboxGitHubPath+path, nil)
+		if rec := serve(req); rec.Code != http.StatusUnauthorized {
+			t.Errorf("Expected %s to need a sandbox token, got %d", path, rec.Code)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		if rec := serve(req); rec.Code != http.StatusOK {
+			t.Errorf("Expected %s to answer, got %d", path, rec.Code)
+		}
+	}
+	req := httptest.NewRequest(http.MethodGet, auth.SandboxGitHubPath+"/api/user", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	var user auth.GitHubUserInfo
+	_ = json.Unmarshal(serve(req).Body.Bytes(), &user)
+	if user.Login != auth.SandboxUser {
+		t.Errorf("Expected to sign in as %s, got %+v", auth.SandboxUser, user)
+	}
+
+	// The GitHub tools work against the sandbox with its token
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	useToolsHTTPClient(t, server.Client())
+	tools.SetGitHubAPIURL(server.URL + auth.SandboxGitHubPath + "/api")
+	t.Cleanup(func() { tools.SetGitHubAPIURL("https://api.github.com") })
+	callReq := githubToolRequest("repo")
+	callReq.Extra.TokenInfo.Extra["github_token"] = token
+	createIssue := tools.CreateIssue{}
+	result, _, err := createIssue.Action(context.TODO(), callReq, &tools.CreateIssueParams{Repo: auth.SandboxUser + "/hello-world", Title: "Try the sandbox"})
+	if text := toolText(t, result, err); !strings.HasPrefix(text, "Created issue #3 in "+auth.SandboxUser+"/hello-world: Try the sandbox") {
+		t.Errorf("Unexpected create-issue response in the sandbox: %q", text)
+	}
+}
+
+func TestSandboxToolsReturnCannedData(t *testing.T) {
+	unsetEnv(t, "ECS_CLUSTER_NAME", "ECS_SERVICE_NAME", "CLOUDFORMATION_STACK_NAMES", "CLOUDWATCH_LOG_GROUPS")
+	tools.SetSandbox(true)
+	t.Cleanup(func() { tools.SetSandbox(false) })
+
+	admin := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: &sdkauth.TokenInfo{
+		Scopes:     []string{"mcp:tools", "mcp:admin"},
+		Expiration: time.Now().Add(time.Hour),
+	}}}
+	text := func(result *mcp.CallToolResult, _ any, err error) string {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("Tool call failed: %v", err)
+		}
+		return result.Content[0].(*mcp.TextContent).Text
+	}
+
+	fortune := &tools.GetFortune{}
+	if first, second := text(fortune.Action(context.Background(), admin, &struct{}{})), text(fortune.Action(context.Background(), admin, &struct{}{})); first == "" || first != second {
+		t.Errorf("Expected the same fortune every time, got %q and %q", first, second)
+	}
+
+	convert := &tools.ConvertCurrency{}
+	if got := text(convert.Action(context.Background(), admin, &tools.ConvertCurrencyParams{Amount: 100, From: "EUR", To: "USD"})); !strings.HasPrefix(got, "100.00 EUR = 111.11 USD") {
+		t.Errorf("Unexpected sandbox conversion %q", got)
+	}
+
+	status := &tools.GetDeploymentStatus{}
+	if got := text(status.Action(context.Background(), admin, &tools.GetDeploymentStatusParams{})); !strings.Contains(got, "sandbox-service (cluster sandbox-cluster): HEALTHY") {
+		t.Errorf("Unexpected sandbox deployment status %q", got)
+	}
+
+	logs := &tools.TailLogs{}
+	if got := text(logs.Action(context.Background(), admin, &tools.TailLogsParams{FilterPattern: "ERROR"})); !strings.HasPrefix(got, "1 events from /ecs/sandbox") {
+		t.Errorf("Unexpected sandbox logs %q", got)
+	}
+
+	costs := &tools.GetAWSCosts{}
+	t.Setenv("AWS_MONTHLY_BUDGET", "")
+	if got := text(costs.Action(context.Background(), admin, &struct{}{})); !strings.Contains(got, "Net month-to-date") || strings.Contains(got, "budget") {
+		t.Errorf("Unexpected sandbox cost report %q", got)
+	}
+	t.Setenv("AWS_MONTHLY_BUDGET", "80")
+	if got := text(costs.Action(context.Background(), admin, &struct{}{})); !strings.Contains(got, "Remaining budget: $27.78 (65% used)") ||
+		!strings.Contains(got, "Projected to exceed the budget by $20.22") {
+		t.Errorf("Expected the sandbox cost report to compare spend with the budget, got %q", got)
+	}
+	t.Setenv("AWS_MONTHLY_BUDGET", "lots")
+	if _, _, err := costs.Action(context.Background(), admin, &struct{}{}); err == nil {
+		t.Errorf("Expected an invalid AWS_MONTHLY_BUDGET to be reported")
+	}
+	// Scopes still apply in the sandbox
+	if _, _, err := costs.Action(context.Background(), &mcp.CallToolRequest{}, &struct{}{}); err == nil {
+		t.Errorf("Expected get-aws-costs to still require mcp:admin")
+	}
+}
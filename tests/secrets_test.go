@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// awsError is an AWS API error with a code, as returned by the SDK
+type awsError string
+
+func (e awsError) Error() string     { return string(e) }
+func (e awsError) ErrorCode() string { return string(e) }
+
+// fakeSecretsManager fails with errs in turn, then returns value
+type fakeSecretsManager struct {
+	errs  []error
+	value string
+	calls int
+}
+
+func (f *fakeSecretsManager) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	f.calls++
+	if f.calls <= len(f.errs) {
+		return nil, f.errs[f.calls-1]
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: &f.value}, nil
+}
+
+// newTestSecretFetcher creates a fetcher reading from client without real backoff delays
+func newTestSecretFetcher(client auth.SecretsManagerAPI) *auth.SecretFetcher {
+	fetcher := &auth.SecretFetcher{Attempts: 4, Timeout: 5 * time.Second, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	fetcher.SetClient(client)
+	return fetcher
+}
+
+func TestSecretFetcherRetriesThrottling(t *testing.T) {
+	unsetEnv(t, "SECRETS_FETCH_ATTEMPTS", "SECRETS_FETCH_TIMEOUT_SECONDS")
+
+	client := &fakeSecretsManager{errs: []error{awsError("ThrottlingException"), awsError("InternalServiceError")}, value: "s3cret"}
+	value, err := newTestSecretFetcher(client).Fetch(context.Background(), "prod/github-oauth")
+	if err != nil || value != "s3cret" || client.calls != 3 {
+		t.Errorf("Expected the secret on the third attempt, got %q, %v after %d calls", value, err, client.calls)
+	}
+
+	client = &fakeSecretsManager{errs: []error{awsError("ResourceNotFoundException")}}
+	if _, err := newTestSecretFetcher(client).Fetch(context.Background(), "missing"); !errors.Is(err, auth.ErrSecretUnavailable) || client.calls != 1 {
+		t.Errorf("Expected a missing secret to fail at once, got %v after %d calls", err, client.calls)
+	}
+
+	throttled := make([]error, 10)
+	for i := range throttled {
+		throttled[i] = awsError("ThrottlingException")
+	}
+	client = &fakeSecretsManager{errs: throttled}
+	if _, err := newTestSecretFetcher(client).Fetch(context.Background(), "busy"); !errors.Is(err, auth.ErrSecretUnavailable) || client.calls != 4 {
+		t.Errorf("Expected to give up after 4 attempts, got %v after %d calls", err, client.calls)
+	}
+
+	t.Setenv("SECRETS_FETCH_ATTEMPTS", "2")
+	client = &fakeSecretsManager{errs: throttled}
+	if _, err := newTestSecretFetcher(client).Fetch(context.Background(), "busy"); err == nil || client.calls != 2 {
+		t.Errorf("Expected SECRETS_FETCH_ATTEMPTS to limit the attempts, got %v after %d calls", err, client.calls)
+	}
+}
+
+func TestSecretFetcherTimeout(t *testing.T) {
+	unsetEnv(t, "SECRETS_FETCH_ATTEMPTS", "SECRETS_FETCH_TIMEOUT_SECONDS")
+
+	throttled := make([]error, 100)
+	for i := range throttled {
+		throttled[i] = awsError("ThrottlingException")
+	}
+	fetcher := &auth.SecretFetcher{Attempts: 100, Timeout: 50 * time.Millisecond, BaseDelay: 20 * time.Millisecond, MaxDelay: 20 * time.Millisecond}
+	fetcher.SetClient(&fakeSecretsManager{errs: throttled})
+
+	start := time.Now()
+	if _, err := fetcher.Fetch(context.Background(), "busy"); !errors.Is(err, auth.ErrSecretUnavailable) {
+		t.Errorf("Expected the fetch to give up, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the timeout to bound retries, took %v", elapsed)
+	}
+}
+
+func TestConfigLoadsGitHubSecretAfterThrottling(t *testing.T) {
+	unsetEnv(t, "GITHUB_CLIENT_ID", "GITHUB_CLIENT_SECRET", "SANDBOX", "SECRETS_FETCH_ATTEMPTS", "SECRETS_FETCH_TIMEOUT_SECONDS")
+	t.Setenv("GITHUB_OAUTH_SECRET_NAME", "prod/github-oauth")
+
+	client := &fakeSecretsManager{
+		errs:  []error{awsError("ThrottlingException")},
+		value: `{"GITHUB_CLIENT_ID":"Iv1.rotated","GITHUB_CLIENT_SECRET":"rotated-secret"}`,
+	}
+	auth.DefaultSecretFetcher.SetClient(client)
+	t.Cleanup(func() { auth.DefaultSecretFetcher.SetClient(nil) })
+
+	config, err := auth.LoadConfigFromEnv()
+	if err != nil {
+		t.Fatalf("LoadConfigFromEnv failed: %v", err)
+	}
+	if id, secret := config.GitHubCredentials(); id != "Iv1.rotated" || secret != "rotated-secret" || client.calls != 2 {
+		t.Errorf("Expected the credentials after a retry, got %q, %q after %d calls", id, secret, client.calls)
+	}
+
+	client.errs = []error{awsError("AccessDeniedException")}
+	client.calls = 0
+	if _, err := auth.LoadConfigFromEnv(); !errors.Is(err, auth.ErrSecretUnavailable) {
+		t.Errorf("Expected an unreadable secret to be reported as unavailable, got %v", err)
+	}
+}
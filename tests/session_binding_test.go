@@ -0,0 +1,132 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// fakeSessionHandler mimics the MCP handler: requests without a session create "session-1"
+func fakeSessionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Mcp-Session-Id") == "" {
+			w.Header().Set("Mcp-Session-Id", "session-1")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func sessionRequest(handler http.Handler, method, token, sessionID string) int {
+	req := httptest.NewRequest(method, "/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if sessionID != "" {
+		req.Header.Set("Mcp-Session-Id", sessionID)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestSessionBindingRejectsOtherTokens(t *testing.T) {
+	handler := auth.NewSessionBindings(30 * time.Minute).Middleware(fakeSessionHandler())
+
+	if status := sessionRequest(handler, http.MethodPost, "token-a", ""); status != http.StatusOK {
+		t.Fatalf("Initialize failed with status %d", status)
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		if status := sessionRequest(handler, method, "token-a", "session-1"); status != http.StatusOK {
+			t.Errorf("%s with the issuing token got status %d", method, status)
+		}
+		if status := sessionRequest(handler, method, "token-b", "session-1"); status != http.StatusNotFound {
+			t.Errorf("%s with another token got status %d, want 404", method, status)
+		}
+	}
+
+	if status := sessionRequest(handler, http.MethodGet, "token-a", "guessed-session"); status != http.StatusNotFound {
+		t.Errorf("Unknown session got status %d, want 404", status)
+	}
+}
+
+func TestSessionBindingUnauthenticatedInitializeThenAuthenticatedCall(t *testing.T) {
+	handler := auth.NewSessionBindings(30 * time.Minute).Middleware(fakeSessionHandler())
+
+	// initialize is allowed without a token, so the session starts out unbound
+	if status := sessionRequest(handler, http.MethodPost, "", ""); status != http.StatusOK {
+		t.Fatalf("Unauthenticated initialize failed with status %d", status)
+	}
+	if status := sessionRequest(handler, http.MethodPost, "", "session-1"); status != http.StatusOK {
+		t.Errorf("Unauthenticated call on the unbound session got status %d", status)
+	}
+
+	// The first authenticated call binds the session to its token
+	if status := sessionRequest(handler, http.MethodPost, "token-a", "session-1"); status != http.StatusOK {
+		t.Fatalf("Authenticated call after initialize got status %d", status)
+	}
+	if status := sessionRequest(handler, http.MethodPost, "token-a", "session-1"); status != http.StatusOK {
+		t.Errorf("Second authenticated call got status %d", status)
+	}
+	if status := sessionRequest(handler, http.MethodPost, "token-b", "session-1"); status != http.StatusNotFound {
+		t.Errorf("Call with another token got status %d, want 404", status)
+	}
+	if status := sessionRequest(handler, http.MethodPost, "", "session-1"); status != http.StatusNotFound {
+		t.Errorf("Unauthenticated call on the bound session got status %d, want 404", status)
+	}
+}
+
+func TestSessionBindingRemovedOnDelete(t *testing.T) {
+	handler := auth.NewSessionBindings(30 * time.Minute).Middleware(fakeSessionHandler())
+
+	sessionRequest(handler, http.MethodPost, "token-a", "")
+	if status := sessionRequest(handler, http.MethodDelete, "token-b", "session-1"); status != http.StatusNotFound {
+		t.Errorf("DELETE with another token got status %d, want 404", status)
+	}
+	if status := sessionRequest(handler, http.MethodDelete, "token-a", "session-1"); status != http.StatusOK {
+		t.Fatalf("DELETE with the issuing token got status %d", status)
+	}
+	if status := sessionRequest(handler, http.MethodGet, "token-a", "session-1"); status != http.StatusNotFound {
+		t.Errorf("Deleted session got status %d, want 404", status)
+	}
+}
+
+func TestSessionBindingExpiresWhenIdle(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	bindings := auth.NewSessionBindings(30 * time.Minute)
+	bindings.SetClock(clock)
+	handler := bindings.Middleware(fakeSessionHandler())
+
+	sessionRequest(handler, http.MethodPost, "token-a", "")
+
+	// Activity keeps the binding alive past the idle timeout
+	clock.Advance(20 * time.Minute)
+	if status := sessionRequest(handler, http.MethodGet, "token-a", "session-1"); status != http.StatusOK {
+		t.Fatalf("Active session got status %d", status)
+	}
+	clock.Advance(20 * time.Minute)
+	if status := sessionRequest(handler, http.MethodGet, "token-a", "session-1"); status != http.StatusOK {
+		t.Fatalf("Active session got status %d", status)
+	}
+
+	clock.Advance(31 * time.Minute)
+	if status := sessionRequest(handler, http.MethodGet, "token-a", "session-1"); status != http.StatusNotFound {
+		t.Errorf("Idle session got status %d, want 404", status)
+	}
+}
+
+func TestSessionBindingIgnoresFailedInitialize(t *testing.T) {
+	handler := auth.NewSessionBindings(30 * time.Minute).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Mcp-Session-Id", "session-1")
+		http.Error(w, "bad request", http.StatusBadRequest)
+	}))
+
+	sessionRequest(handler, http.MethodPost, "token-a", "")
+	if status := sessionRequest(handler, http.MethodGet, "token-a", "session-1"); status != http.StatusNotFound {
+		t.Errorf("Session from a failed request got status %d, want 404", status)
+	}
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestSlowCallMiddlewareReportsUpstreamTimings(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	useToolsHTTPClient(t, &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		time.Sleep(5 * time.Millisecond)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"data":{"message":"Slow"}}`)),
+			Request:    req,
+		}, nil
+	})})
+
+	handler := tools.SlowCallMiddleware(time.Millisecond)(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		tool := tools.GetFortune{}
+		result, _, err := tool.Action(ctx, &mcp.CallToolRequest{}, &struct{}{})
+		return result, err
+	})
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get-fortune"}}
+	if _, err := handler(context.TODO(), "tools/call", req); err != nil {
+		t.Fatalf("Tool call failed: %v", err)
+	}
+
+	output := logs.String()
+	if !strings.Contains(output, "[SLOW] Tool: get-fortune") {
+		t.Errorf("Expected a slow tool call log, got: %s", output)
+	}
+	if !strings.Contains(output, "aphorismcookie.herokuapp.com 200") {
+		t.Errorf("Expected upstream timings in the slow call log, got: %s", output)
+	}
+	if !strings.Contains(output, `[ALERT] {`) || !strings.Contains(output, `"event":"slow_tool_call"`) {
+		t.Errorf("Expected a slow_tool_call alert event, got: %s", output)
+	}
+}
+
+func TestSlowCallMiddlewareIgnoresFastCalls(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	handler := tools.SlowCallMiddleware(time.Hour)(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "get-city-time"}}
+	if _, err := handler(context.TODO(), "tools/call", req); err != nil {
+		t.Fatalf("Tool call failed: %v", err)
+	}
+
+	if strings.Contains(logs.String(), "[SLOW]") {
+		t.Errorf("Fast call was flagged as slow: %s", logs.String())
+	}
+}
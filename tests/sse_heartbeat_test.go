@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/sse"
+)
+
+// streamingHandler opens an event stream, sends one event, and holds the stream open until
+// the client goes away or release is closed
+func streamingHandler(release <-chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("event: message\ndata: {}\n\n"))
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+}
+
+func TestHeartbeatPingsIdleStreams(t *testing.T) {
+	heartbeat := sse.New(20 * time.Millisecond)
+	release := make(chan struct{})
+	server := httptest.NewServer(heartbeat.Middleware(streamingHandler(release)))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	reader := bufio.NewReader(resp.Body)
+	lines := make([]string, 0, 4)
+	for len(lines) < 4 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read stream: %v", err)
+		}
+		lines = append(lines, strings.TrimRight(line, "\n"))
+	}
+	if lines[0] != "event: message" || lines[3] != ": ping" {
+		t.Errorf("Expected the event followed by a ping comment, got %q", lines)
+	}
+
+	if stats := heartbeat.Stats(); stats.Active != 1 || stats.Pings == 0 {
+		t.Errorf("Expected one active stream with pings, got %+v", stats)
+	}
+
+	close(release)
+	_, _ = reader.ReadString('\n')
+	waitForStats(t, heartbeat, func(stats sse.Stats) bool { return stats.Completed == 1 && stats.Active == 0 })
+}
+
+func TestHeartbeatCountsDroppedStreams(t *testing.T) {
+	heartbeat := sse.New(time.Hour)
+	server := httptest.NewServer(heartbeat.Middleware(streamingHandler(nil)))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to open stream: %v", err)
+	}
+	cancel()
+	_ = resp.Body.Close()
+
+	waitForStats(t, heartbeat, func(stats sse.Stats) bool { return stats.Dropped == 1 && stats.Active == 0 })
+}
+
+func TestHeartbeatIgnoresPlainResponses(t *testing.T) {
+	heartbeat := sse.New(10 * time.Millisecond)
+	handler := heartbeat.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if rec.Body.String() != `{}` {
+		t.Errorf("Plain response was modified: %q", rec.Body.String())
+	}
+	if stats := heartbeat.Stats(); stats.Opened != 0 {
+		t.Errorf("Plain responses should not be counted as streams, got %+v", stats)
+	}
+}
+
+// waitForStats polls the heartbeat until done reports true, since streams are counted after the
+// handler returns on the server side
+func waitForStats(t *testing.T, heartbeat *sse.Heartbeat, done func(sse.Stats) bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !done(heartbeat.Stats()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Stream counters never settled: %+v", heartbeat.Stats())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestHeartbeatCountsResumedStreams(t *testing.T) {
+	heartbeat := sse.New(time.Hour)
+	release := make(chan struct{})
+	close(release)
+	handler := heartbeat.Middleware(streamingHandler(release))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Last-Event-ID", "0_3")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if stats := heartbeat.Stats(); stats.Opened != 2 || stats.Resumed != 1 {
+		t.Errorf("Expected one of two streams to be counted as resumed, got %+v", stats)
+	}
+}
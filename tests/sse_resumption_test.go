@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// sseEvent is an event read from a stream
+type sseEvent struct {
+	id   string
+	data string
+}
+
+// readEvents reads n events from an SSE response body
+func readEvents(t *testing.T, resp *http.Response, n int) []sseEvent {
+	t.Helper()
+	reader := bufio.NewReader(resp.Body)
+	var events []sseEvent
+	var current sseEvent
+	for len(events) < n {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Read %d of %d events before: %v", len(events), n, err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			current.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			current.data = strings.TrimPrefix(line, "data: ")
+		case line == "" && current.data != "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	return events
+}
+
+func TestStreamResumesWithLastEventID(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	mcp.AddTool(server, &mcp.Tool{Name: "steps"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		for step := range 2 {
+			_ = req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+				ProgressToken: req.Params.GetProgressToken(),
+				Progress:      float64(step + 1),
+			})
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil, nil
+	})
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, &mcp.StreamableHTTPOptions{
+		EventStore: mcp.NewMemoryEventStore(nil),
+	})
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	send := func(method, sessionID, body, lastEventID string) *http.Response {
+		req, _ := http.NewRequest(method, ts.URL, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		if lastEventID != "" {
+			req.Header.Set("Last-Event-ID", lastEventID)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("%s failed: %v", method, err)
+		}
+		return resp
+	}
+
+	resp := send(http.MethodPost, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`, "")
+	sessionID := resp.Header.Get("Mcp-Session-Id")
+	readEvents(t, resp, 1)
+	_ = resp.Body.Close()
+	_ = send(http.MethodPost, sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, "").Body.Close()
+
+	resp = send(http.MethodPost, sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"steps","arguments":{},"_meta":{"progressToken":"p"}}}`, "")
+	events := readEvents(t, resp, 3)
+	_ = resp.Body.Close()
+	for _, event := range events {
+		if event.id == "" {
+			t.Fatalf("Expected every event to carry an ID to resume from, got %+v", events)
+		}
+	}
+
+	// A client that only saw the first progress notification gets the rest again
+	resp = send(http.MethodGet, sessionID, "", events[0].id)
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Resuming returned %d", resp.StatusCode)
+	}
+	replayed := readEvents(t, resp, 2)
+	if replayed[0] != events[1] || replayed[1] != events[2] {
+		t.Errorf("Expected the missed events %+v, got %+v", events[1:], replayed)
+	}
+}
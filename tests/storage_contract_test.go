@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+func TestTokenStorageContract(t *testing.T) {
+	for _, backend := range tokenStorageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			testsupport.RunTokenStorageContract(t, func(clock *testsupport.FakeClock) auth.TokenStorage {
+				return backend.new(t, clock)
+			})
+		})
+	}
+}
+
+func TestClientStorageContract(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testsupport.RunClientStorageContract(t, func() auth.ClientStorage {
+			return newMemoryClients(t)
+		})
+	})
+	t.Run("dynamodb", func(t *testing.T) {
+		testsupport.RunClientStorageContract(t, func() auth.ClientStorage {
+			return auth.NewDynamoDBStorage(testsupport.NewFakeDynamoDB(), "clients")
+		})
+	})
+	t.Run("redis", func(t *testing.T) {
+		testsupport.RunClientStorageContract(t, func() auth.ClientStorage {
+			return auth.NewRedisStorage(sharedRedis(), uniqueRedisPrefix())
+		})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		testsupport.RunClientStorageContract(t, func() auth.ClientStorage {
+			return auth.NewPostgresStorage(testsupport.NewFakePostgres(), "mcp_oauth")
+		})
+	})
+}
+
+func TestTokenCacheContract(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		testsupport.RunTokenCacheContract(t, func(clock *testsupport.FakeClock) auth.TokenCache {
+			cache := newMemoryCache(t)
+			cache.SetClock(clock)
+			return cache
+		})
+	})
+	t.Run("dynamodb", func(t *testing.T) {
+		testsupport.RunTokenCacheContract(t, func(clock *testsupport.FakeClock) auth.TokenCache {
+			cache := auth.NewDynamoDBStorage(testsupport.NewFakeDynamoDB(), "cache")
+			cache.SetClock(clock)
+			return cache
+		})
+	})
+	t.Run("redis", func(t *testing.T) {
+		testsupport.RunTokenCacheContract(t, func(clock *testsupport.FakeClock) auth.TokenCache {
+			cache := auth.NewRedisStorage(sharedRedis(), uniqueRedisPrefix())
+			cache.SetClock(clock)
+			return cache
+		})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		testsupport.RunTokenCacheContract(t, func(clock *testsupport.FakeClock) auth.TokenCache {
+			cache := auth.NewPostgresStorage(testsupport.NewFakePostgres(), "mcp_oauth")
+			cache.SetClock(clock)
+			return cache
+		})
+	})
+}
+
+func TestStateStorageContract(t *testing.T) {
+	ttl := 5 * time.Minute
+	t.Run("memory", func(t *testing.T) {
+		testsupport.RunStateStorageContract(t, ttl, func(clock *testsupport.FakeClock) auth.StateStorage {
+			store := newStateStore(t, ttl)
+			store.SetClock(clock)
+			return store
+		})
+	})
+	t.Run("dynamodb", func(t *testing.T) {
+		testsupport.RunStateStorageContract(t, ttl, func(clock *testsupport.FakeClock) auth.StateStorage {
+			store := auth.NewDynamoDBStateStore(testsupport.NewFakeDynamoDB(), "states", ttl)
+			store.SetClock(clock)
+			return store
+		})
+	})
+	t.Run("redis", func(t *testing.T) {
+		testsupport.RunStateStorageContract(t, ttl, func(clock *testsupport.FakeClock) auth.StateStorage {
+			store := auth.NewRedisStateStore(sharedRedis(), uniqueRedisPrefix(), ttl)
+			store.SetClock(clock)
+			return store
+		})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		testsupport.RunStateStorageContract(t, ttl, func(clock *testsupport.FakeClock) auth.StateStorage {
+			store := auth.NewPostgresStateStore(testsupport.NewFakePostgres(), "mcp_oauth", ttl)
+			store.SetClock(clock)
+			return store
+		})
+	})
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestTailLogsRejectsUnconfiguredLogGroup(t *testing.T) {
+	t.Setenv("CLOUDWATCH_LOG_GROUPS", "/ecs/app")
+
+	tool := tools.TailLogs{}
+
+	_, _, err := tool.Action(
+		context.TODO(),
+		&mcp.CallToolRequest{
+			Extra: &mcp.RequestExtra{
+				TokenInfo: &auth.TokenInfo{
+					Scopes:     []string{"mcp:admin"},
+					Expiration: time.Now().Add(time.Hour),
+				},
+			},
+		},
+		&tools.TailLogsParams{
+			LogGroup: "/aws/lambda/someone-elses-function",
+		},
+	)
+
+	if err == nil {
+		t.Errorf("Calling tool \"%s\" with a log group that isn't configured should have failed", tool.Name)
+	}
+}
+
+// useFakeAWS points the AWS-backed tools at a fake AWS for the rest of the test
+func useFakeAWS(t *testing.T) *testsupport.FakeAWS {
+	fake := testsupport.NewFakeAWS()
+	tools.SetAWSClient(fake.Config(), fake.Client())
+	t.Cleanup(func() { tools.SetAWSClient(nil, nil) })
+	return fake
+}
+
+// nextTokenPattern finds the nextToken in a tail-logs response
+var nextTokenPattern = regexp.MustCompile(`nextToken: (\S+)`)
+
+func TestTailLogsPagesKeepTheirTimeWindow(t *testing.T) {
+	t.Setenv("CLOUDWATCH_LOG_GROUPS", "/ecs/app,/ecs/worker")
+	fakeClock := testsupport.NewFakeClock(time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC))
+	tools.SetClock(fakeClock)
+	t.Cleanup(func() { tools.SetClock(clock.System{}) })
+
+	fake := useFakeAWS(t)
+	fake.Handle("Logs_20140328.FilterLogEvents", func(input map[string]any) (any, error) {
+		page := "1"
+		if input["nextToken"] == "cloudwatch-page-2" {
+			page = "2"
+		}
+		output := map[string]any{"events": []map[string]any{
+			{"logStreamName": "app/1", "timestamp": fakeClock.Now().UnixMilli(), "message": "page " + page},
+		}}
+		if page == "1" {
+			output["nextToken"] = "cloudwatch-page-2"
+		}
+		return output, nil
+	})
+
+	admin := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{
+		Scopes:     []string{"mcp:admin"},
+		Expiration: time.Now().Add(time.Hour),
+	}}}
+	tool := tools.TailLogs{}
+	result, _, err := tool.Action(context.TODO(), admin, &tools.TailLogsParams{Minutes: 30})
+	match := nextTokenPattern.FindStringSubmatch(toolText(t, result, err))
+	if match == nil {
+		t.Fatalf("Expected a nextToken in the first page")
+	}
+
+	// The next page, requested later, covers the same window with CloudWatch's own token
+	fakeClock.Advance(10 * time.Minute)
+	result, _, err = tool.Action(context.TODO(), admin, &tools.TailLogsParams{NextToken: match[1]})
+	if text := toolText(t, result, err); !strings.Contains(text, "app/1: page 2") {
+		t.Errorf("Expected the second page, got %q", text)
+	}
+
+	calls := fake.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 FilterLogEvents calls, got %d", len(calls))
+	}
+	first, second := calls[0].Input, calls[1].Input
+	if second["nextToken"] != "cloudwatch-page-2" || second["startTime"] != first["startTime"] || second["endTime"] != first["endTime"] {
+		t.Errorf("Expected the second page to reuse the first window, got %v then %v", first, second)
+	}
+	if want := float64(time.Date(2025, 6, 1, 9, 30, 0, 0, time.UTC).UnixMilli()); first["startTime"] != want {
+		t.Errorf("Expected the window to start 30 minutes before the first call, got %v", first["startTime"])
+	}
+
+	// A page token only works for the log group and filter pattern it was issued for
+	if _, _, err := tool.Action(context.TODO(), admin, &tools.TailLogsParams{LogGroup: "/ecs/worker", NextToken: match[1]}); err == nil {
+		t.Errorf("Expected a nextToken from another log group to be rejected")
+	}
+	if _, _, err := tool.Action(context.TODO(), admin, &tools.TailLogsParams{FilterPattern: "ERROR", NextToken: match[1]}); err == nil {
+		t.Errorf("Expected a nextToken from another filter pattern to be rejected")
+	}
+	if _, _, err := tool.Action(context.TODO(), admin, &tools.TailLogsParams{NextToken: "cloudwatch-page-2"}); err == nil {
+		t.Errorf("Expected a nextToken that tail-logs didn't return to be rejected")
+	}
+}
@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/telemetry"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory for the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	// The global provider can't be restored once replaced, so later tests get a no-op one
+	previousPropagator := otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return recorder
+}
+
+func TestToolCallTraceContinuesHTTPSpan(t *testing.T) {
+	recorder := recordSpans(t)
+
+	server := fakeGitHubMembership(t, "octocat")
+	config := auth.DefaultConfig()
+	config.GitHubAPIURL = server.URL
+
+	tokenStorage := newMemoryTokens(t)
+	err := tokenStorage.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Scope:             "mcp:tools",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(time.Hour),
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+	verifier := auth.NewGitHubTokenVerifier(config, nil, tokenStorage)
+
+	// MCP method handlers only see the request headers, not its context
+	toolCall := tools.TracingMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	handler := telemetry.HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r.Context(), "mcp-token", r); err != nil {
+			t.Errorf("Verify failed: %v", err)
+		}
+		req := &mcp.CallToolRequest{
+			Params: &mcp.CallToolParamsRaw{Name: "get-fortune"},
+			Extra:  &mcp.RequestExtra{Header: r.Header},
+		}
+		if _, err := toolCall(context.TODO(), "tools/call", req); err != nil {
+			t.Errorf("Tool call failed: %v", err)
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	edge, verify, validate, tool := spans["POST /"], spans["auth.verify_token"], spans["github.validate_token"], spans["tools/call get-fortune"]
+	if edge == nil || verify == nil || validate == nil || tool == nil {
+		t.Fatalf("Missing spans, got %v", spans)
+	}
+
+	if edge.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("HTTP span did not continue the client's trace: %s", edge.SpanContext().TraceID())
+	}
+	if verify.Parent().SpanID() != edge.SpanContext().SpanID() {
+		t.Errorf("Token verification span is not a child of the HTTP span")
+	}
+	if validate.Parent().SpanID() != verify.SpanContext().SpanID() {
+		t.Errorf("GitHub validation span is not a child of the verification span")
+	}
+	if tool.Parent().SpanID() != edge.SpanContext().SpanID() {
+		t.Errorf("Tool span is not a child of the HTTP span")
+	}
+}
+
+func TestTokenEndpointSpanRecordsErrors(t *testing.T) {
+	recorder := recordSpans(t)
+
+	handler := auth.NewTokenEndpointHandler(auth.DefaultConfig(), newMemoryClients(t), newMemoryTokens(t))
+	req := httptest.NewRequest(http.MethodGet, "/oauth/token", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "oauth.token" {
+		t.Fatalf("Expected one oauth.token span, got %d", len(spans))
+	}
+	if spans[0].Status().Code != codes.Error {
+		t.Errorf("Expected a failed token request to mark the span as an error, got %v", spans[0].Status())
+	}
+}
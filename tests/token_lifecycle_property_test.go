@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+
+	"pgregory.net/rapid"
+)
+
+// tokenStorageBackend constructs a fresh TokenStorage driven by the given clock, closed when t ends
+type tokenStorageBackend struct {
+	name string
+	new  func(t cleaner, clock *testsupport.FakeClock) auth.TokenStorage
+}
+
+// tokenStorageBackends lists every TokenStorage implementation the properties must hold for
+var tokenStorageBackends = []tokenStorageBackend{
+	{
+		name: "memory",
+		new: func(t cleaner, clock *testsupport.FakeClock) auth.TokenStorage {
+			storage := newMemoryTokens(t)
+			storage.SetClock(clock)
+			return storage
+		},
+	},
+	{
+		name: "dynamodb",
+		new: func(t cleaner, clock *testsupport.FakeClock) auth.TokenStorage {
+			storage := auth.NewDynamoDBStorage(testsupport.NewFakeDynamoDB(), "tokens")
+			storage.SetClock(clock)
+			return storage
+		},
+	},
+	{
+		name: "redis",
+		new: func(t cleaner, clock *testsupport.FakeClock) auth.TokenStorage {
+			storage := auth.NewRedisStorage(sharedRedis(), uniqueRedisPrefix())
+			storage.SetClock(clock)
+			return storage
+		},
+	},
+	{
+		name: "postgres",
+		new: func(t cleaner, clock *testsupport.FakeClock) auth.TokenStorage {
+			storage := auth.NewPostgresStorage(testsupport.NewFakePostgres(), "mcp_oauth")
+			storage.SetClock(clock)
+			return storage
+		},
+	},
+}
+
+var propertyEpoch = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func pkceChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// codeVerifierGen generates RFC 7636 code verifiers (43-128 unreserved characters)
+var codeVerifierGen = rapid.StringMatching(`[A-Za-z0-9\-._~]{43,128}`)
+
+func TestPropertyDeletedCodeCanNeverBeRedeemed(t *testing.T) {
+	for _, backend := range tokenStorageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			rapid.Check(t, func(t *rapid.T) {
+				clock := testsupport.NewFakeClock(propertyEpoch)
+				storage := backend.new(t, clock)
+
+				code := rapid.StringMatching(`[A-Za-z0-9_-]{1,64}`).Draw(t, "code")
+				err := storage.StoreAuthCode(code, &auth.AuthCodeInfo{
+					ClientID:  "vscode",
+					ExpiresAt: clock.Now().Add(10 * time.Minute),
+					CreatedAt: clock.Now(),
+				})
+				if err != nil {
+					t.Fatalf("Failed to store auth code: %v", err)
+				}
+
+				if err := storage.DeleteAuthCode(code); err != nil {
+					t.Fatalf("Failed to delete auth code: %v", err)
+				}
+
+				clock.Advance(time.Duration(rapid.Int64Range(0, int64(10*time.Minute)).Draw(t, "elapsed")))
+				if _, err := storage.GetAuthCode(code); err == nil {
+					t.Fatalf("Deleted authorization code %q was redeemable", code)
+				}
+			})
+		})
+	}
+}
+
+func TestPropertyExpiredTokensNeverVerify(t *testing.T) {
+	for _, backend := range tokenStorageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			rapid.Check(t, func(t *rapid.T) {
+				clock := testsupport.NewFakeClock(propertyEpoch)
+				storage := backend.new(t, clock)
+
+				config := auth.DefaultConfig()
+				config.Clock = clock
+				verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), storage)
+
+				lifetime := time.Duration(rapid.Int64Range(1, int64(24*time.Hour)).Draw(t, "lifetime"))
+				elapsed := time.Duration(rapid.Int64Range(1, int64(48*time.Hour)).Draw(t, "elapsed past expiry"))
+
+				err := storage.StoreAccessToken("token", &auth.AccessTokenInfo{
+					ClientID:          "vscode",
+					Scope:             "mcp:tools",
+					GitHubAccessToken: "github-token",
+					ExpiresAt:         clock.Now().Add(lifetime),
+					CreatedAt:         clock.Now(),
+				})
+				if err != nil {
+					t.Fatalf("Failed to store access token: %v", err)
+				}
+
+				clock.Advance(lifetime + elapsed)
+
+				if _, err := storage.GetAccessToken("token"); err == nil {
+					t.Fatalf("Token was returned %v after expiring", elapsed)
+				}
+				// The verifier must reject the token before ever reaching GitHub
+				if _, err := verifier.Verify(context.TODO(), "token", nil); err == nil {
+					t.Fatalf("Token verified %v after expiring", elapsed)
+				}
+			})
+		})
+	}
+}
+
+func TestPropertyPKCEOnlyPassesForItsOwnVerifier(t *testing.T) {
+	for _, backend := range tokenStorageBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			rapid.Check(t, func(t *rapid.T) {
+				clock := testsupport.NewFakeClock(propertyEpoch)
+				storage := backend.new(t, clock)
+
+				config := auth.DefaultConfig()
+				config.Clock = clock
+				handler := auth.NewTokenEndpointHandler(config, newMemoryClientsWithDefaults(t), storage)
+
+				codeVerifier := codeVerifierGen.Draw(t, "code_verifier")
+				otherVerifier := codeVerifierGen.Filter(func(v string) bool {
+					return v != codeVerifier
+				}).Draw(t, "other_verifier")
+
+				err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{
+					ClientID:            "vscode",
+					RedirectURI:         "http://127.0.0.1:33418",
+					Scope:               "mcp:tools",
+					CodeChallenge:       pkceChallenge(codeVerifier),
+					CodeChallengeMethod: "S256",
+					GitHubAccessToken:   "github-token",
+					ExpiresAt:           clock.Now().Add(10 * time.Minute),
+					CreatedAt:           clock.Now(),
+				})
+				if err != nil {
+					t.Fatalf("Failed to store auth code: %v", err)
+				}
+
+				if status := redeemCode(handler, "code", otherVerifier); status == http.StatusOK {
+					t.Fatalf("Code was redeemed with a different verifier")
+				}
+				if status := redeemCode(handler, "code", codeVerifier); status != http.StatusOK {
+					t.Fatalf("Code could not be redeemed with its own verifier: status %d", status)
+				}
+				// Codes are single use, so even the right verifier must fail the second time
+				if status := redeemCode(handler, "code", codeVerifier); status == http.StatusOK {
+					t.Fatalf("Code was redeemed twice")
+				}
+			})
+		})
+	}
+}
+
+// redeemCode exchanges an authorization code at the token endpoint and returns the response status
+func redeemCode(handler http.Handler, code, codeVerifier string) int {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("client_id", "vscode")
+	form.Set("code_verifier", codeVerifier)
+	form.Set("redirect_uri", "http://127.0.0.1:33418")
+
+	req := httptest.NewRequest(http.MethodPost, "/oauth/token", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	return rec.Code
+}
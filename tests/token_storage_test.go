@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+)
+
+// cleaner is a *testing.T or *rapid.T, which close the in-memory stores when the test ends
+type cleaner interface {
+	Cleanup(f func())
+}
+
+// newMemoryTokens returns in-memory token storage whose background cleanup stops with the test
+func newMemoryTokens(t cleaner) *auth.InMemoryTokenStorage {
+	storage := auth.NewInMemoryTokenStorage()
+	t.Cleanup(storage.Close)
+	return storage
+}
+
+// newMemoryClients returns in-memory client storage whose background cleanup stops with the test
+func newMemoryClients(t cleaner) *auth.InMemoryClientStorage {
+	clients := auth.NewInMemoryClientStorage()
+	t.Cleanup(clients.Close)
+	return clients
+}
+
+// newMemoryClientsWithDefaults is newMemoryClients with the default clients registered
+func newMemoryClientsWithDefaults(t cleaner) *auth.InMemoryClientStorage {
+	clients := auth.NewInMemoryClientStorageWithDefaults()
+	t.Cleanup(clients.Close)
+	return clients
+}
+
+// newMemoryCache returns an in-memory token cache whose background cleanup stops with the test
+func newMemoryCache(t cleaner) *auth.InMemoryTokenCache {
+	cache := auth.NewInMemoryTokenCache()
+	t.Cleanup(cache.Close)
+	return cache
+}
+
+// newStateStore returns an in-memory state store whose background cleanup stops with the test
+func newStateStore(t cleaner, ttl time.Duration) *auth.StateStore {
+	states := auth.NewStateStore(ttl)
+	t.Cleanup(states.Close)
+	return states
+}
+
+func TestInMemoryTokenStorageExpiresAccessTokens(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	storage := newMemoryTokens(t)
+	storage.SetClock(clock)
+
+	err := storage.StoreAccessToken("token", &auth.AccessTokenInfo{
+		ClientID:  "vscode",
+		ExpiresAt: clock.Now().Add(time.Hour),
+		CreatedAt: clock.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+
+	clock.Advance(59 * time.Minute)
+	if _, err := storage.GetAccessToken("token"); err != nil {
+		t.Errorf("Access token should still be valid before expiry: %v", err)
+	}
+
+	clock.Advance(2 * time.Minute)
+	if _, err := storage.GetAccessToken("token"); err == nil {
+		t.Errorf("Access token should have expired")
+	}
+}
+
+func TestInMemoryTokenStorageExpiresAuthCodes(t *testing.T) {
+	clock := testsupport.NewFakeClock(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	storage := newMemoryTokens(t)
+	storage.SetClock(clock)
+
+	err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{
+		ClientID:  "vscode",
+		ExpiresAt: clock.Now().Add(10 * time.Minute),
+		CreatedAt: clock.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store auth code: %v", err)
+	}
+
+	clock.Advance(11 * time.Minute)
+	if _, err := storage.GetAuthCode("code"); err == nil {
+		t.Errorf("Authorization code should have expired")
+	}
+}
+
+func TestClosingInMemoryStorageStopsItsSweepers(t *testing.T) {
+	before := runtime.NumGoroutine()
+	var stores []*auth.Storage
+	for range 10 {
+		storage, err := auth.NewStorage(context.Background(), auth.DefaultConfig())
+		if err != nil {
+			t.Fatalf("NewStorage failed: %v", err)
+		}
+		stores = append(stores, storage)
+	}
+	if running := runtime.NumGoroutine(); running < before+40 {
+		t.Fatalf("Expected a sweeper per in-memory store, got %d goroutines from %d", running, before)
+	}
+
+	for _, storage := range stores {
+		storage.Close()
+		storage.Close()
+	}
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("Sweepers still running after Close: %d goroutines, want at most %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := stores[0].Tokens.StoreAuthCode("code", &auth.AuthCodeInfo{ExpiresAt: time.Now().Add(time.Minute)}); err != nil {
+		t.Errorf("Closed storage should stay usable: %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/clock"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/testsupport"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// countingToolHandler answers every tools/call with how many calls it has answered, failing
+// calls whose arguments are {"fail":true}
+func countingToolHandler(calls *int) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		*calls++
+		if string(req.(*mcp.CallToolRequest).Params.Arguments) == `{"fail":true}` {
+			return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "failed"}}}, nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("call %d", *calls)}}}, nil
+	}
+}
+
+func callTool(t *testing.T, handler mcp.MethodHandler, user, name, arguments string) string {
+	t.Helper()
+	call := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: name, Arguments: json.RawMessage(arguments)}, Extra: extraFor(user)}
+	result, err := handler(context.TODO(), "tools/call", call)
+	if err != nil {
+		t.Fatalf("Calling %s failed: %v", name, err)
+	}
+	return result.(*mcp.CallToolResult).Content[0].(*mcp.TextContent).Text
+}
+
+func TestParseCacheTTLs(t *testing.T) {
+	ttls, err := tools.ParseCacheTTLs("get-fortune=60s, convert-currency=5m")
+	if err != nil {
+		t.Fatalf("ParseCacheTTLs failed: %v", err)
+	}
+	if ttls["get-fortune"] != time.Minute || ttls["convert-currency"] != 5*time.Minute {
+		t.Errorf("Unexpected TTLs %v", ttls)
+	}
+
+	for _, spec := range []string{"get-fortune", "get-fortune=soon", "get-fortune=0s", "=60s"} {
+		if _, err := tools.ParseCacheTTLs(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestResultCacheServesRepeatedCalls(t *testing.T) {
+	fakeClock := testsupport.NewFakeClock(time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC))
+	tools.SetClock(fakeClock)
+	t.Cleanup(func() { tools.SetClock(clock.System{}) })
+
+	cache := tools.NewResultCache(map[string]time.Duration{"get-fortune": time.Minute})
+	var calls int
+	handler := cache.Middleware()(countingToolHandler(&calls))
+
+	first := callTool(t, handler, "octocat", "get-fortune", `{"a":1,"b":2}`)
+	// The same arguments in another order, from another caller, hit the cache
+	if got := callTool(t, handler, "hubot", "get-fortune", `{"b":2,"a":1}`); got != first || calls != 1 {
+		t.Errorf("Expected the cached %q without calling the tool again, got %q after %d calls", first, got, calls)
+	}
+	if got := callTool(t, handler, "octocat", "get-fortune", `{"a":2}`); got == first {
+		t.Errorf("Expected different arguments to miss the cache")
+	}
+	callTool(t, handler, "octocat", "get-city-time", `{}`)
+	callTool(t, handler, "octocat", "get-city-time", `{}`)
+	if calls != 4 {
+		t.Errorf("Expected tools without a TTL not to be cached, got %d calls", calls)
+	}
+
+	callTool(t, handler, "octocat", "get-fortune", `{"fail":true}`)
+	callTool(t, handler, "octocat", "get-fortune", `{"fail":true}`)
+	if calls != 6 {
+		t.Errorf("Expected failed results not to be cached, got %d calls", calls)
+	}
+
+	fakeClock.Advance(time.Minute)
+	if got := callTool(t, handler, "octocat", "get-fortune", `{"a":1,"b":2}`); got == first {
+		t.Errorf("Expected the cached result to expire after its TTL")
+	}
+
+	stats := cache.Stats()["get-fortune"]
+	if stats.Hits != 1 || stats.Misses != 5 || stats.Entries != 2 {
+		t.Errorf("Unexpected cache stats %+v", stats)
+	}
+	if _, ok := cache.Stats()["get-city-time"]; ok {
+		t.Errorf("Expected no stats for tools without a TTL")
+	}
+
+	rec := httptest.NewRecorder()
+	tools.NewCacheStatsHandler(cache).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/tool-cache", nil))
+	var body struct {
+		TTLs  map[string]int64            `json:"ttl_seconds"`
+		Tools map[string]tools.CacheStats `json:"tools"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode cache stats: %v", err)
+	}
+	if body.TTLs["get-fortune"] != 60 || body.Tools["get-fortune"].Hits != 1 {
+		t.Errorf("Unexpected cache stats response %+v", body)
+	}
+}
+
+func TestResultCacheKeepsCallersApartForPerCallerTools(t *testing.T) {
+	// Tool profiles are recorded when tools are registered
+	tools.RegisterAll(mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil))
+
+	cache := tools.NewResultCache(map[string]time.Duration{"list-my-repos": time.Minute, "create-issue": time.Minute})
+	var calls int
+	handler := cache.Middleware()(countingToolHandler(&calls))
+
+	octocat := callTool(t, handler, "octocat", "list-my-repos", `{}`)
+	if got := callTool(t, handler, "hubot", "list-my-repos", `{}`); got == octocat {
+		t.Errorf("Expected hubot not to be served octocat's repositories")
+	}
+	if got := callTool(t, handler, "octocat", "list-my-repos", `{}`); got != octocat {
+		t.Errorf("Expected octocat's repositories to be cached for octocat, got %q", got)
+	}
+
+	first := callTool(t, handler, "octocat", "create-issue", `{"title":"Crash"}`)
+	if got := callTool(t, handler, "octocat", "create-issue", `{"title":"Crash"}`); got == first {
+		t.Errorf("Expected tools that write never to be cached")
+	}
+}
@@ -0,0 +1,38 @@
+package tests
+
+import (
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+)
+
+func TestToolsAdvertiseLatencyAndCost(t *testing.T) {
+	t.Setenv("TOOLS_ENABLED", "")
+	t.Setenv("TOOLS_DISABLED", "")
+
+	listed := registeredTools(t)
+	if len(listed) == 0 {
+		t.Fatalf("Expected tools to be listed")
+	}
+	for _, tool := range listed {
+		profile, ok := tools.ToolProfile(tool.Name)
+		if !ok {
+			t.Errorf("Tool %s declares no profile", tool.Name)
+			continue
+		}
+		if tool.Meta["latency"] != profile.Latency || tool.Meta["cost"] != profile.Cost {
+			t.Errorf("Tool %s lists _meta %v, want %+v", tool.Name, tool.Meta, profile)
+		}
+		if tool.Annotations == nil || tool.Annotations.ReadOnlyHint == profile.Writes || tool.Annotations.OpenWorldHint == nil ||
+			*tool.Annotations.OpenWorldHint != profile.External {
+			t.Errorf("Tool %s lists annotations %+v, want them to match %+v", tool.Name, tool.Annotations, profile)
+		}
+	}
+
+	if profile, _ := tools.ToolProfile("get-aws-costs"); profile.Cost != tools.CostMetered {
+		t.Errorf("Expected get-aws-costs to be metered, got %+v", profile)
+	}
+	if profile, _ := tools.ToolProfile("get-city-time"); profile.Latency != tools.LatencyFast || profile.External {
+		t.Errorf("Expected get-city-time to be fast and local, got %+v", profile)
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// extraFor returns request details for an authenticated user, or none for ""
+func extraFor(user string) *mcp.RequestExtra {
+	if user == "" {
+		return nil
+	}
+	return &mcp.RequestExtra{TokenInfo: &auth.TokenInfo{
+		Scopes:     []string{"mcp:tools"},
+		Expiration: time.Now().Add(time.Hour),
+		Extra:      map[string]any{"subject": user},
+	}}
+}
+
+func TestParseRollouts(t *testing.T) {
+	rollouts, err := tools.ParseRollouts("tail-logs=10%, tail-logs=@octocat,get-aws-costs=0")
+	if err != nil {
+		t.Fatalf("ParseRollouts failed: %v", err)
+	}
+	if rollout := rollouts["tail-logs"]; rollout.Percent != 10 || len(rollout.Users) != 1 || rollout.Users[0] != "octocat" {
+		t.Errorf("Unexpected tail-logs rollout %+v", rollout)
+	}
+	if _, ok := rollouts["get-aws-costs"]; !ok {
+		t.Errorf("Expected get-aws-costs to be rolled out to nobody")
+	}
+
+	for _, spec := range []string{"tail-logs", "tail-logs=150%", "=10%", "tail-logs=lots"} {
+		if _, err := tools.ParseRollouts(spec); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestRolloutPercentageIsStable(t *testing.T) {
+	rollout := tools.Rollout{Percent: 30}
+	exposed := 0
+	for i := 0; i < 1000; i++ {
+		user := fmt.Sprintf("user-%d", i)
+		first := rollout.Exposes("tail-logs", user)
+		if first != rollout.Exposes("tail-logs", user) {
+			t.Fatalf("Exposure for %s changed between calls", user)
+		}
+		if first {
+			exposed++
+		}
+	}
+	if exposed < 250 || exposed > 350 {
+		t.Errorf("Expected about 30%% of users to see the tool, got %d of 1000", exposed)
+	}
+	if rollout.Exposes("tail-logs", "") {
+		t.Errorf("Anonymous callers should not see partially rolled out tools")
+	}
+}
+
+func TestRolloutMiddlewareHidesTools(t *testing.T) {
+	middleware := tools.RolloutMiddleware(map[string]tools.Rollout{"tail-logs": {Users: []string{"octocat"}}})
+	handler := middleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if method == "tools/list" {
+			return &mcp.ListToolsResult{Tools: []*mcp.Tool{{Name: "get-fortune"}, {Name: "tail-logs"}}}, nil
+		}
+		return &mcp.CallToolResult{}, nil
+	})
+
+	list := func(user string) []string {
+		result, err := handler(context.TODO(), "tools/list", &mcp.ListToolsRequest{Extra: extraFor(user)})
+		if err != nil {
+			t.Fatalf("tools/list failed: %v", err)
+		}
+		var names []string
+		for _, tool := range result.(*mcp.ListToolsResult).Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+	if names := list("octocat"); len(names) != 2 {
+		t.Errorf("Expected octocat to see both tools, got %v", names)
+	}
+	if names := list("hubot"); len(names) != 1 || names[0] != "get-fortune" {
+		t.Errorf("Expected hubot to only see get-fortune, got %v", names)
+	}
+
+	call := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "tail-logs"}, Extra: extraFor("hubot")}
+	if _, err := handler(context.TODO(), "tools/call", call); err == nil {
+		t.Errorf("Expected hubot's call to a hidden tool to fail")
+	}
+	call.Extra = extraFor("octocat")
+	if _, err := handler(context.TODO(), "tools/call", call); err != nil {
+		t.Errorf("Expected octocat's call to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// callWithScopes runs a tools/call for name through ScopeMiddleware and reports whether the tool
+// handler was reached
+func callWithScopes(t *testing.T, name string, scopes []string) (bool, error) {
+	reached := false
+	handler := tools.ScopeMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		reached = true
+		return &mcp.CallToolResult{}, nil
+	})
+
+	req := &mcp.CallToolRequest{
+		Params: &mcp.CallToolParamsRaw{Name: name},
+		Extra: &mcp.RequestExtra{
+			TokenInfo: &auth.TokenInfo{
+				Scopes:     scopes,
+				Expiration: time.Now().Add(time.Hour),
+			},
+		},
+	}
+	_, err := handler(context.TODO(), "tools/call", req)
+	return reached, err
+}
+
+func TestToolScopesAreRecordedAtRegistration(t *testing.T) {
+	tools.RegisterAll(mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil))
+
+	if scopes := tools.RequiredScopes("tail-logs"); !slices.Equal(scopes, []string{"mcp:admin"}) {
+		t.Errorf("Expected tail-logs to require mcp:admin, got %v", scopes)
+	}
+	if scopes := tools.RequiredScopes("get-fortune"); len(scopes) != 0 {
+		t.Errorf("Expected get-fortune to require no extra scopes, got %v", scopes)
+	}
+}
+
+func TestScopeMiddlewareRejectsUncoveredTools(t *testing.T) {
+	tools.RegisterAll(mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil))
+
+	reached, err := callWithScopes(t, "get-aws-costs", []string{"mcp:tools"})
+	if err == nil || reached {
+		t.Errorf("Calling get-aws-costs without mcp:admin should be rejected before the tool runs")
+	}
+
+	if reached, err := callWithScopes(t, "get-aws-costs", []string{"mcp:tools", "mcp:admin"}); err != nil || !reached {
+		t.Errorf("Calling get-aws-costs with mcp:admin should be allowed, got %v", err)
+	}
+
+	if reached, err := callWithScopes(t, "get-fortune", []string{"mcp:tools"}); err != nil || !reached {
+		t.Errorf("Calling get-fortune should only need mcp:tools, got %v", err)
+	}
+}
+
+func TestAdminToolDescriptionsMentionTheScope(t *testing.T) {
+	t.Setenv("TOOLS_ENABLED", "")
+	t.Setenv("TOOLS_DISABLED", "")
+
+	for _, tool := range registeredTools(t) {
+		if slices.Contains(tools.RequiredScopes(tool.Name), "mcp:admin") && !strings.Contains(tool.Description, "(requires the mcp:admin scope)") {
+			t.Errorf("Expected the %s description to say it requires mcp:admin, got %q", tool.Name, tool.Description)
+		}
+	}
+}
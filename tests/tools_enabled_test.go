@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+)
+
+// listRegisteredTools registers every tool with a new server and returns the names it lists
+func listRegisteredTools(t *testing.T) []string {
+	t.Helper()
+	var names []string
+	for _, tool := range registeredTools(t) {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// registeredTools registers every tool with a new server and returns the tools it lists
+func registeredTools(t *testing.T) []*mcp.Tool {
+	t.Helper()
+	server := mcp.NewServer(&mcp.Implementation{Name: "test", Version: "1.0.0"}, nil)
+	tools.RegisterAll(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(context.Background(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	defer func() { _ = serverSession.Close() }()
+	client := mcp.NewClient(&mcp.Implementation{Name: "client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(context.Background(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	defer func() { _ = session.Close() }()
+
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools failed: %v", err)
+	}
+	return result.Tools
+}
+
+func TestToolsDisabled(t *testing.T) {
+	t.Setenv("TOOLS_ENABLED", "")
+	t.Setenv("TOOLS_DISABLED", "tail-logs,get-aws-costs")
+
+	names := listRegisteredTools(t)
+	if slices.Contains(names, "tail-logs") || slices.Contains(names, "get-aws-costs") {
+		t.Errorf("Expected disabled tools to be left out, got %v", names)
+	}
+	if !slices.Contains(names, "get-fortune") {
+		t.Errorf("Expected other tools to be served, got %v", names)
+	}
+}
+
+func TestToolsEnabled(t *testing.T) {
+	t.Setenv("TOOLS_ENABLED", "get-fortune,get-runbook,not-a-tool")
+	t.Setenv("TOOLS_DISABLED", "get-runbook")
+
+	if names := listRegisteredTools(t); !slices.Equal(names, []string{"get-fortune"}) {
+		t.Errorf("Expected only get-fortune, got %v", names)
+	}
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// useToolsHTTPClient has tools make their outbound requests with client until the test ends
+func useToolsHTTPClient(t *testing.T, client *http.Client) {
+	t.Helper()
+	previous := tools.SetHTTPClient(client)
+	t.Cleanup(func() { tools.SetHTTPClient(previous) })
+}
+
+func TestTracingMiddlewareAddsHeadersToContext(t *testing.T) {
+	header := make(http.Header)
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	header.Set("X-Request-Id", "req-123")
+	header.Set("Authorization", "Bearer secret")
+
+	var got http.Header
+	handler := tools.TracingMiddleware(func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		got = tools.TraceHeadersFromContext(ctx)
+		return nil, nil
+	})
+
+	_, _ = handler(context.TODO(), "tools/call", &mcp.CallToolRequest{Extra: &mcp.RequestExtra{Header: header}})
+
+	if got.Get("Traceparent") != header.Get("traceparent") || got.Get("X-Request-Id") != "req-123" {
+		t.Errorf("Tracing headers not propagated: %v", got)
+	}
+	if got.Get("Authorization") != "" {
+		t.Errorf("Non-tracing header leaked into the tool context")
+	}
+}
+
+func TestSharedHTTPClientForwardsTracingHeaders(t *testing.T) {
+	var outbound http.Header
+	useToolsHTTPClient(t, &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		outbound = req.Header
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader(`{"data":{"message":"Traced"}}`)),
+			Request:    req,
+		}, nil
+	})})
+
+	inbound := make(http.Header)
+	inbound.Set("X-Request-Id", "req-456")
+	ctx := tools.WithTraceHeaders(context.TODO(), inbound)
+
+	tool := tools.GetFortune{}
+	if _, _, err := tool.Action(ctx, &mcp.CallToolRequest{}, &struct{}{}); err != nil {
+		t.Fatalf("Calling tool failed: %v", err)
+	}
+
+	if outbound.Get("X-Request-Id") != "req-456" {
+		t.Errorf("Expected X-Request-Id to be forwarded, got headers %v", outbound)
+	}
+}
@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+// discoveryHandler builds the MCP endpoint middleware with the given allowlist around a handler
+// that echoes the request body, so tests can check the body survives the allowlist check
+func discoveryHandler(t *testing.T, methods []string, maxBody int) http.Handler {
+	config := auth.DefaultConfig()
+	config.UnauthenticatedMethods = methods
+	config.MaxRequestBodyBytes = maxBody
+
+	verifier := auth.NewGitHubTokenVerifier(config, newMemoryCache(t), newMemoryTokens(t))
+	middleware := auth.NewMiddleware(config, verifier)
+
+	return middleware.RequireAuthExceptMethods([]string{"mcp:tools"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		_, _ = w.Write(body)
+	}))
+}
+
+func postJSONRPC(handler http.Handler, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestUnauthenticatedMethodAllowlist(t *testing.T) {
+	handler := discoveryHandler(t, []string{"initialize", "tools/list"}, 1<<20)
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"allowed method", `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, http.StatusOK},
+		{"allowed batch", `[{"jsonrpc":"2.0","id":1,"method":"initialize"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`, http.StatusOK},
+		{"other method", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"tools/list"}}`, http.StatusUnauthorized},
+		{"batch with other method", `[{"jsonrpc":"2.0","id":1,"method":"tools/list"},{"jsonrpc":"2.0","id":2,"method":"tools/call"}]`, http.StatusUnauthorized},
+		{"allowed name in another field", `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"initialize"}}`, http.StatusUnauthorized},
+		{"not JSON-RPC 2.0", `{"id":1,"method":"tools/list"}`, http.StatusUnauthorized},
+		{"malformed", `{"jsonrpc":"2.0","method":"tools/list"`, http.StatusUnauthorized},
+		{"empty batch", `[]`, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postJSONRPC(handler, tt.body)
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if tt.status == http.StatusOK && rec.Body.String() != tt.body {
+				t.Errorf("Handler received %q, want the original body", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestUnauthenticatedMethodsDisabledByDefault(t *testing.T) {
+	handler := discoveryHandler(t, nil, 1<<20)
+
+	if rec := postJSONRPC(handler, `{"jsonrpc":"2.0","id":1,"method":"initialize"}`); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", rec.Code)
+	}
+}
+
+func TestUnauthenticatedRequestBodyLimit(t *testing.T) {
+	handler := discoveryHandler(t, []string{"tools/list"}, 64)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"tools/list","params":{"cursor":"` + strings.Repeat("x", 100) + `"}}`
+	if rec := postJSONRPC(handler, body); rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413, got %d", rec.Code)
+	}
+}
+
+func TestJSONRPCMethods(t *testing.T) {
+	methods, err := auth.JSONRPCMethods([]byte(` [{"jsonrpc":"2.0","method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"}]`))
+	if err != nil {
+		t.Fatalf("JSONRPCMethods failed: %v", err)
+	}
+	if len(methods) != 2 || methods[0] != "ping" || methods[1] != "notifications/initialized" {
+		t.Errorf("Unexpected methods: %v", methods)
+	}
+
+	if _, err := auth.JSONRPCMethods([]byte(`{"jsonrpc":"2.0","result":{}}`)); err == nil {
+		t.Errorf("JSONRPCMethods accepted a response without a method")
+	}
+}
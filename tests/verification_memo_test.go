@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/auth"
+)
+
+func TestVerificationIsMemoizedPerRequest(t *testing.T) {
+	server := fakeGitHubMembership(t, "octocat")
+
+	config := auth.DefaultConfig()
+	config.GitHubAPIURL = server.URL
+
+	tokenStorage := newMemoryTokens(t)
+	err := tokenStorage.StoreAccessToken("mcp-token", &auth.AccessTokenInfo{
+		ClientID:          "vscode",
+		Scope:             "mcp:tools",
+		GitHubAccessToken: "github-token",
+		ExpiresAt:         time.Now().Add(time.Hour),
+		CreatedAt:         time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store access token: %v", err)
+	}
+
+	verifier := auth.NewGitHubTokenVerifier(config, nil, tokenStorage)
+	middleware := auth.NewMiddleware(config, verifier)
+
+	// Two stacked middlewares plus a handler re-verifying the token, as a registration hook would
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := verifier.Verify(r.Context(), "mcp-token", r); err != nil {
+			t.Errorf("Re-verification in the handler failed: %v", err)
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := middleware.RequireAuth([]string{"mcp:tools"})(middleware.RequireAuth([]string{"mcp:tools"})(inner))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Authorization", "Bearer mcp-token")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Request %d got status %d: %s", i+1, rec.Code, rec.Body.String())
+		}
+	}
+
+	// Without a cache, each request validates once and the two repeats come from its memo
+	stats := verifier.Stats()
+	if stats.Verifications != 2 || stats.DuplicatesAvoided != 4 {
+		t.Errorf("Expected 2 verifications and 4 duplicates avoided, got %+v", stats)
+	}
+
+	// Outside a request memo every call validates
+	if _, err := verifier.Verify(context.TODO(), "mcp-token", nil); err != nil {
+		t.Fatalf("Verify resulted in an error: %v", err)
+	}
+	if stats := verifier.Stats(); stats.Verifications != 3 {
+		t.Errorf("Expected a third verification without a memo, got %+v", stats)
+	}
+}
@@ -0,0 +1,92 @@
+package testsupport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// FakeAWSCall is a call made to FakeAWS
+type FakeAWSCall struct {
+	Target string
+	Input  map[string]any
+}
+
+// FakeAWS is an in-memory stand-in for the AWS JSON 1.1 APIs the AWS-backed tools call, such as
+// Cost Explorer and CloudWatch Logs; it answers each operation, by X-Amz-Target, with its handler
+type FakeAWS struct {
+	mu       sync.Mutex
+	handlers map[string]func(input map[string]any) (any, error)
+	calls    []FakeAWSCall
+}
+
+// NewFakeAWS creates a fake answering no operations
+func NewFakeAWS() *FakeAWS {
+	return &FakeAWS{handlers: make(map[string]func(input map[string]any) (any, error))}
+}
+
+// Handle answers the operation target, e.g. "Logs_20140328.FilterLogEvents", with handler; a
+// handler error is returned as a 400 AWS error
+func (f *FakeAWS) Handle(target string, handler func(input map[string]any) (any, error)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers[target] = handler
+}
+
+// Calls returns the calls made so far, oldest first
+func (f *FakeAWS) Calls() []FakeAWSCall {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]FakeAWSCall(nil), f.calls...)
+}
+
+// Config returns an AWS configuration with static credentials in us-east-1
+func (f *FakeAWS) Config() *aws.Config {
+	return &aws.Config{
+		Region: "us-east-1",
+		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+			return aws.Credentials{AccessKeyID: "AKIDFAKE", SecretAccessKey: "fake-secret"}, nil
+		}),
+	}
+}
+
+// Client returns an HTTP client whose requests are all answered by the fake
+func (f *FakeAWS) Client() *http.Client {
+	return &http.Client{Transport: f}
+}
+
+// RoundTrip implements http.RoundTripper
+func (f *FakeAWS) RoundTrip(req *http.Request) (*http.Response, error) {
+	target := req.Header.Get("X-Amz-Target")
+	var input map[string]any
+	if req.Body != nil {
+		_ = json.NewDecoder(req.Body).Decode(&input)
+	}
+
+	f.mu.Lock()
+	f.calls = append(f.calls, FakeAWSCall{Target: target, Input: input})
+	handler, ok := f.handlers[target]
+	f.mu.Unlock()
+
+	status, body := http.StatusOK, any(nil)
+	if !ok {
+		status, body = http.StatusBadRequest, map[string]string{"__type": "UnknownOperationException", "message": target}
+	} else if output, err := handler(input); err != nil {
+		status, body = http.StatusBadRequest, map[string]string{"__type": "ValidationException", "message": err.Error()}
+	} else {
+		body = output
+	}
+
+	encoded, _ := json.Marshal(body)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/x-amz-json-1.1"}},
+		Body:       io.NopCloser(bytes.NewReader(encoded)),
+		Request:    req,
+	}, nil
+}
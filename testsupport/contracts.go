@@ -0,0 +1,498 @@
+package testsupport
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/auth"
+)
+
+// contractEpoch is the fixed start time of every contract test's fake clock
+var contractEpoch = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// RunTokenStorageContract checks that a TokenStorage implementation behaves like every other backend
+// newStorage must return an empty storage whose expiry checks use the given clock
+func RunTokenStorageContract(t *testing.T, newStorage func(clock *FakeClock) auth.TokenStorage) {
+	t.Run("AuthCodeRoundTrip", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		want := &auth.AuthCodeInfo{
+			ClientID:            "client",
+			RedirectURI:         "http://127.0.0.1:33418",
+			Scope:               "mcp:tools read:user",
+			CodeChallenge:       "challenge",
+			CodeChallengeMethod: "S256",
+			Resource:            "http://localhost:8080",
+			GitHubAccessToken:   "github-token",
+			ExpiresAt:           clock.Now().Add(10 * time.Minute),
+			CreatedAt:           clock.Now(),
+		}
+		if err := storage.StoreAuthCode("code", want); err != nil {
+			t.Fatalf("StoreAuthCode failed: %v", err)
+		}
+
+		got, err := storage.GetAuthCode("code")
+		if err != nil {
+			t.Fatalf("GetAuthCode failed: %v", err)
+		}
+		if got.ClientID != want.ClientID || got.RedirectURI != want.RedirectURI || got.Scope != want.Scope ||
+			got.CodeChallenge != want.CodeChallenge || got.CodeChallengeMethod != want.CodeChallengeMethod ||
+			got.Resource != want.Resource || got.GitHubAccessToken != want.GitHubAccessToken ||
+			!got.ExpiresAt.Equal(want.ExpiresAt) {
+			t.Errorf("GetAuthCode returned %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("UnknownAuthCode", func(t *testing.T) {
+		storage := newStorage(NewFakeClock(contractEpoch))
+
+		if _, err := storage.GetAuthCode("missing"); err == nil {
+			t.Errorf("GetAuthCode returned an unknown code")
+		}
+	})
+
+	t.Run("DeletedAuthCode", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		if err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{ClientID: "client", ExpiresAt: clock.Now().Add(time.Minute)}); err != nil {
+			t.Fatalf("StoreAuthCode failed: %v", err)
+		}
+		if err := storage.DeleteAuthCode("code"); err != nil {
+			t.Fatalf("DeleteAuthCode failed: %v", err)
+		}
+		if _, err := storage.GetAuthCode("code"); err == nil {
+			t.Errorf("GetAuthCode returned a deleted code")
+		}
+		// Deleting is idempotent so a failed token exchange can always clean up
+		if err := storage.DeleteAuthCode("code"); err != nil {
+			t.Errorf("DeleteAuthCode of a missing code failed: %v", err)
+		}
+	})
+
+	t.Run("ConsumedAuthCode", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		if err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{ClientID: "client", ExpiresAt: clock.Now().Add(time.Minute)}); err != nil {
+			t.Fatalf("StoreAuthCode failed: %v", err)
+		}
+		got, err := storage.ConsumeAuthCode("code")
+		if err != nil {
+			t.Fatalf("ConsumeAuthCode failed: %v", err)
+		}
+		if got.ClientID != "client" {
+			t.Errorf("ConsumeAuthCode returned client %q, want %q", got.ClientID, "client")
+		}
+		// A code is redeemed once
+		if _, err := storage.ConsumeAuthCode("code"); err == nil {
+			t.Errorf("ConsumeAuthCode redeemed a code twice")
+		}
+		if _, err := storage.GetAuthCode("code"); err == nil {
+			t.Errorf("GetAuthCode returned a consumed code")
+		}
+
+		if err := storage.StoreAuthCode("expired", &auth.AuthCodeInfo{ClientID: "client", ExpiresAt: clock.Now().Add(time.Minute)}); err != nil {
+			t.Fatalf("StoreAuthCode failed: %v", err)
+		}
+		clock.Advance(2 * time.Minute)
+		if _, err := storage.ConsumeAuthCode("expired"); err == nil {
+			t.Errorf("ConsumeAuthCode returned an expired code")
+		}
+	})
+
+	t.Run("ExpiredAuthCode", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		if err := storage.StoreAuthCode("code", &auth.AuthCodeInfo{ClientID: "client", ExpiresAt: clock.Now().Add(time.Minute)}); err != nil {
+			t.Fatalf("StoreAuthCode failed: %v", err)
+		}
+		clock.Advance(2 * time.Minute)
+		if _, err := storage.GetAuthCode("code"); err == nil {
+			t.Errorf("GetAuthCode returned an expired code")
+		}
+	})
+
+	t.Run("AccessTokenRoundTrip", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		want := &auth.AccessTokenInfo{
+			ClientID:          "client",
+			Scope:             "mcp:tools",
+			Resource:          "http://localhost:8080",
+			GitHubAccessToken: "github-token",
+			ExpiresAt:         clock.Now().Add(time.Hour),
+			CreatedAt:         clock.Now(),
+		}
+		if err := storage.StoreAccessToken("token", want); err != nil {
+			t.Fatalf("StoreAccessToken failed: %v", err)
+		}
+
+		got, err := storage.GetAccessToken("token")
+		if err != nil {
+			t.Fatalf("GetAccessToken failed: %v", err)
+		}
+		if got.ClientID != want.ClientID || got.Scope != want.Scope || got.Resource != want.Resource ||
+			got.GitHubAccessToken != want.GitHubAccessToken || !got.ExpiresAt.Equal(want.ExpiresAt) {
+			t.Errorf("GetAccessToken returned %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("UnknownAccessToken", func(t *testing.T) {
+		storage := newStorage(NewFakeClock(contractEpoch))
+
+		if _, err := storage.GetAccessToken("missing"); err == nil {
+			t.Errorf("GetAccessToken returned an unknown token")
+		}
+	})
+
+	t.Run("ExpiredAccessToken", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		if err := storage.StoreAccessToken("token", &auth.AccessTokenInfo{ClientID: "client", ExpiresAt: clock.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("StoreAccessToken failed: %v", err)
+		}
+		clock.Advance(time.Hour + time.Second)
+		if _, err := storage.GetAccessToken("token"); err == nil {
+			t.Errorf("GetAccessToken returned an expired token")
+		}
+	})
+
+	t.Run("RevokedAccessToken", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		if err := storage.StoreAccessToken("token", &auth.AccessTokenInfo{ClientID: "client", ExpiresAt: clock.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("StoreAccessToken failed: %v", err)
+		}
+		if err := storage.RevokeAccessToken("token"); err != nil {
+			t.Fatalf("RevokeAccessToken failed: %v", err)
+		}
+		if _, err := storage.GetAccessToken("token"); err == nil {
+			t.Errorf("GetAccessToken returned a revoked token")
+		}
+		if err := storage.RevokeAccessToken("missing"); err != nil {
+			t.Errorf("RevokeAccessToken failed for an unknown token: %v", err)
+		}
+	})
+
+	t.Run("ListAndRevokeByID", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		storage := newStorage(clock)
+
+		if err := storage.StoreAccessToken("token", &auth.AccessTokenInfo{ClientID: "client", Subject: "octocat", ExpiresAt: clock.Now().Add(time.Hour)}); err != nil {
+			t.Fatalf("StoreAccessToken failed: %v", err)
+		}
+		if err := storage.StoreAccessToken("short", &auth.AccessTokenInfo{ClientID: "client", ExpiresAt: clock.Now().Add(time.Minute)}); err != nil {
+			t.Fatalf("StoreAccessToken failed: %v", err)
+		}
+		clock.Advance(2 * time.Minute)
+
+		tokens, err := storage.ListAccessTokens()
+		if err != nil {
+			t.Fatalf("ListAccessTokens failed: %v", err)
+		}
+		if len(tokens) != 1 || tokens[0].ID != auth.AccessTokenID("token") || tokens[0].Subject != "octocat" {
+			t.Fatalf("ListAccessTokens returned %+v, want only the unexpired token", tokens)
+		}
+
+		info, err := storage.RevokeAccessTokenByID(tokens[0].ID)
+		if err != nil || info.ClientID != "client" {
+			t.Fatalf("RevokeAccessTokenByID returned %+v, %v", info, err)
+		}
+		if _, err := storage.GetAccessToken("token"); err == nil {
+			t.Errorf("GetAccessToken returned a token revoked by ID")
+		}
+		if _, err := storage.RevokeAccessTokenByID(tokens[0].ID); !errors.Is(err, auth.ErrAccessTokenNotFound) {
+			t.Errorf("RevokeAccessTokenByID of a revoked token returned %v, want ErrAccessTokenNotFound", err)
+		}
+		if _, err := storage.RevokeAccessTokenByID(auth.AccessTokenID("short")); !errors.Is(err, auth.ErrAccessTokenNotFound) {
+			t.Errorf("RevokeAccessTokenByID of an expired token returned %v, want ErrAccessTokenNotFound", err)
+		}
+		if tokens, err := storage.ListAccessTokens(); err != nil || len(tokens) != 0 {
+			t.Errorf("ListAccessTokens returned %+v, %v after revoking", tokens, err)
+		}
+	})
+}
+
+// RunClientStorageContract checks that a ClientStorage implementation behaves like every other backend
+// newStorage must return an empty storage
+func RunClientStorageContract(t *testing.T, newStorage func() auth.ClientStorage) {
+	t.Run("RejectsInvalidClients", func(t *testing.T) {
+		storage := newStorage()
+
+		if err := storage.StoreClient(nil); err == nil {
+			t.Errorf("StoreClient accepted a nil client")
+		}
+		if err := storage.StoreClient(&auth.OAuthClient{}); err == nil {
+			t.Errorf("StoreClient accepted a client without an ID")
+		}
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		storage := newStorage()
+
+		want := &auth.OAuthClient{
+			ClientID: "client",
+			Metadata: auth.ClientRegistrationRequest{
+				RedirectURIs:            []string{"http://127.0.0.1:33418"},
+				TokenEndpointAuthMethod: "none",
+				ClientName:              "Contract Client",
+			},
+			CreatedAt: contractEpoch,
+		}
+		if err := storage.StoreClient(want); err != nil {
+			t.Fatalf("StoreClient failed: %v", err)
+		}
+
+		got, err := storage.GetClient("client")
+		if err != nil {
+			t.Fatalf("GetClient failed: %v", err)
+		}
+		if got.ClientID != want.ClientID || got.Metadata.ClientName != want.Metadata.ClientName ||
+			len(got.Metadata.RedirectURIs) != 1 || got.Metadata.RedirectURIs[0] != want.Metadata.RedirectURIs[0] {
+			t.Errorf("GetClient returned %+v, want %+v", got, want)
+		}
+
+		// Callers must not be able to modify stored clients through returned values
+		got.Metadata.ClientName = "Modified"
+		again, err := storage.GetClient("client")
+		if err != nil {
+			t.Fatalf("GetClient failed: %v", err)
+		}
+		if again.Metadata.ClientName != want.Metadata.ClientName {
+			t.Errorf("Modifying a returned client changed the stored client")
+		}
+	})
+
+	t.Run("UnknownClient", func(t *testing.T) {
+		storage := newStorage()
+
+		if _, err := storage.GetClient("missing"); err == nil {
+			t.Errorf("GetClient returned an unknown client")
+		}
+		if _, err := storage.ValidateClientSecret("missing", "secret"); err == nil {
+			t.Errorf("ValidateClientSecret succeeded for an unknown client")
+		}
+		if err := storage.DeleteClient("missing"); err == nil {
+			t.Errorf("DeleteClient succeeded for an unknown client")
+		}
+	})
+
+	t.Run("DeleteAndList", func(t *testing.T) {
+		storage := newStorage()
+
+		for _, id := range []string{"first", "second"} {
+			if err := storage.StoreClient(&auth.OAuthClient{ClientID: id, CreatedAt: contractEpoch}); err != nil {
+				t.Fatalf("StoreClient failed: %v", err)
+			}
+		}
+
+		if err := storage.DeleteClient("first"); err != nil {
+			t.Fatalf("DeleteClient failed: %v", err)
+		}
+		if _, err := storage.GetClient("first"); err == nil {
+			t.Errorf("GetClient returned a deleted client")
+		}
+
+		clients, err := storage.ListClients()
+		if err != nil {
+			t.Fatalf("ListClients failed: %v", err)
+		}
+		if len(clients) != 1 || clients[0].ClientID != "second" {
+			t.Errorf("ListClients returned %d clients, want only \"second\"", len(clients))
+		}
+	})
+
+	t.Run("WrongClientSecret", func(t *testing.T) {
+		storage := newStorage()
+
+		if err := storage.StoreClient(&auth.OAuthClient{ClientID: "client", ClientSecret: "stored-hash"}); err != nil {
+			t.Fatalf("StoreClient failed: %v", err)
+		}
+		valid, err := storage.ValidateClientSecret("client", "wrong")
+		if err != nil {
+			t.Fatalf("ValidateClientSecret failed: %v", err)
+		}
+		if valid {
+			t.Errorf("ValidateClientSecret accepted the wrong secret")
+		}
+	})
+
+	t.Run("ExpiredClient", func(t *testing.T) {
+		storage := newStorage()
+
+		// contractEpoch is in the past, so the client has already expired
+		expiresAt := contractEpoch
+		if err := storage.StoreClient(&auth.OAuthClient{ClientID: "expired", CreatedAt: contractEpoch, ExpiresAt: &expiresAt}); err != nil {
+			t.Fatalf("StoreClient failed: %v", err)
+		}
+		if _, err := storage.GetClient("expired"); err == nil {
+			t.Errorf("GetClient returned an expired client")
+		}
+		clients, err := storage.ListClients()
+		if err != nil {
+			t.Fatalf("ListClients failed: %v", err)
+		}
+		if len(clients) != 0 {
+			t.Errorf("ListClients returned %d clients, want no expired clients", len(clients))
+		}
+	})
+}
+
+// RunTokenCacheContract checks that a TokenCache implementation behaves like every other backend
+// newCache must return an empty cache whose expiry checks use the given clock
+func RunTokenCacheContract(t *testing.T, newCache func(clock *FakeClock) auth.TokenCache) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		cache := newCache(NewFakeClock(contractEpoch))
+
+		want := &auth.TokenValidationResult{Valid: true, Subject: "octocat", Scopes: []string{"mcp:tools"}, GitHubScopes: []string{"read:user", "repo"}}
+		if err := cache.Set("token", want, time.Hour); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+
+		got, ok := cache.Get("token")
+		if !ok {
+			t.Fatalf("Get missed a cached token")
+		}
+		if got.Valid != want.Valid || got.Subject != want.Subject || !slices.Equal(got.GitHubScopes, want.GitHubScopes) {
+			t.Errorf("Get returned %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Miss", func(t *testing.T) {
+		cache := newCache(NewFakeClock(contractEpoch))
+
+		if _, ok := cache.Get("missing"); ok {
+			t.Errorf("Get returned an uncached token")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		cache := newCache(NewFakeClock(contractEpoch))
+
+		if err := cache.Set("token", &auth.TokenValidationResult{Valid: true}, time.Hour); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if err := cache.Delete("token"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, ok := cache.Get("token"); ok {
+			t.Errorf("Get returned a deleted token")
+		}
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		cache := newCache(clock)
+
+		if err := cache.Set("token", &auth.TokenValidationResult{Valid: true}, time.Minute); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		clock.Advance(2 * time.Minute)
+		if _, ok := cache.Get("token"); ok {
+			t.Errorf("Get returned an expired token")
+		}
+	})
+}
+
+// RunStateStorageContract checks that a StateStorage implementation behaves like every other backend
+// newStore must return an empty store whose states expire ttl after creation, using the given clock
+func RunStateStorageContract(t *testing.T, ttl time.Duration, newStore func(clock *FakeClock) auth.StateStorage) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		store := newStore(clock)
+
+		want := &auth.AuthState{
+			ClientID:            "client",
+			RedirectURI:         "http://127.0.0.1:33418",
+			Scope:               "mcp:tools",
+			State:               "client-state",
+			CodeChallenge:       "challenge",
+			CodeChallengeMethod: "S256",
+			Resource:            "http://localhost:8080",
+			CreatedAt:           clock.Now(),
+		}
+		if err := store.Store("state", want); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+
+		got, ok := store.Get("state")
+		if !ok {
+			t.Fatalf("Get missed a stored state")
+		}
+		if got.ClientID != want.ClientID || got.RedirectURI != want.RedirectURI || got.State != want.State ||
+			got.CodeChallenge != want.CodeChallenge || got.Resource != want.Resource {
+			t.Errorf("Get returned %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		store := newStore(clock)
+
+		if err := store.Store("state", &auth.AuthState{ClientID: "client", CreatedAt: clock.Now()}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+		if err := store.Delete("state"); err != nil {
+			t.Fatalf("Delete failed: %v", err)
+		}
+		if _, ok := store.Get("state"); ok {
+			t.Errorf("Get returned a deleted state")
+		}
+	})
+
+	t.Run("Consume", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		store := newStore(clock)
+
+		if err := store.Store("state", &auth.AuthState{ClientID: "client", CreatedAt: clock.Now()}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+		got, ok := store.Consume("state")
+		if !ok {
+			t.Fatalf("Consume missed a stored state")
+		}
+		if got.ClientID != "client" {
+			t.Errorf("Consume returned client %q, want %q", got.ClientID, "client")
+		}
+		// A state is redeemed once
+		if _, ok := store.Consume("state"); ok {
+			t.Errorf("Consume redeemed a state twice")
+		}
+		if _, ok := store.Get("state"); ok {
+			t.Errorf("Get returned a consumed state")
+		}
+
+		if err := store.Store("expired", &auth.AuthState{ClientID: "client", CreatedAt: clock.Now()}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+		clock.Advance(ttl + time.Second)
+		if _, ok := store.Consume("expired"); ok {
+			t.Errorf("Consume returned an expired state")
+		}
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		clock := NewFakeClock(contractEpoch)
+		store := newStore(clock)
+
+		if err := store.Store("state", &auth.AuthState{ClientID: "client", CreatedAt: clock.Now()}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+		clock.Advance(ttl - time.Second)
+		if _, ok := store.Get("state"); !ok {
+			t.Fatalf("Get missed a state before its lifetime ended")
+		}
+		clock.Advance(2 * time.Second)
+		if _, ok := store.Get("state"); ok {
+			t.Errorf("Get returned an expired state")
+		}
+	})
+}
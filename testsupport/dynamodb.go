@@ -0,0 +1,106 @@
+package testsupport
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// FakeDynamoDB is an in-memory stand-in for a single-table DynamoDB client keyed by "pk"
+// It understands attribute_exists conditions, ALL_OLD return values on delete, and begins_with
+// scan filters on the key, which is all auth.DynamoDBStorage uses
+type FakeDynamoDB struct {
+	mu    sync.Mutex
+	items map[string]map[string]types.AttributeValue
+
+	// PageSize limits how many items Scan returns per page, to exercise pagination
+	PageSize int
+}
+
+// NewFakeDynamoDB creates an empty fake table
+func NewFakeDynamoDB() *FakeDynamoDB {
+	return &FakeDynamoDB{items: make(map[string]map[string]types.AttributeValue), PageSize: 2}
+}
+
+func keyOf(key map[string]types.AttributeValue) string {
+	if value, ok := key["pk"].(*types.AttributeValueMemberS); ok {
+		return value.Value
+	}
+	return ""
+}
+
+// GetItem implements auth.DynamoDBAPI
+func (f *FakeDynamoDB) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: f.items[keyOf(params.Key)]}, nil
+}
+
+// PutItem implements auth.DynamoDBAPI
+func (f *FakeDynamoDB) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items[keyOf(params.Item)] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+// DeleteItem implements auth.DynamoDBAPI
+func (f *FakeDynamoDB) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := keyOf(params.Key)
+	if _, exists := f.items[key]; !exists && params.ConditionExpression != nil &&
+		strings.HasPrefix(*params.ConditionExpression, "attribute_exists") {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	output := &dynamodb.DeleteItemOutput{}
+	if params.ReturnValues == types.ReturnValueAllOld {
+		output.Attributes = f.items[key]
+	}
+	delete(f.items, key)
+	return output, nil
+}
+
+// Scan implements auth.DynamoDBAPI
+func (f *FakeDynamoDB) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := ""
+	if params.FilterExpression != nil && strings.HasPrefix(*params.FilterExpression, "begins_with") {
+		if value, ok := params.ExpressionAttributeValues[":prefix"].(*types.AttributeValueMemberS); ok {
+			prefix = value.Value
+		}
+	}
+
+	keys := make([]string, 0, len(f.items))
+	for key := range f.items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	start := keyOf(params.ExclusiveStartKey)
+	output := &dynamodb.ScanOutput{}
+	scanned := 0
+	for i, key := range keys {
+		if start != "" && key <= start {
+			continue
+		}
+		if f.PageSize > 0 && scanned == f.PageSize {
+			output.LastEvaluatedKey = map[string]types.AttributeValue{
+				"pk": &types.AttributeValueMemberS{Value: keys[i-1]},
+			}
+			break
+		}
+		scanned++
+		if strings.HasPrefix(key, prefix) {
+			output.Items = append(output.Items, f.items[key])
+		}
+	}
+	return output, nil
+}
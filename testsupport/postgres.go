@@ -0,0 +1,167 @@
+package testsupport
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// FakePostgres is an in-memory stand-in for the single key/data/expires_at table used by
+// auth.PostgresStorage
+// It recognizes only the statements that storage issues, by their shape, and fails on others
+type FakePostgres struct {
+	mu   sync.Mutex
+	rows map[string]fakePostgresRow
+}
+
+type fakePostgresRow struct {
+	data      []byte
+	expiresAt *time.Time
+}
+
+// NewFakePostgres creates an empty fake table
+func NewFakePostgres() *FakePostgres {
+	return &FakePostgres{rows: make(map[string]fakePostgresRow)}
+}
+
+// Exec implements auth.PostgresAPI
+func (f *FakePostgres) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case strings.HasPrefix(sql, "CREATE "):
+		return pgconn.NewCommandTag("CREATE"), nil
+
+	case strings.HasPrefix(sql, "INSERT INTO "):
+		data := append([]byte(nil), args[1].([]byte)...)
+		f.rows[args[0].(string)] = fakePostgresRow{data: data, expiresAt: args[2].(*time.Time)}
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+
+	case strings.HasPrefix(sql, "DELETE FROM ") && strings.HasSuffix(sql, "WHERE key = $1"):
+		key := args[0].(string)
+		if _, ok := f.rows[key]; !ok {
+			return pgconn.NewCommandTag("DELETE 0"), nil
+		}
+		delete(f.rows, key)
+		return pgconn.NewCommandTag("DELETE 1"), nil
+
+	case strings.HasPrefix(sql, "DELETE FROM ") && strings.HasSuffix(sql, "WHERE expires_at < $1"):
+		now := args[0].(time.Time)
+		deleted := 0
+		for key, row := range f.rows {
+			if row.expiresAt != nil && row.expiresAt.Before(now) {
+				delete(f.rows, key)
+				deleted++
+			}
+		}
+		return pgconn.NewCommandTag(fmt.Sprintf("DELETE %d", deleted)), nil
+	}
+	return pgconn.CommandTag{}, fmt.Errorf("fake postgres: unsupported statement %q", sql)
+}
+
+// QueryRow implements auth.PostgresAPI
+func (f *FakePostgres) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	consume := strings.HasPrefix(sql, "DELETE FROM ") && strings.HasSuffix(sql, "WHERE key = $1 RETURNING data")
+	if !consume && (!strings.HasPrefix(sql, "SELECT data FROM ") || !strings.HasSuffix(sql, "WHERE key = $1")) {
+		return &fakePostgresRows{err: fmt.Errorf("fake postgres: unsupported query %q", sql)}
+	}
+	row, ok := f.rows[args[0].(string)]
+	if !ok {
+		return &fakePostgresRows{err: pgx.ErrNoRows}
+	}
+	if consume {
+		delete(f.rows, args[0].(string))
+	}
+	return &fakePostgresRows{data: [][]byte{row.data}, index: -1}
+}
+
+// Query implements auth.PostgresAPI for listing rows whose key matches a LIKE 'prefix%' pattern
+// and that haven't expired
+func (f *FakePostgres) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	withKeys := strings.HasPrefix(sql, "SELECT key, data FROM ")
+	if !withKeys && !strings.HasPrefix(sql, "SELECT data FROM ") || !strings.Contains(sql, "WHERE key LIKE $1") {
+		return nil, fmt.Errorf("fake postgres: unsupported query %q", sql)
+	}
+	prefix := strings.TrimSuffix(args[0].(string), "%")
+	now := args[1].(time.Time)
+
+	keys := make([]string, 0, len(f.rows))
+	for key, row := range f.rows {
+		if strings.HasPrefix(key, prefix) && (row.expiresAt == nil || row.expiresAt.After(now)) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	rows := &fakePostgresRows{index: -1}
+	for _, key := range keys {
+		rows.data = append(rows.data, f.rows[key].data)
+		if withKeys {
+			rows.keys = append(rows.keys, key)
+		}
+	}
+	return rows, nil
+}
+
+// fakePostgresRows returns rows with a data column, preceded by a key column if keys is set, or
+// err from Scan when QueryRow failed
+type fakePostgresRows struct {
+	keys  []string
+	data  [][]byte
+	index int
+	err   error
+}
+
+func (r *fakePostgresRows) Close()                                       {}
+func (r *fakePostgresRows) Err() error                                   { return nil }
+func (r *fakePostgresRows) CommandTag() pgconn.CommandTag                { return pgconn.NewCommandTag("SELECT") }
+func (r *fakePostgresRows) FieldDescriptions() []pgconn.FieldDescription { return nil }
+func (r *fakePostgresRows) RawValues() [][]byte                          { return [][]byte{r.data[r.index]} }
+func (r *fakePostgresRows) Conn() *pgx.Conn                              { return nil }
+
+func (r *fakePostgresRows) Next() bool {
+	r.index++
+	return r.index < len(r.data)
+}
+
+func (r *fakePostgresRows) Values() ([]any, error) {
+	return []any{r.data[r.index]}, nil
+}
+
+// Scan reads the key column into a *string, if selected, and the data column into a *[]byte; as
+// a pgx.Row it reads the only row
+func (r *fakePostgresRows) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.index < 0 {
+		r.index = 0
+	}
+	if r.keys != nil {
+		key, ok := dest[0].(*string)
+		if len(dest) != 2 || !ok {
+			return fmt.Errorf("fake postgres: rows have a key and a data column")
+		}
+		*key = r.keys[r.index]
+		dest = dest[1:]
+	}
+	target, ok := dest[0].(*[]byte)
+	if len(dest) != 1 || !ok {
+		return fmt.Errorf("fake postgres: rows have a single data column")
+	}
+	*target = append([]byte(nil), r.data[r.index]...)
+	return nil
+}
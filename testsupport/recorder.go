@@ -0,0 +1,204 @@
+// Package testsupport contains helpers shared by the test suites
+package testsupport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// RecorderMode controls whether a Recorder talks to real upstream APIs
+type RecorderMode int
+
+const (
+	// ModeReplay serves responses from the fixture file and fails on unknown requests
+	ModeReplay RecorderMode = iota
+
+	// ModeRecord forwards requests upstream and saves the interactions to the fixture file
+	ModeRecord
+)
+
+// Interaction is a single recorded HTTP request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest identifies a request by method and URL
+type RecordedRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+}
+
+// RecordedResponse holds everything needed to replay a response
+type RecordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that records outbound HTTP interactions to a fixture
+// file, or replays them from it, so tests don't depend on upstream APIs being reachable
+type Recorder struct {
+	mu           sync.Mutex
+	path         string
+	mode         RecorderMode
+	transport    http.RoundTripper
+	interactions []Interaction
+	used         []bool
+}
+
+// NewRecorder creates a recorder backed by the fixture file at path
+// In replay mode the fixture must already exist
+func NewRecorder(path string, mode RecorderMode) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+	}
+
+	if mode == ModeReplay {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture %s (record it with VCR_MODE=record): %w", path, err)
+		}
+		if err := json.Unmarshal(data, &r.interactions); err != nil {
+			return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.interactions))
+	}
+
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeRecord {
+		return r.record(req)
+	}
+	return r.replay(req)
+}
+
+// replay returns the first unused interaction matching the request's method and URL
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != req.URL.String() {
+			continue
+		}
+		r.used[i] = true
+
+		header := make(http.Header)
+		for key, value := range interaction.Response.Headers {
+			header.Set(key, value)
+		}
+
+		return &http.Response{
+			StatusCode:    interaction.Response.StatusCode,
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded interaction for %s %s in %s", req.Method, req.URL, r.path)
+}
+
+// record forwards the request upstream and keeps a copy of the response
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	headers := make(map[string]string)
+	for key := range resp.Header {
+		// Never write cookies into fixtures that get committed
+		if key == "Set-Cookie" {
+			continue
+		}
+		headers[key] = resp.Header.Get(key)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.interactions = append(r.interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    headers,
+			Body:       string(body),
+		},
+	})
+
+	return resp, nil
+}
+
+// Save writes recorded interactions to the fixture file (no-op in replay mode)
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// Client returns an http.Client that sends requests through the recorder
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// NewTestRecorder creates a recorder for testdata/fixtures/<name>.json
+// Set VCR_MODE=record to refresh the fixture from the real upstream API
+func NewTestRecorder(t testing.TB, name string) *Recorder {
+	t.Helper()
+
+	mode := ModeReplay
+	if os.Getenv("VCR_MODE") == "record" {
+		mode = ModeRecord
+	}
+
+	recorder, err := NewRecorder(filepath.Join("testdata", "fixtures", name+".json"), mode)
+	if err != nil {
+		t.Fatalf("Failed to create recorder: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if err := recorder.Save(); err != nil {
+			t.Errorf("Failed to save fixture: %v", err)
+		}
+	})
+
+	return recorder
+}
@@ -0,0 +1,36 @@
+// Package tools is the former import path of the server's tools package, kept so existing
+// imports keep building.
+//
+// Deprecated: Use the public API in
+// EmmanuelDamienDustinDeploymentProject/DeploymentProject/pkg/server; the rest is no longer part of it.
+package tools
+
+import internaltools "EmmanuelDamienDustinDeploymentProject/DeploymentProject/internal/tools"
+
+type (
+	// Deprecated: CalculateAPR is no longer part of the public API.
+	CalculateAPR = internaltools.CalculateAPR
+
+	// Deprecated: CalculateAPRParams is no longer part of the public API.
+	CalculateAPRParams = internaltools.CalculateAPRParams
+
+	// Deprecated: GetCityTime is no longer part of the public API.
+	GetCityTime = internaltools.GetCityTime
+
+	// Deprecated: GetCityTimeParams is no longer part of the public API.
+	GetCityTimeParams = internaltools.GetCityTimeParams
+
+	// Deprecated: GetFortune is no longer part of the public API.
+	GetFortune = internaltools.GetFortune
+
+	// Deprecated: FortuneAPIResponse is no longer part of the public API.
+	FortuneAPIResponse = internaltools.FortuneAPIResponse
+
+	// Deprecated: MCPRegisterableTool is no longer part of the public API.
+	MCPRegisterableTool = internaltools.MCPRegisterableTool
+)
+
+var (
+	// Deprecated: RegisterAll is no longer part of the public API.
+	RegisterAll = internaltools.RegisterAll
+)